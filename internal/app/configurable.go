@@ -17,8 +17,10 @@
 package app
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -38,12 +40,16 @@ const (
 	SourceNames             = "sourcenames"
 	ResourceNames           = "resourcenames"
 	FilterOut               = "filterout"
+	PassthroughOnNoMatch    = "passthroughonnomatch"
 	EncryptionKey           = "key"
 	InitVector              = "initvector"
 	Url                     = "url"
 	ExportMethod            = "method"
 	ExportMethodPost        = "post"
 	ExportMethodPut         = "put"
+	ExportMethodPatch       = "patch"
+	ExportMethodDelete      = "delete"
+	ExportMethodGet         = "get"
 	MimeType                = "mimetype"
 	PersistOnError          = "persistonerror"
 	ContinueOnSendError     = "continueonsenderror"
@@ -59,6 +65,11 @@ const (
 	ValueType               = "valuetype"
 	MediaType               = "mediatype"
 	Rule                    = "rule"
+	ResultKey               = "resultkey"
+	ReturnResult            = "returnresult"
+	Expression              = "expression"
+	ContextKey              = "contextkey"
+	EmptyOnMissing          = "emptyonmissing"
 	BatchThreshold          = "batchthreshold"
 	TimeInterval            = "timeinterval"
 	HeaderName              = "headername"
@@ -71,20 +82,112 @@ const (
 	TransformType           = "type"
 	TransformXml            = "xml"
 	TransformJson           = "json"
+	TransformXmlToJson      = "xmltojson"
+	TransformCsv            = "csv"
+	TransformNdJson         = "ndjson"
+	TransformCborToJson     = "cbortojson"
+	TransformJsonToCbor     = "jsontocbor"
+	CsvColumns              = "csvcolumns"
+	CsvHeader               = "csvheader"
 	AuthMode                = "authmode"
 	Tags                    = "tags"
+	ResolvePlaceholders     = "resolveplaceholders"
+	StrictPlaceholders      = "strictplaceholders"
 	ResponseContentType     = "responsecontenttype"
 	Algorithm               = "algorithm"
 	CompressGZIP            = "gzip"
 	CompressZLIB            = "zlib"
+	CompressZSTD            = "zstd"
+	CompressionLevel        = "compressionlevel"
 	EncryptAES256           = "aes256"
+	EncryptAES256GCM        = "aes256gcm"
 	Mode                    = "mode"
 	BatchByCount            = "bycount"
 	BatchByTime             = "bytime"
 	BatchByTimeAndCount     = "bytimecount"
+	BatchBySize             = "bysize"
+	ByteThreshold           = "bytethreshold"
+	SampleCount             = "samplecount"
+	SampleInterval          = "sampleinterval"
+	Heartbeat               = "heartbeat"
+	MaxAge                  = "maxage"
+	ClockSkewTolerance      = "clockskewtolerance"
+	StateExpiry             = "stateexpiry"
+	EventsPerSecond         = "eventspersecond"
+	Burst                   = "burst"
+	DropWhenLimited         = "dropwhenlimited"
+	UnitConversions         = "conversions"
+	DecimalPlaces           = "decimalplaces"
+	TargetName              = "targetname"
+	AllowPartial            = "allowpartial"
+	Protocol                = "protocol"
+	LatitudeKey             = "latitudekey"
+	LongitudeKey            = "longitudekey"
+	CacheTTL                = "cachettl"
+	CloudEventSource        = "source"
+	CloudEventType          = "eventtype"
+	BinaryMode              = "binarymode"
+	Separator               = "separator"
+	IndexArrays             = "indexarrays"
+	PassThroughNonObject    = "passthroughnonobject"
+	MetricNames             = "metricnames"
+	RedactStrategy          = "strategy"
+	MaxBytes                = "maxbytes"
+	TagName                 = "tagname"
+	Layout                  = "layout"
+	Timezone                = "timezone"
+	IncludeReadings         = "includereadings"
+	Window                  = "window"
+	PassRawReadings         = "passrawreadings"
+	MaxWindows              = "maxwindows"
+	JSONSchema              = "schema"
+	UrlSafe                 = "urlsafe"
 	IsEventData             = "iseventdata"
 	MergeOnSend             = "mergeonsend"
 	HttpRequestHeaders      = "httprequestheaders"
+	HttpRequestTimeout      = "httprequesttimeout"
+	StoreResponseHeaders    = "storeresponseheaders"
+	FailoverUrls            = "failoverurls"
+	ResponseContextKey      = "responsecontextkey"
+	SuccessStatusCodes      = "successstatuscodes"
+	TolerateStatusCodes     = "toleratestatuscodes"
+	FollowRedirects         = "followredirects"
+	SendCorrelationID       = "sendcorrelationid"
+	CorrelationIDHeader     = "correlationidheader"
+	TracePropagation        = "tracepropagation"
+	TraceClientSpan         = "traceclientspan"
+	BodyTemplate            = "bodytemplate"
+	MaxResponseBytes        = "maxresponsebytes"
+	IdempotencyKeyHeader    = "idempotencykeyheader"
+	IdempotencyKeyCtxKey    = "idempotencykeycontextkey"
+	HostMappings            = "hostmappings"
+	LocalAddress            = "localaddress"
+	PayloadHeader           = "payloadheader"
+	MaxIdleConns            = "maxidleconns"
+	IdleConnTimeout         = "idleconntimeout"
+	DisableKeepAlives       = "disablekeepalives"
+	ForceHTTP2              = "forcehttp2"
+	HTTP2Fallback           = "http2fallback"
+	CloudEventHeaders       = "cloudeventheaders"
+	SecretEnvMode           = "secretenvmode"
+	LogRequestResponse      = "logrequestresponse"
+	MultipartFieldName      = "multipartfieldname"
+	MultipartFileName       = "multipartfilename"
+	MultipartFields         = "multipartfields"
+	CircuitBreakerThreshold = "circuitbreakerthreshold"
+	CircuitBreakerCooldown  = "circuitbreakercooldown"
+	UserAgent               = "useragent"
+	SkipWhenContextKey      = "skipwhencontextkey"
+	TenantContextKey        = "tenantcontextkey"
+	LowThreshold            = "low"
+	HighThreshold           = "high"
+	PassNonNumeric          = "passnonnumeric"
+	SetAcceptHeader         = "setacceptheader"
+	ResponseMimeType        = "responsemimetype"
+	TenantSecretNames       = "tenantsecretnames"
+	JSONSerialization       = "jsonserialization"
+	JSONIndent              = "jsonindent"
+	MaxPayloadHeaderBytes   = "maxpayloadheaderbytes"
 	WillEnabled             = "willenabled"
 	WillTopic               = "willtopic"
 	WillQos                 = "willqos"
@@ -102,15 +205,19 @@ const (
 // Configurable contains the helper functions that return the function pointers for building the configurable function pipeline.
 // They transform the parameters map from the Pipeline configuration in to the actual parameters required by the function.
 type Configurable struct {
-	lc logger.LoggingClient
-	sp bootstrapInterfaces.SecretProvider
+	appCtx context.Context
+	lc     logger.LoggingClient
+	sp     bootstrapInterfaces.SecretProvider
+	// batches are the Batch transforms created, which are flushed on graceful shutdown
+	batches []*transforms.BatchConfig
 }
 
 // NewConfigurable returns a new instance of Configurable
-func NewConfigurable(lc logger.LoggingClient, sp bootstrapInterfaces.SecretProvider) *Configurable {
+func NewConfigurable(appCtx context.Context, lc logger.LoggingClient, sp bootstrapInterfaces.SecretProvider) *Configurable {
 	return &Configurable{
-		lc: lc,
-		sp: sp,
+		appCtx: appCtx,
+		lc:     lc,
+		sp:     sp,
 	}
 }
 
@@ -182,7 +289,335 @@ func (app *Configurable) FilterByResourceName(parameters map[string]string) inte
 	return transform.FilterByResourceName
 }
 
-// Transform transforms an EdgeX event to XML or JSON based on specified transform type.
+// FilterByTags - Specify the tag key/values of interest to filter for Events which have all of them, such as
+// those added by the AddTags function. If FilterOut is true, Events which have all the tag key/values are filtered out.
+// If PassthroughOnNoMatch is true, Events which would be filtered out are passed on unchanged instead.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) FilterByTags(parameters map[string]string) interfaces.AppFunction {
+	tags, failed := app.processTagsParameter(parameters)
+	if failed {
+		return nil
+	}
+
+	filterOutBool := false
+	filterOut, ok := parameters[FilterOut]
+	if ok {
+		var err error
+		filterOutBool, err = strconv.ParseBool(filterOut)
+		if err != nil {
+			app.lc.Errorf("Could not convert filterOut value `%s` to bool for FilterByTags", filterOut)
+			return nil
+		}
+	}
+
+	passthrough, ok := app.processPassthroughOnNoMatchParameter("FilterByTags", parameters)
+	if !ok {
+		return nil
+	}
+
+	requiredTags := make(map[string]string, len(tags))
+	for key, value := range tags {
+		requiredTags[key] = fmt.Sprint(value)
+	}
+
+	transform := transforms.TagsFilter{
+		RequiredTags:         requiredTags,
+		FilterOut:            filterOutBool,
+		PassthroughOnNoMatch: passthrough,
+	}
+
+	return transform.FilterByTags
+}
+
+// FilterStale removes readings whose Origin is older than MaxAge from Events, stopping the pipeline if all the Event's
+// readings are stale. ClockSkewTolerance optionally specifies additional age allowed for devices' clocks being behind.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) FilterStale(parameters map[string]string) interfaces.AppFunction {
+	value, ok := parameters[MaxAge]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for FilterStale", MaxAge)
+		return nil
+	}
+
+	maxAge, err := time.ParseDuration(value)
+	if err != nil {
+		app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for FilterStale: %s", value, MaxAge, err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewFreshnessFilter(maxAge)
+	if err != nil {
+		app.lc.Errorf("Unable to configure FilterStale function: %s", err.Error())
+		return nil
+	}
+
+	if value := parameters[ClockSkewTolerance]; len(value) > 0 {
+		tolerance, err := time.ParseDuration(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for FilterStale: %s", value, ClockSkewTolerance, err.Error())
+			return nil
+		}
+
+		if err := transform.SetClockSkewTolerance(tolerance); err != nil {
+			app.lc.Errorf("Unable to configure FilterStale function: %s", err.Error())
+			return nil
+		}
+	}
+
+	return transform.FilterStale
+}
+
+// FilterByThreshold removes the readings for ResourceName whose value isn't inside, or outside, the band from Low to
+// High, per Mode which is 'inside' or 'outside', stopping the pipeline if none of the readings for the resource pass.
+// PassNonNumeric optionally specifies whether readings with non-numeric values are passed rather than dropped.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) FilterByThreshold(parameters map[string]string) interfaces.AppFunction {
+	resourceName, ok := parameters[ResourceName]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for FilterByThreshold", ResourceName)
+		return nil
+	}
+
+	value, ok := parameters[LowThreshold]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for FilterByThreshold", LowThreshold)
+		return nil
+	}
+
+	low, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		app.lc.Errorf("Could not parse '%s' to a float for '%s' parameter for FilterByThreshold: %s", value, LowThreshold, err.Error())
+		return nil
+	}
+
+	value, ok = parameters[HighThreshold]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for FilterByThreshold", HighThreshold)
+		return nil
+	}
+
+	high, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		app.lc.Errorf("Could not parse '%s' to a float for '%s' parameter for FilterByThreshold: %s", value, HighThreshold, err.Error())
+		return nil
+	}
+
+	mode, ok := parameters[Mode]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for FilterByThreshold", Mode)
+		return nil
+	}
+
+	transform, err := transforms.NewThresholdFilter(strings.TrimSpace(resourceName), low, high, strings.ToLower(strings.TrimSpace(mode)))
+	if err != nil {
+		app.lc.Errorf("Unable to configure FilterByThreshold function: %s", err.Error())
+		return nil
+	}
+
+	if value := parameters[PassNonNumeric]; len(value) > 0 {
+		passNonNumeric, err := strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for FilterByThreshold: %s", value, PassNonNumeric, err.Error())
+			return nil
+		}
+
+		transform.SetPassNonNumeric(passNonNumeric)
+	}
+
+	return transform.FilterByThreshold
+}
+
+// Sample forwards a sample of the Events for each device and resource, either one of every SampleCount Events or
+// Events at least SampleInterval apart. Exactly one of SampleCount or SampleInterval must be specified.
+// StateExpiry optionally specifies how long the sampling state is kept for devices and resources no longer sending.
+// Events not selected by the sampling stop the pipeline.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) Sample(parameters map[string]string) interfaces.AppFunction {
+	everyN := 0
+	if value := parameters[SampleCount]; len(value) > 0 {
+		var err error
+		everyN, err = strconv.Atoi(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to an int for '%s' parameter for Sample: %s", value, SampleCount, err.Error())
+			return nil
+		}
+	}
+
+	var minInterval time.Duration
+	if value := parameters[SampleInterval]; len(value) > 0 {
+		var err error
+		minInterval, err = time.ParseDuration(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for Sample: %s", value, SampleInterval, err.Error())
+			return nil
+		}
+	}
+
+	var stateExpiry time.Duration
+	if value := parameters[StateExpiry]; len(value) > 0 {
+		var err error
+		stateExpiry, err = time.ParseDuration(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for Sample: %s", value, StateExpiry, err.Error())
+			return nil
+		}
+	}
+
+	transform, err := transforms.NewSampler(everyN, minInterval, stateExpiry)
+	if err != nil {
+		app.lc.Errorf("Unable to configure Sample function: %s", err.Error())
+		return nil
+	}
+
+	return transform.Sample
+}
+
+// Dedup suppresses Events whose reading values are unchanged from those last forwarded for each device and resource.
+// When Heartbeat is specified, unchanged values are also forwarded once Heartbeat has elapsed since last forwarded.
+// StateExpiry optionally specifies how long the last values are kept for devices and resources no longer sending.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) Dedup(parameters map[string]string) interfaces.AppFunction {
+	var heartbeat time.Duration
+	if value := parameters[Heartbeat]; len(value) > 0 {
+		var err error
+		heartbeat, err = time.ParseDuration(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for Dedup: %s", value, Heartbeat, err.Error())
+			return nil
+		}
+	}
+
+	var stateExpiry time.Duration
+	if value := parameters[StateExpiry]; len(value) > 0 {
+		var err error
+		stateExpiry, err = time.ParseDuration(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for Dedup: %s", value, StateExpiry, err.Error())
+			return nil
+		}
+	}
+
+	transform, err := transforms.NewDedup(heartbeat, stateExpiry)
+	if err != nil {
+		app.lc.Errorf("Unable to configure Dedup function: %s", err.Error())
+		return nil
+	}
+
+	return transform.Dedup
+}
+
+// AddGeoLocation enriches Events with their device's location, which is read from the LatitudeKey and LongitudeKey
+// device protocol properties in Core Metadata. Protocol optionally restricts the lookup to the named protocol.
+// The location is added to the Event's tags unless ContextKey is specified, in which case it is stored in the
+// context as "latitude,longitude". CacheTTL optionally specifies how long device locations are cached.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) AddGeoLocation(parameters map[string]string) interfaces.AppFunction {
+	latitudeKey, ok := parameters[LatitudeKey]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for AddGeoLocation", LatitudeKey)
+		return nil
+	}
+
+	longitudeKey, ok := parameters[LongitudeKey]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for AddGeoLocation", LongitudeKey)
+		return nil
+	}
+
+	var cacheTTL time.Duration
+	if value := parameters[CacheTTL]; len(value) > 0 {
+		var err error
+		cacheTTL, err = time.ParseDuration(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for AddGeoLocation: %s", value, CacheTTL, err.Error())
+			return nil
+		}
+	}
+
+	transform, err := transforms.NewGeoLocation(strings.TrimSpace(parameters[Protocol]), strings.TrimSpace(latitudeKey), strings.TrimSpace(longitudeKey), cacheTTL)
+	if err != nil {
+		app.lc.Errorf("Unable to configure AddGeoLocation function: %s", err.Error())
+		return nil
+	}
+
+	transform.SetContextKey(strings.TrimSpace(parameters[ContextKey]))
+
+	return transform.AddGeoLocation
+}
+
+// AddResourceMetadata annotates Event readings with the units, minimum and maximum of their resource from the device
+// profile in Core Metadata. CacheTTL optionally specifies how long device profiles are cached.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) AddResourceMetadata(parameters map[string]string) interfaces.AppFunction {
+	var cacheTTL time.Duration
+	if value := parameters[CacheTTL]; len(value) > 0 {
+		var err error
+		cacheTTL, err = time.ParseDuration(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for AddResourceMetadata: %s", value, CacheTTL, err.Error())
+			return nil
+		}
+	}
+
+	transform, err := transforms.NewResourceMetadata(cacheTTL)
+	if err != nil {
+		app.lc.Errorf("Unable to configure AddResourceMetadata function: %s", err.Error())
+		return nil
+	}
+
+	return transform.AddResourceMetadata
+}
+
+// RateLimit caps the rate at which data continues through the pipeline to EventsPerSecond, with bursts of up to
+// Burst, which defaults to 1. Data is blocked until allowed unless DropWhenLimited is true, in which case it is
+// dropped and the pipeline stopped.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) RateLimit(parameters map[string]string) interfaces.AppFunction {
+	value, ok := parameters[EventsPerSecond]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for RateLimit", EventsPerSecond)
+		return nil
+	}
+
+	eventsPerSecond, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		app.lc.Errorf("Could not parse '%s' to a float for '%s' parameter for RateLimit: %s", value, EventsPerSecond, err.Error())
+		return nil
+	}
+
+	burst := 1
+	if value := parameters[Burst]; len(value) > 0 {
+		burst, err = strconv.Atoi(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to an int for '%s' parameter for RateLimit: %s", value, Burst, err.Error())
+			return nil
+		}
+	}
+
+	dropWhenLimited := false
+	if value := parameters[DropWhenLimited]; len(value) > 0 {
+		dropWhenLimited, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for RateLimit: %s", value, DropWhenLimited, err.Error())
+			return nil
+		}
+	}
+
+	transform, err := transforms.NewRateLimiter(eventsPerSecond, burst)
+	if err != nil {
+		app.lc.Errorf("Unable to configure RateLimit function: %s", err.Error())
+		return nil
+	}
+
+	transform.SetDropWhenLimited(dropWhenLimited)
+	transform.SetContext(app.appCtx)
+	return transform.Limit
+}
+
+// Transform transforms an EdgeX event to XML, JSON, CSV, NDJSON or CBOR based on specified transform type.
 // It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
 // This function is a configuration function and returns a function pointer.
 func (app *Configurable) Transform(parameters map[string]string) interfaces.AppFunction {
@@ -199,16 +634,88 @@ func (app *Configurable) Transform(parameters map[string]string) interfaces.AppF
 		return transform.TransformToXML
 	case TransformJson:
 		return transform.TransformToJSON
+	case TransformXmlToJson:
+		return transform.ConvertXMLToJSON
+	case TransformCsv:
+		return app.csvTransform(parameters)
+	case TransformNdJson:
+		return transform.ConvertToNDJSON
+	case TransformCborToJson:
+		return transform.ConvertCBORToJSON
+	case TransformJsonToCbor:
+		return transform.ConvertJSONToCBOR
 	default:
 		app.lc.Errorf(
-			"Invalid transform type '%s'. Must be '%s' or '%s'",
+			"Invalid transform type '%s'. Must be '%s', '%s', '%s', '%s', '%s', '%s' or '%s'",
 			transformType,
 			TransformXml,
-			TransformJson)
+			TransformJson,
+			TransformXmlToJson,
+			TransformCsv,
+			TransformNdJson,
+			TransformCborToJson,
+			TransformJsonToCbor)
 		return nil
 	}
 }
 
+func (app *Configurable) csvTransform(parameters map[string]string) interfaces.AppFunction {
+	var columns []string
+	value, ok := parameters[CsvColumns]
+	if ok {
+		columns = util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma))
+	}
+
+	includeHeader := true
+	value, ok = parameters[CsvHeader]
+	if ok {
+		var err error
+		includeHeader, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for Transform: %s", value, CsvHeader, err.Error())
+			return nil
+		}
+	}
+
+	transform, err := transforms.NewCSVConversion(columns, includeHeader)
+	if err != nil {
+		app.lc.Errorf("Unable to configure CSV Transform: %s", err.Error())
+		return nil
+	}
+
+	return transform.ConvertToCSV
+}
+
+// ConvertToCloudEvent converts an EdgeX Event to a CNCF CloudEvent with the specified source and type and the Event
+// JSON as the data. In binary mode the Event JSON is passed on and the CloudEvent attributes are stored in the context
+// for HTTPExport to send as headers when CloudEventHeaders is set.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) ConvertToCloudEvent(parameters map[string]string) interfaces.AppFunction {
+	source, ok := parameters[CloudEventSource]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for ConvertToCloudEvent", CloudEventSource)
+		return nil
+	}
+
+	transform, err := transforms.NewCloudEvent(source, parameters[CloudEventType])
+	if err != nil {
+		app.lc.Errorf("Unable to configure ConvertToCloudEvent function: %s", err.Error())
+		return nil
+	}
+
+	if value, ok := parameters[BinaryMode]; ok {
+		binaryMode, err := strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for ConvertToCloudEvent: %s", value, BinaryMode, err.Error())
+			return nil
+		}
+
+		transform.SetBinaryMode(binaryMode)
+	}
+
+	return transform.ConvertToCloudEvent
+}
+
 // WrapIntoEvent wraps the provided value as an EdgeX Event using the configured event/reading metadata that have been
 // set. The new Event/Reading is returned to the next pipeline function. This function is a configuration function and
 // returns a function pointer.
@@ -291,18 +798,40 @@ func (app *Configurable) Compress(parameters map[string]string) interfaces.AppFu
 		return transform.CompressWithGZIP
 	case CompressZLIB:
 		return transform.CompressWithZLIB
+	case CompressZSTD:
+		// CompressionLevel is optional and the zstd default level is used if not specified
+		level := 0
+		if value := parameters[CompressionLevel]; len(value) > 0 {
+			var err error
+			level, err = strconv.Atoi(value)
+			if err != nil {
+				app.lc.Errorf("Could not parse '%s' to an int for '%s' parameter: %s", value, CompressionLevel, err.Error())
+				return nil
+			}
+		}
+
+		zstdTransform, err := transforms.NewCompressionWithZSTDLevel(level)
+		if err != nil {
+			app.lc.Error(err.Error())
+			return nil
+		}
+
+		return zstdTransform.CompressWithZSTD
 	default:
 		app.lc.Errorf(
-			"Invalid compression algorithm '%s'. Must be '%s' or '%s'",
+			"Invalid compression algorithm '%s'. Must be '%s', '%s' or '%s'",
 			algorithm,
 			CompressGZIP,
-			CompressZLIB)
+			CompressZLIB,
+			CompressZSTD)
 		return nil
 	}
 }
 
 // Encrypt encrypts either a string, []byte, or json.Marshaller type using specified encryption
 // algorithm (AES only at this time). It will return a byte[] of the encrypted data.
+// For AES 256 GCM, the encryption key can be selected per Event using TenantSecretNames, a comma separated list of
+// 'tenant=secretName' for the tenant stored in the context under TenantContextKey, in place of SecretName.
 // This function is a configuration function and returns a function pointer.
 func (app *Configurable) Encrypt(parameters map[string]string) interfaces.AppFunction {
 	algorithm, ok := parameters[Algorithm]
@@ -315,6 +844,10 @@ func (app *Configurable) Encrypt(parameters map[string]string) interfaces.AppFun
 	secretValueKey := parameters[SecretValueKey]
 	encryptionKey := parameters[EncryptionKey]
 
+	if len(parameters[TenantSecretNames]) > 0 {
+		return app.encryptPerTenant(algorithm, parameters)
+	}
+
 	// SecretName & SecretValueKey are optional if EncryptionKey specified
 	// EncryptionKey is optional if SecretName & SecretValueKey are specified
 
@@ -341,33 +874,80 @@ func (app *Configurable) Encrypt(parameters map[string]string) interfaces.AppFun
 		}
 		app.lc.Error("secretName / secretValueKey are required for AES 256 encryption")
 		return nil
+	case EncryptAES256GCM:
+		if len(secretName) > 0 && len(secretValueKey) > 0 {
+			return transforms.NewAESGCMProtection(secretName, secretValueKey).Encrypt
+		}
+		app.lc.Error("secretName / secretValueKey are required for AES 256 GCM encryption")
+		return nil
 	default:
 		app.lc.Errorf(
-			"Invalid encryption algorithm '%s'. Must be '%s",
+			"Invalid encryption algorithm '%s'. Must be '%s' or '%s'",
 			algorithm,
-			EncryptAES256)
+			EncryptAES256,
+			EncryptAES256GCM)
 		return nil
 	}
 }
 
-// HTTPExport will send data from the previous function to the specified Endpoint via http POST or PUT. If no previous function exists,
-// then the event that triggered the pipeline will be used. Passing an empty string to the mimetype
-// method will default to application/json.
-// This function is a configuration function and returns a function pointer.
-func (app *Configurable) HTTPExport(parameters map[string]string) interfaces.AppFunction {
-	options, method, err := app.processHttpExportParameters(parameters)
-	if err != nil {
-		app.lc.Error(err.Error())
+// encryptPerTenant configures AES 256 GCM encryption with the key selected per Event by the tenant in the context
+func (app *Configurable) encryptPerTenant(algorithm string, parameters map[string]string) interfaces.AppFunction {
+	if strings.ToLower(algorithm) != EncryptAES256GCM {
+		app.lc.Errorf("'%s' parameter is only supported for '%s' encryption", TenantSecretNames, EncryptAES256GCM)
 		return nil
 	}
 
-	transform := transforms.NewHTTPSenderWithOptions(options)
+	tenantContextKey := strings.TrimSpace(parameters[TenantContextKey])
+	if len(tenantContextKey) == 0 {
+		app.lc.Errorf("Could not find '%s' parameter for Encrypt, required with '%s'", TenantContextKey, TenantSecretNames)
+		return nil
+	}
 
-	// Unmarshal and set httpRequestHeaders
-	httpRequestHeaders := map[string]string{}
-	if parameters[HttpRequestHeaders] != "" {
-		if err := json.Unmarshal([]byte(parameters[HttpRequestHeaders]), &httpRequestHeaders); err != nil {
-			app.lc.Error("Unable to unmarshal http request headers : %s", err.Error())
+	secretNames := make(map[string]string)
+	for _, mapping := range util.DeleteEmptyAndTrim(strings.FieldsFunc(parameters[TenantSecretNames], util.SplitComma)) {
+		tenant, secretName, found := strings.Cut(mapping, "=")
+		tenant = strings.TrimSpace(tenant)
+		secretName = strings.TrimSpace(secretName)
+		if !found || len(tenant) == 0 || len(secretName) == 0 {
+			app.lc.Errorf("Could not parse '%s' to a tenant=secretName mapping for '%s' parameter for Encrypt", mapping, TenantSecretNames)
+			return nil
+		}
+
+		secretNames[tenant] = secretName
+	}
+
+	protection, err := transforms.NewAESGCMProtectionWithKeyResolver(
+		transforms.EncryptionKeyByTenant(transforms.RouteByContextValue(tenantContextKey), secretNames),
+		strings.TrimSpace(parameters[SecretValueKey]))
+	if err != nil {
+		app.lc.Errorf("Unable to configure Encrypt function: %s", err.Error())
+		return nil
+	}
+
+	return protection.Encrypt
+}
+
+// HTTPExport will send data from the previous function to the specified Endpoint via http POST, PUT, PATCH or DELETE. If no previous function exists,
+// then the event that triggered the pipeline will be used. Passing an empty string to the mimetype
+// method will default to application/json.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) HTTPExport(parameters map[string]string) interfaces.AppFunction {
+	options, method, err := app.processHttpExportParameters(parameters)
+	if err != nil {
+		app.lc.Error(err.Error())
+		return nil
+	}
+
+	// Abort in-flight exports when the service is shutting down
+	options.Context = app.appCtx
+
+	transform := transforms.NewHTTPSenderWithOptions(options)
+
+	// Unmarshal and set httpRequestHeaders
+	httpRequestHeaders := map[string]string{}
+	if parameters[HttpRequestHeaders] != "" {
+		if err := json.Unmarshal([]byte(parameters[HttpRequestHeaders]), &httpRequestHeaders); err != nil {
+			app.lc.Error("Unable to unmarshal http request headers : %s", err.Error())
 			return nil
 		}
 	}
@@ -379,18 +959,28 @@ func (app *Configurable) HTTPExport(parameters map[string]string) interfaces.App
 		return transform.HTTPPost
 	case ExportMethodPut:
 		return transform.HTTPPut
+	case ExportMethodPatch:
+		return transform.HTTPPatch
+	case ExportMethodDelete:
+		return transform.HTTPDelete
+	case ExportMethodGet:
+		return transform.HTTPGet
 	default:
 		app.lc.Errorf(
-			"Invalid HTTPExport method of '%s'. Must be '%s' or '%s'",
+			"Invalid HTTPExport method of '%s'. Must be '%s', '%s', '%s', '%s' or '%s'",
 			method,
 			ExportMethodPost,
-			ExportMethodPut)
+			ExportMethodPut,
+			ExportMethodPatch,
+			ExportMethodDelete,
+			ExportMethodGet)
 		return nil
 	}
 }
 
 // MQTTExport will send data from the previous function to the specified Endpoint via MQTT publish. If no previous function exists,
-// then the event that triggered the pipeline will be used.
+// then the event that triggered the pipeline will be used. The Topic may contain placeholders, such as {deviceName},
+// which are resolved from the Event being exported or the context storage.
 // This function is a configuration function and returns a function pointer.
 func (app *Configurable) MQTTExport(parameters map[string]string) interfaces.AppFunction {
 	var err error
@@ -433,6 +1023,10 @@ func (app *Configurable) MQTTExport(parameters map[string]string) interfaces.App
 			app.lc.Error("Unable to parse " + Qos + " value")
 			return nil
 		}
+		if qos < 0 || qos > 2 {
+			app.lc.Errorf("Invalid '%s' value of '%d'. Must be 0, 1 or 2", Qos, qos)
+			return nil
+		}
 	}
 	retainVal, ok := parameters[Retain]
 	if ok {
@@ -581,13 +1175,44 @@ func (app *Configurable) MQTTExport(parameters map[string]string) interfaces.App
 			return nil
 		}
 	}
-	transform := transforms.NewMQTTSecretSender(mqttConfig, persistOnError)
+	transform := transforms.NewMQTTSecretSenderWithTopicFormatter(mqttConfig, persistOnError, transforms.EventValuesFormatter)
 	if preConnect {
 		transform.ConnectToBroker(app.lc, app.sp, preConnectRetryCount, preConnectRetryInterval)
 	}
 	return transform.MQTTSend
 }
 
+// WebSocketExport will send data from the previous function as messages on a persistent websocket connection to the
+// specified Url. HeaderName, SecretName and SecretValueKey optionally specify a header sent during the handshake with
+// its value from the SecretStore. If no previous function exists, then the event that triggered the pipeline will be used.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) WebSocketExport(parameters map[string]string) interfaces.AppFunction {
+	url, ok := parameters[Url]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for WebSocketExport", Url)
+		return nil
+	}
+
+	options := transforms.WebSocketSenderOptions{
+		URL:            url,
+		HTTPHeaderName: parameters[HeaderName],
+		SecretName:     parameters[SecretName],
+		SecretValueKey: parameters[SecretValueKey],
+	}
+
+	if value := parameters[PersistOnError]; len(value) > 0 {
+		var err error
+		options.PersistOnError, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for WebSocketExport: %s", value, PersistOnError, err.Error())
+			return nil
+		}
+	}
+
+	transform := transforms.NewWebSocketSender(options)
+	return transform.WebSocketSend
+}
+
 // SetResponseData sets the response data to that passed in from the previous function and the response content type
 // to that set in the ResponseContentType configuration parameter. It will return an error and stop the pipeline if
 // data passed in is not of type []byte, string or json.Marshaller
@@ -673,13 +1298,35 @@ func (app *Configurable) Batch(parameters map[string]string) interfaces.AppFunct
 			return nil
 		}
 
+	case BatchBySize:
+		byteThreshold, ok := parameters[ByteThreshold]
+		if !ok {
+			app.lc.Errorf("Could not find '%s' parameter for BatchBySize", ByteThreshold)
+			return nil
+		}
+
+		thresholdValue, err := strconv.Atoi(byteThreshold)
+		if err != nil {
+			app.lc.Errorf(
+				"Could not parse '%s' to an int for '%s' parameter for BatchBySize: %s",
+				byteThreshold, ByteThreshold, err.Error())
+			return nil
+		}
+
+		transform, err = transforms.NewBatchBySize(thresholdValue)
+		if err != nil {
+			app.lc.Error(err.Error())
+			return nil
+		}
+
 	default:
 		app.lc.Errorf(
-			"Invalid batch mode '%s'. Must be '%s', '%s' or '%s'",
+			"Invalid batch mode '%s'. Must be '%s', '%s', '%s' or '%s'",
 			mode,
 			BatchByCount,
 			BatchByTime,
-			BatchByTimeAndCount)
+			BatchByTimeAndCount,
+			BatchBySize)
 		return nil
 	}
 
@@ -707,6 +1354,8 @@ func (app *Configurable) Batch(parameters map[string]string) interfaces.AppFunct
 		transform.MergeOnSend = mergeOnSend
 	}
 
+	app.batches = append(app.batches, transform)
+
 	return transform.Batch
 }
 
@@ -718,127 +1367,612 @@ func (app *Configurable) JSONLogic(parameters map[string]string) interfaces.AppF
 		return nil
 	}
 
-	transform := transforms.NewJSONLogic(rule)
+	resultKey := parameters[ResultKey]
+
+	returnResult := false
+	value, ok := parameters[ReturnResult]
+	if ok {
+		var err error
+		returnResult, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for JSONLogic: %s", value, ReturnResult, err.Error())
+			return nil
+		}
+	}
+
+	transform := transforms.NewJSONLogicWithResult(rule, resultKey, returnResult)
 	return transform.Evaluate
 }
 
-// AddTags adds the configured list of tags to Events passed to the transform.
+// ExtractJSONPath stores the value selected by the JSONPath Expression from the JSON data in the context under
+// ContextKey, passing the data on unchanged. When EmptyOnMissing is true, an empty value is stored if the path isn't
+// found, otherwise an error is returned.
 // This function is a configuration function and returns a function pointer.
-func (app *Configurable) AddTags(parameters map[string]string) interfaces.AppFunction {
-	tags, failed := app.processTagsParameter(parameters)
-	if failed {
+func (app *Configurable) ExtractJSONPath(parameters map[string]string) interfaces.AppFunction {
+	expression, ok := parameters[Expression]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for ExtractJSONPath", Expression)
 		return nil
 	}
 
-	transform := transforms.NewTags(tags)
-	return transform.AddTags
+	contextKey, ok := parameters[ContextKey]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for ExtractJSONPath", ContextKey)
+		return nil
+	}
+
+	emptyOnMissing := false
+	if value := parameters[EmptyOnMissing]; len(value) > 0 {
+		var err error
+		emptyOnMissing, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for ExtractJSONPath: %s", value, EmptyOnMissing, err.Error())
+			return nil
+		}
+	}
+
+	transform, err := transforms.NewJSONPathExtractor(expression, contextKey)
+	if err != nil {
+		app.lc.Errorf("Unable to configure ExtractJSONPath function: %s", err.Error())
+		return nil
+	}
+
+	transform.SetEmptyOnMissing(emptyOnMissing)
+	return transform.Extract
 }
 
-func (app *Configurable) processTagsParameter(parameters map[string]string) (map[string]interface{}, bool) {
-	tagsSpec, ok := parameters[Tags]
+// FlattenJSON flattens the nested objects in the JSON object data into a single level JSON object with the keys of
+// the nested levels joined by Separator, which defaults to '.'. When IndexArrays is true arrays are also flattened
+// using the element index as the key. When PassThroughNonObject is true data that isn't a JSON object is passed on
+// unchanged, otherwise an error is returned.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) FlattenJSON(parameters map[string]string) interfaces.AppFunction {
+	transform := transforms.NewJSONFlattener(parameters[Separator])
+
+	if value := parameters[IndexArrays]; len(value) > 0 {
+		indexArrays, err := strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for FlattenJSON: %s", value, IndexArrays, err.Error())
+			return nil
+		}
+
+		transform.SetIndexArrays(indexArrays)
+	}
+
+	if value := parameters[PassThroughNonObject]; len(value) > 0 {
+		passThrough, err := strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for FlattenJSON: %s", value, PassThroughNonObject, err.Error())
+			return nil
+		}
+
+		transform.SetPassThroughNonObject(passThrough)
+	}
+
+	return transform.FlattenJSON
+}
+
+// ExplodeObjectReading replaces the Object readings for ResourceName with a reading per top-level field of the
+// object, named 'resourceName.field', with the value type inferred from the field value.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) ExplodeObjectReading(parameters map[string]string) interfaces.AppFunction {
+	resourceName, ok := parameters[ResourceName]
 	if !ok {
-		app.lc.Error(fmt.Sprintf("Could not find '%s' parameter", Tags))
-		return nil, true
+		app.lc.Errorf("Could not find '%s' parameter for ExplodeObjectReading", ResourceName)
+		return nil
 	}
 
-	tagKeyValues := util.DeleteEmptyAndTrim(strings.FieldsFunc(tagsSpec, util.SplitComma))
+	transform, err := transforms.NewObjectReadingExploder(strings.TrimSpace(resourceName))
+	if err != nil {
+		app.lc.Errorf("Unable to configure ExplodeObjectReading function: %s", err.Error())
+		return nil
+	}
 
-	tags := make(map[string]interface{})
-	for _, tag := range tagKeyValues {
-		keyValue := util.DeleteEmptyAndTrim(strings.FieldsFunc(tag, util.SplitColon))
-		if len(keyValue) != 2 {
-			app.lc.Errorf("Bad Tags specification format. Expect comma separated list of 'key:value'. Got `%s`", tagsSpec)
-			return nil, true
+	return transform.ExplodeObjectReading
+}
+
+// NormalizeReadingTypes converts the Event to JSON with the values of the Int, Uint and Float readings written as
+// JSON numbers, based on each reading's value type, rather than as strings.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) NormalizeReadingTypes(_ map[string]string) interfaces.AppFunction {
+	return transforms.NewReadingTypeNormalizer().NormalizeReadingTypes
+}
+
+// ConvertUnits converts the numeric reading values and units for the resources specified in Conversions, a comma
+// separated list of 'resourceName:conversionName' using the predefined conversions such as FahrenheitToCelsius.
+// DecimalPlaces optionally specifies the number of decimal places converted float values are rounded to.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) ConvertUnits(parameters map[string]string) interfaces.AppFunction {
+	conversionsSpec, ok := parameters[UnitConversions]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for ConvertUnits", UnitConversions)
+		return nil
+	}
+
+	conversions := make(map[string]transforms.UnitConversion)
+	for _, entry := range util.DeleteEmptyAndTrim(strings.FieldsFunc(conversionsSpec, util.SplitComma)) {
+		resourceConversion := util.DeleteEmptyAndTrim(strings.FieldsFunc(entry, util.SplitColon))
+		if len(resourceConversion) != 2 || len(resourceConversion[0]) == 0 || len(resourceConversion[1]) == 0 {
+			app.lc.Errorf("Bad Conversions specification format. Expect comma separated list of 'resourceName:conversionName'. Got `%s`", conversionsSpec)
+			return nil
 		}
 
-		if len(keyValue[0]) == 0 {
-			app.lc.Errorf("Tag key missing. Got '%s'", tag)
-			return nil, true
+		conversion, err := transforms.NamedUnitConversion(resourceConversion[1])
+		if err != nil {
+			app.lc.Errorf("Unable to configure ConvertUnits function: %s", err.Error())
+			return nil
 		}
-		if len(keyValue[1]) == 0 {
-			app.lc.Errorf("Tag value missing. Got '%s'", tag)
-			return nil, true
+
+		conversions[resourceConversion[0]] = conversion
+	}
+
+	decimalPlaces := -1
+	if value := parameters[DecimalPlaces]; len(value) > 0 {
+		var err error
+		decimalPlaces, err = strconv.Atoi(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to an int for '%s' parameter for ConvertUnits: %s", value, DecimalPlaces, err.Error())
+			return nil
 		}
+	}
 
-		tags[keyValue[0]] = keyValue[1]
+	transform, err := transforms.NewUnitConverter(conversions)
+	if err != nil {
+		app.lc.Errorf("Unable to configure ConvertUnits function: %s", err.Error())
+		return nil
 	}
-	return tags, false
+
+	transform.SetDecimalPlaces(decimalPlaces)
+	return transform.Convert
 }
 
-func (app *Configurable) processFilterParameters(
-	funcName string,
-	parameters map[string]string,
-	paramName string) (*transforms.Filter, bool) {
-	names, ok := parameters[paramName]
+// EmitMetrics reports the values of numeric readings as gauge metrics for the resources in MetricNames, a comma
+// separated list of 'resourceName:metricName'. The Event is passed on unchanged.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) EmitMetrics(parameters map[string]string) interfaces.AppFunction {
+	metricNamesSpec, ok := parameters[MetricNames]
 	if !ok {
-		app.lc.Errorf("Could not find '%s' parameter for %s", paramName, funcName)
-		return nil, false
+		app.lc.Errorf("Could not find '%s' parameter for EmitMetrics", MetricNames)
+		return nil
 	}
 
-	filterOutBool := false
-	filterOut, ok := parameters[FilterOut]
-	if ok {
+	metricNames := make(map[string]string)
+	for _, entry := range util.DeleteEmptyAndTrim(strings.FieldsFunc(metricNamesSpec, util.SplitComma)) {
+		resourceMetric := util.DeleteEmptyAndTrim(strings.FieldsFunc(entry, util.SplitColon))
+		if len(resourceMetric) != 2 || len(resourceMetric[0]) == 0 || len(resourceMetric[1]) == 0 {
+			app.lc.Errorf("Bad MetricNames specification format. Expect comma separated list of 'resourceName:metricName'. Got `%s`", metricNamesSpec)
+			return nil
+		}
+
+		metricNames[resourceMetric[0]] = resourceMetric[1]
+	}
+
+	transform, err := transforms.NewMetricEmitter(metricNames)
+	if err != nil {
+		app.lc.Errorf("Unable to configure EmitMetrics function: %s", err.Error())
+		return nil
+	}
+
+	return transform.EmitMetrics
+}
+
+// CoalesceReadings merges the readings for the comma separated list of ResourceNames into a single Object reading
+// named TargetName. AllowPartial optionally specifies that Events without a reading for all the resources are merged
+// with null values for the missing resources, rather than passed on unchanged.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) CoalesceReadings(parameters map[string]string) interfaces.AppFunction {
+	value, ok := parameters[ResourceNames]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for CoalesceReadings", ResourceNames)
+		return nil
+	}
+
+	resourceNames := util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma))
+
+	targetName, ok := parameters[TargetName]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for CoalesceReadings", TargetName)
+		return nil
+	}
+
+	allowPartial := false
+	if value := parameters[AllowPartial]; len(value) > 0 {
 		var err error
-		filterOutBool, err = strconv.ParseBool(filterOut)
+		allowPartial, err = strconv.ParseBool(value)
 		if err != nil {
-			app.lc.Errorf("Could not convert filterOut value `%s` to bool for %s", filterOut, funcName)
-			return nil, false
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for CoalesceReadings: %s", value, AllowPartial, err.Error())
+			return nil
 		}
 	}
 
-	namesCleaned := util.DeleteEmptyAndTrim(strings.FieldsFunc(names, util.SplitComma))
-	transform := transforms.Filter{
-		FilterValues: namesCleaned,
-		FilterOut:    filterOutBool,
+	transform, err := transforms.NewCoalesce(resourceNames, strings.TrimSpace(targetName))
+	if err != nil {
+		app.lc.Errorf("Unable to configure CoalesceReadings function: %s", err.Error())
+		return nil
 	}
 
-	return &transform, true
+	transform.SetAllowPartial(allowPartial)
+	return transform.CoalesceReadings
 }
 
-func (app *Configurable) processHttpExportParameters(
-	parameters map[string]string) (transforms.HTTPSenderOptions, string, error) {
+// Redact masks the values of the readings for the comma separated list of ResourceNames, such as those containing
+// PII, using the Strategy, which must be one of 'fullmask', 'partialmask' or 'hash'. Other readings are unchanged.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) Redact(parameters map[string]string) interfaces.AppFunction {
+	value, ok := parameters[ResourceNames]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for Redact", ResourceNames)
+		return nil
+	}
 
-	result := transforms.HTTPSenderOptions{}
+	resourceNames := util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma))
 
-	method, ok := parameters[ExportMethod]
+	strategy, ok := parameters[RedactStrategy]
 	if !ok {
-		return result, "", fmt.Errorf("HTTPExport Could not find %s", ExportMethod)
+		app.lc.Errorf("Could not find '%s' parameter for Redact", RedactStrategy)
+		return nil
 	}
 
-	result.URL, ok = parameters[Url]
+	transform, err := transforms.NewRedactor(resourceNames, strings.ToLower(strings.TrimSpace(strategy)))
+	if err != nil {
+		app.lc.Errorf("Unable to configure Redact function: %s", err.Error())
+		return nil
+	}
+
+	return transform.Redact
+}
+
+// EnforceMaxSize stops the pipeline with an error when the serialized data from the previous function, such as the
+// batched data from Batch, is larger than MaxBytes, i.e. to avoid the destination rejecting oversized batches.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) EnforceMaxSize(parameters map[string]string) interfaces.AppFunction {
+	value, ok := parameters[MaxBytes]
 	if !ok {
-		return result, "", fmt.Errorf("HTTPExport Could not find %s", Url)
+		app.lc.Errorf("Could not find '%s' parameter for EnforceMaxSize", MaxBytes)
+		return nil
 	}
-	result.MimeType, ok = parameters[MimeType]
+
+	maxBytes, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		app.lc.Errorf("Could not parse '%s' to an int for '%s' parameter for EnforceMaxSize: %s", value, MaxBytes, err.Error())
+		return nil
+	}
+
+	transform, err := transforms.NewSizeGuard(maxBytes)
+	if err != nil {
+		app.lc.Errorf("Unable to configure EnforceMaxSize function: %s", err.Error())
+		return nil
+	}
+
+	return transform.EnforceMaxSize
+}
+
+// AddSequenceNumber attaches a monotonically increasing sequence number to the data so downstream consumers can
+// detect gaps. The number is stored in the context using ContextKey and/or added to the Event's tags using TagName,
+// at least one of which must be specified.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) AddSequenceNumber(parameters map[string]string) interfaces.AppFunction {
+	contextKey := strings.TrimSpace(parameters[ContextKey])
+	tagName := strings.TrimSpace(parameters[TagName])
+	if len(contextKey) == 0 && len(tagName) == 0 {
+		app.lc.Errorf("Could not find '%s' or '%s' parameter for AddSequenceNumber", ContextKey, TagName)
+		return nil
+	}
+
+	transform := transforms.NewSequencer(contextKey)
+	transform.SetTagName(tagName)
+
+	return transform.Sequence
+}
+
+// FormatTimestamps formats the Event's Origin, and the Readings' origins if IncludeReadings is true, using Layout,
+// which is a Go time layout or 'unix', 'unixmilli' or 'unixmicro', in Timezone, which defaults to UTC. The formatted
+// origins are added as tags named TagName if specified, otherwise the Event is converted to JSON with the origins
+// replaced.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) FormatTimestamps(parameters map[string]string) interfaces.AppFunction {
+	layout, ok := parameters[Layout]
 	if !ok {
-		return result, "", fmt.Errorf("HTTPExport Could not find %s", MimeType)
+		app.lc.Errorf("Could not find '%s' parameter for FormatTimestamps", Layout)
+		return nil
 	}
 
-	// PersistOnError is optional and is false by default.
-	var value string
-	result.PersistOnError = false
-	value, ok = parameters[PersistOnError]
-	if ok {
+	includeReadings := false
+	if value, ok := parameters[IncludeReadings]; ok {
 		var err error
-		result.PersistOnError, err = strconv.ParseBool(value)
+		includeReadings, err = strconv.ParseBool(value)
 		if err != nil {
-			return result, "",
-				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
-					value,
-					PersistOnError,
-					err.Error())
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for FormatTimestamps: %s", value, IncludeReadings, err.Error())
+			return nil
 		}
 	}
 
-	// ContinueOnSendError is optional and is false by default.
-	result.ContinueOnSendError = false
-	value, ok = parameters[ContinueOnSendError]
-	if ok {
-		var err error
-		result.ContinueOnSendError, err = strconv.ParseBool(value)
-		if err != nil {
-			return result, "",
-				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+	transform, err := transforms.NewTimestampFormatter(layout, strings.TrimSpace(parameters[Timezone]))
+	if err != nil {
+		app.lc.Errorf("Unable to configure FormatTimestamps function: %s", err.Error())
+		return nil
+	}
+
+	transform.SetTagName(strings.TrimSpace(parameters[TagName]))
+	transform.SetIncludeReadings(includeReadings)
+
+	return transform.FormatTimestamps
+}
+
+// Aggregate computes the min, max, average and count of the readings for each device and resource over Window,
+// emitting the aggregates with the device's next Event once the window closes. ResourceNames optionally restricts
+// the aggregation to the listed resources, otherwise all numeric readings are aggregated. The aggregated readings
+// are suppressed unless PassRawReadings is true. MaxWindows optionally bounds the number of windows kept.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) Aggregate(parameters map[string]string) interfaces.AppFunction {
+	value, ok := parameters[Window]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for Aggregate", Window)
+		return nil
+	}
+
+	window, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		app.lc.Errorf("Could not parse '%s' to a Duration for '%s' parameter for Aggregate: %s", value, Window, err.Error())
+		return nil
+	}
+
+	passRaw := false
+	if value := parameters[PassRawReadings]; len(value) > 0 {
+		passRaw, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for Aggregate: %s", value, PassRawReadings, err.Error())
+			return nil
+		}
+	}
+
+	maxWindows := 0
+	if value := parameters[MaxWindows]; len(value) > 0 {
+		maxWindows, err = strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to an int for '%s' parameter for Aggregate: %s", value, MaxWindows, err.Error())
+			return nil
+		}
+	}
+
+	resourceNames := util.DeleteEmptyAndTrim(strings.FieldsFunc(parameters[ResourceNames], util.SplitComma))
+
+	transform, err := transforms.NewAggregator(window, resourceNames)
+	if err != nil {
+		app.lc.Errorf("Unable to configure Aggregate function: %s", err.Error())
+		return nil
+	}
+
+	transform.SetPassRawReadings(passRaw)
+	transform.SetMaxWindows(maxWindows)
+
+	return transform.Aggregate
+}
+
+// EncodeBase64 encodes the data from the previous function using base64. UrlSafe optionally specifies the URL and
+// filename safe alphabet is used rather than the standard alphabet.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) EncodeBase64(parameters map[string]string) interfaces.AppFunction {
+	transform := app.base64Transform("EncodeBase64", parameters)
+	if transform == nil {
+		return nil
+	}
+
+	return transform.EncodeBase64
+}
+
+// DecodeBase64 decodes the base64 data from the previous function. UrlSafe optionally specifies the URL and
+// filename safe alphabet is used rather than the standard alphabet.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) DecodeBase64(parameters map[string]string) interfaces.AppFunction {
+	transform := app.base64Transform("DecodeBase64", parameters)
+	if transform == nil {
+		return nil
+	}
+
+	return transform.DecodeBase64
+}
+
+func (app *Configurable) base64Transform(functionName string, parameters map[string]string) *transforms.Base64 {
+	urlSafe := false
+	if value := parameters[UrlSafe]; len(value) > 0 {
+		var err error
+		urlSafe, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for %s: %s", value, UrlSafe, functionName, err.Error())
+			return nil
+		}
+	}
+
+	return transforms.NewBase64(urlSafe)
+}
+
+// ValidateJSONSchema validates the JSON representation of the data against the JSON Schema specified in Schema.
+// Data which isn't valid stops the pipeline.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) ValidateJSONSchema(parameters map[string]string) interfaces.AppFunction {
+	schema, ok := parameters[JSONSchema]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for ValidateJSONSchema", JSONSchema)
+		return nil
+	}
+
+	transform, err := transforms.NewJSONSchemaValidator(schema)
+	if err != nil {
+		app.lc.Errorf("Unable to configure ValidateJSONSchema function: %s", err.Error())
+		return nil
+	}
+
+	return transform.Validate
+}
+
+// AddTags adds the configured list of tags to Events passed to the transform. Tag values may contain {placeholder}
+// tokens resolved from the context storage when resolvePlaceholders is set to true.
+// This function is a configuration function and returns a function pointer.
+func (app *Configurable) AddTags(parameters map[string]string) interfaces.AppFunction {
+	tags, failed := app.processTagsParameter(parameters)
+	if failed {
+		return nil
+	}
+
+	resolvePlaceholders := false
+	value, ok := parameters[ResolvePlaceholders]
+	if ok {
+		var err error
+		resolvePlaceholders, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for AddTags: %s", value, ResolvePlaceholders, err.Error())
+			return nil
+		}
+	}
+
+	if !resolvePlaceholders {
+		transform := transforms.NewTags(tags)
+		return transform.AddTags
+	}
+
+	strict := false
+	value, ok = parameters[StrictPlaceholders]
+	if ok {
+		var err error
+		strict, err = strconv.ParseBool(value)
+		if err != nil {
+			app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for AddTags: %s", value, StrictPlaceholders, err.Error())
+			return nil
+		}
+	}
+
+	transform := transforms.NewTagsWithFormatter(tags, nil, strict)
+	return transform.AddTags
+}
+
+func (app *Configurable) processTagsParameter(parameters map[string]string) (map[string]interface{}, bool) {
+	tagsSpec, ok := parameters[Tags]
+	if !ok {
+		app.lc.Error(fmt.Sprintf("Could not find '%s' parameter", Tags))
+		return nil, true
+	}
+
+	tagKeyValues := util.DeleteEmptyAndTrim(strings.FieldsFunc(tagsSpec, util.SplitComma))
+
+	tags := make(map[string]interface{})
+	for _, tag := range tagKeyValues {
+		keyValue := util.DeleteEmptyAndTrim(strings.FieldsFunc(tag, util.SplitColon))
+		if len(keyValue) != 2 {
+			app.lc.Errorf("Bad Tags specification format. Expect comma separated list of 'key:value'. Got `%s`", tagsSpec)
+			return nil, true
+		}
+
+		if len(keyValue[0]) == 0 {
+			app.lc.Errorf("Tag key missing. Got '%s'", tag)
+			return nil, true
+		}
+		if len(keyValue[1]) == 0 {
+			app.lc.Errorf("Tag value missing. Got '%s'", tag)
+			return nil, true
+		}
+
+		tags[keyValue[0]] = keyValue[1]
+	}
+	return tags, false
+}
+
+func (app *Configurable) processFilterParameters(
+	funcName string,
+	parameters map[string]string,
+	paramName string) (*transforms.Filter, bool) {
+	names, ok := parameters[paramName]
+	if !ok {
+		app.lc.Errorf("Could not find '%s' parameter for %s", paramName, funcName)
+		return nil, false
+	}
+
+	filterOutBool := false
+	filterOut, ok := parameters[FilterOut]
+	if ok {
+		var err error
+		filterOutBool, err = strconv.ParseBool(filterOut)
+		if err != nil {
+			app.lc.Errorf("Could not convert filterOut value `%s` to bool for %s", filterOut, funcName)
+			return nil, false
+		}
+	}
+
+	passthrough, ok := app.processPassthroughOnNoMatchParameter(funcName, parameters)
+	if !ok {
+		return nil, false
+	}
+
+	namesCleaned := util.DeleteEmptyAndTrim(strings.FieldsFunc(names, util.SplitComma))
+	transform := transforms.Filter{
+		FilterValues:         namesCleaned,
+		FilterOut:            filterOutBool,
+		PassthroughOnNoMatch: passthrough,
+	}
+
+	return &transform, true
+}
+
+// processPassthroughOnNoMatchParameter returns the optional PassthroughOnNoMatch parameter, which is false by default
+func (app *Configurable) processPassthroughOnNoMatchParameter(funcName string, parameters map[string]string) (bool, bool) {
+	value, ok := parameters[PassthroughOnNoMatch]
+	if !ok {
+		return false, true
+	}
+
+	passthrough, err := strconv.ParseBool(value)
+	if err != nil {
+		app.lc.Errorf("Could not parse '%s' to a bool for '%s' parameter for %s: %s", value, PassthroughOnNoMatch, funcName, err.Error())
+		return false, false
+	}
+
+	return passthrough, true
+}
+
+func (app *Configurable) processHttpExportParameters(
+	parameters map[string]string) (transforms.HTTPSenderOptions, string, error) {
+
+	result := transforms.HTTPSenderOptions{}
+
+	method, ok := parameters[ExportMethod]
+	if !ok {
+		return result, "", fmt.Errorf("HTTPExport Could not find %s", ExportMethod)
+	}
+
+	result.URL, ok = parameters[Url]
+	if !ok {
+		return result, "", fmt.Errorf("HTTPExport Could not find %s", Url)
+	}
+	result.MimeType, ok = parameters[MimeType]
+	if !ok {
+		return result, "", fmt.Errorf("HTTPExport Could not find %s", MimeType)
+	}
+
+	// PersistOnError is optional and is false by default.
+	var value string
+	result.PersistOnError = false
+	value, ok = parameters[PersistOnError]
+	if ok {
+		var err error
+		result.PersistOnError, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					PersistOnError,
+					err.Error())
+		}
+	}
+
+	// ContinueOnSendError is optional and is false by default.
+	result.ContinueOnSendError = false
+	value, ok = parameters[ContinueOnSendError]
+	if ok {
+		var err error
+		result.ContinueOnSendError, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
 					value,
 					ContinueOnSendError,
 					err.Error())
@@ -860,6 +1994,394 @@ func (app *Configurable) processHttpExportParameters(
 		}
 	}
 
+	// HttpRequestTimeout is optional and no timeout is used by default.
+	value = parameters[HttpRequestTimeout]
+	if len(value) > 0 {
+		var err error
+		result.Timeout, err = time.ParseDuration(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a Duration for '%s' parameter: %s",
+					value,
+					HttpRequestTimeout,
+					err.Error())
+		}
+	}
+
+	// MaxIdleConns is optional and the http.DefaultTransport setting is used by default.
+	value = parameters[MaxIdleConns]
+	if len(value) > 0 {
+		var err error
+		result.MaxIdleConns, err = strconv.Atoi(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to an int for '%s' parameter: %s",
+					value,
+					MaxIdleConns,
+					err.Error())
+		}
+	}
+
+	// IdleConnTimeout is optional and the http.DefaultTransport setting is used by default.
+	value = parameters[IdleConnTimeout]
+	if len(value) > 0 {
+		var err error
+		result.IdleConnTimeout, err = time.ParseDuration(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a Duration for '%s' parameter: %s",
+					value,
+					IdleConnTimeout,
+					err.Error())
+		}
+	}
+
+	// DisableKeepAlives is optional and is false by default.
+	value, ok = parameters[DisableKeepAlives]
+	if ok {
+		var err error
+		result.DisableKeepAlives, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					DisableKeepAlives,
+					err.Error())
+		}
+	}
+
+	// ForceHTTP2 is optional and is false by default.
+	value, ok = parameters[ForceHTTP2]
+	if ok {
+		var err error
+		result.ForceHTTP2, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					ForceHTTP2,
+					err.Error())
+		}
+	}
+
+	// HTTP2Fallback is optional and is false by default.
+	value, ok = parameters[HTTP2Fallback]
+	if ok {
+		var err error
+		result.HTTP2Fallback, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					HTTP2Fallback,
+					err.Error())
+		}
+	}
+
+	// CloudEventHeaders is optional and is false by default.
+	value, ok = parameters[CloudEventHeaders]
+	if ok {
+		var err error
+		result.CloudEventHeaders, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					CloudEventHeaders,
+					err.Error())
+		}
+	}
+
+	// SecretEnvMode is optional and secrets are only retrieved from the SecretStore by default.
+	value = strings.ToLower(strings.TrimSpace(parameters[SecretEnvMode]))
+	if len(value) > 0 {
+		if value != transforms.SecretEnvModeFallback && value != transforms.SecretEnvModeOnly {
+			return result, "",
+				fmt.Errorf("HTTPExport Invalid value '%s' for '%s' parameter, must be '%s' or '%s'",
+					value,
+					SecretEnvMode,
+					transforms.SecretEnvModeFallback,
+					transforms.SecretEnvModeOnly)
+		}
+
+		result.SecretEnvMode = value
+	}
+
+	// LogRequestResponse is optional and is false by default.
+	value, ok = parameters[LogRequestResponse]
+	if ok {
+		var err error
+		result.LogRequestResponse, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					LogRequestResponse,
+					err.Error())
+		}
+	}
+
+	// SetAcceptHeader is optional and is false by default.
+	value, ok = parameters[SetAcceptHeader]
+	if ok {
+		var err error
+		result.SetAcceptHeader, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					SetAcceptHeader,
+					err.Error())
+		}
+	}
+
+	// ResponseMimeType is optional and the MimeType is used for the Accept header by default.
+	result.ResponseMimeType = strings.TrimSpace(parameters[ResponseMimeType])
+
+	// StoreResponseHeaders is optional and no response headers are stored by default.
+	value = parameters[StoreResponseHeaders]
+	if len(value) > 0 {
+		result.StoreResponseHeaders = util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma))
+	}
+
+	// FailoverUrls is optional and no failover is done by default.
+	value = parameters[FailoverUrls]
+	if len(value) > 0 {
+		result.FailoverURLs = util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma))
+	}
+
+	// ResponseContextKey is optional and the response is passed to the next function by default.
+	result.ResponseContextKey = strings.TrimSpace(parameters[ResponseContextKey])
+
+	// SuccessStatusCodes is optional and any 2xx status code is considered success by default.
+	value = parameters[SuccessStatusCodes]
+	if len(value) > 0 {
+		for _, code := range util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma)) {
+			statusCode, err := strconv.Atoi(code)
+			if err != nil {
+				return result, "",
+					fmt.Errorf("HTTPExport Could not parse '%s' to an int for '%s' parameter: %s",
+						code,
+						SuccessStatusCodes,
+						err.Error())
+			}
+
+			result.SuccessStatusCodes = append(result.SuccessStatusCodes, statusCode)
+		}
+	}
+
+	// TolerateStatusCodes is optional and no failure status codes are tolerated by default.
+	value = parameters[TolerateStatusCodes]
+	if len(value) > 0 {
+		for _, code := range util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma)) {
+			statusCode, err := strconv.Atoi(code)
+			if err != nil {
+				return result, "",
+					fmt.Errorf("HTTPExport Could not parse '%s' to an int for '%s' parameter: %s",
+						code,
+						TolerateStatusCodes,
+						err.Error())
+			}
+
+			result.TolerateStatusCodes = append(result.TolerateStatusCodes, statusCode)
+		}
+	}
+
+	// FollowRedirects is optional and is true by default.
+	value, ok = parameters[FollowRedirects]
+	if ok {
+		followRedirects, err := strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					FollowRedirects,
+					err.Error())
+		}
+
+		result.FollowRedirects = &followRedirects
+	}
+
+	// SendCorrelationID is optional and is true by default.
+	value, ok = parameters[SendCorrelationID]
+	if ok {
+		sendCorrelationID, err := strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					SendCorrelationID,
+					err.Error())
+		}
+
+		result.SendCorrelationID = &sendCorrelationID
+	}
+
+	// CorrelationIDHeader is optional and the standard X-Correlation-ID header is used by default.
+	result.CorrelationIDHeader = strings.TrimSpace(parameters[CorrelationIDHeader])
+
+	// MaxResponseBytes is optional and the response size isn't limited by default.
+	value = parameters[MaxResponseBytes]
+	if len(value) > 0 {
+		var err error
+		result.MaxResponseBytes, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to an int for '%s' parameter: %s",
+					value,
+					MaxResponseBytes,
+					err.Error())
+		}
+	}
+
+	// IdempotencyKeyHeader and IdempotencyKeyCtxKey are optional and no idempotency key is computed by default.
+	result.IdempotencyKeyHeader = strings.TrimSpace(parameters[IdempotencyKeyHeader])
+	result.IdempotencyKeyContextKey = strings.TrimSpace(parameters[IdempotencyKeyCtxKey])
+
+	// BodyTemplate is optional and the data is sent as is by default.
+	result.BodyTemplate = parameters[BodyTemplate]
+
+	// PayloadHeader is optional and the data is sent in the body by default.
+	result.PayloadHeader = strings.TrimSpace(parameters[PayloadHeader])
+
+	// MaxPayloadHeaderBytes is optional and the DefaultMaxPayloadHeaderBytes is used by default.
+	value = parameters[MaxPayloadHeaderBytes]
+	if len(value) > 0 {
+		var err error
+		result.MaxPayloadHeaderBytes, err = strconv.Atoi(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to an int for '%s' parameter: %s",
+					value,
+					MaxPayloadHeaderBytes,
+					err.Error())
+		}
+	}
+
+	// HostMappings is optional, i.e. 'export.local=10.0.0.5, backup.local=10.0.0.6:8443', and DNS is used by default.
+	value = parameters[HostMappings]
+	if len(value) > 0 {
+		result.HostMappings = make(map[string]string)
+		for _, mapping := range util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma)) {
+			host, address, found := strings.Cut(mapping, "=")
+			host = strings.TrimSpace(host)
+			address = strings.TrimSpace(address)
+			if !found || len(host) == 0 || len(address) == 0 {
+				return result, "",
+					fmt.Errorf("HTTPExport Could not parse '%s' to a host=address mapping for '%s' parameter", mapping, HostMappings)
+			}
+
+			result.HostMappings[host] = address
+		}
+	}
+
+	// LocalAddress is optional, i.e. '10.0.0.20', and the OS chooses the local address by default.
+	result.LocalAddress = strings.TrimSpace(parameters[LocalAddress])
+	if len(result.LocalAddress) > 0 && net.ParseIP(result.LocalAddress) == nil {
+		return result, "",
+			fmt.Errorf("HTTPExport Could not parse '%s' to an IP address for '%s' parameter", result.LocalAddress, LocalAddress)
+	}
+
+	// SkipWhenContextKey is optional and the data is always sent by default.
+	result.SkipWhenContextKey = strings.TrimSpace(parameters[SkipWhenContextKey])
+
+	// UserAgent is optional and identifies the SDK and its version by default.
+	result.UserAgent = strings.TrimSpace(parameters[UserAgent])
+
+	// JSONSerialization is optional and data is serialized as compact JSON by default.
+	value = strings.ToLower(strings.TrimSpace(parameters[JSONSerialization]))
+	if len(value) > 0 {
+		if value != transforms.JSONSerializationCompact && value != transforms.JSONSerializationIndented {
+			return result, "",
+				fmt.Errorf("HTTPExport Invalid value '%s' for '%s' parameter, must be '%s' or '%s'",
+					value,
+					JSONSerialization,
+					transforms.JSONSerializationCompact,
+					transforms.JSONSerializationIndented)
+		}
+
+		result.JSONSerialization = value
+	}
+
+	// JSONIndent is optional and two spaces are used by default. It isn't trimmed since it is whitespace.
+	result.JSONIndent = parameters[JSONIndent]
+
+	// CircuitBreakerThreshold is optional and the circuit breaker isn't used by default.
+	value = parameters[CircuitBreakerThreshold]
+	if len(value) > 0 {
+		var err error
+		result.CircuitBreakerThreshold, err = strconv.Atoi(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to an int for '%s' parameter: %s",
+					value,
+					CircuitBreakerThreshold,
+					err.Error())
+		}
+	}
+
+	// CircuitBreakerCooldown is optional and DefaultCircuitBreakerCooldown is used by default.
+	value = parameters[CircuitBreakerCooldown]
+	if len(value) > 0 {
+		var err error
+		result.CircuitBreakerCooldown, err = time.ParseDuration(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a Duration for '%s' parameter: %s",
+					value,
+					CircuitBreakerCooldown,
+					err.Error())
+		}
+	}
+
+	// MultipartFieldName, MultipartFileName and MultipartFields are optional and the data is sent as the body by default.
+	result.MultipartFieldName = strings.TrimSpace(parameters[MultipartFieldName])
+	result.MultipartFileName = strings.TrimSpace(parameters[MultipartFileName])
+
+	// MultipartFields is of the form 'source=edgex, device={devicename}'
+	value = parameters[MultipartFields]
+	if len(value) > 0 {
+		result.MultipartFields = make(map[string]string)
+		for _, field := range util.DeleteEmptyAndTrim(strings.FieldsFunc(value, util.SplitComma)) {
+			name, fieldValue, found := strings.Cut(field, "=")
+			name = strings.TrimSpace(name)
+			if !found || len(name) == 0 {
+				return result, "",
+					fmt.Errorf("HTTPExport Could not parse '%s' to a name=value form field for '%s' parameter", field, MultipartFields)
+			}
+
+			result.MultipartFields[name] = strings.TrimSpace(fieldValue)
+		}
+	}
+
+	// TracePropagation is optional and is false by default.
+	value, ok = parameters[TracePropagation]
+	if ok {
+		var err error
+		result.TracePropagation, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					TracePropagation,
+					err.Error())
+		}
+	}
+
+	// TraceClientSpan is optional and is false by default.
+	value, ok = parameters[TraceClientSpan]
+	if ok {
+		var err error
+		result.TraceClientSpan, err = strconv.ParseBool(value)
+		if err != nil {
+			return result, "",
+				fmt.Errorf("HTTPExport Could not parse '%s' to a bool for '%s' parameter: %s",
+					value,
+					TraceClientSpan,
+					err.Error())
+		}
+	}
+
 	result.URL = strings.TrimSpace(result.URL)
 	result.MimeType = strings.TrimSpace(result.MimeType)
 	result.HTTPHeaderName = strings.TrimSpace(parameters[HeaderName])
@@ -16,14 +16,22 @@
 package app
 
 import (
+	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/transforms"
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFilterByProfileName(t *testing.T) {
@@ -39,6 +47,8 @@ func TestFilterByProfileName(t *testing.T) {
 		{"Valid Parameters", map[string]string{ProfileNames: "GS1-AC-Drive, GS0-DC-Drive, GSX-ACDC-Drive"}, false},
 		{"Empty FilterOut Parameters", map[string]string{ProfileNames: "GS1-AC-Drive, GS0-DC-Drive, GSX-ACDC-Drive", FilterOut: ""}, true},
 		{"Valid FilterOut Parameters", map[string]string{ProfileNames: "GS1-AC-Drive, GS0-DC-Drive, GSX-ACDC-Drive", FilterOut: "true"}, false},
+		{"Valid PassthroughOnNoMatch Parameters", map[string]string{ProfileNames: "GS1-AC-Drive", PassthroughOnNoMatch: "true"}, false},
+		{"Bad PassthroughOnNoMatch Parameters", map[string]string{ProfileNames: "GS1-AC-Drive", PassthroughOnNoMatch: "bogus"}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -52,7 +62,7 @@ func TestFilterByProfileName(t *testing.T) {
 	}
 }
 
-func TestFilterByDeviceName(t *testing.T) {
+func TestFilterByTags(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
 	tests := []struct {
@@ -61,24 +71,27 @@ func TestFilterByDeviceName(t *testing.T) {
 		expectNil bool
 	}{
 		{"Non Existent Parameters", map[string]string{"": ""}, true},
-		{"Empty Parameters", map[string]string{DeviceNames: ""}, false},
-		{"Valid Parameters", map[string]string{DeviceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03"}, false},
-		{"Empty FilterOut Parameters", map[string]string{DeviceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03", FilterOut: ""}, true},
-		{"Valid FilterOut Parameters", map[string]string{DeviceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03", FilterOut: "true"}, false},
+		{"Empty Parameters", map[string]string{Tags: ""}, false},
+		{"Valid Parameters", map[string]string{Tags: "site:plant-1, line:a"}, false},
+		{"Bad Tags Parameters", map[string]string{Tags: "site"}, true},
+		{"Empty FilterOut Parameters", map[string]string{Tags: "site:plant-1", FilterOut: ""}, true},
+		{"Valid FilterOut Parameters", map[string]string{Tags: "site:plant-1", FilterOut: "true"}, false},
+		{"Valid PassthroughOnNoMatch Parameters", map[string]string{Tags: "site:plant-1", PassthroughOnNoMatch: "true"}, false},
+		{"Bad PassthroughOnNoMatch Parameters", map[string]string{Tags: "site:plant-1", PassthroughOnNoMatch: "bogus"}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			trx := configurable.FilterByDeviceName(tt.params)
+			trx := configurable.FilterByTags(tt.params)
 			if tt.expectNil {
-				assert.Nil(t, trx, "return result from FilterByDeviceName should be nil")
+				assert.Nil(t, trx, "return result from FilterByTags should be nil")
 			} else {
-				assert.NotNil(t, trx, "return result from FilterByDeviceName should not be nil")
+				assert.NotNil(t, trx, "return result from FilterByTags should not be nil")
 			}
 		})
 	}
 }
 
-func TestFilterBySourceName(t *testing.T) {
+func TestSample(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
 	tests := []struct {
@@ -86,25 +99,25 @@ func TestFilterBySourceName(t *testing.T) {
 		params    map[string]string
 		expectNil bool
 	}{
-		{"Non Existent Parameters", map[string]string{"": ""}, true},
-		{"Empty Parameters", map[string]string{SourceNames: ""}, false},
-		{"Valid Parameters", map[string]string{SourceNames: "R1, C2, R4"}, false},
-		{"Empty FilterOut Parameters", map[string]string{SourceNames: "R1, C2, R4", FilterOut: ""}, true},
-		{"Valid FilterOut Parameters", map[string]string{SourceNames: "R1, C2, R4", FilterOut: "true"}, false},
+		{"Valid Count", map[string]string{SampleCount: "10"}, false},
+		{"Valid Interval", map[string]string{SampleInterval: "5s"}, false},
+		{"Neither Specified", map[string]string{}, true},
+		{"Both Specified", map[string]string{SampleCount: "10", SampleInterval: "5s"}, true},
+		{"Bad Count", map[string]string{SampleCount: "bogus"}, true},
+		{"Bad Interval", map[string]string{SampleInterval: "bogus"}, true},
+		{"Valid Interval and StateExpiry", map[string]string{SampleInterval: "5s", StateExpiry: "10m"}, false},
+		{"StateExpiry less than Interval", map[string]string{SampleInterval: "5s", StateExpiry: "1s"}, true},
+		{"Bad StateExpiry", map[string]string{SampleCount: "10", StateExpiry: "bogus"}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			trx := configurable.FilterBySourceName(tt.params)
-			if tt.expectNil {
-				assert.Nil(t, trx, "return result from FilterBySourceName should be nil")
-			} else {
-				assert.NotNil(t, trx, "return result from FilterBySourceName should not be nil")
-			}
+			trx := configurable.Sample(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
 		})
 	}
 }
 
-func TestFilterByResourceName(t *testing.T) {
+func TestFilterStale(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
 	tests := []struct {
@@ -112,162 +125,1616 @@ func TestFilterByResourceName(t *testing.T) {
 		params    map[string]string
 		expectNil bool
 	}{
-		{"Non Existent Parameters", map[string]string{"": ""}, true},
-		{"Empty Parameters", map[string]string{ResourceNames: ""}, false},
-		{"Valid Parameters", map[string]string{ResourceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03"}, false},
-		{"Empty FilterOut Parameters", map[string]string{ResourceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03", FilterOut: ""}, true},
-		{"Valid FilterOut Parameters", map[string]string{ResourceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03", FilterOut: "true"}, false},
+		{"Valid MaxAge", map[string]string{MaxAge: "1h"}, false},
+		{"Valid MaxAge and ClockSkewTolerance", map[string]string{MaxAge: "1h", ClockSkewTolerance: "5s"}, false},
+		{"Missing MaxAge", map[string]string{}, true},
+		{"Bad MaxAge", map[string]string{MaxAge: "bogus"}, true},
+		{"Zero MaxAge", map[string]string{MaxAge: "0s"}, true},
+		{"Bad ClockSkewTolerance", map[string]string{MaxAge: "1h", ClockSkewTolerance: "bogus"}, true},
+		{"Negative ClockSkewTolerance", map[string]string{MaxAge: "1h", ClockSkewTolerance: "-5s"}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			trx := configurable.FilterByResourceName(tt.params)
-			if tt.expectNil {
-				assert.Nil(t, trx, "return result from FilterByResourceName should be nil")
-			} else {
-				assert.NotNil(t, trx, "return result from FilterByResourceName should not be nil")
+			trx := configurable.FilterStale(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestFilterByThreshold(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid inside", map[string]string{ResourceName: "temperature", LowThreshold: "0", HighThreshold: "40", Mode: "inside"}, false},
+		{"Valid outside with PassNonNumeric", map[string]string{ResourceName: "temperature", LowThreshold: "-10.5", HighThreshold: "40", Mode: "Outside", PassNonNumeric: "true"}, false},
+		{"Missing ResourceName", map[string]string{LowThreshold: "0", HighThreshold: "40", Mode: "inside"}, true},
+		{"Missing Low", map[string]string{ResourceName: "temperature", HighThreshold: "40", Mode: "inside"}, true},
+		{"Missing High", map[string]string{ResourceName: "temperature", LowThreshold: "0", Mode: "inside"}, true},
+		{"Missing Mode", map[string]string{ResourceName: "temperature", LowThreshold: "0", HighThreshold: "40"}, true},
+		{"Bad Low", map[string]string{ResourceName: "temperature", LowThreshold: "bogus", HighThreshold: "40", Mode: "inside"}, true},
+		{"Low greater than High", map[string]string{ResourceName: "temperature", LowThreshold: "50", HighThreshold: "40", Mode: "inside"}, true},
+		{"Bad Mode", map[string]string{ResourceName: "temperature", LowThreshold: "0", HighThreshold: "40", Mode: "between"}, true},
+		{"Bad PassNonNumeric", map[string]string{ResourceName: "temperature", LowThreshold: "0", HighThreshold: "40", Mode: "inside", PassNonNumeric: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.FilterByThreshold(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestDedup(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid Change Only", map[string]string{}, false},
+		{"Valid Heartbeat", map[string]string{Heartbeat: "1m"}, false},
+		{"Valid Heartbeat and StateExpiry", map[string]string{Heartbeat: "1m", StateExpiry: "10m"}, false},
+		{"StateExpiry less than Heartbeat", map[string]string{Heartbeat: "1m", StateExpiry: "10s"}, true},
+		{"Bad Heartbeat", map[string]string{Heartbeat: "bogus"}, true},
+		{"Bad StateExpiry", map[string]string{StateExpiry: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.Dedup(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestAddGeoLocation(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{LatitudeKey: "Latitude", LongitudeKey: "Longitude"}, false},
+		{"Valid with all options", map[string]string{LatitudeKey: "Latitude", LongitudeKey: "Longitude", Protocol: "gps", CacheTTL: "10m", ContextKey: "location"}, false},
+		{"Missing LatitudeKey", map[string]string{LongitudeKey: "Longitude"}, true},
+		{"Missing LongitudeKey", map[string]string{LatitudeKey: "Latitude"}, true},
+		{"Empty LatitudeKey", map[string]string{LatitudeKey: " ", LongitudeKey: "Longitude"}, true},
+		{"Bad CacheTTL", map[string]string{LatitudeKey: "Latitude", LongitudeKey: "Longitude", CacheTTL: "bogus"}, true},
+		{"Negative CacheTTL", map[string]string{LatitudeKey: "Latitude", LongitudeKey: "Longitude", CacheTTL: "-1m"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.AddGeoLocation(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestAddResourceMetadata(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{}, false},
+		{"Valid with CacheTTL", map[string]string{CacheTTL: "10m"}, false},
+		{"Bad CacheTTL", map[string]string{CacheTTL: "bogus"}, true},
+		{"Negative CacheTTL", map[string]string{CacheTTL: "-1m"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.AddResourceMetadata(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestConvertToCloudEvent(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid Source", map[string]string{CloudEventSource: "/edgex/app"}, false},
+		{"Valid Source, EventType and BinaryMode", map[string]string{CloudEventSource: "/edgex/app", CloudEventType: "com.example.reading", BinaryMode: "true"}, false},
+		{"Missing Source", map[string]string{CloudEventType: "com.example.reading"}, true},
+		{"Empty Source", map[string]string{CloudEventSource: ""}, true},
+		{"Bad BinaryMode", map[string]string{CloudEventSource: "/edgex/app", BinaryMode: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.ConvertToCloudEvent(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestFlattenJSON(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid defaults", map[string]string{}, false},
+		{"Valid all specified", map[string]string{Separator: "_", IndexArrays: "true", PassThroughNonObject: "true"}, false},
+		{"Bad IndexArrays", map[string]string{IndexArrays: "bogus"}, true},
+		{"Bad PassThroughNonObject", map[string]string{PassThroughNonObject: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.FlattenJSON(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestExplodeObjectReading(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{ResourceName: "status"}, false},
+		{"Missing ResourceName", map[string]string{}, true},
+		{"Empty ResourceName", map[string]string{ResourceName: " "}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.ExplodeObjectReading(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestNormalizeReadingTypes(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	trx := configurable.NormalizeReadingTypes(nil)
+	assert.NotNil(t, trx)
+}
+
+func TestEmitMetrics(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid single", map[string]string{MetricNames: "temperature:room_temperature"}, false},
+		{"Valid multiple", map[string]string{MetricNames: "temperature:room_temperature, humidity:room_humidity"}, false},
+		{"Missing MetricNames", map[string]string{}, true},
+		{"Empty MetricNames", map[string]string{MetricNames: ""}, true},
+		{"Bad format", map[string]string{MetricNames: "temperature"}, true},
+		{"Missing metric name", map[string]string{MetricNames: "temperature:"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.EmitMetrics(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid fullmask", map[string]string{ResourceNames: "badge", RedactStrategy: "fullmask"}, false},
+		{"Valid partialmask", map[string]string{ResourceNames: "badge, card", RedactStrategy: "PartialMask"}, false},
+		{"Valid hash", map[string]string{ResourceNames: "badge", RedactStrategy: "hash"}, false},
+		{"Missing ResourceNames", map[string]string{RedactStrategy: "hash"}, true},
+		{"Empty ResourceNames", map[string]string{ResourceNames: "", RedactStrategy: "hash"}, true},
+		{"Missing Strategy", map[string]string{ResourceNames: "badge"}, true},
+		{"Bad Strategy", map[string]string{ResourceNames: "badge", RedactStrategy: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.Redact(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestEnforceMaxSize(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{MaxBytes: "1048576"}, false},
+		{"Missing MaxBytes", map[string]string{}, true},
+		{"Bad MaxBytes", map[string]string{MaxBytes: "bogus"}, true},
+		{"Zero MaxBytes", map[string]string{MaxBytes: "0"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.EnforceMaxSize(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestAddSequenceNumber(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid ContextKey", map[string]string{ContextKey: "sequence"}, false},
+		{"Valid TagName", map[string]string{TagName: "sequence"}, false},
+		{"Valid both", map[string]string{ContextKey: "sequence", TagName: "seq"}, false},
+		{"Missing both", map[string]string{}, true},
+		{"Empty both", map[string]string{ContextKey: " ", TagName: ""}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.AddSequenceNumber(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestFormatTimestamps(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{Layout: "2006-01-02T15:04:05Z07:00"}, false},
+		{"Valid all", map[string]string{Layout: "unixmilli", Timezone: "America/New_York", TagName: "origin", IncludeReadings: "true"}, false},
+		{"Missing Layout", map[string]string{}, true},
+		{"Empty Layout", map[string]string{Layout: ""}, true},
+		{"Bad Timezone", map[string]string{Layout: "unix", Timezone: "bogus"}, true},
+		{"Bad IncludeReadings", map[string]string{Layout: "unix", IncludeReadings: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.FormatTimestamps(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{Window: "1m"}, false},
+		{"Valid all", map[string]string{Window: "30s", ResourceNames: "temperature, humidity", PassRawReadings: "true", MaxWindows: "100"}, false},
+		{"Missing Window", map[string]string{}, true},
+		{"Bad Window", map[string]string{Window: "bogus"}, true},
+		{"Zero Window", map[string]string{Window: "0s"}, true},
+		{"Bad PassRawReadings", map[string]string{Window: "1m", PassRawReadings: "bogus"}, true},
+		{"Bad MaxWindows", map[string]string{Window: "1m", MaxWindows: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.Aggregate(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestCoalesceReadings(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{ResourceNames: "x, y,z", TargetName: "acceleration"}, false},
+		{"Valid AllowPartial", map[string]string{ResourceNames: "x,y,z", TargetName: "acceleration", AllowPartial: "true"}, false},
+		{"Missing ResourceNames", map[string]string{TargetName: "acceleration"}, true},
+		{"Empty ResourceNames", map[string]string{ResourceNames: " , ", TargetName: "acceleration"}, true},
+		{"Missing TargetName", map[string]string{ResourceNames: "x,y,z"}, true},
+		{"Empty TargetName", map[string]string{ResourceNames: "x,y,z", TargetName: " "}, true},
+		{"Bad AllowPartial", map[string]string{ResourceNames: "x,y,z", TargetName: "acceleration", AllowPartial: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.CoalesceReadings(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid Rate Only", map[string]string{EventsPerSecond: "10"}, false},
+		{"Valid All", map[string]string{EventsPerSecond: "0.5", Burst: "5", DropWhenLimited: "true"}, false},
+		{"Missing Rate", map[string]string{Burst: "5"}, true},
+		{"Bad Rate", map[string]string{EventsPerSecond: "bogus"}, true},
+		{"Zero Rate", map[string]string{EventsPerSecond: "0"}, true},
+		{"Bad Burst", map[string]string{EventsPerSecond: "10", Burst: "bogus"}, true},
+		{"Zero Burst", map[string]string{EventsPerSecond: "10", Burst: "0"}, true},
+		{"Bad DropWhenLimited", map[string]string{EventsPerSecond: "10", DropWhenLimited: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.RateLimit(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestBase64(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid Default", map[string]string{}, false},
+		{"Valid UrlSafe", map[string]string{UrlSafe: "true"}, false},
+		{"Bad UrlSafe", map[string]string{UrlSafe: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.EncodeBase64(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+			trx = configurable.DecodeBase64(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{JSONSchema: `{"type":"object","required":["deviceName"]}`}, false},
+		{"Missing Schema", map[string]string{}, true},
+		{"Bad Schema JSON", map[string]string{JSONSchema: `{"type":`}, true},
+		{"Invalid Schema", map[string]string{JSONSchema: `{"type":"bogus"}`}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.ValidateJSONSchema(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestConvertUnits(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid Single", map[string]string{UnitConversions: "Temperature:FahrenheitToCelsius"}, false},
+		{"Valid Multiple", map[string]string{UnitConversions: "Temperature:FahrenheitToCelsius, Pressure:PsiToKilopascal", DecimalPlaces: "2"}, false},
+		{"Missing Conversions", map[string]string{DecimalPlaces: "2"}, true},
+		{"Empty Conversions", map[string]string{UnitConversions: ""}, true},
+		{"Bad Conversions Format", map[string]string{UnitConversions: "Temperature"}, true},
+		{"Unknown Conversion", map[string]string{UnitConversions: "Temperature:FurlongsToFathoms"}, true},
+		{"Bad DecimalPlaces", map[string]string{UnitConversions: "Temperature:FahrenheitToCelsius", DecimalPlaces: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.ConvertUnits(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
+func TestFilterByDeviceName(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Non Existent Parameters", map[string]string{"": ""}, true},
+		{"Empty Parameters", map[string]string{DeviceNames: ""}, false},
+		{"Valid Parameters", map[string]string{DeviceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03"}, false},
+		{"Empty FilterOut Parameters", map[string]string{DeviceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03", FilterOut: ""}, true},
+		{"Valid FilterOut Parameters", map[string]string{DeviceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03", FilterOut: "true"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.FilterByDeviceName(tt.params)
+			if tt.expectNil {
+				assert.Nil(t, trx, "return result from FilterByDeviceName should be nil")
+			} else {
+				assert.NotNil(t, trx, "return result from FilterByDeviceName should not be nil")
+			}
+		})
+	}
+}
+
+func TestFilterBySourceName(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Non Existent Parameters", map[string]string{"": ""}, true},
+		{"Empty Parameters", map[string]string{SourceNames: ""}, false},
+		{"Valid Parameters", map[string]string{SourceNames: "R1, C2, R4"}, false},
+		{"Empty FilterOut Parameters", map[string]string{SourceNames: "R1, C2, R4", FilterOut: ""}, true},
+		{"Valid FilterOut Parameters", map[string]string{SourceNames: "R1, C2, R4", FilterOut: "true"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.FilterBySourceName(tt.params)
+			if tt.expectNil {
+				assert.Nil(t, trx, "return result from FilterBySourceName should be nil")
+			} else {
+				assert.NotNil(t, trx, "return result from FilterBySourceName should not be nil")
+			}
+		})
+	}
+}
+
+func TestFilterByResourceName(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Non Existent Parameters", map[string]string{"": ""}, true},
+		{"Empty Parameters", map[string]string{ResourceNames: ""}, false},
+		{"Valid Parameters", map[string]string{ResourceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03"}, false},
+		{"Empty FilterOut Parameters", map[string]string{ResourceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03", FilterOut: ""}, true},
+		{"Valid FilterOut Parameters", map[string]string{ResourceNames: "GS1-AC-Drive01, GS1-AC-Drive02, GS1-AC-Drive03", FilterOut: "true"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.FilterByResourceName(tt.params)
+			if tt.expectNil {
+				assert.Nil(t, trx, "return result from FilterByResourceName should be nil")
+			} else {
+				assert.NotNil(t, trx, "return result from FilterByResourceName should not be nil")
+			}
+		})
+	}
+}
+
+func TestTransform(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name          string
+		TransformType string
+		ExpectValid   bool
+	}{
+		{"Good - XML", "xMl", true},
+		{"Good - JSON", "JsOn", true},
+		{"Good - XML to JSON", "XmlToJson", true},
+		{"Good - CSV", "CsV", true},
+		{"Good - NDJSON", "NdJson", true},
+		{"Good - CBOR to JSON", "CborToJson", true},
+		{"Good - JSON to CBOR", "JsonToCbor", true},
+		{"Bad Type", "baDType", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := make(map[string]string)
+			params[TransformType] = test.TransformType
+			transform := configurable.Transform(params)
+			assert.Equal(t, test.ExpectValid, transform != nil)
+		})
+	}
+}
+
+func TestTransformCSV(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name        string
+		Columns     string
+		Header      string
+		ExpectValid bool
+	}{
+		{"Good - defaults", "", "", true},
+		{"Good - columns and no header", "deviceName, value,units", "false", true},
+		{"Bad - unknown column", "deviceName,bogus", "", false},
+		{"Bad - header", "", "bogus", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := make(map[string]string)
+			params[TransformType] = TransformCsv
+			if len(test.Columns) > 0 {
+				params[CsvColumns] = test.Columns
+			}
+			if len(test.Header) > 0 {
+				params[CsvHeader] = test.Header
+			}
+			transform := configurable.Transform(params)
+			assert.Equal(t, test.ExpectValid, transform != nil)
+		})
+	}
+}
+
+func TestHTTPExport(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	testUrl := "http://url"
+	testMimeType := common.ContentTypeJSON
+	testPersistOnError := "false"
+	testBadPersistOnError := "bogus"
+	testContinueOnSendError := "true"
+	testBadContinueOnSendError := "bogus"
+	testReturnInputData := "true"
+	testBadReturnInputData := "bogus"
+
+	testHeaderName := "My-Header"
+	testSecretName := "my-secret"
+	testSecretValueKey := "header"
+
+	testHTTPRequestHeaders := `{
+		"Connection": "keep-alive",
+		"From": "[user@example.com](mailto:user@example.com)"
+	  }`
+
+	testBadHTTPRequestHeaders := `{
+		"Connection": "keep-alive", 
+		"From": 
+	  `
+
+	tests := []struct {
+		Name                string
+		Method              string
+		Url                 *string
+		MimeType            *string
+		PersistOnError      *string
+		ContinueOnSendError *string
+		ReturnInputData     *string
+		HeaderName          *string
+		SecretName          *string
+		SecretValueKey      *string
+		HTTPRequestHeaders  *string
+		ExpectValid         bool
+	}{
+		{"Valid Post - ony required params", ExportMethodPost, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, nil, true},
+		{"Valid Post - w/o secrets", http.MethodPost, &testUrl, &testMimeType, &testPersistOnError, nil, nil, nil, nil, nil, nil, true},
+		{"Valid Post - with secrets", ExportMethodPost, &testUrl, &testMimeType, nil, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, nil, true},
+		{"Valid Post - with http requet headers", ExportMethodPost, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, &testHTTPRequestHeaders, true},
+		{"Valid Post - with all params", ExportMethodPost, &testUrl, &testMimeType, &testPersistOnError, &testContinueOnSendError, &testReturnInputData, &testHeaderName, &testSecretName, &testSecretValueKey, &testHTTPRequestHeaders, true},
+		{"Invalid Post - no url", ExportMethodPost, nil, &testMimeType, nil, nil, nil, nil, nil, nil, nil, false},
+		{"Invalid Post - no mimeType", ExportMethodPost, &testUrl, nil, nil, nil, nil, nil, nil, nil, nil, false},
+		{"Invalid Post - bad persistOnError", ExportMethodPost, &testUrl, &testMimeType, &testBadPersistOnError, nil, nil, nil, nil, nil, nil, false},
+		{"Invalid Post - missing headerName", ExportMethodPost, &testUrl, &testMimeType, &testPersistOnError, nil, nil, nil, &testSecretName, &testSecretValueKey, nil, false},
+		{"Invalid Post - missing secretName", ExportMethodPost, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, nil, &testSecretValueKey, nil, false},
+		{"Invalid Post - missing secretValueKey", ExportMethodPost, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, &testSecretName, nil, nil, false},
+		{"Invalid Post - unmarshal error for http requet headers", ExportMethodPost, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, &testBadHTTPRequestHeaders, false},
+		{"Valid Put - ony required params", ExportMethodPut, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, nil, true},
+		{"Valid Put - w/o secrets", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, nil, nil, nil, nil, true},
+		{"Valid Put - with secrets", http.MethodPut, &testUrl, &testMimeType, nil, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, nil, true},
+		{"Valid Put - with http request headers", ExportMethodPut, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, &testHTTPRequestHeaders, true},
+		{"Valid Put - with all params", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, &testHTTPRequestHeaders, true},
+		{"Invalid Put - no url", ExportMethodPut, nil, &testMimeType, nil, nil, nil, nil, nil, nil, nil, false},
+		{"Invalid Put - no mimeType", ExportMethodPut, &testUrl, nil, nil, nil, nil, nil, nil, nil, nil, false},
+		{"Invalid Put - bad persistOnError", ExportMethodPut, &testUrl, &testMimeType, &testBadPersistOnError, nil, nil, nil, nil, nil, nil, false},
+		{"Invalid Put - bad continueOnSendError", ExportMethodPut, &testUrl, &testMimeType, nil, &testBadContinueOnSendError, nil, nil, nil, nil, nil, false},
+		{"Invalid Put - bad returnInputData", ExportMethodPut, &testUrl, &testMimeType, nil, nil, &testBadReturnInputData, nil, nil, nil, nil, false},
+		{"Invalid Put - missing headerName", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, nil, &testSecretName, &testSecretValueKey, nil, false},
+		{"Invalid Put - missing secretName", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, nil, &testSecretValueKey, nil, false},
+		{"Invalid Put - missing secretValueKey", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, &testSecretName, nil, nil, false},
+		{"Invalid Put - unmarshal error for http requet headers", ExportMethodPut, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, &testBadHTTPRequestHeaders, false},
+		{"Valid Patch - ony required params", ExportMethodPatch, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, nil, true},
+		{"Valid Patch - with secrets", http.MethodPatch, &testUrl, &testMimeType, nil, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, nil, true},
+		{"Valid Delete - ony required params", ExportMethodDelete, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, nil, true},
+		{"Valid Delete - with secrets", http.MethodDelete, &testUrl, &testMimeType, nil, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, nil, true},
+		{"Valid Get - ony required params", ExportMethodGet, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, nil, true},
+		{"Valid Get - with secrets", http.MethodGet, &testUrl, &testMimeType, nil, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, nil, true},
+		{"Invalid - unknown method", "bogus", &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := make(map[string]string)
+			params[ExportMethod] = test.Method
+
+			if test.Url != nil {
+				params[Url] = *test.Url
+			}
+
+			if test.MimeType != nil {
+				params[MimeType] = *test.MimeType
+			}
+
+			if test.PersistOnError != nil {
+				params[PersistOnError] = *test.PersistOnError
+			}
+
+			if test.ContinueOnSendError != nil {
+				params[ContinueOnSendError] = *test.ContinueOnSendError
+			}
+
+			if test.ReturnInputData != nil {
+				params[ReturnInputData] = *test.ReturnInputData
+			}
+
+			if test.HeaderName != nil {
+				params[HeaderName] = *test.HeaderName
+			}
+
+			if test.SecretName != nil {
+				params[SecretName] = *test.SecretName
+			}
+
+			if test.SecretValueKey != nil {
+				params[SecretValueKey] = *test.SecretValueKey
+			}
+
+			if test.HTTPRequestHeaders != nil {
+				params[HttpRequestHeaders] = *test.HTTPRequestHeaders
+			}
+
+			transform := configurable.HTTPExport(params)
+			assert.Equal(t, test.ExpectValid, transform != nil)
+		})
+	}
+}
+
+func TestHTTPExportRequestTimeout(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name        string
+		Timeout     string
+		ExpectValid bool
+	}{
+		{"Valid - no timeout", "", true},
+		{"Valid - with timeout", "30s", true},
+		{"Invalid - bad timeout", "bogus", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:       ExportMethodPost,
+				Url:                "http://url",
+				MimeType:           common.ContentTypeJSON,
+				HttpRequestTimeout: test.Timeout,
+			}
+
+			transform := configurable.HTTPExport(params)
+			assert.Equal(t, test.ExpectValid, transform != nil)
+		})
+	}
+}
+
+func TestHTTPExportStoreResponseHeaders(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	params := map[string]string{
+		ExportMethod:         ExportMethodPost,
+		Url:                  "http://url",
+		MimeType:             common.ContentTypeJSON,
+		StoreResponseHeaders: "Location, ETag",
+	}
+
+	options, _, err := configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Location", "ETag"}, options.StoreResponseHeaders)
+
+	transform := configurable.HTTPExport(params)
+	assert.NotNil(t, transform)
+}
+
+func TestHTTPExportResponseContextKey(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	params := map[string]string{
+		ExportMethod:       ExportMethodGet,
+		Url:                "http://url",
+		MimeType:           common.ContentTypeJSON,
+		ResponseContextKey: " metadata ",
+	}
+
+	options, _, err := configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Equal(t, "metadata", options.ResponseContextKey)
+
+	transform := configurable.HTTPExport(params)
+	assert.NotNil(t, transform)
+}
+
+func TestHTTPExportBodyTemplate(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	params := map[string]string{
+		ExportMethod: ExportMethodPost,
+		Url:          "http://url",
+		MimeType:     common.ContentTypeJSON,
+		BodyTemplate: `{"payload": {{.Payload}}, "source": "edgex"}`,
+	}
+
+	options, _, err := configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Equal(t, `{"payload": {{.Payload}}, "source": "edgex"}`, options.BodyTemplate)
+
+	transform := configurable.HTTPExport(params)
+	assert.NotNil(t, transform)
+}
+
+func TestHTTPExportMaxResponseBytes(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name             string
+		MaxResponseBytes string
+		Expected         int64
+		ExpectValid      bool
+	}{
+		{"Valid - not specified", "", 0, true},
+		{"Valid - limit", "1048576", 1048576, true},
+		{"Invalid - bad int", "bogus", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:     ExportMethodPost,
+				Url:              "http://url",
+				MimeType:         common.ContentTypeJSON,
+				MaxResponseBytes: test.MaxResponseBytes,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, options.MaxResponseBytes)
+		})
+	}
+}
+
+func TestHTTPExportIdempotencyKey(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	params := map[string]string{
+		ExportMethod:         ExportMethodPost,
+		Url:                  "http://url",
+		MimeType:             common.ContentTypeJSON,
+		IdempotencyKeyHeader: " Idempotency-Key ",
+		IdempotencyKeyCtxKey: "idempotency-key",
+	}
+
+	options, _, err := configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Equal(t, "Idempotency-Key", options.IdempotencyKeyHeader)
+	assert.Equal(t, "idempotency-key", options.IdempotencyKeyContextKey)
+
+	transform := configurable.HTTPExport(params)
+	assert.NotNil(t, transform)
+}
+
+func TestHTTPExportPayloadHeader(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name             string
+		PayloadHeader    string
+		MaxBytes         string
+		ExpectedHeader   string
+		ExpectedMaxBytes int
+		ExpectValid      bool
+	}{
+		{"Valid - not specified", "", "", "", 0, true},
+		{"Valid - header only", " X-Payload ", "", "X-Payload", 0, true},
+		{"Valid - header and max bytes", "X-Payload", "1024", "X-Payload", 1024, true},
+		{"Invalid - bad max bytes", "X-Payload", "bogus", "", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:          ExportMethodPost,
+				Url:                   "http://url",
+				MimeType:              common.ContentTypeJSON,
+				PayloadHeader:         test.PayloadHeader,
+				MaxPayloadHeaderBytes: test.MaxBytes,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedHeader, options.PayloadHeader)
+			assert.Equal(t, test.ExpectedMaxBytes, options.MaxPayloadHeaderBytes)
+		})
+	}
+}
+
+func TestHTTPExportTransportOptions(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name                      string
+		Params                    map[string]string
+		ExpectedMaxIdleConns      int
+		ExpectedIdleConnTimeout   time.Duration
+		ExpectedDisableKeepAlives bool
+		ExpectValid               bool
+	}{
+		{"Valid - not specified", map[string]string{}, 0, 0, false, true},
+		{"Valid - all specified", map[string]string{MaxIdleConns: "10", IdleConnTimeout: "30s", DisableKeepAlives: "true"}, 10, 30 * time.Second, true, true},
+		{"Invalid - bad MaxIdleConns", map[string]string{MaxIdleConns: "bogus"}, 0, 0, false, false},
+		{"Invalid - bad IdleConnTimeout", map[string]string{IdleConnTimeout: "bogus"}, 0, 0, false, false},
+		{"Invalid - bad DisableKeepAlives", map[string]string{DisableKeepAlives: "bogus"}, 0, 0, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod: ExportMethodPost,
+				Url:          "http://url",
+				MimeType:     common.ContentTypeJSON,
+			}
+			for key, value := range test.Params {
+				params[key] = value
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedMaxIdleConns, options.MaxIdleConns)
+			assert.Equal(t, test.ExpectedIdleConnTimeout, options.IdleConnTimeout)
+			assert.Equal(t, test.ExpectedDisableKeepAlives, options.DisableKeepAlives)
+		})
+	}
+}
+
+func TestHTTPExportHTTP2(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name             string
+		Params           map[string]string
+		ExpectedForce    bool
+		ExpectedFallback bool
+		ExpectValid      bool
+	}{
+		{"Valid - not specified", map[string]string{}, false, false, true},
+		{"Valid - force", map[string]string{ForceHTTP2: "true"}, true, false, true},
+		{"Valid - force with fallback", map[string]string{ForceHTTP2: "true", HTTP2Fallback: "true"}, true, true, true},
+		{"Invalid - bad ForceHTTP2", map[string]string{ForceHTTP2: "bogus"}, false, false, false},
+		{"Invalid - bad HTTP2Fallback", map[string]string{ForceHTTP2: "true", HTTP2Fallback: "bogus"}, false, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod: ExportMethodPost,
+				Url:          "http://url",
+				MimeType:     common.ContentTypeJSON,
+			}
+			for key, value := range test.Params {
+				params[key] = value
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedForce, options.ForceHTTP2)
+			assert.Equal(t, test.ExpectedFallback, options.HTTP2Fallback)
+		})
+	}
+}
+
+func TestHTTPExportCloudEventHeaders(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name        string
+		Value       string
+		Expected    bool
+		ExpectValid bool
+	}{
+		{"Valid - not specified", "", false, true},
+		{"Valid - true", "true", true, true},
+		{"Valid - false", "false", false, true},
+		{"Invalid - bad value", "bogus", false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod: ExportMethodPost,
+				Url:          "http://url",
+				MimeType:     common.ContentTypeJSON,
+			}
+			if len(test.Value) > 0 {
+				params[CloudEventHeaders] = test.Value
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, options.CloudEventHeaders)
+		})
+	}
+}
+
+func TestHTTPExportLogRequestResponse(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name        string
+		Value       string
+		Expected    bool
+		ExpectValid bool
+	}{
+		{"Valid - not specified", "", false, true},
+		{"Valid - true", "true", true, true},
+		{"Valid - false", "false", false, true},
+		{"Invalid - bad value", "bogus", false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod: ExportMethodPost,
+				Url:          "http://url",
+				MimeType:     common.ContentTypeJSON,
+			}
+			if len(test.Value) > 0 {
+				params[LogRequestResponse] = test.Value
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, options.LogRequestResponse)
+		})
+	}
+}
+
+func TestHTTPExportAcceptHeader(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name             string
+		Value            string
+		ResponseMimeType string
+		Expected         bool
+		ExpectedMimeType string
+		ExpectValid      bool
+	}{
+		{"Valid - not specified", "", "", false, "", true},
+		{"Valid - true", "true", "", true, "", true},
+		{"Valid - true with response mime type", "true", " application/xml ", true, "application/xml", true},
+		{"Invalid - bad value", "bogus", "", false, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:     ExportMethodPost,
+				Url:              "http://url",
+				MimeType:         common.ContentTypeJSON,
+				ResponseMimeType: test.ResponseMimeType,
+			}
+			if len(test.Value) > 0 {
+				params[SetAcceptHeader] = test.Value
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, options.SetAcceptHeader)
+			assert.Equal(t, test.ExpectedMimeType, options.ResponseMimeType)
+		})
+	}
+}
+
+func TestHTTPExportSecretEnvMode(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name         string
+		Value        string
+		ExpectedMode string
+		ExpectValid  bool
+	}{
+		{"Valid - not specified", "", transforms.SecretEnvModeNone, true},
+		{"Valid - fallback", "fallback", transforms.SecretEnvModeFallback, true},
+		{"Valid - env", " ENV ", transforms.SecretEnvModeOnly, true},
+		{"Invalid - bad mode", "bogus", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:  ExportMethodPost,
+				Url:           "http://url",
+				MimeType:      common.ContentTypeJSON,
+				SecretEnvMode: test.Value,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedMode, options.SecretEnvMode)
+		})
+	}
+}
+
+func TestHTTPExportJSONSerialization(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name                  string
+		Value                 string
+		Indent                string
+		ExpectedSerialization string
+		ExpectValid           bool
+	}{
+		{"Valid - not specified", "", "", "", true},
+		{"Valid - compact", "compact", "", transforms.JSONSerializationCompact, true},
+		{"Valid - indented with indent", " Indented ", "\t", transforms.JSONSerializationIndented, true},
+		{"Invalid - bad serialization", "pretty", "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:      ExportMethodPost,
+				Url:               "http://url",
+				MimeType:          common.ContentTypeJSON,
+				JSONSerialization: test.Value,
+				JSONIndent:        test.Indent,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedSerialization, options.JSONSerialization)
+			assert.Equal(t, test.Indent, options.JSONIndent)
+		})
+	}
+}
+
+func TestHTTPExportHostMappings(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name             string
+		HostMappings     string
+		ExpectedMappings map[string]string
+		ExpectValid      bool
+	}{
+		{"Valid - not specified", "", nil, true},
+		{"Valid - single mapping", "export.local=10.0.0.5", map[string]string{"export.local": "10.0.0.5"}, true},
+		{"Valid - multiple mappings", "export.local = 10.0.0.5, backup.local=10.0.0.6:8443",
+			map[string]string{"export.local": "10.0.0.5", "backup.local": "10.0.0.6:8443"}, true},
+		{"Invalid - missing address", "export.local", nil, false},
+		{"Invalid - empty host", "=10.0.0.5", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod: ExportMethodPost,
+				Url:          "http://export.local",
+				MimeType:     common.ContentTypeJSON,
+				HostMappings: test.HostMappings,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedMappings, options.HostMappings)
+		})
+	}
+}
+
+func TestHTTPExportLocalAddress(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name         string
+		LocalAddress string
+		Expected     string
+		ExpectValid  bool
+	}{
+		{"Valid - not specified", "", "", true},
+		{"Valid - IPv4", " 10.0.0.20 ", "10.0.0.20", true},
+		{"Valid - IPv6", "fd00::20", "fd00::20", true},
+		{"Invalid - interface name", "eth0", "", false},
+		{"Invalid - with port", "10.0.0.20:8080", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod: ExportMethodPost,
+				Url:          "http://export.local",
+				MimeType:     common.ContentTypeJSON,
+				LocalAddress: test.LocalAddress,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, options.LocalAddress)
+		})
+	}
+}
+
+func TestHTTPExportUserAgent(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	params := map[string]string{
+		ExportMethod: ExportMethodPost,
+		Url:          "http://export.local",
+		MimeType:     common.ContentTypeJSON,
+	}
+
+	options, _, err := configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Empty(t, options.UserAgent)
+
+	params[UserAgent] = " my-service/1.0 "
+	options, _, err = configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Equal(t, "my-service/1.0", options.UserAgent)
+}
+
+func TestHTTPExportSkipWhenContextKey(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	params := map[string]string{
+		ExportMethod: ExportMethodPost,
+		Url:          "http://export.local",
+		MimeType:     common.ContentTypeJSON,
+	}
+
+	options, _, err := configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Empty(t, options.SkipWhenContextKey)
+
+	params[SkipWhenContextKey] = " shouldexport "
+	options, _, err = configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Equal(t, "shouldexport", options.SkipWhenContextKey)
+}
+
+func TestHTTPExportCircuitBreaker(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name              string
+		Threshold         string
+		Cooldown          string
+		ExpectedThreshold int
+		ExpectedCooldown  time.Duration
+		ExpectValid       bool
+	}{
+		{"Valid - not specified", "", "", 0, 0, true},
+		{"Valid - threshold only", "5", "", 5, 0, true},
+		{"Valid - threshold and cooldown", "3", "45s", 3, 45 * time.Second, true},
+		{"Invalid - bad threshold", "bogus", "", 0, 0, false},
+		{"Invalid - bad cooldown", "3", "bogus", 0, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:            ExportMethodPost,
+				Url:                     "http://export.local",
+				MimeType:                common.ContentTypeJSON,
+				CircuitBreakerThreshold: test.Threshold,
+				CircuitBreakerCooldown:  test.Cooldown,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedThreshold, options.CircuitBreakerThreshold)
+			assert.Equal(t, test.ExpectedCooldown, options.CircuitBreakerCooldown)
+		})
+	}
+}
+
+func TestHTTPExportMultipart(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name           string
+		Fields         string
+		ExpectedFields map[string]string
+		ExpectValid    bool
+	}{
+		{"Valid - no fields", "", nil, true},
+		{"Valid - multiple fields", "source=edgex, device = {devicename}, empty=",
+			map[string]string{"source": "edgex", "device": "{devicename}", "empty": ""}, true},
+		{"Invalid - missing value", "source", nil, false},
+		{"Invalid - empty name", "=edgex", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:       ExportMethodPost,
+				Url:                "http://upload.local",
+				MimeType:           common.ContentTypeJSON,
+				MultipartFieldName: " file ",
+				MultipartFileName:  "{devicename}.json",
+				MultipartFields:    test.Fields,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "file", options.MultipartFieldName)
+			assert.Equal(t, "{devicename}.json", options.MultipartFileName)
+			assert.Equal(t, test.ExpectedFields, options.MultipartFields)
+		})
+	}
+}
+
+func TestHTTPExportFailoverUrls(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	params := map[string]string{
+		ExportMethod: ExportMethodPost,
+		Url:          "http://url",
+		MimeType:     common.ContentTypeJSON,
+		FailoverUrls: "http://backup1, http://backup2",
+	}
+
+	options, _, err := configurable.processHttpExportParameters(params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://backup1", "http://backup2"}, options.FailoverURLs)
+
+	transform := configurable.HTTPExport(params)
+	assert.NotNil(t, transform)
+}
+
+func TestHTTPExportSuccessStatusCodes(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name          string
+		StatusCodes   string
+		ExpectedCodes []int
+		ExpectValid   bool
+	}{
+		{"Valid - not specified", "", nil, true},
+		{"Valid - single code", "202", []int{202}, true},
+		{"Valid - multiple codes", "200, 202,301", []int{200, 202, 301}, true},
+		{"Invalid - bad code", "200,bogus", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:       ExportMethodPost,
+				Url:                "http://url",
+				MimeType:           common.ContentTypeJSON,
+				SuccessStatusCodes: test.StatusCodes,
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedCodes, options.SuccessStatusCodes)
+		})
+	}
+}
+
+func TestHTTPExportTolerateStatusCodes(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name          string
+		StatusCodes   string
+		ExpectedCodes []int
+		ExpectValid   bool
+	}{
+		{"Valid - not specified", "", nil, true},
+		{"Valid - single code", "409", []int{409}, true},
+		{"Valid - multiple codes", "404, 409", []int{404, 409}, true},
+		{"Invalid - bad code", "409,bogus", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod:        ExportMethodPost,
+				Url:                 "http://url",
+				MimeType:            common.ContentTypeJSON,
+				TolerateStatusCodes: test.StatusCodes,
 			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedCodes, options.TolerateStatusCodes)
 		})
 	}
 }
 
-func TestTransform(t *testing.T) {
+func TestHTTPExportFollowRedirects(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
+	followRedirects := true
+	doNotFollowRedirects := false
+
 	tests := []struct {
-		Name          string
-		TransformType string
-		ExpectValid   bool
+		Name            string
+		FollowRedirects string
+		Expected        *bool
+		ExpectValid     bool
 	}{
-		{"Good - XML", "xMl", true},
-		{"Good - JSON", "JsOn", true},
-		{"Bad Type", "baDType", false},
+		{"Valid - not specified", "", nil, true},
+		{"Valid - true", "true", &followRedirects, true},
+		{"Valid - false", "false", &doNotFollowRedirects, true},
+		{"Invalid - bad bool", "bogus", nil, false},
 	}
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			params := make(map[string]string)
-			params[TransformType] = test.TransformType
-			transform := configurable.Transform(params)
-			assert.Equal(t, test.ExpectValid, transform != nil)
+			params := map[string]string{
+				ExportMethod: ExportMethodPost,
+				Url:          "http://url",
+				MimeType:     common.ContentTypeJSON,
+			}
+			if len(test.FollowRedirects) > 0 {
+				params[FollowRedirects] = test.FollowRedirects
+			}
+
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, options.FollowRedirects)
 		})
 	}
 }
 
-func TestHTTPExport(t *testing.T) {
+func TestHTTPExportCorrelationID(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
-	testUrl := "http://url"
-	testMimeType := common.ContentTypeJSON
-	testPersistOnError := "false"
-	testBadPersistOnError := "bogus"
-	testContinueOnSendError := "true"
-	testBadContinueOnSendError := "bogus"
-	testReturnInputData := "true"
-	testBadReturnInputData := "bogus"
-
-	testHeaderName := "My-Header"
-	testSecretName := "my-secret"
-	testSecretValueKey := "header"
-
-	testHTTPRequestHeaders := `{
-		"Connection": "keep-alive",
-		"From": "[user@example.com](mailto:user@example.com)"
-	  }`
-
-	testBadHTTPRequestHeaders := `{
-		"Connection": "keep-alive", 
-		"From": 
-	  `
+	sendCorrelationID := true
+	doNotSendCorrelationID := false
 
 	tests := []struct {
 		Name                string
-		Method              string
-		Url                 *string
-		MimeType            *string
-		PersistOnError      *string
-		ContinueOnSendError *string
-		ReturnInputData     *string
-		HeaderName          *string
-		SecretName          *string
-		SecretValueKey      *string
-		HTTPRequestHeaders  *string
+		SendCorrelationID   string
+		CorrelationIDHeader string
+		Expected            *bool
+		ExpectedHeader      string
 		ExpectValid         bool
 	}{
-		{"Valid Post - ony required params", ExportMethodPost, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, nil, true},
-		{"Valid Post - w/o secrets", http.MethodPost, &testUrl, &testMimeType, &testPersistOnError, nil, nil, nil, nil, nil, nil, true},
-		{"Valid Post - with secrets", ExportMethodPost, &testUrl, &testMimeType, nil, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, nil, true},
-		{"Valid Post - with http requet headers", ExportMethodPost, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, &testHTTPRequestHeaders, true},
-		{"Valid Post - with all params", ExportMethodPost, &testUrl, &testMimeType, &testPersistOnError, &testContinueOnSendError, &testReturnInputData, &testHeaderName, &testSecretName, &testSecretValueKey, &testHTTPRequestHeaders, true},
-		{"Invalid Post - no url", ExportMethodPost, nil, &testMimeType, nil, nil, nil, nil, nil, nil, nil, false},
-		{"Invalid Post - no mimeType", ExportMethodPost, &testUrl, nil, nil, nil, nil, nil, nil, nil, nil, false},
-		{"Invalid Post - bad persistOnError", ExportMethodPost, &testUrl, &testMimeType, &testBadPersistOnError, nil, nil, nil, nil, nil, nil, false},
-		{"Invalid Post - missing headerName", ExportMethodPost, &testUrl, &testMimeType, &testPersistOnError, nil, nil, nil, &testSecretName, &testSecretValueKey, nil, false},
-		{"Invalid Post - missing secretName", ExportMethodPost, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, nil, &testSecretValueKey, nil, false},
-		{"Invalid Post - missing secretValueKey", ExportMethodPost, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, &testSecretName, nil, nil, false},
-		{"Invalid Post - unmarshal error for http requet headers", ExportMethodPost, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, &testBadHTTPRequestHeaders, false},
-		{"Valid Put - ony required params", ExportMethodPut, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, nil, true},
-		{"Valid Put - w/o secrets", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, nil, nil, nil, nil, true},
-		{"Valid Put - with secrets", http.MethodPut, &testUrl, &testMimeType, nil, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, nil, true},
-		{"Valid Put - with http request headers", ExportMethodPut, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, &testHTTPRequestHeaders, true},
-		{"Valid Put - with all params", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, &testSecretName, &testSecretValueKey, &testHTTPRequestHeaders, true},
-		{"Invalid Put - no url", ExportMethodPut, nil, &testMimeType, nil, nil, nil, nil, nil, nil, nil, false},
-		{"Invalid Put - no mimeType", ExportMethodPut, &testUrl, nil, nil, nil, nil, nil, nil, nil, nil, false},
-		{"Invalid Put - bad persistOnError", ExportMethodPut, &testUrl, &testMimeType, &testBadPersistOnError, nil, nil, nil, nil, nil, nil, false},
-		{"Invalid Put - bad continueOnSendError", ExportMethodPut, &testUrl, &testMimeType, nil, &testBadContinueOnSendError, nil, nil, nil, nil, nil, false},
-		{"Invalid Put - bad returnInputData", ExportMethodPut, &testUrl, &testMimeType, nil, nil, &testBadReturnInputData, nil, nil, nil, nil, false},
-		{"Invalid Put - missing headerName", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, nil, &testSecretName, &testSecretValueKey, nil, false},
-		{"Invalid Put - missing secretName", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, nil, &testSecretValueKey, nil, false},
-		{"Invalid Put - missing secretValueKey", ExportMethodPut, &testUrl, &testMimeType, &testPersistOnError, nil, nil, &testHeaderName, &testSecretName, nil, nil, false},
-		{"Invalid Put - unmarshal error for http requet headers", ExportMethodPut, &testUrl, &testMimeType, nil, nil, nil, nil, nil, nil, &testBadHTTPRequestHeaders, false},
+		{"Valid - not specified", "", "", nil, "", true},
+		{"Valid - true", "true", "", &sendCorrelationID, "", true},
+		{"Valid - false", "false", "", &doNotSendCorrelationID, "", true},
+		{"Valid - custom header", "", " X-Trace-Id ", nil, "X-Trace-Id", true},
+		{"Invalid - bad bool", "bogus", "", nil, "", false},
 	}
 
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
-			params := make(map[string]string)
-			params[ExportMethod] = test.Method
-
-			if test.Url != nil {
-				params[Url] = *test.Url
+			params := map[string]string{
+				ExportMethod:        ExportMethodPost,
+				Url:                 "http://url",
+				MimeType:            common.ContentTypeJSON,
+				CorrelationIDHeader: test.CorrelationIDHeader,
 			}
-
-			if test.MimeType != nil {
-				params[MimeType] = *test.MimeType
+			if len(test.SendCorrelationID) > 0 {
+				params[SendCorrelationID] = test.SendCorrelationID
 			}
 
-			if test.PersistOnError != nil {
-				params[PersistOnError] = *test.PersistOnError
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
 			}
 
-			if test.ContinueOnSendError != nil {
-				params[ContinueOnSendError] = *test.ContinueOnSendError
-			}
+			require.NoError(t, err)
+			assert.Equal(t, test.Expected, options.SendCorrelationID)
+			assert.Equal(t, test.ExpectedHeader, options.CorrelationIDHeader)
+		})
+	}
+}
 
-			if test.ReturnInputData != nil {
-				params[ReturnInputData] = *test.ReturnInputData
-			}
+func TestHTTPExportTracing(t *testing.T) {
+	configurable := Configurable{lc: lc}
 
-			if test.HeaderName != nil {
-				params[HeaderName] = *test.HeaderName
-			}
+	tests := []struct {
+		Name                     string
+		TracePropagation         string
+		TraceClientSpan          string
+		ExpectedTracePropagation bool
+		ExpectedTraceClientSpan  bool
+		ExpectValid              bool
+	}{
+		{"Valid - not specified", "", "", false, false, true},
+		{"Valid - propagation only", "true", "", true, false, true},
+		{"Valid - propagation with client span", "true", "true", true, true, true},
+		{"Invalid - bad propagation bool", "bogus", "", false, false, false},
+		{"Invalid - bad client span bool", "true", "bogus", false, false, false},
+	}
 
-			if test.SecretName != nil {
-				params[SecretName] = *test.SecretName
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				ExportMethod: ExportMethodPost,
+				Url:          "http://url",
+				MimeType:     common.ContentTypeJSON,
 			}
-
-			if test.SecretValueKey != nil {
-				params[SecretValueKey] = *test.SecretValueKey
+			if len(test.TracePropagation) > 0 {
+				params[TracePropagation] = test.TracePropagation
+			}
+			if len(test.TraceClientSpan) > 0 {
+				params[TraceClientSpan] = test.TraceClientSpan
 			}
 
-			if test.HTTPRequestHeaders != nil {
-				params[HttpRequestHeaders] = *test.HTTPRequestHeaders
+			options, _, err := configurable.processHttpExportParameters(params)
+			if !test.ExpectValid {
+				require.Error(t, err)
+				assert.Nil(t, configurable.HTTPExport(params))
+				return
 			}
 
-			transform := configurable.HTTPExport(params)
-			assert.Equal(t, test.ExpectValid, transform != nil)
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedTracePropagation, options.TracePropagation)
+			assert.Equal(t, test.ExpectedTraceClientSpan, options.TraceClientSpan)
 		})
 	}
 }
 
+func TestHTTPExportUsesAppContext(t *testing.T) {
+	appCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	configurable := NewConfigurable(appCtx, lc, nil)
+
+	params := map[string]string{
+		ExportMethod: ExportMethodPost,
+		Url:          "http://127.0.0.1:0",
+		MimeType:     common.ContentTypeJSON,
+	}
+
+	transform := configurable.HTTPExport(params)
+	require.NotNil(t, transform)
+
+	// Metrics manager not needed for this test
+	testDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+	})
+
+	// App context is already cancelled, so the export must fail as cancelled rather than attempt to connect
+	continuePipeline, result := transform(appfunction.NewContext("123", testDic, ""), "test data")
+	require.False(t, continuePipeline)
+	assert.ErrorIs(t, result.(error), context.Canceled)
+}
+
 func TestSetOutputData(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
@@ -329,6 +1796,34 @@ func TestBatchByTimeAndCount(t *testing.T) {
 	assert.NotNil(t, trx, "return result for BatchByTimeAndCount should not be nil")
 }
 
+func TestBatchBySize(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name          string
+		ByteThreshold string
+		ExpectValid   bool
+	}{
+		{"Valid", "1024", true},
+		{"Invalid - missing threshold", "", false},
+		{"Invalid - bad threshold", "bogus", false},
+		{"Invalid - zero threshold", "0", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := make(map[string]string)
+			params[Mode] = BatchBySize
+			if len(test.ByteThreshold) > 0 {
+				params[ByteThreshold] = test.ByteThreshold
+			}
+
+			transform := configurable.Batch(params)
+			assert.Equal(t, test.ExpectValid, transform != nil)
+		})
+	}
+}
+
 func TestJSONLogic(t *testing.T) {
 	params := make(map[string]string)
 	params[Rule] = "{}"
@@ -340,6 +1835,60 @@ func TestJSONLogic(t *testing.T) {
 
 }
 
+func TestJSONLogicWithResult(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name         string
+		ResultKey    string
+		ReturnResult string
+		ExpectNil    bool
+	}{
+		{"Good - result key", "total", "", false},
+		{"Good - return result", "", "true", false},
+		{"Good - result key and return result", "total", "false", false},
+		{"Bad - return result", "total", "bogus", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				Rule:      `{"+": [1, 1]}`,
+				ResultKey: test.ResultKey,
+			}
+			if len(test.ReturnResult) > 0 {
+				params[ReturnResult] = test.ReturnResult
+			}
+
+			trx := configurable.JSONLogic(params)
+			assert.Equal(t, test.ExpectNil, trx == nil)
+		})
+	}
+}
+
+func TestExtractJSONPath(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{Expression: "$.sensor.type", ContextKey: "type"}, false},
+		{"Valid EmptyOnMissing", map[string]string{Expression: "$.readings[0].value", ContextKey: "value", EmptyOnMissing: "true"}, false},
+		{"Missing Expression", map[string]string{ContextKey: "type"}, true},
+		{"Missing ContextKey", map[string]string{Expression: "$.sensor.type"}, true},
+		{"Invalid Expression", map[string]string{Expression: "$.readings[*]", ContextKey: "type"}, true},
+		{"Bad EmptyOnMissing", map[string]string{Expression: "$.sensor.type", ContextKey: "type", EmptyOnMissing: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.ExtractJSONPath(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
 func TestMQTTExport(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
@@ -367,6 +1916,42 @@ func TestMQTTExport(t *testing.T) {
 	assert.NotNil(t, trx, "return result from MQTTSecretSend should not be nil")
 }
 
+func TestMQTTExportQosAndRetain(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name      string
+		Qos       string
+		Retain    string
+		ExpectNil bool
+	}{
+		{"Good - QoS 0", "0", "false", false},
+		{"Good - QoS 1 with retain", "1", "true", false},
+		{"Good - QoS 2", "2", "false", false},
+		{"Bad - QoS out of range", "3", "false", true},
+		{"Bad - negative QoS", "-1", "false", true},
+		{"Bad - QoS", "junk", "false", true},
+		{"Bad - Retain", "1", "junk", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			params := map[string]string{
+				BrokerAddress: "mqtt://broker:8883",
+				Topic:         "topic",
+				SecretName:    "my-secret",
+				ClientID:      "clientid",
+				AuthMode:      "none",
+				Qos:           test.Qos,
+				Retain:        test.Retain,
+			}
+
+			trx := configurable.MQTTExport(params)
+			assert.Equal(t, test.ExpectNil, trx == nil)
+		})
+	}
+}
+
 func TestMQTTExportWillOptions(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
@@ -437,6 +2022,27 @@ func TestMQTTExportWillOptions(t *testing.T) {
 	}
 }
 
+func TestWebSocketExport(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{Url: "ws://localhost/ingest"}, false},
+		{"Valid With Secret Header", map[string]string{Url: "ws://localhost/ingest", HeaderName: "X-Api-Key", SecretName: "ws", SecretValueKey: "key", PersistOnError: "true"}, false},
+		{"Missing Url", map[string]string{PersistOnError: "true"}, true},
+		{"Bad PersistOnError", map[string]string{Url: "ws://localhost/ingest", PersistOnError: "bogus"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.WebSocketExport(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
 func copyMap(src map[string]string) map[string]string {
 	dst := make(map[string]string)
 	for k, v := range src {
@@ -475,6 +2081,70 @@ func TestAddTags(t *testing.T) {
 	}
 }
 
+func TestAddTagsWithPlaceholders(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name                string
+		ResolvePlaceholders string
+		StrictPlaceholders  string
+		ExpectNil           bool
+	}{
+		{"Good - resolve", "true", "", false},
+		{"Good - resolve strict", "true", "true", false},
+		{"Good - not resolved", "false", "bogus", false},
+		{"Bad - resolve", "bogus", "", true},
+		{"Bad - strict", "true", "bogus", true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.Name, func(t *testing.T) {
+			params := map[string]string{
+				Tags:                "Pipeline:{receivedtopic}",
+				ResolvePlaceholders: testCase.ResolvePlaceholders,
+			}
+			if len(testCase.StrictPlaceholders) > 0 {
+				params[StrictPlaceholders] = testCase.StrictPlaceholders
+			}
+
+			transform := configurable.AddTags(params)
+			assert.Equal(t, testCase.ExpectNil, transform == nil)
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		Name             string
+		Algorithm        string
+		CompressionLevel string
+		ExpectNil        bool
+	}{
+		{"GZIP", CompressGZIP, "", false},
+		{"ZLIB", CompressZLIB, "", false},
+		{"ZSTD - default level", CompressZSTD, "", false},
+		{"ZSTD - with level", CompressZSTD, "19", false},
+		{"ZSTD - bad level", CompressZSTD, "bogus", true},
+		{"ZSTD - level out of range", CompressZSTD, "30", true},
+		{"Bad - unknown algorithm", "bogus", "", true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.Name, func(t *testing.T) {
+			params := make(map[string]string)
+			params[Algorithm] = testCase.Algorithm
+			if len(testCase.CompressionLevel) > 0 {
+				params[CompressionLevel] = testCase.CompressionLevel
+			}
+
+			transform := configurable.Compress(params)
+			assert.Equal(t, testCase.ExpectNil, transform == nil)
+		})
+	}
+}
+
 func TestEncrypt(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
@@ -487,6 +2157,9 @@ func TestEncrypt(t *testing.T) {
 	}{
 		{"AES256 - Bad - No secrets ", EncryptAES256, "", "", true},
 		{"AES256 - good - secrets", EncryptAES256, uuid.NewString(), uuid.NewString(), false},
+		{"AES256GCM - Bad - No secrets ", EncryptAES256GCM, "", "", true},
+		{"AES256GCM - good - secrets", EncryptAES256GCM, uuid.NewString(), uuid.NewString(), false},
+		{"Bad - unknown algorithm", "bogus", uuid.NewString(), uuid.NewString(), true},
 	}
 
 	for _, testCase := range tests {
@@ -508,6 +2181,28 @@ func TestEncrypt(t *testing.T) {
 	}
 }
 
+func TestEncryptPerTenant(t *testing.T) {
+	configurable := Configurable{lc: lc}
+
+	tests := []struct {
+		name      string
+		params    map[string]string
+		expectNil bool
+	}{
+		{"Valid", map[string]string{Algorithm: EncryptAES256GCM, TenantContextKey: "tenant", TenantSecretNames: "a=keys-a, b=keys-b", SecretValueKey: "key"}, false},
+		{"AES256 not supported", map[string]string{Algorithm: EncryptAES256, TenantContextKey: "tenant", TenantSecretNames: "a=keys-a", SecretValueKey: "key"}, true},
+		{"Missing TenantContextKey", map[string]string{Algorithm: EncryptAES256GCM, TenantSecretNames: "a=keys-a", SecretValueKey: "key"}, true},
+		{"Missing SecretValueKey", map[string]string{Algorithm: EncryptAES256GCM, TenantContextKey: "tenant", TenantSecretNames: "a=keys-a"}, true},
+		{"Bad TenantSecretNames", map[string]string{Algorithm: EncryptAES256GCM, TenantContextKey: "tenant", TenantSecretNames: "a=keys-a, b", SecretValueKey: "key"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trx := configurable.Encrypt(tt.params)
+			assert.Equal(t, tt.expectNil, trx == nil)
+		})
+	}
+}
+
 func TestConfigurable_WrapIntoEvent(t *testing.T) {
 	configurable := Configurable{lc: lc}
 
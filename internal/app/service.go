@@ -48,6 +48,7 @@ import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/runtime"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/webserver"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/transforms"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
 	clientInterfaces "github.com/edgexfoundry/go-mod-core-contracts/v3/clients/interfaces"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
@@ -104,11 +105,21 @@ type Service struct {
 	backgroundPublishChannel   <-chan interfaces.BackgroundMessage
 	customTriggerFactories     map[string]func(sdk *Service) (interfaces.Trigger, error)
 	customStoreClientFactories map[string]func(db bootstrapConfig.Database, cred bootstrapConfig.Credentials) (interfaces.StoreClient, error)
+	deadLetterHandler          interfaces.DeadLetterHandler
 	profileSuffixPlaceholder   string
 	commandLine                commandLineFlags
 	flags                      *flags.Default
 	configProcessor            *config.Processor
 	requestTimeout             time.Duration
+	pipelineBatches            []pipelineBatch
+}
+
+// pipelineBatch is a configurable Batch function and its position in the pipeline, used to flush the
+// batched data through the rest of the pipeline on graceful shutdown.
+type pipelineBatch struct {
+	pipelineId string
+	position   int
+	batch      *transforms.BatchConfig
 }
 
 type commandLineFlags struct {
@@ -267,6 +278,14 @@ func (svc *Service) Run() (pahoMqtt.Client, error) {
 		svc.ctx.storeForwardWg.Wait()
 	}
 
+	// Flush before cancelling the app context so exports of the batched data aren't aborted
+	svc.flushPipelineBatches()
+
+	// Drain after the flush so any flushed data that failed to export also gets a final retry
+	if svc.config.Writable.StoreAndForward.Enabled {
+		svc.drainStoreForward()
+	}
+
 	svc.ctx.appCancelCtx() // Cancel all long-running go funcs
 	svc.ctx.appWg.Wait()
 	// Call all the deferred funcs that need to happen when exiting.
@@ -278,11 +297,55 @@ func (svc *Service) Run() (pahoMqtt.Client, error) {
 	return t.(*mqtt.Trigger).MqttClient, err
 }
 
+// drainStoreForward does a final retry of the Store and Forward data, bounded by the configured ShutdownDrainTimeout.
+func (svc *Service) drainStoreForward() {
+	drainTimeout := svc.config.Writable.StoreAndForward.ShutdownDrainTimeout
+	if len(drainTimeout) == 0 {
+		return
+	}
+
+	timeout, err := time.ParseDuration(drainTimeout)
+	if err != nil {
+		svc.lc.Errorf("StoreAndForward ShutdownDrainTimeout failed to parse, skipping drain: %s", err.Error())
+		return
+	}
+
+	svc.runtime.DrainStoreAndForward(timeout)
+}
+
+// flushPipelineBatches flushes any partially accumulated batches from the configurable Batch functions
+// and sends the batched data through the remainder of their pipelines, so it isn't lost on shutdown.
+func (svc *Service) flushPipelineBatches() {
+	for _, entry := range svc.pipelineBatches {
+		pipeline := svc.runtime.GetPipelineById(entry.pipelineId)
+		if pipeline == nil {
+			continue
+		}
+
+		appContext := appfunction.NewContext(uuid.NewString(), svc.dic, "")
+		appContext.AddValue(interfaces.PIPELINEID, pipeline.Id)
+
+		continuePipeline, result := entry.batch.Flush(appContext)
+		if !continuePipeline {
+			if err, ok := result.(error); ok {
+				svc.lc.Errorf("Unable to flush batched data in pipeline '%s': %s", pipeline.Id, err.Error())
+			}
+			continue
+		}
+
+		svc.lc.Infof("Sending flushed batched data through pipeline '%s'", pipeline.Id)
+		if messageError := svc.runtime.ExecutePipeline(result, appContext, pipeline, entry.position+1, false); messageError != nil {
+			svc.lc.Errorf("Failed to process flushed batched data in pipeline '%s': %s", pipeline.Id, messageError.Err.Error())
+		}
+	}
+}
+
 // LoadConfigurableFunctionPipelines return the configured function pipelines (default and per topic) from configuration.
 func (svc *Service) LoadConfigurableFunctionPipelines() (map[string]interfaces.FunctionPipeline, error) {
 	pipelines := make(map[string]interfaces.FunctionPipeline)
 
 	svc.usingConfigurablePipeline = true
+	svc.pipelineBatches = nil
 
 	svc.targetType = nil
 
@@ -297,7 +360,7 @@ func (svc *Service) LoadConfigurableFunctionPipelines() (map[string]interfaces.F
 		return nil, fmt.Errorf("pipline TargetType of '%s' is not supported", svc.config.Writable.Pipeline.TargetType)
 	}
 
-	configurable := reflect.ValueOf(NewConfigurable(svc.lc, svc.SecretProvider()))
+	configurable := reflect.ValueOf(NewConfigurable(svc.ctx.appCtx, svc.lc, svc.SecretProvider()))
 	pipelineConfig := svc.config.Writable.Pipeline
 
 	defaultExecutionOrder := strings.TrimSpace(pipelineConfig.ExecutionOrder)
@@ -387,6 +450,9 @@ func (svc *Service) loadConfigurablePipelineTransforms(
 			}
 		}
 
+		configurableBatches := configurable.Interface().(*Configurable).batches
+		batchCount := len(configurableBatches)
+
 		function, ok := functionValue.Call(inputParameters)[0].Interface().(interfaces.AppFunction)
 		if !ok {
 			return nil, fmt.Errorf("failed to cast function %s as AppFunction type for pipeline '%s'", functionName, pipelineId)
@@ -396,6 +462,16 @@ func (svc *Service) loadConfigurablePipelineTransforms(
 			return nil, fmt.Errorf("%s from configuration failed for pipeline '%s'", functionName, pipelineId)
 		}
 
+		// Capture any Batch transform created so it can be flushed on graceful shutdown
+		configurableBatches = configurable.Interface().(*Configurable).batches
+		if len(configurableBatches) > batchCount {
+			svc.pipelineBatches = append(svc.pipelineBatches, pipelineBatch{
+				pipelineId: pipelineId,
+				position:   len(transforms),
+				batch:      configurableBatches[batchCount],
+			})
+		}
+
 		transforms = append(transforms, function)
 		svc.lc.Debugf("%s function added to '%s' configurable pipeline with parameters: [%s]",
 			functionName,
@@ -436,10 +512,14 @@ func (svc *Service) AddFunctionsPipelineForTopics(id string, topics []string, tr
 		}
 	}
 
-	// Must add the base topic to all the input topics
-	var fullTopics []string
-	for _, topic := range topics {
-		fullTopics = append(fullTopics, coreCommon.BuildTopic(svc.config.MessageBus.GetBaseTopicPrefix(), topic))
+	// Must add the base topic to all the input topics, except for the External MQTT trigger since the topics
+	// received from the external broker don't have the EdgeX base topic
+	fullTopics := topics
+	if !strings.EqualFold(svc.config.Trigger.Type, TriggerTypeMQTT) {
+		fullTopics = nil
+		for _, topic := range topics {
+			fullTopics = append(fullTopics, coreCommon.BuildTopic(svc.config.MessageBus.GetBaseTopicPrefix(), topic))
+		}
 	}
 
 	err := svc.runtime.AddFunctionsPipeline(id, fullTopics, transforms)
@@ -572,6 +652,7 @@ func (svc *Service) Initialize() error {
 	}
 
 	svc.runtime = runtime.NewFunctionPipelineRuntime(svc.serviceKey, svc.targetType, svc.dic)
+	svc.runtime.SetDeadLetterHandler(svc.deadLetterHandler)
 
 	// Bootstrapping is complete, so now need to retrieve the needed objects from the containers.
 	svc.lc = bootstrapContainer.LoggingClientFrom(svc.dic.Get)
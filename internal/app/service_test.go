@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
@@ -398,6 +399,43 @@ func TestService_AddFunctionsPipelineForTopics(t *testing.T) {
 	}
 }
 
+func TestService_AddFunctionsPipelineForTopicsBaseTopic(t *testing.T) {
+	tags := builtin.NewTags(nil)
+	transforms := []interfaces.AppFunction{tags.AddTags}
+
+	tests := []struct {
+		name           string
+		trigger        string
+		expectedTopics []string
+	}{
+		{"MessageBus", TriggerTypeMessageBus, []string{"edgex/events/#", "edgex/commands/+"}},
+		{"External MQTT", TriggerTypeMQTT, []string{"events/#", "commands/+"}},
+		{"External MQTT lower case", strings.ToLower(TriggerTypeMQTT), []string{"events/#", "commands/+"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := Service{
+				lc:      lc,
+				dic:     dic,
+				runtime: runtime.NewFunctionPipelineRuntime("", nil, dic),
+				config: &common.ConfigurationStruct{
+					Trigger: common.TriggerInfo{
+						Type: test.trigger,
+					},
+				},
+			}
+
+			err := service.AddFunctionsPipelineForTopics("123", []string{"events/#", "commands/+"}, transforms...)
+			require.NoError(t, err)
+
+			actual := service.runtime.GetPipelineById("123")
+			require.NotNil(t, actual)
+			assert.Equal(t, test.expectedTopics, actual.Topics)
+		})
+	}
+}
+
 func TestService_RemoveAllFunctionPipelines(t *testing.T) {
 	service := Service{
 		lc:      lc,
@@ -639,6 +677,61 @@ func TestLoadConfigurableFunctionPipelinesNumFunctions(t *testing.T) {
 	assert.Equal(t, expectedTransformsCount, len(pipeline.Transforms))
 }
 
+func TestFlushPipelineBatches(t *testing.T) {
+	functions := make(map[string]common.PipelineFunction)
+	functions["Batch"] = common.PipelineFunction{
+		Parameters: map[string]string{Mode: BatchByCount, BatchThreshold: "10"},
+	}
+
+	sdk := Service{
+		lc:      lc,
+		dic:     dic,
+		runtime: runtime.NewFunctionPipelineRuntime("", nil, dic),
+		config: &common.ConfigurationStruct{
+			Trigger: common.TriggerInfo{
+				Type: TriggerTypeMessageBus,
+			},
+			Writable: common.WritableInfo{
+				Pipeline: common.PipelineInfo{
+					ExecutionOrder: "Batch",
+					TargetType:     rawTargetType,
+					Functions:      functions,
+				},
+			},
+		},
+	}
+
+	pipelines, err := sdk.LoadConfigurableFunctionPipelines()
+	require.NoError(t, err)
+	require.Len(t, sdk.pipelineBatches, 1)
+	assert.Equal(t, interfaces.DefaultPipelineId, sdk.pipelineBatches[0].pipelineId)
+	assert.Equal(t, 0, sdk.pipelineBatches[0].position)
+
+	var flushedData interface{}
+	capture := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		flushedData = data
+		return false, nil
+	}
+
+	batch := pipelines[interfaces.DefaultPipelineId].Transforms[0]
+	err = sdk.SetDefaultFunctionsPipeline(batch, capture)
+	require.NoError(t, err)
+
+	appContext := sdk.BuildContext(uuid.NewString(), "")
+	continuePipeline, _ := batch(appContext, []byte("one"))
+	require.False(t, continuePipeline)
+	continuePipeline, _ = batch(appContext, []byte("two"))
+	require.False(t, continuePipeline)
+
+	sdk.flushPipelineBatches()
+	assert.Equal(t, [][]byte{[]byte("one"), []byte("two")}, flushedData)
+
+	// Batch is now empty so nothing more is flushed
+	flushedData = nil
+	sdk.flushPipelineBatches()
+	assert.Nil(t, flushedData)
+}
+
 func TestTargetType(t *testing.T) {
 	functions := make(map[string]common.PipelineFunction)
 	functions["Compress"] = common.PipelineFunction{
@@ -830,7 +923,7 @@ func TestFindMatchingFunction(t *testing.T) {
 		profileSuffixPlaceholder: interfaces.ProfileSuffixPlaceholder,
 	}
 
-	configurable := reflect.ValueOf(NewConfigurable(svc.lc, svc.SecretProvider()))
+	configurable := reflect.ValueOf(NewConfigurable(svc.ctx.appCtx, svc.lc, svc.SecretProvider()))
 
 	tests := []struct {
 		Name         string
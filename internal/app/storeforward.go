@@ -49,6 +49,14 @@ func (svc *Service) RegisterCustomStoreFactory(name string, factory func(cfg boo
 	return nil
 }
 
+// RegisterDeadLetterHandler registers a handler which is called with each Store&Forward item that is given up on
+func (svc *Service) RegisterDeadLetterHandler(handler interfaces.DeadLetterHandler) {
+	svc.deadLetterHandler = handler
+	if svc.runtime != nil {
+		svc.runtime.SetDeadLetterHandler(handler)
+	}
+}
+
 func (svc *Service) createStoreClient(database bootstrapConfig.Database, credentials bootstrapConfig.Credentials) (interfaces.StoreClient, error) {
 	switch strings.ToLower(database.Type) {
 	case db.RedisDB:
@@ -53,6 +53,19 @@ func Test_service_RegisterCustomStoreFactory(t *testing.T) {
 	assert.True(t, found)
 }
 
+func Test_service_RegisterDeadLetterHandler(t *testing.T) {
+	sut := &Service{}
+
+	called := false
+	sut.RegisterDeadLetterHandler(func(item interfaces.StoredObject, lastError error) {
+		called = true
+	})
+
+	require.NotNil(t, sut.deadLetterHandler)
+	sut.deadLetterHandler(interfaces.StoredObject{}, nil)
+	assert.True(t, called)
+}
+
 func Test_service_NewStoreClient_Invalid(t *testing.T) {
 	sut := &Service{}
 
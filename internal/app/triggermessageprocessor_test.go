@@ -19,10 +19,12 @@ package app
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
 	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	coreCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/common"
 	"github.com/edgexfoundry/go-mod-messaging/v3/pkg/types"
 	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
@@ -32,6 +34,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/common"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/runtime"
 	triggerMocks "github.com/edgexfoundry/app-functions-sdk-go/v3/internal/trigger/mocks"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
@@ -168,6 +171,68 @@ func Test_triggerMessageProcessor_MessageReceived(t *testing.T) {
 	}
 }
 
+func Test_triggerMessageProcessor_MessageReceivedPerTopicPipelines(t *testing.T) {
+	service := &Service{
+		lc:      lc,
+		dic:     dic,
+		runtime: runtime.NewFunctionPipelineRuntime("", nil, dic),
+		config: &common.ConfigurationStruct{
+			Trigger: common.TriggerInfo{
+				Type: TriggerTypeMQTT,
+			},
+		},
+	}
+	service.runtime.TargetType = &[]byte{}
+
+	var executedLock sync.Mutex
+	executed := map[string]string{}
+	recordPipeline := func(pipelineId string) interfaces.AppFunction {
+		return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+			receivedTopic, _ := ctx.GetValue(interfaces.RECEIVEDTOPIC)
+
+			executedLock.Lock()
+			defer executedLock.Unlock()
+			executed[pipelineId] = receivedTopic
+
+			return false, nil
+		}
+	}
+
+	require.NoError(t, service.AddFunctionsPipelineForTopics("commands", []string{"commands/#"}, recordPipeline("commands")))
+	require.NoError(t, service.AddFunctionsPipelineForTopics("telemetry", []string{"telemetry/#"}, recordPipeline("telemetry")))
+
+	mmMock := mocks.MetricsManager{}
+	mmMock.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	processor := NewTriggerMessageProcessor(NewTriggerServiceBinding(service), &mmMock)
+
+	tests := []struct {
+		name          string
+		receivedTopic string
+		expected      map[string]string
+	}{
+		{"commands", "commands/device1/reboot", map[string]string{"commands": "commands/device1/reboot"}},
+		{"telemetry", "telemetry/device2", map[string]string{"telemetry": "telemetry/device2"}},
+		{"no match", "alerts/device1", map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed = map[string]string{}
+
+			envelope := types.MessageEnvelope{
+				CorrelationID: uuid.NewString(),
+				ContentType:   coreCommon.ContentTypeJSON,
+				Payload:       []byte(`{"value":1}`),
+				ReceivedTopic: tt.receivedTopic,
+			}
+
+			err := processor.MessageReceived(nil, envelope, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, executed)
+		})
+	}
+}
+
 func testPipeline() *interfaces.FunctionPipeline {
 	return &interfaces.FunctionPipeline{
 		MessagesProcessed:     gometrics.NewCounter(),
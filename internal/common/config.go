@@ -163,6 +163,15 @@ type StoreAndForwardInfo struct {
 	Enabled       bool
 	RetryInterval string
 	MaxRetryCount int
+	// RetryConcurrency is the maximum number of stored data items retried concurrently. Items are retried
+	// sequentially when set to 0 or 1.
+	RetryConcurrency int
+	// RetryPreserveOrder indicates the stored data items for the same pipeline are retried sequentially in the
+	// order they were stored when retrying concurrently.
+	RetryPreserveOrder bool
+	// ShutdownDrainTimeout is the maximum time to spend doing a final retry of the stored data when the service
+	// is shutting down. The final retry is skipped when not set.
+	ShutdownDrainTimeout string
 }
 
 // Credentials encapsulates username-password attributes.
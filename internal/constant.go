@@ -40,9 +40,25 @@ const (
 	PipelineProcessingErrorsName      = "PipelineProcessingErrors-" + PipelineIdTxt
 	HttpExportSizeName                = "HttpExportSize"
 	HttpExportErrorsName              = "HttpExportErrors"
+	HttpExportSuccessesName           = "HttpExportSuccesses"
+	HttpExportLatencyName             = "HttpExportLatency"
+	HttpExportCircuitStateName        = "HttpExportCircuitState"
+	HttpExportCircuitOpenedName       = "HttpExportCircuitOpened"
 	MqttExportSizeName                = "MqttExportSize"
 	MqttExportErrorsName              = "MqttExportErrors"
+	WebSocketExportSizeName           = "WebSocketExportSize"
+	WebSocketExportErrorsName         = "WebSocketExportErrors"
+	GrpcExportSizeName                = "GrpcExportSize"
+	GrpcExportErrorsName              = "GrpcExportErrors"
+	KafkaExportSizeName               = "KafkaExportSize"
+	KafkaExportErrorsName             = "KafkaExportErrors"
+	AwsExportSizeName                 = "AwsExportSize"
+	AwsExportErrorsName               = "AwsExportErrors"
 	StoreForwardQueueSizeName         = "StoreForwardQueueSize"
+	ZstdCompressedSizeName            = "ZstdCompressedSize"
+	RateLimiterDroppedName            = "RateLimiterDropped"
+	JSONSchemaValidationFailuresName  = "JSONSchemaValidationFailures"
+	FreshnessFilterDroppedName        = "FreshnessFilterDropped"
 
 	// MetricsReservoirSize is the default Metrics Sample Reservoir size
 	MetricsReservoirSize = 1028
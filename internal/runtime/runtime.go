@@ -28,6 +28,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
 	bootstrapInterfaces "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces"
@@ -103,6 +104,11 @@ func NewFunctionPipelineRuntime(serviceKey string, targetType interface{}, dic *
 	return fpr
 }
 
+// SetDeadLetterHandler sets the handler called with each Store and Forward item that is given up on
+func (fpr *FunctionsPipelineRuntime) SetDeadLetterHandler(handler interfaces.DeadLetterHandler) {
+	fpr.storeForward.setDeadLetterHandler(handler)
+}
+
 // SetDefaultFunctionsPipeline sets the default function pipeline
 func (fpr *FunctionsPipelineRuntime) SetDefaultFunctionsPipeline(transforms []interfaces.AppFunction) {
 	pipeline := fpr.GetDefaultPipeline() // ensures the default pipeline exists
@@ -362,6 +368,11 @@ func (fpr *FunctionsPipelineRuntime) StartStoreAndForward(
 	fpr.storeForward.startStoreAndForwardRetryLoop(appWg, appCtx, enabledWg, enabledCtx, serviceKey)
 }
 
+// DrainStoreAndForward does a final retry of all the stored data, waiting at most for the specified timeout
+func (fpr *FunctionsPipelineRuntime) DrainStoreAndForward(timeout time.Duration) {
+	fpr.storeForward.drainStoredData(timeout)
+}
+
 func (fpr *FunctionsPipelineRuntime) processEventPayload(envelope types.MessageEnvelope) (*dtos.Event, error) {
 
 	fpr.lc.Debug("Attempting to process Payload as an AddEventRequest DTO")
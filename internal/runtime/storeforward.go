@@ -46,6 +46,8 @@ type storeForwardInfo struct {
 	serviceKey      string
 	dataCount       gometrics.Counter
 	retryInProgress atomic.Bool
+	handlerMutex    sync.Mutex
+	deadLetter      interfaces.DeadLetterHandler
 }
 
 func newStoreAndForward(runtime *FunctionsPipelineRuntime, dic *di.Container, serviceKey string) *storeForwardInfo {
@@ -138,7 +140,7 @@ func (sf *storeForwardInfo) startStoreAndForwardRetryLoop(
 				break exit
 
 			case <-time.After(retryInterval):
-				sf.retryStoredData(serviceKey)
+				sf.retryStoredData(appCtx, serviceKey)
 			}
 		}
 
@@ -175,13 +177,15 @@ func (sf *storeForwardInfo) storeForLaterRetry(
 	sf.dataCount.Inc(1)
 }
 
-func (sf *storeForwardInfo) retryStoredData(serviceKey string) {
+// retryStoredData retries all the stored data items for the service and returns the number of items that were
+// successfully retried and the number that failed. No more items are retried once the context is cancelled, leaving
+// the remaining items in the store. false is returned if the retry was skipped since another retry is in progress.
+func (sf *storeForwardInfo) retryStoredData(ctx context.Context, serviceKey string) (int, int, bool) {
 	// Skip if another thread is already doing the retry
-	if sf.retryInProgress.Load() {
-		return
+	if !sf.retryInProgress.CompareAndSwap(false, true) {
+		return 0, 0, false
 	}
 
-	sf.retryInProgress.Store(true)
 	defer sf.retryInProgress.Store(false)
 
 	storeClient := container.StoreClientFrom(sf.dic.Get)
@@ -189,13 +193,17 @@ func (sf *storeForwardInfo) retryStoredData(serviceKey string) {
 	items, err := storeClient.RetrieveFromStore(serviceKey)
 	if err != nil {
 		sf.lc.Errorf("Unable to load store and forward items from DB: %s", err.Error())
-		return
+		return 0, 0, true
 	}
 
 	sf.lc.Debugf("%d stored data items found for retrying", len(items))
 
+	succeeded := 0
+	failed := 0
 	if len(items) > 0 {
-		itemsToRemove, itemsToUpdate := sf.processRetryItems(items)
+		var itemsToRemove, itemsToUpdate []interfaces.StoredObject
+		itemsToRemove, itemsToUpdate, succeeded = sf.processRetryItems(ctx, items)
+		failed = len(itemsToRemove) + len(itemsToUpdate) - succeeded
 
 		sf.lc.Debugf(" %d stored data items will be removed post retry", len(itemsToRemove))
 		sf.lc.Debugf(" %d stored data items will be updated post retry", len(itemsToUpdate))
@@ -204,8 +212,8 @@ func (sf *storeForwardInfo) retryStoredData(serviceKey string) {
 			if err := storeClient.RemoveFromStore(item); err != nil {
 				sf.lc.Errorf("Unable to remove stored data item for pipeline '%s' from DB, objectID=%s: %s",
 					item.PipelineId,
-					err.Error(),
-					item.ID)
+					item.ID,
+					err.Error())
 			}
 		}
 
@@ -213,75 +221,205 @@ func (sf *storeForwardInfo) retryStoredData(serviceKey string) {
 			if err := storeClient.Update(item); err != nil {
 				sf.lc.Errorf("Unable to update stored data item for pipeline '%s' from DB, objectID=%s: %s",
 					item.PipelineId,
-					err.Error(),
-					item.ID)
+					item.ID,
+					err.Error())
 			}
 		}
 
 		sf.dataCount.Dec(int64(len(itemsToRemove)))
 	}
+
+	return succeeded, failed, true
+}
+
+// drainStoredData does a final retry pass of all the stored data items, waiting at most for the specified timeout
+// before the pass is cancelled. The pass is always finished before returning, i.e. the items being retried when
+// it is cancelled complete, so it doesn't use the store after it has been closed on shutdown.
+func (sf *storeForwardInfo) drainStoredData(timeout time.Duration) {
+	type drainResult struct {
+		succeeded int
+		failed    int
+		ran       bool
+	}
+
+	done := make(chan drainResult, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sf.lc.Infof("Draining Store and Forward data with %s timeout", timeout.String())
+
+	go func() {
+		succeeded, failed, ran := sf.retryStoredData(ctx, sf.serviceKey)
+		done <- drainResult{succeeded: succeeded, failed: failed, ran: ran}
+	}()
+
+	var result drainResult
+	select {
+	case result = <-done:
+	case <-time.After(timeout):
+		cancel()
+		result = <-done
+		if result.ran {
+			sf.lc.Warnf("Store and Forward drain did not complete within %s timeout: %d stored data items succeeded, %d failed. "+
+				"Remaining data will be retried on next start",
+				timeout.String(),
+				result.succeeded,
+				result.failed)
+			return
+		}
+	}
+
+	if !result.ran {
+		sf.lc.Warn("Store and Forward drain skipped since a retry is already in progress. Remaining data will be retried on next start")
+		return
+	}
+
+	sf.lc.Infof("Store and Forward drain complete: %d stored data items succeeded, %d failed",
+		result.succeeded,
+		result.failed)
 }
 
-func (sf *storeForwardInfo) processRetryItems(items []interfaces.StoredObject) ([]interfaces.StoredObject, []interfaces.StoredObject) {
+// retryOutcome is the result of retrying a single stored data item
+type retryOutcome int
+
+const (
+	retrySucceeded retryOutcome = iota
+	retryFailedUpdate
+	retryFailedRemove
+)
+
+func (sf *storeForwardInfo) processRetryItems(ctx context.Context, items []interfaces.StoredObject) ([]interfaces.StoredObject, []interfaces.StoredObject, int) {
 	config := container.ConfigurationFrom(sf.dic.Get)
 
 	var itemsToRemove []interfaces.StoredObject
 	var itemsToUpdate []interfaces.StoredObject
+	succeeded := 0
+	resultsMutex := sync.Mutex{}
 
-	// Item will be removed from store if:
-	//    - successfully retried
-	//    - max retries exceeded
-	//    - version no longer matches current Pipeline
-	// Item will not be removed if retry failed and more retries available (hit 'continue' above)
-	for _, item := range items {
-		pipeline := sf.runtime.GetPipelineById(item.PipelineId)
-
-		if pipeline == nil {
-			sf.lc.Errorf("Stored data item's pipeline '%s' no longer exists. Removing item from DB", item.PipelineId)
-			itemsToRemove = append(itemsToRemove, item)
-			continue
-		}
+	processGroup := func(group []interfaces.StoredObject) {
+		for _, item := range group {
+			if ctx.Err() != nil {
+				return
+			}
 
-		if item.Version != pipeline.Hash {
-			sf.lc.Error("Stored data item's pipeline Version doesn't match '%s' pipeline's Version. Removing item from DB", item.PipelineId)
-			itemsToRemove = append(itemsToRemove, item)
-			continue
+			outcome, processedItem := sf.processRetryItem(item, config.Writable.StoreAndForward.MaxRetryCount)
+
+			resultsMutex.Lock()
+			switch outcome {
+			case retrySucceeded:
+				itemsToRemove = append(itemsToRemove, processedItem)
+				succeeded++
+			case retryFailedUpdate:
+				itemsToUpdate = append(itemsToUpdate, processedItem)
+			case retryFailedRemove:
+				itemsToRemove = append(itemsToRemove, processedItem)
+			}
+			resultsMutex.Unlock()
 		}
+	}
 
-		if !sf.retryExportFunction(item, pipeline) {
-			item.RetryCount++
-			if config.Writable.StoreAndForward.MaxRetryCount == 0 ||
-				item.RetryCount < config.Writable.StoreAndForward.MaxRetryCount {
-				sf.lc.Tracef("Export retry failed for pipeline '%s'. retries=%d, Incrementing retry count (%s=%s)",
-					item.PipelineId,
-					item.RetryCount,
-					common.CorrelationHeader,
-					item.CorrelationID)
-				itemsToUpdate = append(itemsToUpdate, item)
-				continue
+	concurrency := config.Writable.StoreAndForward.RetryConcurrency
+	if concurrency <= 1 {
+		processGroup(items)
+		return itemsToRemove, itemsToUpdate, succeeded
+	}
+
+	// Items in the same group are retried sequentially, so grouping by pipeline preserves the order of each pipeline's items.
+	var groups [][]interfaces.StoredObject
+	if config.Writable.StoreAndForward.RetryPreserveOrder {
+		groupIndexes := make(map[string]int)
+		for _, item := range items {
+			index, exists := groupIndexes[item.PipelineId]
+			if !exists {
+				index = len(groups)
+				groupIndexes[item.PipelineId] = index
+				groups = append(groups, nil)
 			}
+			groups[index] = append(groups[index], item)
+		}
+	} else {
+		for _, item := range items {
+			groups = append(groups, []interfaces.StoredObject{item})
+		}
+	}
 
-			sf.lc.Tracef("Max retries exceeded for pipeline '%s'. retries=%d, Removing item from DB (%s=%s)",
-				item.PipelineId,
-				item.RetryCount,
-				common.CorrelationHeader,
-				item.CorrelationID)
-			itemsToRemove = append(itemsToRemove, item)
+	sf.lc.Debugf("Retrying %d stored data items in %d groups with concurrency of %d", len(items), len(groups), concurrency)
+
+	// Bound the number of concurrent retries to protect the export destinations
+	semaphore := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for _, group := range groups {
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(group []interfaces.StoredObject) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			processGroup(group)
+		}(group)
+	}
+	wg.Wait()
+
+	return itemsToRemove, itemsToUpdate, succeeded
+}
 
-			// Note that item will be removed for DB below.
-		} else {
-			sf.lc.Tracef("Retry successful for pipeline '%s'. Removing item from DB (%s=%s)",
+// processRetryItem retries a single stored data item and returns the outcome along with the item, which has its
+// retry count incremented if the retry failed.
+// Item will be removed from store if:
+//   - successfully retried
+//   - max retries exceeded
+//   - version no longer matches current Pipeline
+//
+// Item will be updated if retry failed and more retries available
+func (sf *storeForwardInfo) processRetryItem(item interfaces.StoredObject, maxRetryCount int) (retryOutcome, interfaces.StoredObject) {
+	pipeline := sf.runtime.GetPipelineById(item.PipelineId)
+
+	if pipeline == nil {
+		sf.lc.Errorf("Stored data item's pipeline '%s' no longer exists. Removing item from DB", item.PipelineId)
+		sf.handleDeadLetter(item, fmt.Errorf("pipeline '%s' no longer exists", item.PipelineId))
+		return retryFailedRemove, item
+	}
+
+	if item.Version != pipeline.Hash {
+		sf.lc.Errorf("Stored data item's pipeline Version doesn't match '%s' pipeline's Version. Removing item from DB", item.PipelineId)
+		sf.handleDeadLetter(item, fmt.Errorf("pipeline '%s' version no longer matches", item.PipelineId))
+		return retryFailedRemove, item
+	}
+
+	if err := sf.retryExportFunction(item, pipeline); err != nil {
+		item.RetryCount++
+		if maxRetryCount == 0 || item.RetryCount < maxRetryCount {
+			sf.lc.Debugf("Export retry failed for pipeline '%s'. retries=%d, maxRetries=%d, Incrementing retry count (%s=%s): %s",
 				item.PipelineId,
+				item.RetryCount,
+				maxRetryCount,
 				common.CorrelationHeader,
-				item.CorrelationID)
-			itemsToRemove = append(itemsToRemove, item)
+				item.CorrelationID,
+				err.Error())
+			return retryFailedUpdate, item
 		}
+
+		sf.lc.Warnf("Max retries exceeded for pipeline '%s'. retries=%d, Removing item from DB (%s=%s): %s",
+			item.PipelineId,
+			item.RetryCount,
+			common.CorrelationHeader,
+			item.CorrelationID,
+			err.Error())
+		sf.handleDeadLetter(item, err)
+		return retryFailedRemove, item
 	}
 
-	return itemsToRemove, itemsToUpdate
+	sf.lc.Tracef("Retry successful for pipeline '%s'. Removing item from DB (%s=%s)",
+		item.PipelineId,
+		common.CorrelationHeader,
+		item.CorrelationID)
+	return retrySucceeded, item
 }
 
-func (sf *storeForwardInfo) retryExportFunction(item interfaces.StoredObject, pipeline *interfaces.FunctionPipeline) bool {
+func (sf *storeForwardInfo) retryExportFunction(item interfaces.StoredObject, pipeline *interfaces.FunctionPipeline) error {
 	appContext := appfunction.NewContext(item.CorrelationID, sf.dic, "")
 
 	for k, v := range item.ContextData {
@@ -293,12 +431,47 @@ func (sf *storeForwardInfo) retryExportFunction(item interfaces.StoredObject, pi
 		common.CorrelationHeader,
 		appContext.CorrelationID())
 
-	return sf.runtime.ExecutePipeline(
+	if messageError := sf.runtime.ExecutePipeline(
 		item.Payload,
 		appContext,
 		pipeline,
 		item.PipelinePosition,
-		true) == nil
+		true); messageError != nil {
+		return messageError.Err
+	}
+
+	return nil
+}
+
+func (sf *storeForwardInfo) setDeadLetterHandler(handler interfaces.DeadLetterHandler) {
+	sf.handlerMutex.Lock()
+	defer sf.handlerMutex.Unlock()
+
+	sf.deadLetter = handler
+}
+
+// handleDeadLetter passes the item being given up on to the registered dead letter handler, if any.
+// Panics in the handler are recovered so that they don't stop the retry loop.
+func (sf *storeForwardInfo) handleDeadLetter(item interfaces.StoredObject, lastError error) {
+	sf.handlerMutex.Lock()
+	handler := sf.deadLetter
+	sf.handlerMutex.Unlock()
+
+	if handler == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			sf.lc.Errorf("Dead letter handler panicked for stored data item for pipeline '%s' (%s=%s): %v",
+				item.PipelineId,
+				common.CorrelationHeader,
+				item.CorrelationID,
+				r)
+		}
+	}()
+
+	handler(item, lastError)
 }
 
 func (sf *storeForwardInfo) triggerRetry() {
@@ -310,6 +483,6 @@ func (sf *storeForwardInfo) triggerRetry() {
 		}
 
 		sf.lc.Debug("Triggering Store and Forward retry of failed data")
-		sf.retryStoredData(sf.serviceKey)
+		sf.retryStoredData(context.Background(), sf.serviceKey)
 	}
 }
@@ -17,9 +17,15 @@
 package runtime
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
 	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
@@ -147,7 +153,7 @@ func TestProcessRetryItems(t *testing.T) {
 			storedObject := interfaces.NewStoredObject("dummy", []byte(test.ExpectedPayload), pipeline.Id, 2, version, contextData)
 			storedObject.RetryCount = test.RetryCount
 
-			removes, updates := runtime.storeForward.processRetryItems([]interfaces.StoredObject{storedObject})
+			removes, updates, _ := runtime.storeForward.processRetryItems(context.Background(), []interfaces.StoredObject{storedObject})
 			assert.Equal(t, test.TargetTransformWasCalled, targetTransformWasCalled, "Target transform not called")
 			if test.RetryCount != test.ExpectedRetryCount {
 				if assert.True(t, len(updates) > 0, "Remove count not as expected") {
@@ -211,7 +217,7 @@ func TestDoStoreAndForwardRetry(t *testing.T) {
 			_, _ = mockStoreObject(object)
 
 			// Target of this test
-			runtime.storeForward.retryStoredData(serviceKey)
+			runtime.storeForward.retryStoredData(context.Background(), serviceKey)
 
 			objects := mockRetrieveObjects(serviceKey)
 			assert.Equal(t, int64(len(objects)), runtime.storeForward.dataCount.Count())
@@ -226,6 +232,309 @@ func TestDoStoreAndForwardRetry(t *testing.T) {
 	}
 }
 
+func TestStoreAndForwardPoisonRecordEvicted(t *testing.T) {
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+	})
+
+	attempts := 0
+	poisonTransform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		attempts++
+		return false, errors.New("rejected")
+	}
+
+	runtime := NewFunctionPipelineRuntime(serviceKey, nil, updateDicWithMockStoreClient())
+	runtime.SetDefaultFunctionsPipeline([]interfaces.AppFunction{poisonTransform})
+	pipeline := runtime.GetDefaultPipeline()
+	require.NotNil(t, pipeline)
+
+	object := interfaces.NewStoredObject(serviceKey, []byte("poison"), pipeline.Id, 0, pipeline.Hash, nil)
+	_, _ = mockStoreObject(object)
+	runtime.storeForward.dataCount.Inc(1)
+
+	maxRetryCount := container.ConfigurationFrom(dic.Get).Writable.StoreAndForward.MaxRetryCount
+	for i := 1; i < maxRetryCount; i++ {
+		runtime.storeForward.retryStoredData(context.Background(), serviceKey)
+		objects := mockRetrieveObjects(serviceKey)
+		require.Len(t, objects, 1, "record evicted early after %d attempts", i)
+		assert.Equal(t, i, objects[0].RetryCount)
+	}
+
+	runtime.storeForward.retryStoredData(context.Background(), serviceKey)
+
+	assert.Equal(t, maxRetryCount, attempts)
+	assert.Empty(t, mockRetrieveObjects(serviceKey))
+	assert.Equal(t, int64(0), runtime.storeForward.dataCount.Count())
+}
+
+func TestStoreAndForwardDeadLetterHandler(t *testing.T) {
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return logger.NewMockClient()
+		},
+	})
+
+	failureTransform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return false, errors.New("export rejected")
+	}
+
+	maxRetryCount := container.ConfigurationFrom(dic.Get).Writable.StoreAndForward.MaxRetryCount
+
+	tests := []struct {
+		Name               string
+		RetryCount         int
+		BadVersion         bool
+		HandlerPanics      bool
+		ExpectCalled       bool
+		ExpectedRetryCount int
+		ExpectedError      string
+	}{
+		{"Not called when retries remain", 1, false, false, false, 0, ""},
+		{"Called when max retries exceeded", maxRetryCount - 1, false, false, true, maxRetryCount, "export rejected"},
+		{"Called when pipeline version changed", 0, true, false, true, 0, "version no longer matches"},
+		{"Handler panic recovered", maxRetryCount - 1, false, true, true, maxRetryCount, "export rejected"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			runtime := NewFunctionPipelineRuntime(serviceKey, nil, updateDicWithMockStoreClient())
+			runtime.SetDefaultFunctionsPipeline([]interfaces.AppFunction{failureTransform})
+			pipeline := runtime.GetDefaultPipeline()
+			require.NotNil(t, pipeline)
+
+			var called []interfaces.StoredObject
+			var lastErrors []error
+			runtime.SetDeadLetterHandler(func(item interfaces.StoredObject, lastError error) {
+				called = append(called, item)
+				lastErrors = append(lastErrors, lastError)
+				if test.HandlerPanics {
+					panic("handler failed")
+				}
+			})
+
+			version := pipeline.Hash
+			if test.BadVersion {
+				version = "some bad version"
+			}
+
+			object := interfaces.NewStoredObject(serviceKey, []byte("My Payload"), pipeline.Id, 0, version, map[string]string{"x": "y"})
+			object.CorrelationID = "CorrelationID"
+			object.RetryCount = test.RetryCount
+			_, _ = mockStoreObject(object)
+			runtime.storeForward.dataCount.Inc(1)
+
+			runtime.storeForward.retryStoredData(context.Background(), serviceKey)
+
+			if !test.ExpectCalled {
+				assert.Empty(t, called)
+				return
+			}
+
+			require.Len(t, called, 1)
+			assert.Equal(t, []byte("My Payload"), called[0].Payload)
+			assert.Equal(t, pipeline.Id, called[0].PipelineId)
+			assert.Equal(t, "CorrelationID", called[0].CorrelationID)
+			assert.Equal(t, test.ExpectedRetryCount, called[0].RetryCount)
+			require.Error(t, lastErrors[0])
+			assert.Contains(t, lastErrors[0].Error(), test.ExpectedError)
+			assert.Empty(t, mockRetrieveObjects(serviceKey))
+		})
+	}
+}
+
+func TestProcessRetryItemsConcurrency(t *testing.T) {
+	tests := []struct {
+		Name                  string
+		Concurrency           int
+		PreserveOrder         bool
+		ExpectedMaxConcurrent int32
+	}{
+		{"Sequential - not set", 0, false, 1},
+		{"Sequential - 1", 1, false, 1},
+		{"Concurrent - 3", 3, false, 3},
+		{"Concurrent - 3 preserve order", 3, true, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			config := container.ConfigurationFrom(dic.Get)
+			config.Writable.StoreAndForward.RetryConcurrency = test.Concurrency
+			config.Writable.StoreAndForward.RetryPreserveOrder = test.PreserveOrder
+			defer func() {
+				config.Writable.StoreAndForward.RetryConcurrency = 0
+				config.Writable.StoreAndForward.RetryPreserveOrder = false
+			}()
+
+			var active atomic.Int32
+			var maxActive atomic.Int32
+			orderMutex := sync.Mutex{}
+			exportOrder := make(map[string][]string)
+
+			exportTransform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+				current := active.Add(1)
+				defer active.Add(-1)
+				for {
+					previous := maxActive.Load()
+					if current <= previous || maxActive.CompareAndSwap(previous, current) {
+						break
+					}
+				}
+
+				// Give the other workers a chance to start so the concurrency level is reached
+				time.Sleep(50 * time.Millisecond)
+
+				pipelineId, payload, _ := strings.Cut(string(data.([]byte)), ":")
+				orderMutex.Lock()
+				exportOrder[pipelineId] = append(exportOrder[pipelineId], payload)
+				orderMutex.Unlock()
+
+				if strings.HasPrefix(payload, "fail") {
+					return false, errors.New("export failed")
+				}
+				return false, nil
+			}
+
+			runtime := NewFunctionPipelineRuntime(serviceKey, nil, updateDicWithMockStoreClient())
+			require.NoError(t, runtime.AddFunctionsPipeline("pipeline-a", []string{"a"}, []interfaces.AppFunction{exportTransform}))
+			require.NoError(t, runtime.AddFunctionsPipeline("pipeline-b", []string{"b"}, []interfaces.AppFunction{exportTransform}))
+
+			var items []interfaces.StoredObject
+			for _, pipelineId := range []string{"pipeline-a", "pipeline-b"} {
+				pipeline := runtime.GetPipelineById(pipelineId)
+				require.NotNil(t, pipeline)
+				for i, payload := range []string{"success-1", "fail-2", "success-3"} {
+					item := interfaces.NewStoredObject(serviceKey, []byte(pipelineId+":"+payload), pipeline.Id, 0, pipeline.Hash, nil)
+					item.ID = fmt.Sprintf("%s-%d", pipelineId, i)
+					items = append(items, item)
+				}
+			}
+
+			removes, updates, succeeded := runtime.storeForward.processRetryItems(context.Background(), items)
+
+			assert.Equal(t, test.ExpectedMaxConcurrent, maxActive.Load(), "max concurrent retries not as expected")
+			assert.Equal(t, 4, succeeded)
+			assert.Len(t, removes, 4)
+			require.Len(t, updates, 2)
+			for _, item := range updates {
+				assert.True(t, strings.HasSuffix(string(item.Payload), ":fail-2"))
+				assert.Equal(t, 1, item.RetryCount)
+			}
+
+			if test.PreserveOrder || test.Concurrency <= 1 {
+				for _, pipelineId := range []string{"pipeline-a", "pipeline-b"} {
+					assert.Equal(t, []string{"success-1", "fail-2", "success-3"}, exportOrder[pipelineId])
+				}
+			}
+		})
+	}
+}
+
+func TestDrainStoredData(t *testing.T) {
+	payload := []byte("My Payload")
+
+	tests := []struct {
+		Name                string
+		ExportFails         bool
+		ExpectedObjectCount int
+	}{
+		{"Pending record retried successfully", false, 0},
+		{"Pending record retry fails", true, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			exportCalled := false
+			exportTransform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+				exportCalled = true
+				assert.Equal(t, payload, data)
+				if test.ExportFails {
+					return false, errors.New("export failed")
+				}
+				return false, nil
+			}
+
+			runtime := NewFunctionPipelineRuntime(serviceKey, nil, updateDicWithMockStoreClient())
+			runtime.SetDefaultFunctionsPipeline([]interfaces.AppFunction{exportTransform})
+			pipeline := runtime.GetDefaultPipeline()
+			require.NotNil(t, pipeline)
+
+			object := interfaces.NewStoredObject(serviceKey, payload, pipeline.Id, 0, pipeline.Hash, nil)
+			_, _ = mockStoreObject(object)
+			runtime.storeForward.dataCount.Inc(1)
+
+			runtime.DrainStoreAndForward(5 * time.Second)
+
+			assert.True(t, exportCalled, "export not retried during drain")
+			assert.Len(t, mockRetrieveObjects(serviceKey), test.ExpectedObjectCount)
+			assert.Equal(t, int64(test.ExpectedObjectCount), runtime.storeForward.dataCount.Count())
+		})
+	}
+}
+
+func TestDrainStoredDataTimeout(t *testing.T) {
+	release := make(chan struct{})
+	var exportCount atomic.Int32
+
+	blockingTransform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		exportCount.Add(1)
+		<-release
+		return false, nil
+	}
+
+	runtime := NewFunctionPipelineRuntime(serviceKey, nil, updateDicWithMockStoreClient())
+	runtime.SetDefaultFunctionsPipeline([]interfaces.AppFunction{blockingTransform})
+	pipeline := runtime.GetDefaultPipeline()
+	require.NotNil(t, pipeline)
+
+	for i := 0; i < 2; i++ {
+		object := interfaces.NewStoredObject(serviceKey, []byte("My Payload"), pipeline.Id, 0, pipeline.Hash, nil)
+		_, _ = mockStoreObject(object)
+	}
+	runtime.storeForward.dataCount.Inc(2)
+
+	releaseDelay := 300 * time.Millisecond
+	go func() {
+		time.Sleep(releaseDelay)
+		close(release)
+	}()
+
+	start := time.Now()
+	runtime.DrainStoreAndForward(100 * time.Millisecond)
+
+	// The drain waits for the item in flight when the timeout cancels the retry, but doesn't retry any more items
+	assert.GreaterOrEqual(t, time.Since(start), releaseDelay, "drain returned before the retry in flight finished")
+	assert.False(t, runtime.storeForward.retryInProgress.Load())
+	assert.Equal(t, int32(1), exportCount.Load())
+	assert.Len(t, mockRetrieveObjects(serviceKey), 1)
+	assert.Equal(t, int64(1), runtime.storeForward.dataCount.Count())
+}
+
+func TestDrainStoredDataRetryInProgress(t *testing.T) {
+	exportCalled := false
+	exportTransform := func(appContext interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		exportCalled = true
+		return false, nil
+	}
+
+	runtime := NewFunctionPipelineRuntime(serviceKey, nil, updateDicWithMockStoreClient())
+	runtime.SetDefaultFunctionsPipeline([]interfaces.AppFunction{exportTransform})
+	pipeline := runtime.GetDefaultPipeline()
+	require.NotNil(t, pipeline)
+
+	object := interfaces.NewStoredObject(serviceKey, []byte("My Payload"), pipeline.Id, 0, pipeline.Hash, nil)
+	_, _ = mockStoreObject(object)
+
+	runtime.storeForward.retryInProgress.Store(true)
+	defer runtime.storeForward.retryInProgress.Store(false)
+
+	runtime.DrainStoreAndForward(5 * time.Second)
+
+	assert.False(t, exportCalled, "drain retried data while another retry was in progress")
+	assert.Len(t, mockRetrieveObjects(serviceKey), 1)
+}
+
 func TestStoreForLaterRetry(t *testing.T) {
 	payload := []byte("My Payload")
 
@@ -117,7 +117,7 @@ func TestTrigger_responseHandler(t *testing.T) {
 				qos:            qos,
 				retain:         retain,
 				publishTopic:   tt.fields.publishTopic,
-				mqttClient:     client,
+				MqttClient:     client,
 			}
 			if err := trigger.responseHandler(ctx, tt.args.pipeline); (err != nil) != tt.wantErr {
 				t.Errorf("responseHandler() error = %v, wantErr %v", err, tt.wantErr)
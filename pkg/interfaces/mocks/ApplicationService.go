@@ -497,6 +497,11 @@ func (_m *ApplicationService) RegisterCustomTriggerFactory(name string, factory
 	return r0
 }
 
+// RegisterDeadLetterHandler provides a mock function with given fields: handler
+func (_m *ApplicationService) RegisterDeadLetterHandler(handler interfaces.DeadLetterHandler) {
+	_m.Called(handler)
+}
+
 // RegistryClient provides a mock function with given fields:
 func (_m *ApplicationService) RegistryClient() registry.Client {
 	ret := _m.Called()
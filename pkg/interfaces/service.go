@@ -122,6 +122,9 @@ type ApplicationService interface {
 	// to be executed when the incoming topic matches any of the specified topics. The specified topic may contain the '#' wildcard
 	// so that it matches multiple incoming topics. If just "#" is used for the specified topic it will match all incoming
 	// topics and the specified functions pipeline will execute on every message received.
+	// The topics are prefixed with the MessageBus base topic, except when using the External MQTT trigger, in which case
+	// they are matched against the topics received from the external broker as is, i.e. "commands/#" and "telemetry/#".
+	// The received topic is stored in the context storage under RECEIVEDTOPIC.
 	AddFunctionsPipelineForTopics(id string, topic []string, transforms ...AppFunction) error
 	// RemoveAllFunctionPipelines removes all existing function pipelines
 	RemoveAllFunctionPipelines()
@@ -138,6 +141,10 @@ type ApplicationService interface {
 	RegisterCustomTriggerFactory(name string, factory func(TriggerConfig) (Trigger, error)) error
 	// RegisterCustomStoreFactory registers a factory function that can be used to create a custom storage client for the Store & Forward loop.
 	RegisterCustomStoreFactory(name string, factory func(cfg config.Database, cred config.Credentials) (StoreClient, error)) error
+	// RegisterDeadLetterHandler registers a handler which is called with each Store & Forward item that is given up on,
+	// i.e. once it has exceeded the max retries, so failed exports can be kept elsewhere for manual inspection.
+	// Panics in the handler are recovered and logged so they don't stop the Store & Forward retry loop.
+	RegisterDeadLetterHandler(handler DeadLetterHandler)
 	// AddBackgroundPublisher Adds and returns a BackgroundPublisher which is used to publish
 	// asynchronously to the Edgex MessageBus.
 	// Not valid for use with the HTTP or External MQTT triggers *DEPRECATED*
@@ -43,6 +43,12 @@ type StoredObject struct {
 	ContextData map[string]string
 }
 
+// DeadLetterHandler is called when a Store and Forward item is given up on and removed from the store, i.e. the max
+// retries have been exceeded or its pipeline no longer exists or has changed. The item contains the payload,
+// pipeline id and the number of failed retries. lastError is the error from the last retry attempt or the reason
+// the item wasn't retried. The handler may be called concurrently when Store and Forward RetryConcurrency is greater than 1.
+type DeadLetterHandler func(item StoredObject, lastError error)
+
 // NewStoredObject creates a new instance of StoredObject and is the preferred way to create one.
 func NewStoredObject(appServiceKey string, payload []byte, pipelineId string, pipelinePosition int,
 	version string, contextData map[string]string) StoredObject {
@@ -0,0 +1,208 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+)
+
+const aes256KeySize = 32
+
+// EncryptionKeyResolver returns the name of the secret in the Secret Store containing the encryption key for the
+// data, so that the key can be selected per Event, i.e. per tenant in a shared pipeline.
+type EncryptionKeyResolver func(ctx interfaces.AppFunctionContext, data interface{}) (string, error)
+
+type AESGCMProtection struct {
+	SecretName     string
+	SecretValueKey string
+	keyResolver    EncryptionKeyResolver
+}
+
+// NewAESGCMProtection creates, initializes and returns a new instance of AESGCMProtection configured
+// to retrieve the hex encoded 256 bit encryption key from the Secret Store
+func NewAESGCMProtection(secretName string, secretValueKey string) *AESGCMProtection {
+	return &AESGCMProtection{
+		SecretName:     secretName,
+		SecretValueKey: secretValueKey,
+	}
+}
+
+// NewAESGCMProtectionWithKeyResolver creates, initializes and returns a new instance of AESGCMProtection configured
+// to retrieve the hex encoded 256 bit encryption key, at secretValueKey, from the secret selected for each Event by
+// the keyResolver. See EncryptionKeyByTenant for the provided resolver.
+func NewAESGCMProtectionWithKeyResolver(keyResolver EncryptionKeyResolver, secretValueKey string) (*AESGCMProtection, error) {
+	if keyResolver == nil {
+		return nil, errors.New("encryption key resolver must be specified")
+	}
+
+	if len(secretValueKey) == 0 {
+		return nil, errors.New("secret value key of the encryption keys must be specified")
+	}
+
+	return &AESGCMProtection{
+		SecretValueKey: secretValueKey,
+		keyResolver:    keyResolver,
+	}, nil
+}
+
+// EncryptionKeyByTenant returns an EncryptionKeyResolver which selects the secret containing the encryption key from
+// secretNames, keyed by the tenant extracted from the data by tenantExtractor, i.e. RouteByContextValue or
+// RouteByJSONPath. Data for a tenant without a secret isn't encrypted with another tenant's key, but fails.
+func EncryptionKeyByTenant(tenantExtractor RouteKeyExtractor, secretNames map[string]string) EncryptionKeyResolver {
+	return func(ctx interfaces.AppFunctionContext, data interface{}) (string, error) {
+		tenant, err := tenantExtractor(ctx, data)
+		if err != nil {
+			return "", fmt.Errorf("unable to determine tenant: %s", err.Error())
+		}
+
+		secretName, found := secretNames[tenant]
+		if !found || len(secretName) == 0 {
+			return "", fmt.Errorf("no encryption key configured for tenant '%s'", tenant)
+		}
+
+		return secretName, nil
+	}
+}
+
+// Encrypt encrypts a string, []byte, or json.Marshaller type using AES-256-GCM authenticated encryption.
+// The random nonce is prepended to the encrypted data and authentication tag.
+// It will return a Base64 encode []byte of the encrypted data.
+func (protection *AESGCMProtection) Encrypt(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Encrypt in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Encrypting with AES256 GCM in pipeline '%s'", ctx.PipelineId())
+
+	// The key is resolved from the data as received, i.e. the Event, rather than its serialized form
+	secretName, err := protection.resolveSecretName(ctx, data)
+	if err != nil {
+		return false, err
+	}
+
+	byteData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	aead, err := protection.newAEAD(ctx, secretName)
+	if err != nil {
+		return false, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return false, err
+	}
+
+	encrypted := aead.Seal(nonce, nonce, byteData, nil)
+
+	encodedData := []byte(base64.StdEncoding.EncodeToString(encrypted))
+
+	// Set response "content-type" header to "text/plain"
+	ctx.SetResponseContentType(common.ContentTypeText)
+
+	return true, encodedData
+}
+
+// Decrypt decrypts the Base64 encoded data produced by Encrypt, verifying it hasn't been tampered with.
+// When a key resolver is used, it receives the encrypted data, so must select the key from the context.
+// It will return a []byte of the decrypted data.
+func (protection *AESGCMProtection) Decrypt(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Decrypt in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Decrypting with AES256 GCM in pipeline '%s'", ctx.PipelineId())
+
+	byteData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(string(byteData))
+	if err != nil {
+		return false, fmt.Errorf("unable to Base64 decode encrypted data in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	secretName, err := protection.resolveSecretName(ctx, data)
+	if err != nil {
+		return false, err
+	}
+
+	aead, err := protection.newAEAD(ctx, secretName)
+	if err != nil {
+		return false, err
+	}
+
+	if len(encrypted) < aead.NonceSize() {
+		return false, fmt.Errorf("encrypted data is too short to contain the nonce in pipeline '%s'", ctx.PipelineId())
+	}
+
+	nonce, ciphertext := encrypted[:aead.NonceSize()], encrypted[aead.NonceSize():]
+	decrypted, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return false, fmt.Errorf("unable to decrypt data in pipeline '%s', authentication failed: data has been tampered with or the key is incorrect", ctx.PipelineId())
+	}
+
+	return true, decrypted
+}
+
+// resolveSecretName returns the name of the secret containing the encryption key for the data, which is the
+// SecretName unless a key resolver is used.
+func (protection *AESGCMProtection) resolveSecretName(ctx interfaces.AppFunctionContext, data interface{}) (string, error) {
+	if protection.keyResolver == nil {
+		return protection.SecretName, nil
+	}
+
+	secretName, err := protection.keyResolver(ctx, data)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve encryption key in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.LoggingClient().Debugf("Resolved encryption key secret '%s' in pipeline '%s'", secretName, ctx.PipelineId())
+	return secretName, nil
+}
+
+func (protection *AESGCMProtection) newAEAD(ctx interfaces.AppFunctionContext, secretName string) (cipher.AEAD, error) {
+	key, err := getEncryptionKey(ctx, secretName, protection.SecretValueKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clearKey(key)
+
+	if len(key) != aes256KeySize {
+		return nil, fmt.Errorf("AES256 GCM encryption key must be %d bytes, but is %d bytes in pipeline '%s'", aes256KeySize, len(key), ctx.PipelineId())
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.New("unable to create AES cipher: " + err.Error())
+	}
+
+	return cipher.NewGCM(block)
+}
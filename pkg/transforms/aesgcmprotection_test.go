@@ -0,0 +1,247 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces/mocks"
+	bootstrapMocks "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const aesGCMKey = "217A24432646294A404E635266556A586E3272357538782F413F442A472D4B61"
+
+func newAESGCMTestContext(secretName string, secretValueKey string, key string) *mocks.AppFunctionContext {
+	mockSecretProvider := &bootstrapMocks.SecretProvider{}
+	mockSecretProvider.On("GetSecret", secretName, secretValueKey).Return(map[string]string{secretValueKey: key}, nil)
+	ctx := &mocks.AppFunctionContext{}
+	ctx.On("SetResponseContentType", common.ContentTypeText).Return()
+	ctx.On("PipelineId").Return("pipeline-id")
+	ctx.On("LoggingClient").Return(logger.NewMockClient())
+	ctx.On("SecretProvider").Return(mockSecretProvider)
+	return ctx
+}
+
+func TestNewAESGCMProtection(t *testing.T) {
+	secretName := uuid.NewString()
+	secretValueKey := uuid.NewString()
+
+	sut := NewAESGCMProtection(secretName, secretValueKey)
+
+	assert.Equal(t, secretName, sut.SecretName)
+	assert.Equal(t, secretValueKey, sut.SecretValueKey)
+}
+
+func TestAESGCMProtection_RoundTrip(t *testing.T) {
+	secretName := uuid.NewString()
+	secretValueKey := uuid.NewString()
+	ctx := newAESGCMTestContext(secretName, secretValueKey, aesGCMKey)
+
+	protection := NewAESGCMProtection(secretName, secretValueKey)
+
+	continuePipeline, encrypted := protection.Encrypt(ctx, []byte(plainString))
+	require.True(t, continuePipeline)
+	assert.NotContains(t, string(encrypted.([]byte)), plainString)
+
+	// Random nonce results in different encrypted data for the same input
+	continuePipeline, encryptedAgain := protection.Encrypt(ctx, []byte(plainString))
+	require.True(t, continuePipeline)
+	assert.NotEqual(t, encrypted, encryptedAgain)
+
+	continuePipeline, decrypted := protection.Decrypt(ctx, encrypted)
+	require.True(t, continuePipeline)
+	assert.Equal(t, plainString, string(decrypted.([]byte)))
+}
+
+func TestAESGCMProtection_DecryptTampered(t *testing.T) {
+	secretName := uuid.NewString()
+	secretValueKey := uuid.NewString()
+	ctx := newAESGCMTestContext(secretName, secretValueKey, aesGCMKey)
+
+	protection := NewAESGCMProtection(secretName, secretValueKey)
+
+	continuePipeline, encrypted := protection.Encrypt(ctx, []byte(plainString))
+	require.True(t, continuePipeline)
+
+	encryptedBytes, err := base64.StdEncoding.DecodeString(string(encrypted.([]byte)))
+	require.NoError(t, err)
+
+	// Flip a bit in the ciphertext
+	encryptedBytes[len(encryptedBytes)-1] ^= 0x01
+	tampered := []byte(base64.StdEncoding.EncodeToString(encryptedBytes))
+
+	continuePipeline, result := protection.Decrypt(ctx, tampered)
+	require.False(t, continuePipeline)
+	require.Error(t, result.(error))
+	assert.Contains(t, result.(error).Error(), "authentication failed")
+}
+
+func TestAESGCMProtection_Errors(t *testing.T) {
+	secretName := uuid.NewString()
+	secretValueKey := uuid.NewString()
+
+	tests := []struct {
+		Name                 string
+		Key                  string
+		Data                 interface{}
+		Decrypt              bool
+		ExpectedErrorMessage string
+	}{
+		{"Encrypt - no data", aesGCMKey, nil, false, "No Data Received"},
+		{"Encrypt - key wrong size", aesGCMKey[:32], []byte(plainString), false, "must be 32 bytes"},
+		{"Encrypt - key not hex", "not hex", []byte(plainString), false, "invalid byte"},
+		{"Decrypt - no data", aesGCMKey, nil, true, "No Data Received"},
+		{"Decrypt - not base64", aesGCMKey, []byte("not base64!"), true, "unable to Base64 decode"},
+		{"Decrypt - too short", aesGCMKey, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), true, "too short"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := newAESGCMTestContext(secretName, secretValueKey, test.Key)
+			protection := NewAESGCMProtection(secretName, secretValueKey)
+
+			var continuePipeline bool
+			var result interface{}
+			if test.Decrypt {
+				continuePipeline, result = protection.Decrypt(ctx, test.Data)
+			} else {
+				continuePipeline, result = protection.Encrypt(ctx, test.Data)
+			}
+
+			require.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+		})
+	}
+}
+
+func TestAESGCMProtection_DecryptWrongKey(t *testing.T) {
+	secretName := uuid.NewString()
+	secretValueKey := uuid.NewString()
+
+	protection := NewAESGCMProtection(secretName, secretValueKey)
+
+	continuePipeline, encrypted := protection.Encrypt(newAESGCMTestContext(secretName, secretValueKey, aesGCMKey), []byte(plainString))
+	require.True(t, continuePipeline)
+
+	wrongKey := "00112233445566778899AABBCCDDEEFF00112233445566778899AABBCCDDEEFF"
+	continuePipeline, result := protection.Decrypt(newAESGCMTestContext(secretName, secretValueKey, wrongKey), encrypted)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "authentication failed")
+}
+
+const aesGCMTenantBKey = "6B5970337336763979244226452948404D635166546A576E5A7234743777217A"
+
+func newAESGCMTenantTestContext(tenant string) *mocks.AppFunctionContext {
+	mockSecretProvider := &bootstrapMocks.SecretProvider{}
+	mockSecretProvider.On("GetSecret", "tenant-a-keys", "key").Return(map[string]string{"key": aesGCMKey}, nil)
+	mockSecretProvider.On("GetSecret", "tenant-b-keys", "key").Return(map[string]string{"key": aesGCMTenantBKey}, nil)
+	ctx := &mocks.AppFunctionContext{}
+	ctx.On("SetResponseContentType", common.ContentTypeText).Return()
+	ctx.On("PipelineId").Return("pipeline-id")
+	ctx.On("LoggingClient").Return(logger.NewMockClient())
+	ctx.On("SecretProvider").Return(mockSecretProvider)
+	ctx.On("GetValue", "tenant").Return(tenant, len(tenant) > 0)
+	return ctx
+}
+
+func TestNewAESGCMProtectionWithKeyResolver(t *testing.T) {
+	resolver := EncryptionKeyByTenant(RouteByContextValue("tenant"), map[string]string{"tenant-a": "tenant-a-keys"})
+
+	_, err := NewAESGCMProtectionWithKeyResolver(nil, "key")
+	assert.Error(t, err)
+
+	_, err = NewAESGCMProtectionWithKeyResolver(resolver, "")
+	assert.Error(t, err)
+
+	sut, err := NewAESGCMProtectionWithKeyResolver(resolver, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "key", sut.SecretValueKey)
+	assert.NotNil(t, sut.keyResolver)
+}
+
+func TestAESGCMProtection_KeyPerTenant(t *testing.T) {
+	secretNames := map[string]string{
+		"tenant-a": "tenant-a-keys",
+		"tenant-b": "tenant-b-keys",
+	}
+
+	protection, err := NewAESGCMProtectionWithKeyResolver(EncryptionKeyByTenant(RouteByContextValue("tenant"), secretNames), "key")
+	require.NoError(t, err)
+
+	tenantACtx := newAESGCMTenantTestContext("tenant-a")
+	tenantBCtx := newAESGCMTenantTestContext("tenant-b")
+
+	continuePipeline, encryptedA := protection.Encrypt(tenantACtx, []byte(plainString))
+	require.True(t, continuePipeline)
+	continuePipeline, encryptedB := protection.Encrypt(tenantBCtx, []byte(plainString))
+	require.True(t, continuePipeline)
+
+	continuePipeline, decrypted := protection.Decrypt(tenantACtx, encryptedA)
+	require.True(t, continuePipeline)
+	assert.Equal(t, plainString, string(decrypted.([]byte)))
+
+	continuePipeline, decrypted = protection.Decrypt(tenantBCtx, encryptedB)
+	require.True(t, continuePipeline)
+	assert.Equal(t, plainString, string(decrypted.([]byte)))
+
+	// Each tenant's data can only be decrypted with that tenant's key
+	continuePipeline, result := protection.Decrypt(tenantBCtx, encryptedA)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "authentication failed")
+
+	// The same protection with a fixed key for tenant A's secret decrypts tenant A's data
+	continuePipeline, decrypted = NewAESGCMProtection("tenant-a-keys", "key").Decrypt(tenantACtx, encryptedA)
+	require.True(t, continuePipeline)
+	assert.Equal(t, plainString, string(decrypted.([]byte)))
+}
+
+func TestAESGCMProtection_KeyResolverErrors(t *testing.T) {
+	protection, err := NewAESGCMProtectionWithKeyResolver(
+		EncryptionKeyByTenant(RouteByContextValue("tenant"), map[string]string{"tenant-a": "tenant-a-keys"}), "key")
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name                 string
+		Tenant               string
+		ExpectedErrorMessage string
+	}{
+		{"Unknown tenant", "tenant-c", "no encryption key configured for tenant 'tenant-c'"},
+		{"Missing tenant", "", "unable to determine tenant: context value 'tenant' not found"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := newAESGCMTenantTestContext(test.Tenant)
+
+			continuePipeline, result := protection.Encrypt(ctx, []byte(plainString))
+			require.False(t, continuePipeline)
+			assert.Contains(t, result.(error).Error(), "unable to resolve encryption key")
+			assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+
+			continuePipeline, result = protection.Decrypt(ctx, []byte(base64.StdEncoding.EncodeToString([]byte(plainString))))
+			require.False(t, continuePipeline)
+			assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+		})
+	}
+}
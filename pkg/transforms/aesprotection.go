@@ -97,31 +97,36 @@ func (protection *AESProtection) Encrypt(ctx interfaces.AppFunctionContext, data
 }
 
 func (protection *AESProtection) getKey(ctx interfaces.AppFunctionContext) ([]byte, error) {
+	return getEncryptionKey(ctx, protection.SecretName, protection.SecretValueKey)
+}
+
+// getEncryptionKey retrieves the hex encoded encryption key from the Secret Store
+func getEncryptionKey(ctx interfaces.AppFunctionContext, secretName string, secretValueKey string) ([]byte, error) {
 	// If using Secret Store for the encryption key
-	if len(protection.SecretName) != 0 && len(protection.SecretValueKey) != 0 {
+	if len(secretName) != 0 && len(secretValueKey) != 0 {
 		// Note secrets are cached so this call doesn't result in unneeded calls to SecretStore Service and
 		// the cache is invalidated when StoreSecrets is used.
-		secretData, err := ctx.SecretProvider().GetSecret(protection.SecretName, protection.SecretValueKey)
+		secretData, err := ctx.SecretProvider().GetSecret(secretName, secretValueKey)
 		if err != nil {
 			return nil, fmt.Errorf(
 				"unable to retieve encryption key at SecretName=%s and SecretValueKey=%s in pipeline '%s'",
-				protection.SecretName,
-				protection.SecretValueKey,
+				secretName,
+				secretValueKey,
 				ctx.PipelineId())
 		}
 
-		key, ok := secretData[protection.SecretValueKey]
+		key, ok := secretData[secretValueKey]
 		if !ok {
 			return nil, fmt.Errorf(
 				"unable find encryption key in secret data for name=%s in pipeline '%s'",
-				protection.SecretValueKey,
+				secretValueKey,
 				ctx.PipelineId())
 		}
 
 		ctx.LoggingClient().Debugf(
 			"Using encryption key from Secret Store at SecretName=%s & SecretValueKey=%s in pipeline '%s'",
-			protection.SecretName,
-			protection.SecretValueKey,
+			secretName,
+			secretValueKey,
 			ctx.PipelineId())
 
 		return hex.DecodeString(key)
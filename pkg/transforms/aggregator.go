@@ -0,0 +1,262 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// DefaultAggregatorMaxWindows is the maximum number of device and resource windows the Aggregator keeps when no
+// maximum is specified.
+const DefaultAggregatorMaxWindows = 10000
+
+// Keys of the aggregate reading's Object value
+const (
+	AggregateMin   = "min"
+	AggregateMax   = "max"
+	AggregateAvg   = "avg"
+	AggregateCount = "count"
+)
+
+// Aggregator computes the min, max, average and count of the numeric reading values for each device and resource
+// over a time window, emitting the aggregates when the window closes.
+type Aggregator struct {
+	window     time.Duration
+	resources  map[string]bool
+	passRaw    bool
+	maxWindows int
+	mutex      sync.Mutex
+	devices    map[string]map[string]*aggregateWindow
+	count      int
+	lastSweep  time.Time
+}
+
+type aggregateWindow struct {
+	profileName string
+	end         time.Time
+	min         float64
+	max         float64
+	sum         float64
+	count       int64
+}
+
+// NewAggregator creates, initializes and returns a new instance of Aggregator which aggregates the readings for
+// the specified resources, or all numeric readings if resources is empty, over windows of the specified duration.
+// A device's window for a resource starts with the first reading received for it.
+func NewAggregator(window time.Duration, resources []string) (*Aggregator, error) {
+	if window <= 0 {
+		return nil, errors.New("window must be greater than zero")
+	}
+
+	resourceSet := make(map[string]bool, len(resources))
+	for _, name := range resources {
+		if len(name) == 0 {
+			return nil, errors.New("resource names must not be empty")
+		}
+		resourceSet[name] = true
+	}
+
+	return &Aggregator{
+		window:     window,
+		resources:  resourceSet,
+		maxWindows: DefaultAggregatorMaxWindows,
+		devices:    make(map[string]map[string]*aggregateWindow),
+	}, nil
+}
+
+// SetPassRawReadings sets whether the aggregated readings are passed on as well as being aggregated. By default,
+// they are suppressed so only the aggregates are passed on.
+func (aggregator *Aggregator) SetPassRawReadings(passRaw bool) {
+	aggregator.passRaw = passRaw
+}
+
+// SetMaxWindows sets the maximum number of device and resource windows kept, which bounds the memory used.
+// Readings for a device and resource without a window aren't aggregated while the maximum is reached.
+func (aggregator *Aggregator) SetMaxWindows(maxWindows int) {
+	if maxWindows > 0 {
+		aggregator.maxWindows = maxWindows
+	}
+}
+
+// Aggregate adds the Event's readings to their windows. When a device's window has closed, the aggregate is added
+// to the device's next Event as an Object reading for the resource, with AggregateMin, AggregateMax, AggregateAvg
+// and AggregateCount values and the time the window closed as its origin. Aggregated readings are removed from the
+// Event unless SetPassRawReadings is set, and the pipeline is stopped if no readings remain. Since aggregates are
+// emitted with the device's next Event, the windows of a device that stops sending are discarded once a further
+// window has elapsed after they closed.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (aggregator *Aggregator) Aggregate(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Aggregate in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function Aggregate in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	readings, dropped := aggregator.aggregate(event, time.Now())
+	if dropped > 0 {
+		ctx.LoggingClient().Warnf("Maximum of %d aggregation windows reached, %d readings from %s not aggregated in pipeline '%s'",
+			aggregator.maxWindows, dropped, event.DeviceName, ctx.PipelineId())
+	}
+
+	if len(readings) == 0 {
+		ctx.LoggingClient().Debugf("Readings from %s aggregated in pipeline '%s'", event.DeviceName, ctx.PipelineId())
+		return false, nil
+	}
+
+	event.Readings = readings
+	return true, event
+}
+
+// aggregate returns the readings to pass on, which are the aggregates for the device's closed windows and the
+// readings which weren't aggregated, along with the number of readings not aggregated due to the maximum windows.
+func (aggregator *Aggregator) aggregate(event dtos.Event, now time.Time) ([]dtos.BaseReading, int) {
+	aggregator.mutex.Lock()
+	defer aggregator.mutex.Unlock()
+
+	aggregator.evictStale(now)
+
+	readings := aggregator.closeWindows(event.DeviceName, now)
+
+	windows, found := aggregator.devices[event.DeviceName]
+	if !found {
+		windows = make(map[string]*aggregateWindow)
+		aggregator.devices[event.DeviceName] = windows
+	}
+
+	dropped := 0
+	for _, reading := range event.Readings {
+		value, isNumeric := aggregateValue(reading)
+		if !isNumeric || (len(aggregator.resources) > 0 && !aggregator.resources[reading.ResourceName]) {
+			readings = append(readings, reading)
+			continue
+		}
+
+		window, found := windows[reading.ResourceName]
+		if !found {
+			if aggregator.count >= aggregator.maxWindows {
+				dropped++
+				readings = append(readings, reading)
+				continue
+			}
+
+			window = &aggregateWindow{end: now.Add(aggregator.window), min: value, max: value}
+			windows[reading.ResourceName] = window
+			aggregator.count++
+		}
+
+		window.profileName = reading.ProfileName
+		window.min = min(window.min, value)
+		window.max = max(window.max, value)
+		window.sum += value
+		window.count++
+
+		if aggregator.passRaw {
+			readings = append(readings, reading)
+		}
+	}
+
+	if len(windows) == 0 {
+		delete(aggregator.devices, event.DeviceName)
+	}
+
+	return readings, dropped
+}
+
+// closeWindows removes the device's closed windows, returning their aggregate readings ordered by resource name
+func (aggregator *Aggregator) closeWindows(deviceName string, now time.Time) []dtos.BaseReading {
+	windows := aggregator.devices[deviceName]
+
+	var closed []string
+	for resourceName, window := range windows {
+		if !now.Before(window.end) {
+			closed = append(closed, resourceName)
+		}
+	}
+	sort.Strings(closed)
+
+	readings := make([]dtos.BaseReading, 0, len(closed))
+	for _, resourceName := range closed {
+		window := windows[resourceName]
+		reading := dtos.NewObjectReading(window.profileName, deviceName, resourceName, map[string]any{
+			AggregateMin:   window.min,
+			AggregateMax:   window.max,
+			AggregateAvg:   window.sum / float64(window.count),
+			AggregateCount: window.count,
+		})
+		reading.Origin = window.end.UnixNano()
+		readings = append(readings, reading)
+
+		delete(windows, resourceName)
+		aggregator.count--
+	}
+
+	return readings
+}
+
+// evictStale discards the windows which closed more than a window ago, i.e. for devices which stopped sending.
+// The sweep is done at most once per window so that the cost is amortized across Events.
+func (aggregator *Aggregator) evictStale(now time.Time) {
+	if now.Sub(aggregator.lastSweep) < aggregator.window {
+		return
+	}
+
+	for deviceName, windows := range aggregator.devices {
+		for resourceName, window := range windows {
+			if now.Sub(window.end) >= aggregator.window {
+				delete(windows, resourceName)
+				aggregator.count--
+			}
+		}
+
+		if len(windows) == 0 {
+			delete(aggregator.devices, deviceName)
+		}
+	}
+
+	aggregator.lastSweep = now
+}
+
+// aggregateValue returns the reading's value as a float64 and whether it is numeric
+func aggregateValue(reading dtos.BaseReading) (float64, bool) {
+	switch reading.ValueType {
+	case common.ValueTypeFloat32, common.ValueTypeFloat64:
+	default:
+		if _, found := integerBitSizes[reading.ValueType]; !found {
+			return 0, false
+		}
+	}
+
+	value, err := strconv.ParseFloat(reading.Value, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return value, true
+}
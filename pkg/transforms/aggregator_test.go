@@ -0,0 +1,184 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func aggregatorEvent(device string, values map[string]float64) dtos.Event {
+	event := dtos.NewEvent(profileName1, device, sourceName1)
+	for _, resource := range []string{"temperature", "humidity", "pressure"} {
+		if value, found := values[resource]; found {
+			_ = event.AddSimpleReading(resource, common.ValueTypeFloat64, value)
+		}
+	}
+	return event
+}
+
+func TestNewAggregator(t *testing.T) {
+	_, err := NewAggregator(0, nil)
+	assert.Error(t, err)
+
+	_, err = NewAggregator(time.Minute, []string{"temperature", ""})
+	assert.Error(t, err)
+
+	aggregator, err := NewAggregator(time.Minute, []string{"temperature"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultAggregatorMaxWindows, aggregator.maxWindows)
+}
+
+func TestAggregateWindowClose(t *testing.T) {
+	aggregator, err := NewAggregator(time.Minute, []string{"temperature", "humidity"})
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	values := []float64{20, 25, 18, 21}
+	for index, value := range values {
+		readings, dropped := aggregator.aggregate(aggregatorEvent(deviceName1, map[string]float64{
+			"temperature": value,
+			"humidity":    50,
+			"pressure":    1013,
+		}), start.Add(time.Duration(index)*10*time.Second))
+		assert.Zero(t, dropped)
+
+		// Only the pressure reading, which isn't aggregated, is passed on while the window is open
+		require.Len(t, readings, 1)
+		assert.Equal(t, "pressure", readings[0].ResourceName)
+	}
+
+	// The next Event after the window closes emits the aggregates and starts a new window
+	readings, _ := aggregator.aggregate(aggregatorEvent(deviceName1, map[string]float64{"temperature": 30}), start.Add(time.Minute))
+	require.Len(t, readings, 2)
+
+	assert.Equal(t, "humidity", readings[0].ResourceName)
+	assert.Equal(t, map[string]any{AggregateMin: 50.0, AggregateMax: 50.0, AggregateAvg: 50.0, AggregateCount: int64(4)}, readings[0].ObjectValue)
+
+	temperature := readings[1]
+	assert.Equal(t, "temperature", temperature.ResourceName)
+	assert.Equal(t, common.ValueTypeObject, temperature.ValueType)
+	assert.Equal(t, deviceName1, temperature.DeviceName)
+	assert.Equal(t, profileName1, temperature.ProfileName)
+	assert.Equal(t, start.Add(time.Minute).UnixNano(), temperature.Origin)
+	assert.Equal(t, map[string]any{AggregateMin: 18.0, AggregateMax: 25.0, AggregateAvg: 21.0, AggregateCount: int64(4)}, temperature.ObjectValue)
+
+	// The new window only contains the reading received after the previous window closed
+	readings, _ = aggregator.aggregate(aggregatorEvent(deviceName1, map[string]float64{"temperature": 10}), start.Add(2*time.Minute))
+	require.Len(t, readings, 1)
+	assert.Equal(t, map[string]any{AggregateMin: 30.0, AggregateMax: 30.0, AggregateAvg: 30.0, AggregateCount: int64(1)}, readings[0].ObjectValue)
+}
+
+func TestAggregatePerDevice(t *testing.T) {
+	aggregator, err := NewAggregator(time.Minute, nil)
+	require.NoError(t, err)
+	aggregator.SetPassRawReadings(true)
+
+	start := time.Now()
+	readings, _ := aggregator.aggregate(aggregatorEvent("device-a", map[string]float64{"temperature": 1}), start)
+	assert.Len(t, readings, 1, "raw reading is passed on")
+	aggregator.aggregate(aggregatorEvent("device-b", map[string]float64{"temperature": 100}), start)
+	aggregator.aggregate(aggregatorEvent("device-a", map[string]float64{"temperature": 3}), start.Add(time.Second))
+
+	readings, _ = aggregator.aggregate(aggregatorEvent("device-a", map[string]float64{"pressure": 5}), start.Add(time.Minute))
+	require.Len(t, readings, 2)
+	assert.Equal(t, map[string]any{AggregateMin: 1.0, AggregateMax: 3.0, AggregateAvg: 2.0, AggregateCount: int64(2)}, readings[0].ObjectValue)
+	assert.Equal(t, "pressure", readings[1].ResourceName)
+	assert.Equal(t, common.ValueTypeFloat64, readings[1].ValueType)
+
+	readings, _ = aggregator.aggregate(aggregatorEvent("device-b", map[string]float64{}), start.Add(time.Minute))
+	require.Len(t, readings, 1)
+	assert.Equal(t, "device-b", readings[0].DeviceName)
+	assert.Equal(t, map[string]any{AggregateMin: 100.0, AggregateMax: 100.0, AggregateAvg: 100.0, AggregateCount: int64(1)}, readings[0].ObjectValue)
+}
+
+func TestAggregateMemoryBounded(t *testing.T) {
+	aggregator, err := NewAggregator(time.Minute, nil)
+	require.NoError(t, err)
+	aggregator.SetMaxWindows(2)
+
+	start := time.Now()
+	readings, dropped := aggregator.aggregate(aggregatorEvent("device-a", map[string]float64{"temperature": 1, "humidity": 2, "pressure": 3}), start)
+	assert.Equal(t, 1, dropped)
+	require.Len(t, readings, 1)
+	assert.Equal(t, "pressure", readings[0].ResourceName)
+
+	// Windows of devices which stopped sending are discarded a window after they closed, freeing the space
+	readings, dropped = aggregator.aggregate(aggregatorEvent("device-b", map[string]float64{"temperature": 1}), start.Add(2*time.Minute))
+	assert.Zero(t, dropped)
+	assert.Empty(t, readings)
+	assert.Equal(t, 1, aggregator.count)
+	assert.NotContains(t, aggregator.devices, "device-a")
+}
+
+func TestAggregateConcurrent(t *testing.T) {
+	aggregator, err := NewAggregator(time.Hour, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	var wait sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wait.Add(1)
+		go func(value int) {
+			defer wait.Done()
+			aggregator.aggregate(aggregatorEvent(deviceName1, map[string]float64{"temperature": float64(value)}), start)
+		}(i)
+	}
+	wait.Wait()
+
+	readings, _ := aggregator.aggregate(aggregatorEvent(deviceName1, nil), start.Add(time.Hour))
+	require.Len(t, readings, 1)
+	assert.Equal(t, map[string]any{AggregateMin: 1.0, AggregateMax: 100.0, AggregateAvg: 50.5, AggregateCount: int64(100)}, readings[0].ObjectValue)
+}
+
+func TestAggregate(t *testing.T) {
+	aggregator, err := NewAggregator(time.Hour, []string{"temperature"})
+	require.NoError(t, err)
+
+	continuePipeline, result := aggregator.Aggregate(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = aggregator.Aggregate(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+
+	// The pipeline is stopped when all the readings are aggregated
+	continuePipeline, result = aggregator.Aggregate(ctx, aggregatorEvent(deviceName1, map[string]float64{"temperature": 20}))
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+
+	// Non-numeric readings aren't aggregated
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	_ = event.AddSimpleReading("temperature", common.ValueTypeString, "warm")
+	continuePipeline, result = aggregator.Aggregate(ctx, event)
+	require.True(t, continuePipeline)
+	require.Len(t, result.(dtos.Event).Readings, 1)
+	assert.Equal(t, "warm", result.(dtos.Event).Readings[0].Value)
+
+	event = dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	_ = event.AddSimpleReading("count", common.ValueTypeInt32, int32(7))
+	continuePipeline, result = aggregator.Aggregate(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, "7", result.(dtos.Event).Readings[0].Value)
+}
@@ -0,0 +1,590 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snsTypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqsTypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	coreCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	gometrics "github.com/rcrowley/go-metrics"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// awsMaxBatchEntries is the maximum number of messages SQS and SNS accept in a single batch request
+const awsMaxBatchEntries = 10
+
+// AWSSenderOptions contains all options available to the AWSSender
+type AWSSenderOptions struct {
+	// QueueURL is the URL of the SQS queue the messages are sent to. Exactly one of QueueURL or TopicARN must be
+	// specified.
+	QueueURL string
+	// TopicARN is the ARN of the SNS topic the messages are published to. Exactly one of QueueURL or TopicARN must
+	// be specified.
+	TopicARN string
+	// Region is the AWS region of the queue or topic, i.e. "us-east-1"
+	Region string
+	// Endpoint is the optional URL used in place of the default AWS endpoint, i.e. for LocalStack
+	Endpoint string
+	// SecretName is the name of the secret in the SecretStore containing the AWS credentials
+	SecretName string
+	// AccessKeyIDKey is the key for the access key id in the SecretName secret data
+	AccessKeyIDKey string
+	// SecretAccessKeyKey is the key for the secret access key in the SecretName secret data
+	SecretAccessKeyKey string
+	// SessionTokenKey is the optional key for the session token of temporary credentials in the SecretName secret data
+	SessionTokenKey string
+	// MessageAttributes are the String message attributes sent with each message, keyed by attribute name. Each value
+	// is formatted using the AttributeFormatter, i.e. '{devicename}'. Attributes whose value is empty aren't sent.
+	MessageAttributes map[string]string
+	// AttributeFormatter specifies custom formatting behavior to be applied to the MessageAttributes values, i.e.
+	// EventValuesFormatter to use the values from each Event. If nil, the default behavior is to replace
+	// placeholders with the values found in the context storage.
+	AttributeFormatter StringValuesFormatter
+	// ResourceNamesAttribute is the name of the optional message attribute set to the sorted, comma separated
+	// resource names of the Event's readings. Not sent if empty or the data isn't an Event.
+	ResourceNamesAttribute string
+	// BatchSlices enables sending each element of a slice, such as the batched data from Batch with IsEventData
+	// set, as a separate message using batch requests of up to 10 messages. JSON arrays received as a string or
+	// []byte, such as the data persisted for retry, are also split in to their elements. Only the messages which
+	// failed are persisted for retry.
+	BatchSlices bool
+	// Timeout is the time limit for each send request. Zero means no timeout.
+	Timeout time.Duration
+	// PersistOnError enables use of store & forward loop if true
+	PersistOnError bool
+	// ContinueOnSendError allows execution of subsequent chained senders after errors if true
+	ContinueOnSendError bool
+	// ReturnInputData enables chaining multiple senders if true
+	ReturnInputData bool
+	// Context is the parent of the context used for each send request so that in-flight requests are aborted when
+	// it is cancelled, i.e. set to the ApplicationService's AppContext(). Defaults to context.Background() if nil.
+	Context context.Context
+}
+
+// sqsClient is the subset of the SQS client used to send messages
+type sqsClient interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error)
+}
+
+// snsClient is the subset of the SNS client used to publish messages
+type snsClient interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+	PublishBatch(ctx context.Context, params *sns.PublishBatchInput, optFns ...func(*sns.Options)) (*sns.PublishBatchOutput, error)
+}
+
+// awsClient is the SQS or SNS client used for a send, only one of which is set
+type awsClient struct {
+	sqs sqsClient
+	sns snsClient
+}
+
+// awsMessage is a message body and its String message attributes
+type awsMessage struct {
+	body       string
+	attributes map[string]string
+}
+
+// AWSSender sends the pipeline data as messages to an SQS queue or publishes them to an SNS topic. The client is
+// reused for all messages and is re-created when the secrets have been updated.
+type AWSSender struct {
+	options              AWSSenderOptions
+	sqsClient            sqsClient
+	snsClient            snsClient
+	clientLock           sync.Mutex
+	clientSecretsUpdated time.Time
+	newSQSClient         func(config aws.Config, endpoint string) sqsClient
+	newSNSClient         func(config aws.Config, endpoint string) snsClient
+	awsSizeMetrics       gometrics.Histogram
+	awsErrorMetric       gometrics.Counter
+}
+
+// NewAWSSender creates, initializes and returns a new instance of AWSSender
+func NewAWSSender(options AWSSenderOptions) (*AWSSender, error) {
+	if (len(options.QueueURL) > 0) == (len(options.TopicARN) > 0) {
+		return nil, errors.New("exactly one of QueueURL or TopicARN must be specified")
+	}
+
+	if len(options.Region) == 0 {
+		return nil, errors.New("region must be specified")
+	}
+
+	if len(options.SecretName) == 0 || len(options.AccessKeyIDKey) == 0 || len(options.SecretAccessKeyKey) == 0 {
+		return nil, errors.New("SecretName, AccessKeyIDKey & SecretAccessKeyKey must be specified")
+	}
+
+	if options.PersistOnError && options.ContinueOnSendError {
+		return nil, errors.New("persistOnError & continueOnSendError can not both be set to true")
+	}
+
+	if options.ContinueOnSendError && !options.ReturnInputData {
+		return nil, errors.New("continueOnSendError can only be used in conjunction returnInputData")
+	}
+
+	return &AWSSender{
+		options:        options,
+		newSQSClient:   newSQSClient,
+		newSNSClient:   newSNSClient,
+		awsErrorMetric: gometrics.NewCounter(),
+		awsSizeMetrics: gometrics.NewHistogram(gometrics.NewUniformSample(internal.MetricsReservoirSize)),
+	}, nil
+}
+
+func newSQSClient(config aws.Config, endpoint string) sqsClient {
+	return sqs.NewFromConfig(config, func(options *sqs.Options) {
+		if len(endpoint) > 0 {
+			options.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+}
+
+func newSNSClient(config aws.Config, endpoint string) snsClient {
+	return sns.NewFromConfig(config, func(options *sns.Options) {
+		if len(endpoint) > 0 {
+			options.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+}
+
+// AWSSend sends the data from the previous function as a message to the SQS queue or SNS topic, or as a message per
+// element when BatchSlices is set and a slice is received. The input data is passed to the next function when
+// ReturnInputData is set, otherwise there is no data for the next function.
+// If no previous function exists, then the event that triggered the pipeline will be used.
+func (sender *AWSSender) AWSSend(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function AWSSend in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	items := []interface{}{data}
+	if sender.options.BatchSlices {
+		items = splitBatchItems(data)
+	}
+
+	messages := make([]awsMessage, 0, len(items))
+	for _, item := range items {
+		message, err := sender.createMessage(ctx, item)
+		if err != nil {
+			return false, fmt.Errorf("in pipeline '%s', unable to create AWS message: %s", ctx.PipelineId(), err.Error())
+		}
+
+		messages = append(messages, message)
+	}
+
+	destination := sender.options.QueueURL
+	if len(destination) == 0 {
+		destination = sender.options.TopicARN
+	}
+
+	tag := map[string]string{"destination": destination}
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.AwsExportErrorsName, destination) },
+		func() any { return sender.awsErrorMetric },
+		tag)
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.AwsExportSizeName, destination) },
+		func() any { return sender.awsSizeMetrics },
+		tag)
+
+	failed, err := sender.send(ctx, messages)
+	if err != nil {
+		sender.awsErrorMetric.Inc(1)
+		err = fmt.Errorf("in pipeline '%s', AWS export to %s failed: %w", ctx.PipelineId(), destination, err)
+
+		// If continuing on send error then can't be persisting on error since Store and Forward retries starting
+		// with the function that failed and stopped the execution of the pipeline.
+		if !sender.options.ContinueOnSendError {
+			if sender.options.PersistOnError {
+				sender.setRetryData(ctx, messages, failed)
+			}
+			return false, err
+		}
+
+		ctx.LoggingClient().Errorf("Continuing pipeline on error in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		return true, data
+	}
+
+	// Data successfully sent, so retry any failed data, if Store and Forward enabled and data has been saved
+	if sender.options.PersistOnError {
+		ctx.TriggerRetryFailedData()
+	}
+
+	exportDataBytes := 0
+	for _, message := range messages {
+		exportDataBytes += len(message.body)
+	}
+	sender.awsSizeMetrics.Update(int64(exportDataBytes))
+
+	ctx.LoggingClient().Debugf("Sent %d messages with %d bytes of data to %s in pipeline '%s'", len(messages), exportDataBytes, destination, ctx.PipelineId())
+	ctx.LoggingClient().Tracef("Data exported to AWS in pipeline '%s': %s=%s", ctx.PipelineId(), coreCommon.CorrelationHeader, ctx.CorrelationID())
+
+	if sender.options.ReturnInputData {
+		return true, data
+	}
+
+	return true, nil
+}
+
+// splitBatchItems returns the elements of a slice, other than []byte, or of a JSON array received as a string or
+// []byte. Any other data is returned as the only item.
+func splitBatchItems(data interface{}) []interface{} {
+	var jsonData []byte
+	switch input := data.(type) {
+	case string:
+		jsonData = []byte(input)
+	case []byte:
+		jsonData = input
+	default:
+		value := reflect.ValueOf(data)
+		if value.Kind() != reflect.Slice {
+			return []interface{}{data}
+		}
+
+		items := make([]interface{}, value.Len())
+		for index := range items {
+			items[index] = value.Index(index).Interface()
+		}
+		return items
+	}
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal(jsonData, &elements); err != nil {
+		return []interface{}{data}
+	}
+
+	items := make([]interface{}, len(elements))
+	for index, element := range elements {
+		items[index] = []byte(element)
+	}
+	return items
+}
+
+// createMessage creates the message for the data with the formatted message attributes
+func (sender *AWSSender) createMessage(ctx interfaces.AppFunctionContext, data interface{}) (awsMessage, error) {
+	body, err := util.CoerceType(data)
+	if err != nil {
+		return awsMessage{}, err
+	}
+
+	message := awsMessage{body: string(body), attributes: make(map[string]string)}
+	for name, format := range sender.options.MessageAttributes {
+		value, err := sender.options.AttributeFormatter.invoke(format, ctx, data)
+		if err != nil {
+			return awsMessage{}, fmt.Errorf("message attribute '%s' formatting failed: %s", name, err.Error())
+		}
+
+		if len(value) > 0 {
+			message.attributes[name] = value
+		}
+	}
+
+	if event, ok := data.(dtos.Event); ok && len(sender.options.ResourceNamesAttribute) > 0 && len(event.Readings) > 0 {
+		resourceNames := make(map[string]bool)
+		for _, reading := range event.Readings {
+			resourceNames[reading.ResourceName] = true
+		}
+
+		names := make([]string, 0, len(resourceNames))
+		for name := range resourceNames {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		message.attributes[sender.options.ResourceNamesAttribute] = strings.Join(names, ",")
+	}
+
+	return message, nil
+}
+
+// send sends the messages, in batches when there is more than one, returning the indexes of the messages which
+// failed to send along with the error
+func (sender *AWSSender) send(ctx interfaces.AppFunctionContext, messages []awsMessage) ([]int, error) {
+	client, err := sender.getClient(ctx)
+	if err != nil {
+		return allIndexes(len(messages)), err
+	}
+
+	requestContext := sender.options.Context
+	if requestContext == nil {
+		requestContext = context.Background()
+	}
+
+	if len(messages) == 1 {
+		if sender.options.Timeout > 0 {
+			var cancel context.CancelFunc
+			requestContext, cancel = context.WithTimeout(requestContext, sender.options.Timeout)
+			defer cancel()
+		}
+
+		if err := sender.sendMessage(requestContext, client, messages[0]); err != nil {
+			return []int{0}, err
+		}
+
+		return nil, nil
+	}
+
+	var failed []int
+	var errs []string
+	for start := 0; start < len(messages); start += awsMaxBatchEntries {
+		end := start + awsMaxBatchEntries
+		if end > len(messages) {
+			end = len(messages)
+		}
+
+		batchFailed, err := sender.sendBatchWithTimeout(requestContext, client, messages[start:end])
+		for _, index := range batchFailed {
+			failed = append(failed, start+index)
+		}
+
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return failed, fmt.Errorf("%d of %d messages failed: %s", len(failed), len(messages), strings.Join(errs, "; "))
+	}
+
+	return nil, nil
+}
+
+func (sender *AWSSender) sendBatchWithTimeout(requestContext context.Context, client awsClient, messages []awsMessage) ([]int, error) {
+	if sender.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		requestContext, cancel = context.WithTimeout(requestContext, sender.options.Timeout)
+		defer cancel()
+	}
+
+	return sender.sendBatch(requestContext, client, messages)
+}
+
+// sendMessage sends a single message to the queue or topic
+func (sender *AWSSender) sendMessage(requestContext context.Context, client awsClient, message awsMessage) error {
+	if client.sqs != nil {
+		_, err := client.sqs.SendMessage(requestContext, &sqs.SendMessageInput{
+			QueueUrl:          aws.String(sender.options.QueueURL),
+			MessageBody:       aws.String(message.body),
+			MessageAttributes: sqsMessageAttributes(message.attributes),
+		})
+		return err
+	}
+
+	_, err := client.sns.Publish(requestContext, &sns.PublishInput{
+		TopicArn:          aws.String(sender.options.TopicARN),
+		Message:           aws.String(message.body),
+		MessageAttributes: snsMessageAttributes(message.attributes),
+	})
+	return err
+}
+
+// sendBatch sends up to awsMaxBatchEntries messages in a single request. Each entry's id is its index in the batch.
+func (sender *AWSSender) sendBatch(requestContext context.Context, client awsClient, messages []awsMessage) ([]int, error) {
+	var failedEntries []batchFailure
+	if client.sqs != nil {
+		entries := make([]sqsTypes.SendMessageBatchRequestEntry, len(messages))
+		for index, message := range messages {
+			entries[index] = sqsTypes.SendMessageBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(index)),
+				MessageBody:       aws.String(message.body),
+				MessageAttributes: sqsMessageAttributes(message.attributes),
+			}
+		}
+
+		output, err := client.sqs.SendMessageBatch(requestContext, &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(sender.options.QueueURL),
+			Entries:  entries,
+		})
+		if err != nil {
+			return allIndexes(len(messages)), err
+		}
+
+		for _, entry := range output.Failed {
+			failedEntries = append(failedEntries, batchFailure{id: aws.ToString(entry.Id), code: aws.ToString(entry.Code), message: aws.ToString(entry.Message)})
+		}
+	} else {
+		entries := make([]snsTypes.PublishBatchRequestEntry, len(messages))
+		for index, message := range messages {
+			entries[index] = snsTypes.PublishBatchRequestEntry{
+				Id:                aws.String(strconv.Itoa(index)),
+				Message:           aws.String(message.body),
+				MessageAttributes: snsMessageAttributes(message.attributes),
+			}
+		}
+
+		output, err := client.sns.PublishBatch(requestContext, &sns.PublishBatchInput{
+			TopicArn:                   aws.String(sender.options.TopicARN),
+			PublishBatchRequestEntries: entries,
+		})
+		if err != nil {
+			return allIndexes(len(messages)), err
+		}
+
+		for _, entry := range output.Failed {
+			failedEntries = append(failedEntries, batchFailure{id: aws.ToString(entry.Id), code: aws.ToString(entry.Code), message: aws.ToString(entry.Message)})
+		}
+	}
+
+	if len(failedEntries) == 0 {
+		return nil, nil
+	}
+
+	failed := make([]int, 0, len(failedEntries))
+	errs := make([]string, 0, len(failedEntries))
+	for _, entry := range failedEntries {
+		index, err := strconv.Atoi(entry.id)
+		if err != nil || index < 0 || index >= len(messages) {
+			return allIndexes(len(messages)), fmt.Errorf("unexpected failed batch entry id '%s'", entry.id)
+		}
+
+		failed = append(failed, index)
+		errs = append(errs, fmt.Sprintf("%s: %s", entry.code, entry.message))
+	}
+
+	sort.Ints(failed)
+	return failed, errors.New(strings.Join(errs, ", "))
+}
+
+// batchFailure is a failed entry from an SQS or SNS batch request
+type batchFailure struct {
+	id      string
+	code    string
+	message string
+}
+
+func allIndexes(count int) []int {
+	indexes := make([]int, count)
+	for index := range indexes {
+		indexes[index] = index
+	}
+	return indexes
+}
+
+func sqsMessageAttributes(attributes map[string]string) map[string]sqsTypes.MessageAttributeValue {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	values := make(map[string]sqsTypes.MessageAttributeValue, len(attributes))
+	for name, value := range attributes {
+		values[name] = sqsTypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	return values
+}
+
+func snsMessageAttributes(attributes map[string]string) map[string]snsTypes.MessageAttributeValue {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	values := make(map[string]snsTypes.MessageAttributeValue, len(attributes))
+	for name, value := range attributes {
+		values[name] = snsTypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+	}
+	return values
+}
+
+// setRetryData persists the failed messages, as is for a single message and as a JSON array otherwise so only
+// those messages are re-sent when retried with BatchSlices set
+func (sender *AWSSender) setRetryData(ctx interfaces.AppFunctionContext, messages []awsMessage, failed []int) {
+	if len(failed) == 1 {
+		// A message which is itself a JSON array would be split when retried, so is persisted in an array
+		body := []byte(messages[failed[0]].body)
+		var elements []json.RawMessage
+		if !sender.options.BatchSlices || json.Unmarshal(body, &elements) != nil {
+			ctx.SetRetryData(body)
+			return
+		}
+	}
+
+	elements := make([]json.RawMessage, 0, len(failed))
+	for _, index := range failed {
+		elements = append(elements, json.RawMessage(messages[index].body))
+	}
+
+	retryData, err := json.Marshal(elements)
+	if err != nil {
+		ctx.LoggingClient().Errorf("Unable to persist failed AWS messages for retry in pipeline '%s', messages must be JSON: %s", ctx.PipelineId(), err.Error())
+		return
+	}
+
+	ctx.SetRetryData(retryData)
+}
+
+// getClient returns the SQS or SNS client, creating it on first use and re-creating it if the secrets have been
+// updated since it was created. The client is returned rather than used from the sender so that sends in flight
+// keep using the client they started with when it is re-created.
+func (sender *AWSSender) getClient(ctx interfaces.AppFunctionContext) (awsClient, error) {
+	sender.clientLock.Lock()
+	defer sender.clientLock.Unlock()
+
+	secretProvider := ctx.SecretProvider()
+	if (sender.sqsClient != nil || sender.snsClient != nil) && !sender.clientSecretsUpdated.Before(secretProvider.SecretsLastUpdated()) {
+		return awsClient{sqs: sender.sqsClient, sns: sender.snsClient}, nil
+	}
+
+	keys := []string{sender.options.AccessKeyIDKey, sender.options.SecretAccessKeyKey}
+	if len(sender.options.SessionTokenKey) > 0 {
+		keys = append(keys, sender.options.SessionTokenKey)
+	}
+
+	secrets, err := secretProvider.GetSecret(sender.options.SecretName, keys...)
+	if err != nil {
+		return awsClient{}, fmt.Errorf("unable to retrieve AWS credentials from secret '%s': %s", sender.options.SecretName, err.Error())
+	}
+
+	credentials := aws.Credentials{
+		AccessKeyID:     secrets[sender.options.AccessKeyIDKey],
+		SecretAccessKey: secrets[sender.options.SecretAccessKeyKey],
+		SessionToken:    secrets[sender.options.SessionTokenKey],
+		Source:          "SecretStore",
+	}
+
+	config := aws.Config{
+		Region: sender.options.Region,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return credentials, nil
+		}),
+	}
+
+	if len(sender.options.QueueURL) > 0 {
+		sender.sqsClient = sender.newSQSClient(config, sender.options.Endpoint)
+	} else {
+		sender.snsClient = sender.newSNSClient(config, sender.options.Endpoint)
+	}
+
+	sender.clientSecretsUpdated = time.Now()
+	return awsClient{sqs: sender.sqsClient, sns: sender.snsClient}, nil
+}
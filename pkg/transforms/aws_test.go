@@ -0,0 +1,413 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqsTypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+const (
+	testQueueURL = "https://sqs.us-east-1.amazonaws.com/123456789012/edgex"
+	testTopicARN = "arn:aws:sns:us-east-1:123456789012:edgex"
+)
+
+// mockSQSClient records the messages sent, failing with err if set and failing the batch entries in failedIds
+type mockSQSClient struct {
+	messages  []*sqs.SendMessageInput
+	batches   []*sqs.SendMessageBatchInput
+	err       error
+	failedIds map[string]bool
+}
+
+func (client *mockSQSClient) SendMessage(ctx context.Context, params *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	if client.err != nil {
+		return nil, client.err
+	}
+
+	client.messages = append(client.messages, params)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func (client *mockSQSClient) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	if client.err != nil {
+		return nil, client.err
+	}
+
+	client.batches = append(client.batches, params)
+	output := &sqs.SendMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		if client.failedIds[aws.ToString(entry.Id)] {
+			output.Failed = append(output.Failed, sqsTypes.BatchResultErrorEntry{Id: entry.Id, Code: aws.String("InternalError"), Message: aws.String("try again")})
+		}
+	}
+	return output, nil
+}
+
+// mockSNSClient records the messages published, failing with err if set
+type mockSNSClient struct {
+	messages []*sns.PublishInput
+	batches  []*sns.PublishBatchInput
+	err      error
+}
+
+func (client *mockSNSClient) Publish(ctx context.Context, params *sns.PublishInput, _ ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	if client.err != nil {
+		return nil, client.err
+	}
+
+	client.messages = append(client.messages, params)
+	return &sns.PublishOutput{}, nil
+}
+
+func (client *mockSNSClient) PublishBatch(ctx context.Context, params *sns.PublishBatchInput, _ ...func(*sns.Options)) (*sns.PublishBatchOutput, error) {
+	if client.err != nil {
+		return nil, client.err
+	}
+
+	client.batches = append(client.batches, params)
+	return &sns.PublishBatchOutput{}, nil
+}
+
+// newAWSTestContext returns a context whose SecretProvider holds the AWS credentials, updated at secretsUpdated
+func newAWSTestContext(secretsUpdated *time.Time) *appfunction.Context {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "aws", "access-key-id", "secret-access-key").
+		Return(map[string]string{"access-key-id": "AKIAEXAMPLE", "secret-access-key": "example-secret"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(func() time.Time { return *secretsUpdated })
+
+	return appfunction.NewContext("123", di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	}), "")
+}
+
+func newTestAWSSender(t *testing.T, options AWSSenderOptions, sqsMock *mockSQSClient, snsMock *mockSNSClient) (*AWSSender, *[]aws.Config) {
+	options.Region = "us-east-1"
+	options.SecretName = "aws"
+	options.AccessKeyIDKey = "access-key-id"
+	options.SecretAccessKeyKey = "secret-access-key"
+
+	sender, err := NewAWSSender(options)
+	require.NoError(t, err)
+
+	var configs []aws.Config
+	sender.newSQSClient = func(config aws.Config, _ string) sqsClient {
+		configs = append(configs, config)
+		return sqsMock
+	}
+	sender.newSNSClient = func(config aws.Config, _ string) snsClient {
+		configs = append(configs, config)
+		return snsMock
+	}
+
+	return sender, &configs
+}
+
+func newAWSTestEvent(deviceName string, resourceNames ...string) dtos.Event {
+	event := dtos.NewEvent(profileName1, deviceName, sourceName1)
+	for _, resourceName := range resourceNames {
+		_ = event.AddSimpleReading(resourceName, common.ValueTypeInt32, int32(1))
+	}
+	return event
+}
+
+func TestNewAWSSender(t *testing.T) {
+	valid := func(options AWSSenderOptions) AWSSenderOptions {
+		options.Region = "us-east-1"
+		options.SecretName = "aws"
+		options.AccessKeyIDKey = "access-key-id"
+		options.SecretAccessKeyKey = "secret-access-key"
+		return options
+	}
+
+	tests := []struct {
+		Name        string
+		Options     AWSSenderOptions
+		ExpectError bool
+	}{
+		{"Valid SQS", valid(AWSSenderOptions{QueueURL: testQueueURL}), false},
+		{"Valid SNS", valid(AWSSenderOptions{TopicARN: testTopicARN}), false},
+		{"Missing queue and topic", valid(AWSSenderOptions{}), true},
+		{"Both queue and topic", valid(AWSSenderOptions{QueueURL: testQueueURL, TopicARN: testTopicARN}), true},
+		{"Missing region", AWSSenderOptions{QueueURL: testQueueURL, SecretName: "aws", AccessKeyIDKey: "id", SecretAccessKeyKey: "key"}, true},
+		{"Missing secret", AWSSenderOptions{QueueURL: testQueueURL, Region: "us-east-1"}, true},
+		{"Persist and continue", valid(AWSSenderOptions{QueueURL: testQueueURL, PersistOnError: true, ContinueOnSendError: true, ReturnInputData: true}), true},
+		{"Continue without return input", valid(AWSSenderOptions{QueueURL: testQueueURL, ContinueOnSendError: true}), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender, err := NewAWSSender(test.Options)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, sender)
+		})
+	}
+}
+
+func TestAWSSendSQS(t *testing.T) {
+	secretsUpdated := time.Now().Add(-time.Hour)
+	sendCtx := newAWSTestContext(&secretsUpdated)
+	sendCtx.AddValue("site", "plant-1")
+
+	sqsMock := &mockSQSClient{}
+	sender, configs := newTestAWSSender(t, AWSSenderOptions{
+		QueueURL:               testQueueURL,
+		MessageAttributes:      map[string]string{"deviceName": "{deviceName}", "site": "{site}", "empty": ""},
+		AttributeFormatter:     EventValuesFormatter,
+		ResourceNamesAttribute: "resourceNames",
+		ReturnInputData:        true,
+	}, sqsMock, nil)
+
+	event := newAWSTestEvent("sensor-1", "temperature", "humidity", "temperature")
+	continuePipeline, result := sender.AWSSend(sendCtx, event)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, event, result)
+
+	require.Len(t, sqsMock.messages, 1)
+	message := sqsMock.messages[0]
+	assert.Equal(t, testQueueURL, aws.ToString(message.QueueUrl))
+
+	expectedBody, err := json.Marshal(event)
+	require.NoError(t, err)
+	assert.Equal(t, string(expectedBody), aws.ToString(message.MessageBody))
+
+	attributes := make(map[string]string)
+	for name, value := range message.MessageAttributes {
+		assert.Equal(t, "String", aws.ToString(value.DataType))
+		attributes[name] = aws.ToString(value.StringValue)
+	}
+	assert.Equal(t, map[string]string{"deviceName": "sensor-1", "site": "plant-1", "resourceNames": "humidity,temperature"}, attributes)
+
+	// The client is created with the credentials from the SecretStore and reused until the secrets are updated
+	require.Len(t, *configs, 1)
+	assert.Equal(t, "us-east-1", (*configs)[0].Region)
+	credentials, err := (*configs)[0].Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "AKIAEXAMPLE", credentials.AccessKeyID)
+	assert.Equal(t, "example-secret", credentials.SecretAccessKey)
+
+	_, _ = sender.AWSSend(sendCtx, event)
+	assert.Len(t, *configs, 1)
+
+	secretsUpdated = time.Now().Add(time.Second)
+	_, _ = sender.AWSSend(sendCtx, event)
+	assert.Len(t, *configs, 2)
+	assert.Len(t, sqsMock.messages, 3)
+}
+
+func TestAWSSendSNS(t *testing.T) {
+	secretsUpdated := time.Now().Add(-time.Hour)
+	sendCtx := newAWSTestContext(&secretsUpdated)
+	sendCtx.AddValue(interfaces.DEVICENAME, "sensor-1")
+
+	snsMock := &mockSNSClient{}
+	sender, _ := newTestAWSSender(t, AWSSenderOptions{
+		TopicARN:          testTopicARN,
+		MessageAttributes: map[string]string{"deviceName": "{devicename}"},
+	}, nil, snsMock)
+
+	continuePipeline, result := sender.AWSSend(sendCtx, msgStr)
+	require.True(t, continuePipeline, result)
+	assert.Nil(t, result)
+
+	require.Len(t, snsMock.messages, 1)
+	message := snsMock.messages[0]
+	assert.Equal(t, testTopicARN, aws.ToString(message.TopicArn))
+	assert.Equal(t, msgStr, aws.ToString(message.Message))
+	require.Contains(t, message.MessageAttributes, "deviceName")
+	assert.Equal(t, "sensor-1", aws.ToString(message.MessageAttributes["deviceName"].StringValue))
+}
+
+func TestAWSSendBatch(t *testing.T) {
+	secretsUpdated := time.Now().Add(-time.Hour)
+	sendCtx := newAWSTestContext(&secretsUpdated)
+
+	var events []dtos.Event
+	for i := 0; i < 12; i++ {
+		events = append(events, newAWSTestEvent(fmt.Sprintf("device-%d", i), "temperature"))
+	}
+
+	t.Run("SQS", func(t *testing.T) {
+		sqsMock := &mockSQSClient{}
+		sender, _ := newTestAWSSender(t, AWSSenderOptions{
+			QueueURL:           testQueueURL,
+			MessageAttributes:  map[string]string{"deviceName": "{deviceName}"},
+			AttributeFormatter: EventValuesFormatter,
+			BatchSlices:        true,
+		}, sqsMock, nil)
+
+		continuePipeline, result := sender.AWSSend(sendCtx, events)
+		require.True(t, continuePipeline, result)
+		assert.Empty(t, sqsMock.messages)
+
+		// The events are sent in batches of up to 10 messages, each with the attributes for its event
+		require.Len(t, sqsMock.batches, 2)
+		assert.Len(t, sqsMock.batches[0].Entries, 10)
+		assert.Len(t, sqsMock.batches[1].Entries, 2)
+		for batchIndex, batch := range sqsMock.batches {
+			assert.Equal(t, testQueueURL, aws.ToString(batch.QueueUrl))
+			for entryIndex, entry := range batch.Entries {
+				event := events[batchIndex*10+entryIndex]
+				expectedBody, err := json.Marshal(event)
+				require.NoError(t, err)
+				assert.Equal(t, string(expectedBody), aws.ToString(entry.MessageBody))
+				assert.Equal(t, event.DeviceName, aws.ToString(entry.MessageAttributes["deviceName"].StringValue))
+			}
+		}
+	})
+
+	t.Run("SNS", func(t *testing.T) {
+		snsMock := &mockSNSClient{}
+		sender, _ := newTestAWSSender(t, AWSSenderOptions{TopicARN: testTopicARN, BatchSlices: true}, nil, snsMock)
+
+		continuePipeline, result := sender.AWSSend(sendCtx, events[:3])
+		require.True(t, continuePipeline, result)
+		require.Len(t, snsMock.batches, 1)
+		assert.Equal(t, testTopicARN, aws.ToString(snsMock.batches[0].TopicArn))
+		assert.Len(t, snsMock.batches[0].PublishBatchRequestEntries, 3)
+	})
+
+	t.Run("JSON array", func(t *testing.T) {
+		sqsMock := &mockSQSClient{}
+		sender, _ := newTestAWSSender(t, AWSSenderOptions{QueueURL: testQueueURL, BatchSlices: true}, sqsMock, nil)
+
+		continuePipeline, result := sender.AWSSend(sendCtx, []byte(`[{"a":1}, {"b":2}]`))
+		require.True(t, continuePipeline, result)
+		require.Len(t, sqsMock.batches, 1)
+		require.Len(t, sqsMock.batches[0].Entries, 2)
+		assert.Equal(t, `{"a":1}`, aws.ToString(sqsMock.batches[0].Entries[0].MessageBody))
+		assert.Equal(t, `{"b":2}`, aws.ToString(sqsMock.batches[0].Entries[1].MessageBody))
+	})
+}
+
+func TestAWSSendBatchPartialFailure(t *testing.T) {
+	secretsUpdated := time.Now().Add(-time.Hour)
+	sendCtx := newAWSTestContext(&secretsUpdated)
+
+	items := [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`), []byte(`{"c":3}`)}
+
+	tests := []struct {
+		Name              string
+		FailedIds         map[string]bool
+		ExpectedRetryData string
+		ExpectedResent    []string
+	}{
+		{"One failed", map[string]bool{"1": true}, `{"b":2}`, []string{`{"b":2}`}},
+		{"Two failed", map[string]bool{"0": true, "2": true}, `[{"a":1},{"c":3}]`, []string{`{"a":1}`, `{"c":3}`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sqsMock := &mockSQSClient{failedIds: test.FailedIds}
+			sender, _ := newTestAWSSender(t, AWSSenderOptions{QueueURL: testQueueURL, BatchSlices: true, PersistOnError: true}, sqsMock, nil)
+
+			continuePipeline, result := sender.AWSSend(sendCtx, items)
+			require.False(t, continuePipeline)
+			assert.Contains(t, result.(error).Error(), fmt.Sprintf("%d of 3 messages failed", len(test.FailedIds)))
+			assert.Contains(t, result.(error).Error(), "InternalError: try again")
+			assert.Equal(t, test.ExpectedRetryData, string(sendCtx.RetryData()))
+
+			// Only the failed messages are re-sent when the persisted data is retried
+			retryMock := &mockSQSClient{}
+			sender.sqsClient = retryMock
+			continuePipeline, result = sender.AWSSend(sendCtx, sendCtx.RetryData())
+			require.True(t, continuePipeline, result)
+
+			var resent []string
+			for _, message := range retryMock.messages {
+				resent = append(resent, aws.ToString(message.MessageBody))
+			}
+			for _, batch := range retryMock.batches {
+				for _, entry := range batch.Entries {
+					resent = append(resent, aws.ToString(entry.MessageBody))
+				}
+			}
+			assert.Equal(t, test.ExpectedResent, resent)
+		})
+	}
+}
+
+func TestAWSSendErrors(t *testing.T) {
+	secretsUpdated := time.Now().Add(-time.Hour)
+	sendCtx := newAWSTestContext(&secretsUpdated)
+	sendErr := errors.New("queue does not exist")
+
+	tests := []struct {
+		Name                 string
+		Options              AWSSenderOptions
+		Data                 interface{}
+		ExpectedContinue     bool
+		ExpectedRetryData    bool
+		ExpectedErrorMessage string
+	}{
+		{"Send failed", AWSSenderOptions{}, msgStr, false, false, "queue does not exist"},
+		{"Send failed persisted", AWSSenderOptions{PersistOnError: true}, msgStr, false, true, "queue does not exist"},
+		{"Send failed continued", AWSSenderOptions{ContinueOnSendError: true, ReturnInputData: true}, msgStr, true, false, ""},
+		{"Batch failed persisted", AWSSenderOptions{PersistOnError: true, BatchSlices: true}, []string{`"a"`, `"b"`}, false, true, "queue does not exist"},
+		{"Missing attribute value", AWSSenderOptions{MessageAttributes: map[string]string{"site": "{bogus}"}}, msgStr, false, false, "message attribute 'site' formatting failed"},
+		{"No data", AWSSenderOptions{}, nil, false, false, "No Data Received"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sendCtx.SetRetryData(nil)
+
+			test.Options.QueueURL = testQueueURL
+			sender, _ := newTestAWSSender(t, test.Options, &mockSQSClient{err: sendErr}, nil)
+
+			continuePipeline, result := sender.AWSSend(sendCtx, test.Data)
+			require.Equal(t, test.ExpectedContinue, continuePipeline)
+			assert.Equal(t, test.ExpectedRetryData, sendCtx.RetryData() != nil)
+			if test.ExpectedContinue {
+				assert.Equal(t, test.Data, result)
+				assert.Equal(t, int64(1), sender.awsErrorMetric.Count())
+				return
+			}
+
+			assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// Base64 encodes and decodes data using base64
+type Base64 struct {
+	encoding *base64.Encoding
+}
+
+// NewBase64 creates, initializes and returns a new instance of Base64 which uses the standard base64 alphabet,
+// or the URL and filename safe alphabet if urlSafe is true. Both alphabets use padding.
+func NewBase64(urlSafe bool) *Base64 {
+	encoding := base64.StdEncoding
+	if urlSafe {
+		encoding = base64.URLEncoding
+	}
+
+	return &Base64{encoding: encoding}
+}
+
+// EncodeBase64 encodes data received as either a string, []byte, or json.Marshaller and returns the base64 encoded
+// string as a []byte. It will return an error and stop the pipeline if no data is received.
+func (b64 *Base64) EncodeBase64(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function EncodeBase64 in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Encoding data to base64 in pipeline '%s'", ctx.PipelineId())
+
+	rawData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	encodedData := make([]byte, b64.encoding.EncodedLen(len(rawData)))
+	b64.encoding.Encode(encodedData, rawData)
+
+	return true, encodedData
+}
+
+// DecodeBase64 decodes base64 data received as either a string or []byte and returns the decoded []byte. Leading
+// and trailing whitespace is ignored. It will return an error and stop the pipeline if no data is received or the
+// data isn't valid base64 for the alphabet used.
+func (b64 *Base64) DecodeBase64(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function DecodeBase64 in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Decoding data from base64 in pipeline '%s'", ctx.PipelineId())
+
+	encodedData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	encodedData = bytes.TrimSpace(encodedData)
+	decodedData := make([]byte, b64.encoding.DecodedLen(len(encodedData)))
+	length, err := b64.encoding.Decode(decodedData, encodedData)
+	if err != nil {
+		return false, fmt.Errorf("function DecodeBase64 in pipeline '%s': unable to decode base64 data: %s", ctx.PipelineId(), err.Error())
+	}
+
+	return true, decodedData[:length]
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64RoundTrip(t *testing.T) {
+	// These bytes encode to '+' and '/' in the standard alphabet and '-' and '_' in the URL safe alphabet
+	rawData := []byte{0xfb, 0xff, 0xbf, 0x00, 0x01, 0x02}
+
+	tests := []struct {
+		Name            string
+		UrlSafe         bool
+		ExpectedEncoded string
+	}{
+		{"Standard", false, "+/+/AAEC"},
+		{"URL safe", true, "-_-_AAEC"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			b64 := NewBase64(test.UrlSafe)
+
+			continuePipeline, encoded := b64.EncodeBase64(ctx, rawData)
+			require.True(t, continuePipeline, encoded)
+			assert.Equal(t, []byte(test.ExpectedEncoded), encoded)
+
+			continuePipeline, decoded := b64.DecodeBase64(ctx, encoded)
+			require.True(t, continuePipeline, decoded)
+			assert.Equal(t, rawData, decoded)
+		})
+	}
+}
+
+func TestBase64EncodeString(t *testing.T) {
+	continuePipeline, encoded := NewBase64(false).EncodeBase64(ctx, "hello")
+	require.True(t, continuePipeline, encoded)
+	assert.Equal(t, []byte("aGVsbG8="), encoded)
+}
+
+func TestBase64DecodeWhitespace(t *testing.T) {
+	continuePipeline, decoded := NewBase64(false).DecodeBase64(ctx, "aGVsbG8=\n")
+	require.True(t, continuePipeline, decoded)
+	assert.Equal(t, []byte("hello"), decoded)
+}
+
+func TestBase64DecodeInvalid(t *testing.T) {
+	tests := []struct {
+		Name    string
+		UrlSafe bool
+		Data    interface{}
+	}{
+		{"Not base64", false, "not base64!"},
+		{"Bad padding", false, "aGVsbG8"},
+		{"URL safe data with standard alphabet", false, "-_-_AAEC"},
+		{"Standard data with URL safe alphabet", true, "+/+/AAEC"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := NewBase64(test.UrlSafe).DecodeBase64(ctx, test.Data)
+			require.False(t, continuePipeline)
+			require.NotNil(t, result)
+			assert.Contains(t, result.(error).Error(), "unable to decode base64 data")
+		})
+	}
+}
+
+func TestBase64NoData(t *testing.T) {
+	b64 := NewBase64(false)
+
+	continuePipeline, result := b64.EncodeBase64(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = b64.DecodeBase64(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+}
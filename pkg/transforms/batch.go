@@ -36,21 +36,50 @@ const (
 	BatchByCountOnly = iota
 	BatchByTimeOnly
 	BatchByTimeAndCount
+	BatchBySizeOnly
 )
 
 type atomicBatchData struct {
 	mutex sync.Mutex
 	data  [][]byte
+	size  int
 }
 
 func (d *atomicBatchData) append(toBeAdded []byte) [][]byte {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
 	d.data = append(d.data, toBeAdded)
+	d.size += len(toBeAdded)
 	result := d.data
 	return result
 }
 
+// appendBySize appends the data and returns the batched data to send once the threshold is reached, otherwise nil.
+// Data which would take the batch over the threshold is not added, instead the current batch is returned and
+// the data starts the next batch. This is done while locked so the threshold check and reset are atomic.
+func (d *atomicBatchData) appendBySize(toBeAdded []byte, byteThreshold int) [][]byte {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.data) > 0 && d.size+len(toBeAdded) > byteThreshold {
+		result := d.data
+		d.data = [][]byte{toBeAdded}
+		d.size = len(toBeAdded)
+		return result
+	}
+
+	d.data = append(d.data, toBeAdded)
+	d.size += len(toBeAdded)
+	if d.size < byteThreshold {
+		return nil
+	}
+
+	result := d.data
+	d.data = nil
+	d.size = 0
+	return result
+}
+
 func (d *atomicBatchData) all() [][]byte {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
@@ -58,10 +87,15 @@ func (d *atomicBatchData) all() [][]byte {
 	return result
 }
 
-func (d *atomicBatchData) removeAll() {
+// takeAll returns the batched data and empties the batch while locked, so data appended concurrently is either
+// returned or left for the next batch, but never lost.
+func (d *atomicBatchData) takeAll() [][]byte {
 	d.mutex.Lock()
 	defer d.mutex.Unlock()
+	result := d.data
 	d.data = nil
+	d.size = 0
+	return result
 }
 
 func (d *atomicBatchData) length() int {
@@ -78,6 +112,7 @@ type BatchConfig struct {
 	timeInterval   string
 	parsedDuration time.Duration
 	batchThreshold int
+	byteThreshold  int
 	batchMode      BatchMode
 	batchData      atomicBatchData
 	timerActive    common.AtomicBool
@@ -127,6 +162,22 @@ func NewBatchByTimeAndCount(timeInterval string, batchThreshold int) (*BatchConf
 	return &config, nil
 }
 
+// NewBatchBySize create, initializes  and returns a new instance for BatchConfig
+// The batched data is sent once its total size reaches byteThreshold. Data which would take the batch over the
+// byteThreshold starts the next batch, so a batch is only larger than byteThreshold when it contains a single item.
+func NewBatchBySize(byteThreshold int) (*BatchConfig, error) {
+	if byteThreshold <= 0 {
+		return nil, fmt.Errorf("byte threshold must be greater than zero, got %d", byteThreshold)
+	}
+
+	config := BatchConfig{
+		byteThreshold: byteThreshold,
+		batchMode:     BatchBySizeOnly,
+	}
+
+	return &config, nil
+}
+
 // Batch ...
 func (batch *BatchConfig) Batch(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
 	if data == nil {
@@ -139,6 +190,17 @@ func (batch *BatchConfig) Batch(ctx interfaces.AppFunctionContext, data interfac
 	if err != nil {
 		return false, err
 	}
+
+	if batch.batchMode == BatchBySizeOnly {
+		batchedData := batch.batchData.appendBySize(byteData, batch.byteThreshold)
+		if batchedData == nil {
+			return false, nil
+		}
+
+		ctx.LoggingClient().Debugf("Batch size has been reached in pipeline '%s'", ctx.PipelineId())
+		return batch.prepareBatchedData(ctx, batchedData)
+	}
+
 	// always append data
 	batch.batchData.append(byteData)
 
@@ -177,34 +239,49 @@ func (batch *BatchConfig) Batch(ctx interfaces.AppFunctionContext, data interfac
 
 	ctx.LoggingClient().Debugf("Forwarding Batched Data in pipeline '%s'", ctx.PipelineId())
 	// we've met the threshold, lets clear out the buffer and send it forward in the pipeline
-	if batch.batchData.length() > 0 {
-		var resultData interface{}
-		copyOfData := batch.batchData.all()
-		resultData = copyOfData
-		if batch.IsEventData {
-			ctx.LoggingClient().Debug("Marshaling batched data to []Event")
-			var events []dtos.Event
-			for _, data := range copyOfData {
-				event := dtos.Event{}
-				if err := json.Unmarshal(data, &event); err != nil {
-					return false, fmt.Errorf("unable to marshal batched data to slice of Events in pipeline '%s': %s", ctx.PipelineId(), err.Error())
-				}
-				events = append(events, event)
-			}
+	if batchedData := batch.batchData.takeAll(); len(batchedData) > 0 {
+		return batch.prepareBatchedData(ctx, batchedData)
+	}
 
-			resultData = events
-		} else if batch.MergeOnSend {
-			var mergedData []byte
-			for _, data := range copyOfData {
-				mergedData = append(mergedData, data...)
+	return false, nil
+}
+
+// Flush forces the currently batched data to be sent forward regardless of the batch thresholds, leaving the batch empty.
+// Returns false and nil if there is no batched data. Called by the SDK for the configurable Batch function during
+// graceful shutdown and can be called from a custom function so partially accumulated batches are not lost.
+func (batch *BatchConfig) Flush(ctx interfaces.AppFunctionContext) (bool, interface{}) {
+	batchedData := batch.batchData.takeAll()
+	if len(batchedData) == 0 {
+		return false, nil
+	}
+
+	ctx.LoggingClient().Debugf("Flushing %d batched items in pipeline '%s'", len(batchedData), ctx.PipelineId())
+	return batch.prepareBatchedData(ctx, batchedData)
+}
+
+// prepareBatchedData converts the batched data to the type sent forward in the pipeline
+func (batch *BatchConfig) prepareBatchedData(ctx interfaces.AppFunctionContext, batchedData [][]byte) (bool, interface{}) {
+	var resultData interface{} = batchedData
+	if batch.IsEventData {
+		ctx.LoggingClient().Debug("Marshaling batched data to []Event")
+		var events []dtos.Event
+		for _, data := range batchedData {
+			event := dtos.Event{}
+			if err := json.Unmarshal(data, &event); err != nil {
+				return false, fmt.Errorf("unable to marshal batched data to slice of Events in pipeline '%s': %s", ctx.PipelineId(), err.Error())
 			}
+			events = append(events, event)
+		}
 
-			resultData = mergedData
+		resultData = events
+	} else if batch.MergeOnSend {
+		var mergedData []byte
+		for _, data := range batchedData {
+			mergedData = append(mergedData, data...)
 		}
 
-		batch.batchData.removeAll()
-		return true, resultData
+		resultData = mergedData
 	}
 
-	return false, nil
+	return true, resultData
 }
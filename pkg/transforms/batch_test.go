@@ -273,3 +273,168 @@ func TestBatch_MergeOnSend(t *testing.T) {
 	require.NotNil(t, actual)
 	assert.Equal(t, expected, string(actual))
 }
+
+func TestNewBatchBySizeInvalidThreshold(t *testing.T) {
+	_, err := NewBatchBySize(0)
+	require.Error(t, err)
+}
+
+func TestBatchInSizeMode(t *testing.T) {
+	bs, err := NewBatchBySize(10)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name             string
+		Data             string
+		ExpectedContinue bool
+		ExpectedBatch    []string
+		ExpectedBuffered int
+	}{
+		{"Below threshold", "1234", false, nil, 1},
+		{"Still below threshold", "123", false, nil, 2},
+		{"Reaches threshold", "123", true, []string{"1234", "123", "123"}, 0},
+		{"Below threshold after reset", "12345", false, nil, 1},
+		{"Would exceed threshold, starts next batch", "123456", true, []string{"12345"}, 1},
+		{"Larger than threshold, sent on its own", "123456789012", true, []string{"123456"}, 1},
+		{"Next item sends large item on its own", "1", true, []string{"123456789012"}, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := bs.Batch(ctx, test.Data)
+			require.Equal(t, test.ExpectedContinue, continuePipeline)
+			assert.Len(t, bs.batchData.all(), test.ExpectedBuffered)
+
+			if !test.ExpectedContinue {
+				assert.Nil(t, result)
+				return
+			}
+
+			batched, ok := result.([][]byte)
+			require.True(t, ok)
+			require.Len(t, batched, len(test.ExpectedBatch))
+			for i, expected := range test.ExpectedBatch {
+				assert.Equal(t, expected, string(batched[i]))
+			}
+		})
+	}
+}
+
+func TestBatchInSizeModeLargeItemOnEmptyBatch(t *testing.T) {
+	bs, err := NewBatchBySize(10)
+	require.NoError(t, err)
+
+	continuePipeline, result := bs.Batch(ctx, "123456789012")
+	require.True(t, continuePipeline)
+	assert.Equal(t, [][]byte{[]byte("123456789012")}, result)
+	assert.Len(t, bs.batchData.all(), 0)
+}
+
+func TestBatchInSizeModeConcurrent(t *testing.T) {
+	const threshold = 100
+	const itemCount = 1000
+
+	bs, err := NewBatchBySize(threshold)
+	require.NoError(t, err)
+
+	var mutex sync.Mutex
+	var batchedItems int
+	wg := sync.WaitGroup{}
+	for i := 0; i < itemCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			continuePipeline, result := bs.Batch(ctx, "1234567")
+			if !continuePipeline {
+				return
+			}
+
+			batched := result.([][]byte)
+			size := 0
+			for _, item := range batched {
+				size += len(item)
+			}
+			assert.LessOrEqual(t, size, threshold)
+
+			mutex.Lock()
+			batchedItems += len(batched)
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Every item is either in a sent batch or still buffered, none are lost or duplicated
+	assert.Equal(t, itemCount, batchedItems+len(bs.batchData.all()))
+}
+
+func TestBatchFlush(t *testing.T) {
+	tests := []struct {
+		Name        string
+		MergeOnSend bool
+		Expected    interface{}
+	}{
+		{"Not merged", false, [][]byte{[]byte(dataToBatch[0]), []byte(dataToBatch[1])}},
+		{"Merged", true, []byte(dataToBatch[0] + dataToBatch[1])},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			bs, err := NewBatchByCount(10)
+			require.NoError(t, err)
+			bs.MergeOnSend = test.MergeOnSend
+
+			continuePipeline, _ := bs.Batch(ctx, []byte(dataToBatch[0]))
+			require.False(t, continuePipeline)
+			continuePipeline, _ = bs.Batch(ctx, []byte(dataToBatch[1]))
+			require.False(t, continuePipeline)
+
+			continuePipeline, result := bs.Flush(ctx)
+			require.True(t, continuePipeline)
+			assert.Equal(t, test.Expected, result)
+			assert.Len(t, bs.batchData.all(), 0, "Records should have been cleared")
+
+			// Nothing left to flush
+			continuePipeline, result = bs.Flush(ctx)
+			assert.False(t, continuePipeline)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestBatchFlushConcurrentWithBatch(t *testing.T) {
+	const itemCount = 1000
+
+	bs, err := NewBatchByCount(itemCount * 2)
+	require.NoError(t, err)
+
+	var mutex sync.Mutex
+	var flushedItems int
+	flush := func() {
+		continuePipeline, result := bs.Flush(ctx)
+		if continuePipeline {
+			mutex.Lock()
+			flushedItems += len(result.([][]byte))
+			mutex.Unlock()
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < itemCount; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			bs.Batch(ctx, []byte(dataToBatch[0]))
+		}()
+		go func() {
+			defer wg.Done()
+			flush()
+		}()
+	}
+	wg.Wait()
+
+	flush()
+
+	// Every item is flushed exactly once and the batch is left empty
+	assert.Equal(t, itemCount, flushedItems)
+	assert.Len(t, bs.batchData.all(), 0)
+}
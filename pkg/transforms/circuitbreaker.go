@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+
+	gometrics "github.com/rcrowley/go-metrics"
+)
+
+// DefaultCircuitBreakerCooldown is how long the HTTPSender's circuit breaker stays open before allowing a probe
+// request when no cooldown is specified.
+const DefaultCircuitBreakerCooldown = 30 * time.Second
+
+// Circuit breaker states, as reported by the HttpExportCircuitState metric
+const (
+	circuitClosed int64 = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker stops sends being attempted for a cooldown period after a number of consecutive failures. Once the
+// cooldown has elapsed, a single probe send is allowed, which closes the breaker if it succeeds and re-opens it
+// for another cooldown period if it fails.
+type circuitBreaker struct {
+	threshold    int
+	cooldown     time.Duration
+	lock         sync.Mutex
+	state        int64
+	failures     int
+	openedAt     time.Time
+	probing      bool
+	now          func() time.Time
+	stateMetric  gometrics.Gauge
+	openedMetric gometrics.Counter
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if cooldown <= 0 {
+		cooldown = DefaultCircuitBreakerCooldown
+	}
+
+	return &circuitBreaker{
+		threshold:    threshold,
+		cooldown:     cooldown,
+		state:        circuitClosed,
+		now:          time.Now,
+		stateMetric:  gometrics.NewGauge(),
+		openedMetric: gometrics.NewCounter(),
+	}
+}
+
+// allow returns whether a send may be attempted. When the cooldown has elapsed the breaker is half-opened and only
+// the first caller is allowed to send, as the probe, until its result is recorded.
+func (breaker *circuitBreaker) allow(ctx interfaces.AppFunctionContext) bool {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+
+	switch breaker.state {
+	case circuitOpen:
+		if breaker.now().Sub(breaker.openedAt) < breaker.cooldown {
+			return false
+		}
+
+		breaker.setState(circuitHalfOpen)
+		ctx.LoggingClient().Infof("HTTP export circuit breaker half-open in pipeline '%s', sending probe request", ctx.PipelineId())
+		breaker.probing = true
+		return true
+	case circuitHalfOpen:
+		if breaker.probing {
+			return false
+		}
+
+		breaker.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record records the result of an allowed send, opening the breaker once the threshold of consecutive failures is
+// reached or the probe fails, and closing it when the probe succeeds.
+func (breaker *circuitBreaker) record(ctx interfaces.AppFunctionContext, success bool) {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+
+	breaker.probing = false
+
+	if success {
+		breaker.failures = 0
+		if breaker.state != circuitClosed {
+			breaker.setState(circuitClosed)
+			ctx.LoggingClient().Infof("HTTP export circuit breaker closed in pipeline '%s'", ctx.PipelineId())
+		}
+		return
+	}
+
+	breaker.failures++
+	if breaker.state == circuitHalfOpen || (breaker.state == circuitClosed && breaker.failures >= breaker.threshold) {
+		breaker.openedAt = breaker.now()
+		breaker.setState(circuitOpen)
+		breaker.openedMetric.Inc(1)
+		ctx.LoggingClient().Warnf("HTTP export circuit breaker opened in pipeline '%s' after %d consecutive failures, sends are skipped for %s",
+			ctx.PipelineId(), breaker.failures, breaker.cooldown)
+	}
+}
+
+// release releases the probe without recording a result, i.e. when the send was cancelled or couldn't be attempted,
+// so that another probe can be sent.
+func (breaker *circuitBreaker) release() {
+	breaker.lock.Lock()
+	defer breaker.lock.Unlock()
+
+	breaker.probing = false
+}
+
+func (breaker *circuitBreaker) setState(state int64) {
+	breaker.state = state
+	breaker.stateMetric.Update(state)
+}
@@ -0,0 +1,154 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/google/uuid"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+const (
+	// CloudEventSpecVersion is the version of the CloudEvents specification produced by ConvertToCloudEvent
+	CloudEventSpecVersion = "1.0"
+	// DefaultCloudEventType is the CloudEvent type used by ConvertToCloudEvent when none is specified
+	DefaultCloudEventType = "org.edgexfoundry.event"
+	// ContentTypeCloudEventJSON is the mime type for the structured mode CloudEvent produced by ConvertToCloudEvent
+	ContentTypeCloudEventJSON = "application/cloudevents+json"
+	// CloudEventHeaderPrefix is the prefix of the context keys, and HTTP header names, for the binary mode
+	// CloudEvent attributes stored by ConvertToCloudEvent
+	CloudEventHeaderPrefix = "ce-"
+)
+
+// CloudEvent converts EdgeX Events to CNCF CloudEvents envelopes
+type CloudEvent struct {
+	source     string
+	eventType  string
+	binaryMode bool
+}
+
+// cloudEventEnvelope is the structured mode JSON representation of a CloudEvent
+type cloudEventEnvelope struct {
+	SpecVersion     string     `json:"specversion"`
+	Id              string     `json:"id"`
+	Source          string     `json:"source"`
+	Type            string     `json:"type"`
+	Subject         string     `json:"subject,omitempty"`
+	Time            string     `json:"time,omitempty"`
+	DataContentType string     `json:"datacontenttype"`
+	Data            dtos.Event `json:"data"`
+}
+
+// NewCloudEvent creates, initializes and returns a new instance of CloudEvent which produces CloudEvents with the
+// specified source and type. DefaultCloudEventType is used if eventType is empty.
+func NewCloudEvent(source string, eventType string) (*CloudEvent, error) {
+	if len(source) == 0 {
+		return nil, errors.New("CloudEvent source must be specified")
+	}
+
+	if len(eventType) == 0 {
+		eventType = DefaultCloudEventType
+	}
+
+	return &CloudEvent{
+		source:    source,
+		eventType: eventType,
+	}, nil
+}
+
+// SetBinaryMode sets whether the CloudEvent is produced in binary mode, where the Event JSON is the output and the
+// CloudEvent attributes are stored in the context for HTTPSender to send as headers, rather than structured mode.
+func (cloudEvent *CloudEvent) SetBinaryMode(enabled bool) {
+	cloudEvent.binaryMode = enabled
+}
+
+// ConvertToCloudEvent converts an EdgeX Event to a CloudEvent with the Event JSON as the data. The Event Id is used as
+// the CloudEvent id, or a new UUID if the Event doesn't have one, the Event's DeviceName as the subject and the
+// Event's Origin as the time.
+// In structured mode the JSON encoded CloudEvent is returned. In binary mode the JSON encoded Event is returned and
+// the CloudEvent attributes are stored in the context with keys of the attribute names prefixed with
+// CloudEventHeaderPrefix, i.e. ce-id, which HTTPSender sends as headers when CloudEventHeaders is set.
+// It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (cloudEvent *CloudEvent) ConvertToCloudEvent(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function ConvertToCloudEvent in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function ConvertToCloudEvent in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Converting to CloudEvent in pipeline '%s'", ctx.PipelineId())
+
+	envelope := cloudEventEnvelope{
+		SpecVersion:     CloudEventSpecVersion,
+		Id:              event.Id,
+		Source:          cloudEvent.source,
+		Type:            cloudEvent.eventType,
+		Subject:         event.DeviceName,
+		DataContentType: common.ContentTypeJSON,
+		Data:            event,
+	}
+
+	if len(envelope.Id) == 0 {
+		envelope.Id = uuid.NewString()
+	}
+
+	if event.Origin != 0 {
+		envelope.Time = time.Unix(0, event.Origin).UTC().Format(time.RFC3339Nano)
+	}
+
+	if cloudEvent.binaryMode {
+		result, err := json.Marshal(event)
+		if err != nil {
+			return false, fmt.Errorf("unable to marshal Event to JSON in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		}
+
+		attributes := map[string]string{
+			"specversion": envelope.SpecVersion,
+			"id":          envelope.Id,
+			"source":      envelope.Source,
+			"type":        envelope.Type,
+			"subject":     envelope.Subject,
+			"time":        envelope.Time,
+		}
+		for name, value := range attributes {
+			if len(value) > 0 {
+				ctx.AddValue(CloudEventHeaderPrefix+name, value)
+			}
+		}
+
+		ctx.SetResponseContentType(common.ContentTypeJSON)
+		return true, result
+	}
+
+	result, err := json.Marshal(envelope)
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal CloudEvent to JSON in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.SetResponseContentType(ContentTypeCloudEventJSON)
+	return true, result
+}
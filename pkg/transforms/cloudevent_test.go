@@ -0,0 +1,142 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+)
+
+func TestNewCloudEvent(t *testing.T) {
+	cloudEvent, err := NewCloudEvent("/edgex/app", "")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCloudEventType, cloudEvent.eventType)
+
+	cloudEvent, err = NewCloudEvent("/edgex/app", "com.example.reading")
+	require.NoError(t, err)
+	assert.Equal(t, "com.example.reading", cloudEvent.eventType)
+
+	_, err = NewCloudEvent("", "com.example.reading")
+	require.Error(t, err)
+}
+
+func TestConvertToCloudEventStructured(t *testing.T) {
+	origin := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	event := dtos.NewEvent("profile1", deviceName1, "source1")
+	event.Origin = origin.UnixNano()
+	require.NoError(t, event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(21)))
+
+	cloudEvent, err := NewCloudEvent("/edgex/app", "com.example.reading")
+	require.NoError(t, err)
+
+	continuePipeline, result := cloudEvent.ConvertToCloudEvent(ctx, event)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, ContentTypeCloudEventJSON, ctx.ResponseContentType())
+
+	var attributes map[string]interface{}
+	require.NoError(t, json.Unmarshal(result.([]byte), &attributes))
+
+	for _, name := range []string{"specversion", "id", "source", "type", "time", "datacontenttype", "subject"} {
+		require.IsType(t, "", attributes[name], "attribute '%s' must be a string", name)
+	}
+	assert.Equal(t, CloudEventSpecVersion, attributes["specversion"])
+	assert.Equal(t, event.Id, attributes["id"])
+	assert.Equal(t, "/edgex/app", attributes["source"])
+	assert.Equal(t, "com.example.reading", attributes["type"])
+	assert.Equal(t, deviceName1, attributes["subject"])
+	assert.Equal(t, common.ContentTypeJSON, attributes["datacontenttype"])
+
+	eventTime, err := time.Parse(time.RFC3339, attributes["time"].(string))
+	require.NoError(t, err)
+	assert.True(t, origin.Equal(eventTime))
+
+	var envelope struct {
+		Data dtos.Event `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(result.([]byte), &envelope))
+	assert.Equal(t, event, envelope.Data)
+}
+
+func TestConvertToCloudEventWithoutIdOrOrigin(t *testing.T) {
+	cloudEvent, err := NewCloudEvent("/edgex/app", "")
+	require.NoError(t, err)
+
+	continuePipeline, result := cloudEvent.ConvertToCloudEvent(ctx, dtos.Event{DeviceName: deviceName1})
+	require.True(t, continuePipeline, result)
+
+	var attributes map[string]interface{}
+	require.NoError(t, json.Unmarshal(result.([]byte), &attributes))
+
+	_, err = uuid.Parse(attributes["id"].(string))
+	assert.NoError(t, err, "id should be a generated UUID")
+	assert.Equal(t, DefaultCloudEventType, attributes["type"])
+	assert.NotContains(t, attributes, "time")
+}
+
+func TestConvertToCloudEventBinary(t *testing.T) {
+	event := dtos.NewEvent("profile1", deviceName1, "source1")
+	event.Origin = time.Now().UnixNano()
+
+	cloudEvent, err := NewCloudEvent("/edgex/app", "com.example.reading")
+	require.NoError(t, err)
+	cloudEvent.SetBinaryMode(true)
+
+	binaryCtx := appfunction.NewContext("123", dic, "")
+	continuePipeline, result := cloudEvent.ConvertToCloudEvent(binaryCtx, event)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, common.ContentTypeJSON, binaryCtx.ResponseContentType())
+
+	var actual dtos.Event
+	require.NoError(t, json.Unmarshal(result.([]byte), &actual))
+	assert.Equal(t, event, actual)
+
+	expected := map[string]string{
+		"ce-specversion": CloudEventSpecVersion,
+		"ce-id":          event.Id,
+		"ce-source":      "/edgex/app",
+		"ce-type":        "com.example.reading",
+		"ce-subject":     deviceName1,
+		"ce-time":        time.Unix(0, event.Origin).UTC().Format(time.RFC3339Nano),
+	}
+	for key, value := range expected {
+		actualValue, found := binaryCtx.GetValue(key)
+		require.True(t, found, "context value '%s' not found", key)
+		assert.Equal(t, value, actualValue)
+	}
+}
+
+func TestConvertToCloudEventInvalidData(t *testing.T) {
+	cloudEvent, err := NewCloudEvent("/edgex/app", "")
+	require.NoError(t, err)
+
+	continuePipeline, result := cloudEvent.ConvertToCloudEvent(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = cloudEvent.ConvertToCloudEvent(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
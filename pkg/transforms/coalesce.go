@@ -0,0 +1,188 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// Coalesce merges the readings for a set of resources, such as the x, y and z axes of an accelerometer, into a
+// single composite Object reading.
+type Coalesce struct {
+	resourceNames []string
+	targetName    string
+	allowPartial  bool
+}
+
+// NewCoalesce creates, initializes and returns a new instance of Coalesce which merges the readings for the
+// specified resources into a single Object reading for the targetName resource. The composite reading's value is
+// an object keyed by the resource names. By default, Events which don't have a reading for all the resources are
+// passed on unchanged. Use SetAllowPartial to merge the readings which are present instead.
+func NewCoalesce(resourceNames []string, targetName string) (*Coalesce, error) {
+	if len(resourceNames) == 0 {
+		return nil, errors.New("at least one resource name must be specified")
+	}
+
+	if len(targetName) == 0 {
+		return nil, errors.New("target name must be specified")
+	}
+
+	seen := make(map[string]bool, len(resourceNames))
+	for _, name := range resourceNames {
+		if len(name) == 0 {
+			return nil, errors.New("resource names must not be empty")
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("resource name '%s' specified more than once", name)
+		}
+		seen[name] = true
+	}
+
+	return &Coalesce{
+		resourceNames: resourceNames,
+		targetName:    targetName,
+	}, nil
+}
+
+// SetAllowPartial sets whether the readings are merged when the Event doesn't have a reading for all the resources,
+// in which case the missing resources have a null value in the composite reading.
+func (coalesce *Coalesce) SetAllowPartial(allowPartial bool) {
+	coalesce.allowPartial = allowPartial
+}
+
+// CoalesceReadings replaces the Event's readings for the configured resources with a single composite Object
+// reading, placed where the first of them was. Numeric and bool reading values are added to the composite value as
+// numbers and bools, Object reading values as is, and all other values as strings. The composite reading's origin
+// is the latest origin of the merged readings.
+// This function will return an error and stop the pipeline if a non-edgex event is received, if no data is received
+// or if a reading value can not be parsed for its value type.
+func (coalesce *Coalesce) CoalesceReadings(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function CoalesceReadings in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function CoalesceReadings in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	matched := make(map[string]dtos.BaseReading, len(coalesce.resourceNames))
+	firstIndex := -1
+	for index, reading := range event.Readings {
+		if !coalesce.isComponent(reading.ResourceName) {
+			continue
+		}
+
+		if firstIndex < 0 {
+			firstIndex = index
+		}
+		matched[reading.ResourceName] = reading
+	}
+
+	if len(matched) == 0 || (!coalesce.allowPartial && len(matched) < len(coalesce.resourceNames)) {
+		ctx.LoggingClient().Debugf("Event from %s doesn't have readings for all of %v, not coalescing in pipeline '%s'",
+			event.DeviceName, coalesce.resourceNames, ctx.PipelineId())
+		return true, event
+	}
+
+	composite := make(map[string]any, len(coalesce.resourceNames))
+	var origin int64
+	for _, name := range coalesce.resourceNames {
+		reading, found := matched[name]
+		if !found {
+			composite[name] = nil
+			continue
+		}
+
+		value, err := coalesceValue(reading)
+		if err != nil {
+			return false, fmt.Errorf("function CoalesceReadings in pipeline '%s': unable to parse reading value for resource '%s': %s",
+				ctx.PipelineId(), name, err.Error())
+		}
+
+		composite[name] = value
+		if reading.Origin > origin {
+			origin = reading.Origin
+		}
+	}
+
+	first := event.Readings[firstIndex]
+	compositeReading := dtos.NewObjectReading(first.ProfileName, first.DeviceName, coalesce.targetName, composite)
+	compositeReading.Origin = origin
+
+	readings := make([]dtos.BaseReading, 0, len(event.Readings)-len(matched)+1)
+	for index, reading := range event.Readings {
+		if index == firstIndex {
+			readings = append(readings, compositeReading)
+			continue
+		}
+
+		if !coalesce.isComponent(reading.ResourceName) {
+			readings = append(readings, reading)
+		}
+	}
+
+	event.Readings = readings
+
+	ctx.LoggingClient().Debugf("Coalesced %d readings into '%s' reading for Event from %s in pipeline '%s'",
+		len(matched), coalesce.targetName, event.DeviceName, ctx.PipelineId())
+
+	return true, event
+}
+
+func (coalesce *Coalesce) isComponent(resourceName string) bool {
+	for _, name := range coalesce.resourceNames {
+		if name == resourceName {
+			return true
+		}
+	}
+	return false
+}
+
+// coalesceValue returns the reading's value as the type it represents
+func coalesceValue(reading dtos.BaseReading) (any, error) {
+	switch reading.ValueType {
+	case common.ValueTypeObject, common.ValueTypeObjectArray:
+		return reading.ObjectValue, nil
+	case common.ValueTypeBinary:
+		return reading.BinaryValue, nil
+	case common.ValueTypeBool:
+		return strconv.ParseBool(reading.Value)
+	case common.ValueTypeFloat32:
+		// float32 so that the value is marshaled to JSON without float64 precision artifacts
+		value, err := strconv.ParseFloat(reading.Value, 32)
+		return float32(value), err
+	case common.ValueTypeFloat64:
+		return strconv.ParseFloat(reading.Value, 64)
+	}
+
+	if integerType, found := integerBitSizes[reading.ValueType]; found {
+		if integerType.signed {
+			return strconv.ParseInt(reading.Value, 10, integerType.bitSize)
+		}
+		return strconv.ParseUint(reading.Value, 10, integerType.bitSize)
+	}
+
+	return reading.Value, nil
+}
@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func coalesceReading(t *testing.T, resourceName string, valueType string, value any, origin int64) dtos.BaseReading {
+	reading, err := dtos.NewSimpleReading("profile1", deviceName1, resourceName, valueType, value)
+	require.NoError(t, err)
+	reading.Origin = origin
+	return reading
+}
+
+func TestNewCoalesce(t *testing.T) {
+	tests := []struct {
+		Name          string
+		ResourceNames []string
+		TargetName    string
+		ExpectError   bool
+	}{
+		{"Valid", []string{"x", "y", "z"}, "acceleration", false},
+		{"No resource names", nil, "acceleration", true},
+		{"Empty resource name", []string{"x", ""}, "acceleration", true},
+		{"Duplicate resource name", []string{"x", "x"}, "acceleration", true},
+		{"No target name", []string{"x", "y", "z"}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewCoalesce(test.ResourceNames, test.TargetName)
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestCoalesceReadings(t *testing.T) {
+	coalesce, err := NewCoalesce([]string{"x", "y", "z"}, "acceleration")
+	require.NoError(t, err)
+
+	event := dtos.NewEvent("profile1", deviceName1, "accelerometer")
+	event.Readings = []dtos.BaseReading{
+		coalesceReading(t, "temperature", common.ValueTypeInt32, int32(21), 100),
+		coalesceReading(t, "x", common.ValueTypeFloat64, 0.5, 100),
+		coalesceReading(t, "y", common.ValueTypeFloat32, float32(-1.1), 300),
+		coalesceReading(t, "z", common.ValueTypeInt16, int16(9), 200),
+	}
+
+	continuePipeline, result := coalesce.CoalesceReadings(ctx, event)
+	require.True(t, continuePipeline, result)
+
+	coalesced, ok := result.(dtos.Event)
+	require.True(t, ok)
+	require.Len(t, coalesced.Readings, 2)
+	assert.Equal(t, "temperature", coalesced.Readings[0].ResourceName)
+
+	composite := coalesced.Readings[1]
+	assert.Equal(t, "acceleration", composite.ResourceName)
+	assert.Equal(t, common.ValueTypeObject, composite.ValueType)
+	assert.Equal(t, deviceName1, composite.DeviceName)
+	assert.Equal(t, "profile1", composite.ProfileName)
+	assert.Equal(t, int64(300), composite.Origin)
+
+	value, err := json.Marshal(composite.ObjectValue)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"x":0.5,"y":-1.1,"z":9}`, string(value))
+
+	// The input Event's readings are not modified
+	assert.Len(t, event.Readings, 4)
+}
+
+func TestCoalesceReadingsMissingComponents(t *testing.T) {
+	tests := []struct {
+		Name             string
+		AllowPartial     bool
+		Readings         []string
+		ExpectedReadings []string
+		ExpectedValue    string
+	}{
+		{"Skip when missing", false, []string{"x", "z"}, []string{"x", "z"}, ""},
+		{"Partial fill when missing", true, []string{"x", "z"}, []string{"acceleration"}, `{"x":1,"y":null,"z":1}`},
+		{"No components", true, []string{"temperature"}, []string{"temperature"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			coalesce, err := NewCoalesce([]string{"x", "y", "z"}, "acceleration")
+			require.NoError(t, err)
+			coalesce.SetAllowPartial(test.AllowPartial)
+
+			event := dtos.NewEvent("profile1", deviceName1, "accelerometer")
+			for _, name := range test.Readings {
+				event.Readings = append(event.Readings, coalesceReading(t, name, common.ValueTypeInt8, int8(1), 100))
+			}
+
+			continuePipeline, result := coalesce.CoalesceReadings(ctx, event)
+			require.True(t, continuePipeline, result)
+
+			coalesced := result.(dtos.Event)
+			var names []string
+			for _, reading := range coalesced.Readings {
+				names = append(names, reading.ResourceName)
+			}
+			assert.Equal(t, test.ExpectedReadings, names)
+
+			if len(test.ExpectedValue) > 0 {
+				value, err := json.Marshal(coalesced.Readings[0].ObjectValue)
+				require.NoError(t, err)
+				assert.JSONEq(t, test.ExpectedValue, string(value))
+			}
+		})
+	}
+}
+
+func TestCoalesceReadingsErrors(t *testing.T) {
+	coalesce, err := NewCoalesce([]string{"x"}, "acceleration")
+	require.NoError(t, err)
+
+	continuePipeline, result := coalesce.CoalesceReadings(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = coalesce.CoalesceReadings(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+
+	event := dtos.Event{Readings: []dtos.BaseReading{{
+		ResourceName:  "x",
+		ValueType:     common.ValueTypeFloat64,
+		SimpleReading: dtos.SimpleReading{Value: "bogus"},
+	}}}
+	continuePipeline, result = coalesce.CoalesceReadings(ctx, event)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "unable to parse reading value for resource 'x'")
+}
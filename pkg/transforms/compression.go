@@ -26,7 +26,10 @@ import (
 	"sync"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+	"github.com/klauspost/compress/zstd"
+	gometrics "github.com/rcrowley/go-metrics"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
 
@@ -34,9 +37,12 @@ import (
 )
 
 type Compression struct {
-	gzipWriter *gzip.Writer
-	zlibWriter *zlib.Writer
-	mutex      sync.Mutex
+	gzipWriter      *gzip.Writer
+	zlibWriter      *zlib.Writer
+	mutex           sync.Mutex
+	zstdLevel       int
+	zstdEncoder     *zstd.Encoder
+	zstdSizeMetrics gometrics.Histogram
 }
 
 // NewCompression creates, initializes and returns a new instance of Compression
@@ -44,6 +50,16 @@ func NewCompression() *Compression {
 	return &Compression{}
 }
 
+// NewCompressionWithZSTDLevel creates, initializes and returns a new instance of Compression which uses the
+// specified zstd compression level (1-22) for CompressWithZSTD. Zero uses the zstd default level.
+func NewCompressionWithZSTDLevel(level int) (*Compression, error) {
+	if level < 0 || level > 22 {
+		return nil, fmt.Errorf("zstd compression level must be between 1 and 22, got %d", level)
+	}
+
+	return &Compression{zstdLevel: level}, nil
+}
+
 // CompressWithGZIP compresses data received as either a string,[]byte, or json.Marshaller using gzip algorithm
 // and returns a base64 encoded string as a []byte.
 func (compression *Compression) CompressWithGZIP(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
@@ -124,6 +140,64 @@ func (compression *Compression) CompressWithZLIB(ctx interfaces.AppFunctionConte
 	return true, bytesBufferToBase64(ctx.LoggingClient(), buf)
 }
 
+// CompressWithZSTD compresses data received as either a string,[]byte, or json.Marshaller using zstd algorithm
+// and returns a base64 encoded string as a []byte.
+func (compression *Compression) CompressWithZSTD(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		// We didn't receive a result
+		return false, fmt.Errorf("function CompressWithZSTD in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+	ctx.LoggingClient().Debugf("Compression with ZSTD in pipeline '%s'", ctx.PipelineId())
+	byteData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	encoder, err := compression.getZSTDEncoder()
+	if err != nil {
+		return false, fmt.Errorf("unable to create ZSTD encoder in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.ZstdCompressedSizeName, ctx.PipelineId()) },
+		func() any { return compression.zstdSizeMetrics },
+		map[string]string{"pipeline": ctx.PipelineId()})
+
+	// EncodeAll is safe for concurrent use, so the mutex is only needed when creating the encoder
+	compressed := encoder.EncodeAll(byteData, make([]byte, 0, len(byteData)))
+	compression.zstdSizeMetrics.Update(int64(len(compressed)))
+
+	// Set response "content-type" header to "text/plain"
+	ctx.SetResponseContentType(common.ContentTypeText)
+
+	return true, bytesBufferToBase64(ctx.LoggingClient(), *bytes.NewBuffer(compressed))
+}
+
+// getZSTDEncoder returns the zstd encoder, creating it on first use so it is reused across all invocations
+func (compression *Compression) getZSTDEncoder() (*zstd.Encoder, error) {
+	compression.mutex.Lock()
+	defer compression.mutex.Unlock()
+
+	if compression.zstdEncoder != nil {
+		return compression.zstdEncoder, nil
+	}
+
+	level := zstd.SpeedDefault
+	if compression.zstdLevel > 0 {
+		level = zstd.EncoderLevelFromZstd(compression.zstdLevel)
+	}
+
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+	if err != nil {
+		return nil, err
+	}
+
+	compression.zstdEncoder = encoder
+	compression.zstdSizeMetrics = gometrics.NewHistogram(gometrics.NewUniformSample(internal.MetricsReservoirSize))
+
+	return compression.zstdEncoder, nil
+}
+
 func bytesBufferToBase64(lc logger.LoggingClient, buf bytes.Buffer) []byte {
 	lc.Debugf("Encoding compressed bytes of length %d vs %d", len(buf.Bytes()), buf.Len())
 	dst := make([]byte, base64.StdEncoding.EncodedLen(buf.Len()))
@@ -29,6 +29,7 @@ import (
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -88,6 +89,64 @@ func TestZlib(t *testing.T) {
 	assert.Equal(t, ctx.ResponseContentType(), common.ContentTypeText)
 }
 
+func TestZstd(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Level int
+	}{
+		{"Default level", 0},
+		{"Fastest level", 1},
+		{"Best level", 22},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			comp, err := NewCompressionWithZSTDLevel(test.Level)
+			require.NoError(t, err)
+
+			continuePipeline, result := comp.CompressWithZSTD(ctx, []byte(clearString))
+			assert.True(t, continuePipeline)
+			require.NotNil(t, result)
+
+			compressed, err := base64.StdEncoding.DecodeString(string(result.([]byte)))
+			require.NoError(t, err)
+
+			decoder, err := zstd.NewReader(nil)
+			require.NoError(t, err)
+			defer decoder.Close()
+
+			decoded, err := decoder.DecodeAll(compressed, nil)
+			require.NoError(t, err)
+			require.Equal(t, clearString, string(decoded))
+			assert.Equal(t, ctx.ResponseContentType(), common.ContentTypeText)
+
+			// Encoder is reused across invocations
+			encoder := comp.zstdEncoder
+			continuePipeline2, result2 := comp.CompressWithZSTD(ctx, []byte(clearString))
+			assert.True(t, continuePipeline2)
+			assert.Equal(t, result.([]byte), result2.([]byte))
+			assert.Same(t, encoder, comp.zstdEncoder)
+
+			assert.Equal(t, int64(2), comp.zstdSizeMetrics.Count())
+			assert.Equal(t, int64(len(compressed)), comp.zstdSizeMetrics.Max())
+		})
+	}
+}
+
+func TestZstdNoData(t *testing.T) {
+	comp := NewCompression()
+	continuePipeline, result := comp.CompressWithZSTD(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+}
+
+func TestNewCompressionWithZSTDLevelInvalid(t *testing.T) {
+	_, err := NewCompressionWithZSTDLevel(23)
+	require.Error(t, err)
+	_, err = NewCompressionWithZSTDLevel(-1)
+	require.Error(t, err)
+}
+
 var result []byte
 
 func BenchmarkGzip(b *testing.B) {
@@ -149,3 +208,18 @@ func BenchmarkZlibWith1000Goroutines(b *testing.B) {
 	}
 	b.StopTimer()
 }
+
+func BenchmarkZstdWith1000Goroutines(b *testing.B) {
+	comp := NewCompression()
+	wg := &sync.WaitGroup{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < 1000; n++ {
+			go runCompression(wg, comp.CompressWithZSTD)
+		}
+		wg.Wait()
+	}
+	b.StopTimer()
+}
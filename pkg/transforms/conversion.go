@@ -17,17 +17,78 @@
 package transforms
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/fxamacker/cbor/v2"
 )
 
-// Conversion houses various built in conversion transforms (XML, JSON, CSV)
+const (
+	// XMLAttributePrefix is the prefix added to XML attribute names when converted to JSON keys by ConvertXMLToJSON
+	XMLAttributePrefix = "@"
+	// XMLTextKey is the JSON key for an XML element's text when the element also has attributes or child elements
+	XMLTextKey = "#text"
+
+	// ContentTypeCSV is the mime type for the CSV data produced by ConvertToCSV
+	ContentTypeCSV = "text/csv"
+	// ContentTypeNDJSON is the mime type for the newline-delimited JSON data produced by ConvertToNDJSON
+	ContentTypeNDJSON = "application/x-ndjson"
+)
+
+// CSV column names supported by ConvertToCSV
+const (
+	CSVColumnEventId      = "eventId"
+	CSVColumnId           = "id"
+	CSVColumnDeviceName   = "deviceName"
+	CSVColumnProfileName  = "profileName"
+	CSVColumnSourceName   = "sourceName"
+	CSVColumnResourceName = "resourceName"
+	CSVColumnValueType    = "valueType"
+	CSVColumnValue        = "value"
+	CSVColumnUnits        = "units"
+	CSVColumnMediaType    = "mediaType"
+	CSVColumnOrigin       = "origin"
+)
+
+// DefaultCSVColumns are the columns used by ConvertToCSV when none are specified
+var DefaultCSVColumns = []string{CSVColumnDeviceName, CSVColumnResourceName, CSVColumnValue, CSVColumnOrigin}
+
+var csvColumnValues = map[string]func(event *dtos.Event, reading dtos.BaseReading) string{
+	CSVColumnEventId:      func(event *dtos.Event, _ dtos.BaseReading) string { return event.Id },
+	CSVColumnId:           func(_ *dtos.Event, reading dtos.BaseReading) string { return reading.Id },
+	CSVColumnDeviceName:   func(_ *dtos.Event, reading dtos.BaseReading) string { return reading.DeviceName },
+	CSVColumnProfileName:  func(_ *dtos.Event, reading dtos.BaseReading) string { return reading.ProfileName },
+	CSVColumnSourceName:   func(event *dtos.Event, _ dtos.BaseReading) string { return event.SourceName },
+	CSVColumnResourceName: func(_ *dtos.Event, reading dtos.BaseReading) string { return reading.ResourceName },
+	CSVColumnValueType:    func(_ *dtos.Event, reading dtos.BaseReading) string { return reading.ValueType },
+	CSVColumnValue:        csvReadingValue,
+	CSVColumnUnits:        func(_ *dtos.Event, reading dtos.BaseReading) string { return reading.Units },
+	CSVColumnMediaType:    func(_ *dtos.Event, reading dtos.BaseReading) string { return reading.MediaType },
+	CSVColumnOrigin: func(_ *dtos.Event, reading dtos.BaseReading) string {
+		if reading.Origin == 0 {
+			return ""
+		}
+		return strconv.FormatInt(reading.Origin, 10)
+	},
+}
+
+// Conversion houses various built in conversion transforms (XML, JSON, CSV, CBOR)
 type Conversion struct {
+	csvColumns       []string
+	csvIncludeHeader bool
 }
 
 // NewConversion creates, initializes and returns a new instance of Conversion
@@ -35,6 +96,35 @@ func NewConversion() *Conversion {
 	return &Conversion{}
 }
 
+// NewCSVConversion creates, initializes and returns a new instance of Conversion for use with ConvertToCSV
+// which outputs the specified columns, with a header row if includeHeader is true. The DefaultCSVColumns are used
+// if no columns are specified. Column names are matched case-insensitively against the CSVColumn... names.
+func NewCSVConversion(columns []string, includeHeader bool) (*Conversion, error) {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	conversion := &Conversion{csvIncludeHeader: includeHeader}
+	for _, column := range columns {
+		name, found := findCSVColumn(column)
+		if !found {
+			return nil, fmt.Errorf("unsupported CSV column '%s'", column)
+		}
+		conversion.csvColumns = append(conversion.csvColumns, name)
+	}
+
+	return conversion, nil
+}
+
+func findCSVColumn(column string) (string, bool) {
+	for name := range csvColumnValues {
+		if strings.EqualFold(name, strings.TrimSpace(column)) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 // TransformToXML transforms an EdgeX event to XML.
 // It will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
 func (f *Conversion) TransformToXML(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, stringType interface{}) {
@@ -79,3 +169,355 @@ func (f *Conversion) TransformToJSON(ctx interfaces.AppFunctionContext, data int
 
 	return false, fmt.Errorf("function TransformToJSON in pipeline '%s': unexpected type received", ctx.PipelineId())
 }
+
+// ConvertXMLToJSON converts XML received as a string or []byte to JSON.
+// Each element becomes a JSON key whose value is the element's text, or an object when the element has attributes
+// or child elements. Attributes are keyed by their name prefixed with XMLAttributePrefix and the element's text,
+// if any, is keyed by XMLTextKey. Repeated elements become an array.
+// It will return an error and stop the pipeline if the data received is not valid XML or if no data is received.
+func (f *Conversion) ConvertXMLToJSON(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, stringType interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function ConvertXMLToJSON in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Converting XML to JSON in pipeline '%s'", ctx.PipelineId())
+
+	var xmlData []byte
+	switch input := data.(type) {
+	case []byte:
+		xmlData = input
+	case string:
+		xmlData = []byte(input)
+	default:
+		return false, fmt.Errorf("function ConvertXMLToJSON in pipeline '%s': unexpected type received, must be string or []byte", ctx.PipelineId())
+	}
+
+	converted, err := xmlToMap(xmlData)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse XML in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	b, err := json.Marshal(converted)
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal converted XML to JSON in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.SetResponseContentType(common.ContentTypeJSON)
+	return true, string(b)
+}
+
+// xmlToMap decodes the XML document into a map keyed by the root element name
+func xmlToMap(xmlData []byte) (map[string]interface{}, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(xmlData))
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("no root element found")
+			}
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			value, err := decodeXMLElement(decoder, element)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{element.Name.Local: value}, nil
+		case xml.CharData:
+			if len(bytes.TrimSpace(element)) > 0 {
+				return nil, errors.New("unexpected text found before root element")
+			}
+		}
+	}
+}
+
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	result := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		result[XMLAttributePrefix+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("element '%s' is not closed", start.Name.Local)
+			}
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, element)
+			if err != nil {
+				return nil, err
+			}
+
+			name := element.Name.Local
+			existing, found := result[name]
+			if !found {
+				result[name] = child
+			} else if children, isArray := existing.([]interface{}); isArray {
+				result[name] = append(children, child)
+			} else {
+				result[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(element)
+		case xml.EndElement:
+			trimmedText := strings.TrimSpace(text.String())
+			if len(result) == 0 {
+				return trimmedText, nil
+			}
+
+			if len(trimmedText) > 0 {
+				result[XMLTextKey] = trimmedText
+			}
+			return result, nil
+		}
+	}
+}
+
+// ConvertToCSV converts an EdgeX event, a slice of events (i.e. from Batch with IsEventData set) or a slice of
+// readings to CSV, with one row per reading. Fields which aren't available for a reading, such as units which weren't
+// set or sourceName when converting readings, are output as empty cells. Use NewCSVConversion to set the columns.
+// It will return an error and stop the pipeline if an unexpected type is received or if no data is received.
+func (f *Conversion) ConvertToCSV(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, result interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function ConvertToCSV in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Converting to CSV in pipeline '%s'", ctx.PipelineId())
+
+	var events []dtos.Event
+	switch input := data.(type) {
+	case dtos.Event:
+		events = []dtos.Event{input}
+	case []dtos.Event:
+		events = input
+	case []dtos.BaseReading:
+		events = []dtos.Event{{Readings: input}}
+	default:
+		return false, fmt.Errorf("function ConvertToCSV in pipeline '%s': unexpected type received", ctx.PipelineId())
+	}
+
+	columns := f.csvColumns
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if f.csvIncludeHeader {
+		if err := writer.Write(columns); err != nil {
+			return false, fmt.Errorf("unable to write CSV header in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		}
+	}
+
+	row := make([]string, len(columns))
+	for i := range events {
+		for _, reading := range events[i].Readings {
+			for index, column := range columns {
+				row[index] = csvColumnValues[column](&events[i], reading)
+			}
+
+			if err := writer.Write(row); err != nil {
+				return false, fmt.Errorf("unable to write CSV row in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return false, fmt.Errorf("unable to write CSV in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.SetResponseContentType(ContentTypeCSV)
+	return true, buf.Bytes()
+}
+
+// csvReadingValue returns the reading's value. Object values are output as JSON and binary values are omitted.
+func csvReadingValue(_ *dtos.Event, reading dtos.BaseReading) string {
+	if reading.ObjectValue != nil {
+		value, err := json.Marshal(reading.ObjectValue)
+		if err != nil {
+			return ""
+		}
+		return string(value)
+	}
+
+	return reading.Value
+}
+
+// ConvertToNDJSON converts a slice, i.e. from Batch, to newline-delimited JSON with one compactly serialized element
+// per line. Elements of a [][]byte, as produced by Batch, must each be a JSON document, while elements of any other
+// slice type, such as the []Event produced by Batch with IsEventData set, are marshaled to JSON.
+// An empty slice results in empty output.
+// It will return an error and stop the pipeline if an element isn't valid JSON, a non-slice type is received or if
+// no data is received.
+func (f *Conversion) ConvertToNDJSON(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, result interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function ConvertToNDJSON in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Converting to NDJSON in pipeline '%s'", ctx.PipelineId())
+
+	var buf bytes.Buffer
+	switch input := data.(type) {
+	case [][]byte:
+		for index, element := range input {
+			if err := json.Compact(&buf, element); err != nil {
+				return false, fmt.Errorf("unable to convert element %d to NDJSON in pipeline '%s': %s", index, ctx.PipelineId(), err.Error())
+			}
+			buf.WriteByte('\n')
+		}
+	case []byte:
+		// Can't be split in to elements, i.e. from Batch with MergeOnSend set
+		return false, fmt.Errorf("function ConvertToNDJSON in pipeline '%s': unexpected type received, must be a slice of elements", ctx.PipelineId())
+	default:
+		value := reflect.ValueOf(data)
+		if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+			return false, fmt.Errorf("function ConvertToNDJSON in pipeline '%s': unexpected type received, must be a slice of elements", ctx.PipelineId())
+		}
+
+		for index := 0; index < value.Len(); index++ {
+			// json.Marshal output is compact and escapes any newlines within string values
+			element, err := json.Marshal(value.Index(index).Interface())
+			if err != nil {
+				return false, fmt.Errorf("unable to convert element %d to NDJSON in pipeline '%s': %s", index, ctx.PipelineId(), err.Error())
+			}
+			buf.Write(element)
+			buf.WriteByte('\n')
+		}
+	}
+
+	ctx.SetResponseContentType(ContentTypeNDJSON)
+	return true, buf.Bytes()
+}
+
+// ConvertCBORToJSON converts CBOR received as []byte to JSON.
+// Map keys that aren't text strings are converted to their string representation, byte strings become base64
+// encoded strings, date/time values become RFC 3339 strings and other tagged values are replaced by their content.
+// It will return an error and stop the pipeline if the data received is not valid CBOR, a non []byte type is
+// received or if no data is received.
+func (f *Conversion) ConvertCBORToJSON(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, stringType interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function ConvertCBORToJSON in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Converting CBOR to JSON in pipeline '%s'", ctx.PipelineId())
+
+	cborData, ok := data.([]byte)
+	if !ok {
+		return false, fmt.Errorf("function ConvertCBORToJSON in pipeline '%s': unexpected type received, must be []byte", ctx.PipelineId())
+	}
+
+	var decoded interface{}
+	if err := cbor.Unmarshal(cborData, &decoded); err != nil {
+		return false, fmt.Errorf("unable to decode CBOR in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	b, err := json.Marshal(cborToJSONValue(decoded))
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal converted CBOR to JSON in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.SetResponseContentType(common.ContentTypeJSON)
+	return true, string(b)
+}
+
+// cborToJSONValue converts the generic values decoded from CBOR to values that can be marshaled to JSON
+func cborToJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			converted[fmt.Sprint(key)] = cborToJSONValue(element)
+		}
+		return converted
+	case []interface{}:
+		for index, element := range v {
+			v[index] = cborToJSONValue(element)
+		}
+		return v
+	case cbor.Tag:
+		return cborToJSONValue(v.Content)
+	default:
+		return v
+	}
+}
+
+// ConvertJSONToCBOR converts JSON received as a string, []byte or a type that marshals to JSON, such as an Event,
+// to CBOR. Integer numbers are encoded as CBOR integers and all other numbers as floating point.
+// It will return an error and stop the pipeline if the data received is not valid JSON or if no data is received.
+func (f *Conversion) ConvertJSONToCBOR(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, result interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function ConvertJSONToCBOR in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Converting JSON to CBOR in pipeline '%s'", ctx.PipelineId())
+
+	jsonData, err := util.CoerceType(data)
+	if err != nil {
+		return false, fmt.Errorf("function ConvertJSONToCBOR in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return false, fmt.Errorf("unable to parse JSON in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	converted, err := jsonToCBORValue(decoded)
+	if err != nil {
+		return false, fmt.Errorf("unable to convert JSON to CBOR in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	b, err := cbor.Marshal(converted)
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal converted JSON to CBOR in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.SetResponseContentType(common.ContentTypeCBOR)
+	return true, b
+}
+
+// jsonToCBORValue converts the json.Number values decoded from JSON to integer or floating point values
+func jsonToCBORValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			converted, err := jsonToCBORValue(element)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+		return v, nil
+	case []interface{}:
+		for index, element := range v {
+			converted, err := jsonToCBORValue(element)
+			if err != nil {
+				return nil, err
+			}
+			v[index] = converted
+		}
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u, nil
+		}
+		return v.Float64()
+	default:
+		return v, nil
+	}
+}
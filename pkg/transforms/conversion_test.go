@@ -17,10 +17,16 @@
 package transforms
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
 	"testing"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/fxamacker/cbor/v2"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -88,3 +94,378 @@ func TestTransformToJSONNotAnEvent(t *testing.T) {
 	require.Contains(t, result.(error).Error(), "unexpected type received")
 	assert.False(t, continuePipeline)
 }
+
+func TestConvertXMLToJSON(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Data     interface{}
+		Expected string
+	}{
+		{"simple element", `<value>12.5</value>`, `{"value":"12.5"}`},
+		{"nested elements", []byte(`<device><name>sensor-1</name><location><site>plant-1</site></location></device>`),
+			`{"device":{"location":{"site":"plant-1"},"name":"sensor-1"}}`},
+		{"attributes", `<?xml version="1.0"?><reading id="1" unit="C">21.5</reading>`,
+			`{"reading":{"#text":"21.5","@id":"1","@unit":"C"}}`},
+		{"repeated elements become arrays", `<readings>
+			<reading resource="temp">21</reading>
+			<reading resource="humidity">40</reading>
+			<reading resource="pressure">1013</reading>
+			<count>3</count>
+		</readings>`,
+			`{"readings":{"count":"3","reading":[{"#text":"21","@resource":"temp"},{"#text":"40","@resource":"humidity"},{"#text":"1013","@resource":"pressure"}]}}`},
+		{"empty element", `<device><name/></device>`, `{"device":{"name":""}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			conv := NewConversion()
+			continuePipeline, result := conv.ConvertXMLToJSON(ctx, test.Data)
+			require.True(t, continuePipeline, "unexpected error: %v", result)
+			assert.JSONEq(t, test.Expected, result.(string))
+			assert.Equal(t, common.ContentTypeJSON, ctx.ResponseContentType())
+		})
+	}
+}
+
+func TestConvertXMLToJSONErrors(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Data          interface{}
+		ExpectedError string
+	}{
+		{"no data", nil, "No Data Received"},
+		{"unexpected type", dtos.Event{}, "unexpected type received"},
+		{"JSON input", `{"device":"sensor-1"}`, "unexpected text found before root element"},
+		{"empty input", "", "no root element found"},
+		{"not closed", `<device><name>sensor-1</name>`, "unable to parse XML"},
+		{"mismatched tags", `<device><name>sensor-1</device></name>`, "unable to parse XML"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			conv := NewConversion()
+			continuePipeline, result := conv.ConvertXMLToJSON(ctx, test.Data)
+			assert.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			assert.Contains(t, result.(error).Error(), test.ExpectedError)
+		})
+	}
+}
+
+func TestConvertToCSV(t *testing.T) {
+	event := dtos.Event{
+		Id:         "event-1",
+		DeviceName: deviceName1,
+		SourceName: "source1",
+		Readings: []dtos.BaseReading{
+			{
+				DeviceName:    deviceName1,
+				ResourceName:  "temperature",
+				Origin:        1000,
+				Units:         "C",
+				SimpleReading: dtos.SimpleReading{Value: "21.5"},
+			},
+			{
+				DeviceName:    deviceName1,
+				ResourceName:  "status",
+				SimpleReading: dtos.SimpleReading{Value: "ok, \"running\"\nsince boot"},
+			},
+			{
+				DeviceName:    deviceName1,
+				ResourceName:  "location",
+				Origin:        3000,
+				ObjectReading: dtos.ObjectReading{ObjectValue: map[string]any{"lat": 1, "lon": 2}},
+			},
+		},
+	}
+
+	tests := []struct {
+		Name           string
+		Columns        []string
+		IncludeHeader  bool
+		Data           interface{}
+		ExpectedResult string
+	}{
+		{
+			Name:          "default columns with header",
+			IncludeHeader: true,
+			Data:          event,
+			ExpectedResult: "deviceName,resourceName,value,origin\n" +
+				"device1,temperature,21.5,1000\n" +
+				"device1,status,\"ok, \"\"running\"\"\nsince boot\",\n" +
+				"device1,location,\"{\"\"lat\"\":1,\"\"lon\"\":2}\",3000\n",
+		},
+		{
+			Name:    "custom columns without header",
+			Columns: []string{"EventId", "sourceName", "resourceName", "units"},
+			Data:    []dtos.Event{event},
+			ExpectedResult: "event-1,source1,temperature,C\n" +
+				"event-1,source1,status,\n" +
+				"event-1,source1,location,\n",
+		},
+		{
+			Name:          "readings with missing event fields",
+			Columns:       []string{"eventId", "sourceName", "resourceName", "value"},
+			IncludeHeader: true,
+			Data:          event.Readings[:1],
+			ExpectedResult: "eventId,sourceName,resourceName,value\n" +
+				",,temperature,21.5\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			conv, err := NewCSVConversion(test.Columns, test.IncludeHeader)
+			require.NoError(t, err)
+
+			continuePipeline, result := conv.ConvertToCSV(ctx, test.Data)
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, ContentTypeCSV, ctx.ResponseContentType())
+			assert.Equal(t, test.ExpectedResult, string(result.([]byte)))
+		})
+	}
+}
+
+func TestConvertToCSVErrors(t *testing.T) {
+	_, err := NewCSVConversion([]string{"deviceName", "bogus"}, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported CSV column 'bogus'")
+
+	conv, err := NewCSVConversion(nil, true)
+	require.NoError(t, err)
+
+	continuePipeline, result := conv.ConvertToCSV(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = conv.ConvertToCSV(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "unexpected type received")
+}
+
+func TestConvertToNDJSON(t *testing.T) {
+	events := []dtos.Event{
+		{Id: "event-1", DeviceName: deviceName1, Tags: map[string]interface{}{"note": "line1\nline2"}},
+		{Id: "event-2", DeviceName: deviceName1},
+	}
+
+	tests := []struct {
+		Name           string
+		Data           interface{}
+		ExpectedLines  int
+		ExpectedResult string
+	}{
+		{
+			Name:           "batched bytes",
+			Data:           [][]byte{[]byte("{\n  \"a\": 1\n}"), []byte(`{"b": [1, 2]}`)},
+			ExpectedLines:  2,
+			ExpectedResult: "{\"a\":1}\n{\"b\":[1,2]}\n",
+		},
+		{
+			Name:          "batched events",
+			Data:          events,
+			ExpectedLines: 2,
+		},
+		{
+			Name:           "generic slice",
+			Data:           []interface{}{map[string]string{"a": "x\ny"}, 2, "three"},
+			ExpectedLines:  3,
+			ExpectedResult: "{\"a\":\"x\\ny\"}\n2\n\"three\"\n",
+		},
+		{
+			Name:           "empty batch",
+			Data:           [][]byte{},
+			ExpectedLines:  0,
+			ExpectedResult: "",
+		},
+		{
+			Name:           "empty events",
+			Data:           []dtos.Event{},
+			ExpectedLines:  0,
+			ExpectedResult: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			conv := NewConversion()
+
+			continuePipeline, result := conv.ConvertToNDJSON(ctx, test.Data)
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, ContentTypeNDJSON, ctx.ResponseContentType())
+
+			output := result.([]byte)
+			assert.Equal(t, test.ExpectedLines, bytes.Count(output, []byte("\n")))
+			if test.ExpectedResult != "" || test.ExpectedLines == 0 {
+				assert.Equal(t, test.ExpectedResult, string(output))
+			}
+
+			for _, line := range bytes.SplitAfter(output, []byte("\n")) {
+				if len(line) > 0 {
+					assert.True(t, json.Valid(line), "line is not valid JSON: %s", line)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertToNDJSONErrors(t *testing.T) {
+	conv := NewConversion()
+
+	tests := []struct {
+		Name          string
+		Data          interface{}
+		ExpectedError string
+	}{
+		{"no data", nil, "No Data Received"},
+		{"not a slice", dtos.Event{}, "unexpected type received"},
+		{"merged bytes", []byte(`{"a":1}`), "unexpected type received"},
+		{"invalid JSON element", [][]byte{[]byte(`{"a":1}`), []byte("not json")}, "unable to convert element 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := conv.ConvertToNDJSON(ctx, test.Data)
+			assert.False(t, continuePipeline)
+			assert.Contains(t, result.(error).Error(), test.ExpectedError)
+		})
+	}
+}
+
+func conversionTestEvent() dtos.Event {
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	_ = event.AddSimpleReading("temperature", common.ValueTypeFloat64, 21.5)
+	event.AddBinaryReading("image", []byte{0x00, 0x01, 0xfe, 0xff}, "image/png")
+	return event
+}
+
+func TestConvertCBORToJSON(t *testing.T) {
+	binaryValue := []byte{0x00, 0x01, 0xfe, 0xff}
+
+	tests := []struct {
+		Name     string
+		Data     interface{}
+		Expected string
+	}{
+		{"nested maps and arrays", map[string]interface{}{
+			"device":   "sensor-1",
+			"readings": []interface{}{map[string]interface{}{"resource": "temp", "value": 21.5}, int64(-3)},
+			"location": map[string]interface{}{"site": "plant-1", "floor": uint64(2)},
+		}, `{"device":"sensor-1","location":{"floor":2,"site":"plant-1"},"readings":[{"resource":"temp","value":21.5},-3]}`},
+		{"binary value", map[string]interface{}{"image": binaryValue},
+			`{"image":"` + base64.StdEncoding.EncodeToString(binaryValue) + `"}`},
+		{"non-string keys", map[interface{}]interface{}{uint64(1): "one", true: []interface{}{nil}},
+			`{"1":"one","true":[null]}`},
+		{"tagged value", cbor.Tag{Number: 4000, Content: []interface{}{"a", int64(1)}}, `["a",1]`},
+		{"epoch time", cbor.Tag{Number: 1, Content: int64(1700000000)}, `"2023-11-14T22:13:20Z"`},
+		{"event", conversionTestEvent(), ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cborData, err := cbor.Marshal(test.Data)
+			require.NoError(t, err)
+
+			expected := test.Expected
+			if expected == "" {
+				jsonData, err := json.Marshal(test.Data)
+				require.NoError(t, err)
+				expected = string(jsonData)
+			}
+
+			conv := NewConversion()
+			continuePipeline, result := conv.ConvertCBORToJSON(ctx, cborData)
+			require.True(t, continuePipeline, "unexpected error: %v", result)
+			assert.JSONEq(t, expected, result.(string))
+			assert.Equal(t, common.ContentTypeJSON, ctx.ResponseContentType())
+		})
+	}
+}
+
+func TestConvertCBORToJSONErrors(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Data          interface{}
+		ExpectedError string
+	}{
+		{"no data", nil, "No Data Received"},
+		{"unexpected type", "a string", "unexpected type received"},
+		{"empty input", []byte{}, "unable to decode CBOR"},
+		{"truncated map", []byte{0xa2, 0x61, 0x61, 0x01}, "unable to decode CBOR"},
+		{"reserved additional information", []byte{0x1c}, "unable to decode CBOR"},
+		{"extraneous data", []byte{0x01, 0x02}, "unable to decode CBOR"},
+		{"JSON input", []byte(`{"device":"sensor-1"}`), "unable to decode CBOR"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			conv := NewConversion()
+			continuePipeline, result := conv.ConvertCBORToJSON(ctx, test.Data)
+			assert.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			assert.Contains(t, result.(error).Error(), test.ExpectedError)
+		})
+	}
+}
+
+func TestConvertJSONToCBOR(t *testing.T) {
+	conv := NewConversion()
+
+	tests := []struct {
+		Name string
+		Data interface{}
+	}{
+		{"nested object", `{"device":"sensor-1","location":{"site":"plant-1","floor":2},"readings":[{"resource":"temp","value":21.5},{"resource":"count","value":-3}],"ok":true,"none":null}`},
+		{"large integers", []byte(`{"max":18446744073709551615,"min":-9223372036854775808,"exp":1e3}`)},
+		{"array", `[1,"two",[3.5]]`},
+		{"event", conversionTestEvent()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := conv.ConvertJSONToCBOR(ctx, test.Data)
+			require.True(t, continuePipeline, "unexpected error: %v", result)
+			assert.Equal(t, common.ContentTypeCBOR, ctx.ResponseContentType())
+			require.NoError(t, cbor.Wellformed(result.([]byte)))
+
+			continuePipeline, roundTrip := conv.ConvertCBORToJSON(ctx, result)
+			require.True(t, continuePipeline, "unexpected error: %v", roundTrip)
+
+			expected, err := util.CoerceType(test.Data)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), roundTrip.(string))
+		})
+	}
+
+	t.Run("integers encoded as integers", func(t *testing.T) {
+		continuePipeline, result := conv.ConvertJSONToCBOR(ctx, `{"int":42,"float":42.5}`)
+		require.True(t, continuePipeline, "unexpected error: %v", result)
+
+		var decoded map[string]interface{}
+		require.NoError(t, cbor.Unmarshal(result.([]byte), &decoded))
+		assert.Equal(t, uint64(42), decoded["int"])
+		assert.Equal(t, 42.5, decoded["float"])
+	})
+}
+
+func TestConvertJSONToCBORErrors(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Data          interface{}
+		ExpectedError string
+	}{
+		{"no data", nil, "No Data Received"},
+		{"invalid JSON", `{"device":`, "unable to parse JSON"},
+		{"not JSON", []byte("not json"), "unable to parse JSON"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			conv := NewConversion()
+			continuePipeline, result := conv.ConvertJSONToCBOR(ctx, test.Data)
+			assert.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			assert.Contains(t, result.(error).Error(), test.ExpectedError)
+		})
+	}
+}
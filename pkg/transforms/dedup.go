@@ -0,0 +1,161 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// DefaultDedupStateExpiry is how long the last forwarded value for a device and resource is kept when no
+// state expiry is specified.
+const DefaultDedupStateExpiry = time.Hour
+
+// Dedup suppresses Events whose reading values are the same as those last forwarded for each device and resource.
+type Dedup struct {
+	heartbeat   time.Duration
+	stateExpiry time.Duration
+	mutex       sync.Mutex
+	states      map[string]*dedupState
+	lastEvicted time.Time
+}
+
+type dedupState struct {
+	value         string
+	lastForwarded time.Time
+	lastSeen      time.Time
+}
+
+// NewDedup creates, initializes and returns a new instance of Dedup. When heartbeat is zero, Events are only
+// forwarded when a reading's value changes ("forward on change only"). Otherwise, an unchanged value is also
+// forwarded once heartbeat has elapsed since it was last forwarded. The value kept for a device and resource is
+// evicted when no readings have been received for it within stateExpiry, after which its next reading is
+// forwarded. DefaultDedupStateExpiry is used if stateExpiry is zero.
+func NewDedup(heartbeat time.Duration, stateExpiry time.Duration) (*Dedup, error) {
+	if heartbeat < 0 || stateExpiry < 0 {
+		return nil, errors.New("heartbeat and state expiry must not be negative")
+	}
+
+	if stateExpiry == 0 {
+		stateExpiry = DefaultDedupStateExpiry
+	}
+
+	if heartbeat > stateExpiry {
+		return nil, errors.New("state expiry must not be less than heartbeat")
+	}
+
+	return &Dedup{
+		heartbeat:   heartbeat,
+		stateExpiry: stateExpiry,
+		states:      make(map[string]*dedupState),
+	}, nil
+}
+
+// Dedup forwards the Event if any of its readings' values differ from the value last forwarded for the reading's
+// device and resource, or the heartbeat has elapsed for any of them. Otherwise, the pipeline is stopped.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (dedup *Dedup) Dedup(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Dedup in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function Dedup in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	if !dedup.isForwarded(event, time.Now()) {
+		ctx.LoggingClient().Debugf("Duplicate Event from %s dropped in pipeline '%s'", event.DeviceName, ctx.PipelineId())
+		return false, nil
+	}
+
+	return true, event
+}
+
+func (dedup *Dedup) isForwarded(event dtos.Event, now time.Time) bool {
+	dedup.mutex.Lock()
+	defer dedup.mutex.Unlock()
+
+	dedup.evictStale(now)
+
+	forward := false
+	values := make([]string, len(event.Readings))
+	for index, reading := range event.Readings {
+		values[index] = dedupValue(reading)
+
+		state, found := dedup.states[reading.DeviceName+"/"+reading.ResourceName]
+		if !found || state.value != values[index] ||
+			(dedup.heartbeat > 0 && now.Sub(state.lastForwarded) >= dedup.heartbeat) {
+			forward = true
+		}
+	}
+
+	for index, reading := range event.Readings {
+		key := reading.DeviceName + "/" + reading.ResourceName
+		state, found := dedup.states[key]
+		if !found {
+			state = &dedupState{}
+			dedup.states[key] = state
+		}
+
+		state.lastSeen = now
+		if forward {
+			state.value = values[index]
+			state.lastForwarded = now
+		}
+	}
+
+	return forward
+}
+
+// evictStale removes the state for devices and resources which haven't been seen within the state expiry.
+// The sweep is done at most once per state expiry so that the cost is amortized across Events.
+func (dedup *Dedup) evictStale(now time.Time) {
+	if now.Sub(dedup.lastEvicted) < dedup.stateExpiry {
+		return
+	}
+
+	for key, state := range dedup.states {
+		if now.Sub(state.lastSeen) >= dedup.stateExpiry {
+			delete(dedup.states, key)
+		}
+	}
+
+	dedup.lastEvicted = now
+}
+
+func dedupValue(reading dtos.BaseReading) string {
+	switch {
+	case reading.ObjectValue != nil:
+		value, err := json.Marshal(reading.ObjectValue)
+		if err != nil {
+			return fmt.Sprint(reading.ObjectValue)
+		}
+		return string(value)
+	case reading.BinaryValue != nil:
+		return string(reading.BinaryValue)
+	default:
+		return reading.Value
+	}
+}
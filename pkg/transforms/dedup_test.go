@@ -0,0 +1,183 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dedupEvent(device string, values ...string) dtos.Event {
+	event := dtos.Event{DeviceName: device, SourceName: "source"}
+	resources := []string{"temperature", "humidity"}
+	for index, value := range values {
+		event.Readings = append(event.Readings, dtos.BaseReading{
+			DeviceName:    device,
+			ResourceName:  resources[index],
+			SimpleReading: dtos.SimpleReading{Value: value},
+		})
+	}
+	return event
+}
+
+func TestNewDedup(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Heartbeat   time.Duration
+		StateExpiry time.Duration
+		ExpectError bool
+	}{
+		{"Valid - change only", 0, 0, false},
+		{"Valid - heartbeat", time.Minute, 0, false},
+		{"Valid - heartbeat and expiry", time.Minute, time.Minute, false},
+		{"Invalid - negative heartbeat", -time.Second, 0, true},
+		{"Invalid - negative expiry", 0, -time.Second, true},
+		{"Invalid - expiry less than heartbeat", time.Minute, time.Second, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			dedup, err := NewDedup(test.Heartbeat, test.StateExpiry)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, dedup)
+		})
+	}
+}
+
+func TestDedupRepeatedValues(t *testing.T) {
+	dedup, err := NewDedup(0, 0)
+	require.NoError(t, err)
+
+	event := dedupEvent(deviceName1, "20", "50")
+
+	continuePipeline, result := dedup.Dedup(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, event, result)
+
+	for i := 0; i < 3; i++ {
+		continuePipeline, result = dedup.Dedup(ctx, dedupEvent(deviceName1, "20", "50"))
+		assert.False(t, continuePipeline)
+		assert.Nil(t, result)
+	}
+
+	// Same values from another device aren't duplicates
+	continuePipeline, _ = dedup.Dedup(ctx, dedupEvent(deviceName2, "20", "50"))
+	assert.True(t, continuePipeline)
+}
+
+func TestDedupValueChanges(t *testing.T) {
+	dedup, err := NewDedup(0, 0)
+	require.NoError(t, err)
+
+	now := time.Now()
+	assert.True(t, dedup.isForwarded(dedupEvent(deviceName1, "20", "50"), now))
+	// Only one of the readings changing is enough to forward the Event
+	assert.True(t, dedup.isForwarded(dedupEvent(deviceName1, "20", "51"), now))
+	assert.False(t, dedup.isForwarded(dedupEvent(deviceName1, "20", "51"), now))
+	// Changing back to a previous value is a change
+	assert.True(t, dedup.isForwarded(dedupEvent(deviceName1, "20", "50"), now))
+	// Change only mode never forwards unchanged values, regardless of time elapsed
+	assert.False(t, dedup.isForwarded(dedupEvent(deviceName1, "20", "50"), now.Add(30*time.Minute)))
+
+	objectEvent := dtos.Event{DeviceName: deviceName1, Readings: []dtos.BaseReading{{
+		DeviceName:    deviceName1,
+		ResourceName:  "location",
+		ObjectReading: dtos.ObjectReading{ObjectValue: map[string]interface{}{"lat": 1}},
+	}}}
+	assert.True(t, dedup.isForwarded(objectEvent, now))
+	assert.False(t, dedup.isForwarded(objectEvent, now))
+	objectEvent.Readings[0].ObjectValue = map[string]interface{}{"lat": 2}
+	assert.True(t, dedup.isForwarded(objectEvent, now))
+}
+
+func TestDedupHeartbeatExpiry(t *testing.T) {
+	dedup, err := NewDedup(time.Minute, 0)
+	require.NoError(t, err)
+
+	start := time.Now()
+	event := dedupEvent(deviceName1, "20")
+
+	assert.True(t, dedup.isForwarded(event, start))
+	assert.False(t, dedup.isForwarded(event, start.Add(30*time.Second)))
+	assert.False(t, dedup.isForwarded(event, start.Add(59*time.Second)))
+	// Heartbeat elapsed so unchanged value is forwarded, which restarts the heartbeat
+	assert.True(t, dedup.isForwarded(event, start.Add(time.Minute)))
+	assert.False(t, dedup.isForwarded(event, start.Add(90*time.Second)))
+	assert.True(t, dedup.isForwarded(event, start.Add(2*time.Minute)))
+}
+
+func TestDedupStateEviction(t *testing.T) {
+	dedup, err := NewDedup(0, time.Minute)
+	require.NoError(t, err)
+
+	start := time.Now()
+	assert.True(t, dedup.isForwarded(dedupEvent(deviceName1, "20"), start))
+	assert.True(t, dedup.isForwarded(dedupEvent(deviceName2, "20"), start))
+	assert.Len(t, dedup.states, 2)
+
+	// Device 2 keeps being seen, so only device 1 is evicted
+	assert.False(t, dedup.isForwarded(dedupEvent(deviceName2, "20"), start.Add(30*time.Second)))
+	assert.False(t, dedup.isForwarded(dedupEvent(deviceName2, "20"), start.Add(70*time.Second)))
+	assert.Len(t, dedup.states, 1)
+
+	// Evicted, so same value is forwarded again
+	assert.True(t, dedup.isForwarded(dedupEvent(deviceName1, "20"), start.Add(80*time.Second)))
+}
+
+func TestDedupConcurrent(t *testing.T) {
+	dedup, err := NewDedup(0, 0)
+	require.NoError(t, err)
+
+	var forwarded atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if continuePipeline, _ := dedup.Dedup(ctx, dedupEvent(deviceName1, "20")); continuePipeline {
+				forwarded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), forwarded.Load())
+}
+
+func TestDedupBadInput(t *testing.T) {
+	dedup, err := NewDedup(0, 0)
+	require.NoError(t, err)
+
+	continuePipeline, result := dedup.Dedup(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = dedup.Dedup(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
@@ -0,0 +1,630 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// DefaultDiskSpoolRetryInterval is how long the DiskSpool's forwarder waits before retrying a failed forward
+// when no retry interval is specified.
+const DefaultDiskSpoolRetryInterval = 5 * time.Second
+
+const (
+	diskSpoolSegmentPrefix    = "spool-"
+	diskSpoolSegmentSuffix    = ".log"
+	diskSpoolAckFileName      = "spool.ack"
+	diskSpoolRecordHeaderSize = 8
+	maxDiskSpoolSegmentBytes  = 4 * 1024 * 1024
+)
+
+// DiskSpoolContextBuilder builds the context the DiskSpool's forwarder passes to the forward function for each
+// spooled item, i.e. the ApplicationService's BuildContext.
+type DiskSpoolContextBuilder func(correlationId string, contentType string) interfaces.AppFunctionContext
+
+// DiskSpool durably buffers data to disk and forwards it asynchronously, in order, using a background forwarder, so
+// that the pipeline isn't held up while the destination is unavailable. Data is appended to rolling segment files
+// in the spool directory and the position of the next item to forward is saved once each item is forwarded, so
+// items not yet forwarded are replayed after a restart or crash. Since an item may have been forwarded just before
+// a crash, before its position was saved, delivery is at least once.
+type DiskSpool struct {
+	dir           string
+	maxBytes      int64
+	segmentBytes  int64
+	retryInterval time.Duration
+
+	lock        sync.Mutex
+	segments    []diskSpoolSegment
+	writer      *os.File
+	readSegment uint64
+	readOffset  int64
+	size        int64
+	closed      bool
+	notify      chan struct{}
+	cancel      context.CancelFunc
+	done        chan struct{}
+
+	reader        *os.File
+	readerSegment uint64
+}
+
+type diskSpoolSegment struct {
+	id   uint64
+	size int64
+}
+
+type diskSpoolAck struct {
+	Segment uint64 `json:"segment"`
+	Offset  int64  `json:"offset"`
+}
+
+// NewDiskSpool creates, initializes and returns a new instance of DiskSpool which spools to dir, which is created if
+// it doesn't exist. maxBytes is the maximum size of the spool files, after which new data is rejected until
+// spooled data has been forwarded. Data left in dir from a previous run that wasn't forwarded is recovered, and a
+// partially written item, i.e. due to a crash, is discarded.
+func NewDiskSpool(dir string, maxBytes int64) (*DiskSpool, error) {
+	if len(dir) == 0 {
+		return nil, errors.New("spool directory must be specified")
+	}
+
+	if maxBytes <= 0 {
+		return nil, fmt.Errorf("maxBytes must be greater than zero, got %d", maxBytes)
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, fmt.Errorf("unable to create spool directory: %s", err.Error())
+	}
+
+	spool := &DiskSpool{
+		dir:           dir,
+		maxBytes:      maxBytes,
+		segmentBytes:  min(maxDiskSpoolSegmentBytes, max(maxBytes/4, 1)),
+		retryInterval: DefaultDiskSpoolRetryInterval,
+		notify:        make(chan struct{}, 1),
+	}
+
+	if err := spool.recover(); err != nil {
+		return nil, fmt.Errorf("unable to recover spool in '%s': %s", dir, err.Error())
+	}
+
+	return spool, nil
+}
+
+// SetRetryInterval sets how long the forwarder waits before retrying a failed forward
+func (spool *DiskSpool) SetRetryInterval(retryInterval time.Duration) {
+	spool.retryInterval = retryInterval
+}
+
+// Spool appends the data to the spool to be forwarded by the forwarder, along with the context's correlation id and
+// content type. The pipeline is stopped since the data is forwarded asynchronously.
+// It will return an error and stop the pipeline if the spool is full or the data can't be written to disk.
+func (spool *DiskSpool) Spool(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Spool in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	payload, err := util.CoerceType(data)
+	if err != nil {
+		return false, fmt.Errorf("function Spool in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	contentType := ctx.ResponseContentType()
+	if len(contentType) == 0 {
+		contentType = ctx.InputContentType()
+	}
+
+	if err := spool.append(encodeDiskSpoolRecord(ctx.CorrelationID(), contentType, payload)); err != nil {
+		return false, fmt.Errorf("unable to spool data in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.LoggingClient().Debugf("Spooled %d bytes of data in pipeline '%s'", len(payload), ctx.PipelineId())
+	return false, nil
+}
+
+// StartForwarder starts the background forwarder, which forwards the spooled data, in order, to the forward
+// function, i.e. an HTTPSender's HTTPPost, using a context built by contextBuilder for each item. An item is
+// retried after the retry interval for as long as the forward function returns an error. The forwarder stops when
+// ctx is done or the spool is closed.
+func (spool *DiskSpool) StartForwarder(ctx context.Context, contextBuilder DiskSpoolContextBuilder, forward interfaces.AppFunction) error {
+	if contextBuilder == nil || forward == nil {
+		return errors.New("context builder and forward function must be specified")
+	}
+
+	spool.lock.Lock()
+	defer spool.lock.Unlock()
+
+	if spool.closed {
+		return errors.New("spool is closed")
+	}
+
+	if spool.done != nil {
+		return errors.New("forwarder already started")
+	}
+
+	forwarderCtx, cancel := context.WithCancel(ctx)
+	spool.cancel = cancel
+	spool.done = make(chan struct{})
+
+	go spool.forward(forwarderCtx, contextBuilder, forward)
+
+	return nil
+}
+
+// Close stops the forwarder, waiting for an in progress forward to complete, and closes the spool files.
+// Data not yet forwarded remains in the spool directory and is recovered when the spool is next created.
+func (spool *DiskSpool) Close() error {
+	spool.lock.Lock()
+	if spool.closed {
+		spool.lock.Unlock()
+		return nil
+	}
+	spool.closed = true
+	cancel, done := spool.cancel, spool.done
+	spool.lock.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	spool.lock.Lock()
+	defer spool.lock.Unlock()
+
+	spool.closeReader()
+	return spool.writer.Close()
+}
+
+// recover loads the segments and the position of the next item to forward, discarding segments that have been
+// forwarded and any partially written item at the end of the last segment.
+func (spool *DiskSpool) recover() error {
+	entries, err := os.ReadDir(spool.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, diskSpoolSegmentPrefix) || !strings.HasSuffix(name, diskSpoolSegmentSuffix) {
+			continue
+		}
+
+		id, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, diskSpoolSegmentPrefix), diskSpoolSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		spool.segments = append(spool.segments, diskSpoolSegment{id: id, size: info.Size()})
+	}
+
+	sort.Slice(spool.segments, func(i, j int) bool { return spool.segments[i].id < spool.segments[j].id })
+
+	ack, err := spool.loadAck()
+	if err != nil {
+		return err
+	}
+
+	// Segments before the acknowledged segment were forwarded, but not removed before the spool was stopped
+	for len(spool.segments) > 0 && spool.segments[0].id < ack.Segment {
+		if err := os.Remove(spool.segmentPath(spool.segments[0].id)); err != nil {
+			return err
+		}
+		spool.segments = spool.segments[1:]
+	}
+
+	if len(spool.segments) == 0 {
+		spool.segments = append(spool.segments, diskSpoolSegment{id: ack.Segment + 1})
+	}
+
+	last := &spool.segments[len(spool.segments)-1]
+	validSize, err := spool.validSize(last.id)
+	if err != nil {
+		return err
+	}
+
+	spool.writer, err = os.OpenFile(spool.segmentPath(last.id), os.O_CREATE|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+
+	if validSize < last.size {
+		if err := spool.writer.Truncate(validSize); err != nil {
+			_ = spool.writer.Close()
+			return err
+		}
+		last.size = validSize
+	}
+
+	if _, err := spool.writer.Seek(last.size, io.SeekStart); err != nil {
+		_ = spool.writer.Close()
+		return err
+	}
+
+	spool.readSegment = spool.segments[0].id
+	if ack.Segment == spool.readSegment {
+		spool.readOffset = min(ack.Offset, spool.segments[0].size)
+	}
+
+	for _, segment := range spool.segments {
+		spool.size += segment.size
+	}
+
+	return nil
+}
+
+// validSize returns the size of the complete, uncorrupted items at the start of the segment
+func (spool *DiskSpool) validSize(id uint64) (int64, error) {
+	file, err := os.Open(spool.segmentPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	var offset int64
+	for {
+		_, next, err := readDiskSpoolRecord(file, offset, info.Size())
+		if err != nil {
+			return offset, nil
+		}
+		offset = next
+	}
+}
+
+// append writes the record to the last segment, rolling to a new segment when the last segment is full or when all
+// its items have been forwarded and the space is needed.
+func (spool *DiskSpool) append(record []byte) error {
+	spool.lock.Lock()
+	defer spool.lock.Unlock()
+
+	if spool.closed {
+		return errors.New("spool is closed")
+	}
+
+	recordSize := int64(len(record))
+	last := spool.segments[len(spool.segments)-1]
+	allForwarded := spool.readSegment == last.id && spool.readOffset == last.size
+
+	if last.size > 0 && (last.size+recordSize > spool.segmentBytes || (allForwarded && spool.size+recordSize > spool.maxBytes)) {
+		if err := spool.roll(); err != nil {
+			return err
+		}
+	}
+
+	if spool.size+recordSize > spool.maxBytes {
+		return fmt.Errorf("spool is full, %d of %d bytes used", spool.size, spool.maxBytes)
+	}
+
+	last = spool.segments[len(spool.segments)-1]
+	if _, err := spool.writer.Write(record); err != nil {
+		// Discard anything partially written so the next item is appended after the last complete item
+		_ = spool.writer.Truncate(last.size)
+		_, _ = spool.writer.Seek(last.size, io.SeekStart)
+		return err
+	}
+
+	if err := spool.writer.Sync(); err != nil {
+		return err
+	}
+
+	spool.segments[len(spool.segments)-1].size += recordSize
+	spool.size += recordSize
+
+	select {
+	case spool.notify <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// roll starts a new segment, removing the last segment if all its items have been forwarded
+func (spool *DiskSpool) roll() error {
+	last := spool.segments[len(spool.segments)-1]
+	next := diskSpoolSegment{id: last.id + 1}
+
+	writer, err := os.OpenFile(spool.segmentPath(next.id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+
+	_ = spool.writer.Close()
+	spool.writer = writer
+	spool.segments = append(spool.segments, next)
+
+	if spool.readSegment == last.id && spool.readOffset == last.size {
+		return spool.advanceSegment()
+	}
+
+	return nil
+}
+
+// advanceSegment removes the read segment, all of whose items have been forwarded, and moves to the next segment.
+// Must be called with the lock held.
+func (spool *DiskSpool) advanceSegment() error {
+	removed := spool.segments[0]
+	spool.segments = spool.segments[1:]
+	spool.size -= removed.size
+	spool.readSegment = spool.segments[0].id
+	spool.readOffset = 0
+
+	if spool.readerSegment == removed.id {
+		spool.closeReader()
+	}
+
+	if err := spool.saveAck(); err != nil {
+		return err
+	}
+
+	return os.Remove(spool.segmentPath(removed.id))
+}
+
+// forward forwards the spooled items until ctx is done
+func (spool *DiskSpool) forward(ctx context.Context, contextBuilder DiskSpoolContextBuilder, forward interfaces.AppFunction) {
+	defer close(spool.done)
+
+	for ctx.Err() == nil {
+		record, next, err := spool.next()
+		if err != nil {
+			if !spool.wait(ctx, spool.retryInterval) {
+				return
+			}
+			continue
+		}
+
+		if record == nil {
+			if !spool.wait(ctx, 0) {
+				return
+			}
+			continue
+		}
+
+		correlationId, contentType, payload := decodeDiskSpoolRecord(record)
+		forwardCtx := contextBuilder(correlationId, contentType)
+
+		// The pipeline is considered stopped without error, i.e. filtered, when no error is returned
+		if ok, result := forward(forwardCtx, payload); !ok {
+			if err, isError := result.(error); isError {
+				forwardCtx.LoggingClient().Warnf("Unable to forward spooled data, retrying in %s: %s", spool.retryInterval, err.Error())
+				if !spool.wait(ctx, spool.retryInterval) {
+					return
+				}
+				continue
+			}
+		}
+
+		if err := spool.acknowledge(next); err != nil {
+			forwardCtx.LoggingClient().Errorf("Unable to save spool position, the forwarded data may be forwarded again after a restart: %s", err.Error())
+		}
+	}
+}
+
+// wait waits for the timeout, or for data to be spooled if the timeout is zero, returning false if ctx is done
+func (spool *DiskSpool) wait(ctx context.Context, timeout time.Duration) bool {
+	if timeout <= 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-spool.notify:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// next returns the next record to forward and the offset following it, or a nil record if there is none
+func (spool *DiskSpool) next() ([]byte, int64, error) {
+	spool.lock.Lock()
+	defer spool.lock.Unlock()
+
+	for {
+		segment := spool.segments[0]
+		if spool.readOffset < segment.size {
+			break
+		}
+
+		if len(spool.segments) == 1 {
+			return nil, 0, nil
+		}
+
+		if err := spool.advanceSegment(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if spool.reader == nil || spool.readerSegment != spool.readSegment {
+		spool.closeReader()
+
+		reader, err := os.Open(spool.segmentPath(spool.readSegment))
+		if err != nil {
+			return nil, 0, err
+		}
+
+		spool.reader = reader
+		spool.readerSegment = spool.readSegment
+	}
+
+	record, next, err := readDiskSpoolRecord(spool.reader, spool.readOffset, spool.segments[0].size)
+	if err != nil {
+		// The rest of a corrupted segment can't be read, so is skipped
+		spool.readOffset = spool.segments[0].size
+		return nil, 0, fmt.Errorf("unable to read spooled data, skipping the rest of segment %d: %s", spool.readSegment, err.Error())
+	}
+
+	return record, next, nil
+}
+
+// acknowledge saves the position of the next item to forward
+func (spool *DiskSpool) acknowledge(next int64) error {
+	spool.lock.Lock()
+	defer spool.lock.Unlock()
+
+	spool.readOffset = next
+	return spool.saveAck()
+}
+
+func (spool *DiskSpool) loadAck() (diskSpoolAck, error) {
+	var ack diskSpoolAck
+
+	data, err := os.ReadFile(filepath.Join(spool.dir, diskSpoolAckFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return ack, nil
+	} else if err != nil {
+		return ack, err
+	}
+
+	if err := json.Unmarshal(data, &ack); err != nil {
+		return ack, fmt.Errorf("invalid spool position: %s", err.Error())
+	}
+
+	return ack, nil
+}
+
+// saveAck atomically replaces the file containing the position of the next item to forward
+func (spool *DiskSpool) saveAck() error {
+	data, err := json.Marshal(diskSpoolAck{Segment: spool.readSegment, Offset: spool.readOffset})
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(spool.dir, diskSpoolAckFileName)
+	file, err := os.OpenFile(path+".tmp", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+func (spool *DiskSpool) closeReader() {
+	if spool.reader != nil {
+		_ = spool.reader.Close()
+		spool.reader = nil
+	}
+}
+
+func (spool *DiskSpool) segmentPath(id uint64) string {
+	return filepath.Join(spool.dir, fmt.Sprintf("%s%020d%s", diskSpoolSegmentPrefix, id, diskSpoolSegmentSuffix))
+}
+
+// encodeDiskSpoolRecord encodes the item as the length and CRC-32 of the body followed by the body, which is the
+// length prefixed correlation id and content type followed by the payload.
+func encodeDiskSpoolRecord(correlationId string, contentType string, payload []byte) []byte {
+	bodySize := 2 + len(correlationId) + 2 + len(contentType) + len(payload)
+	record := make([]byte, diskSpoolRecordHeaderSize, diskSpoolRecordHeaderSize+bodySize)
+
+	record = binary.BigEndian.AppendUint16(record, uint16(len(correlationId)))
+	record = append(record, correlationId...)
+	record = binary.BigEndian.AppendUint16(record, uint16(len(contentType)))
+	record = append(record, contentType...)
+	record = append(record, payload...)
+
+	binary.BigEndian.PutUint32(record[0:4], uint32(bodySize))
+	binary.BigEndian.PutUint32(record[4:8], crc32.ChecksumIEEE(record[diskSpoolRecordHeaderSize:]))
+
+	return record
+}
+
+func decodeDiskSpoolRecord(body []byte) (string, string, []byte) {
+	correlationIdSize := int(binary.BigEndian.Uint16(body))
+	correlationId := string(body[2 : 2+correlationIdSize])
+	body = body[2+correlationIdSize:]
+
+	contentTypeSize := int(binary.BigEndian.Uint16(body))
+	contentType := string(body[2 : 2+contentTypeSize])
+
+	return correlationId, contentType, body[2+contentTypeSize:]
+}
+
+// readDiskSpoolRecord reads the body of the record at offset, returning the offset of the following record.
+// An error is returned if the record is incomplete, i.e. extends past size, or corrupted.
+func readDiskSpoolRecord(file *os.File, offset int64, size int64) ([]byte, int64, error) {
+	header := make([]byte, diskSpoolRecordHeaderSize)
+	if _, err := file.ReadAt(header, offset); err != nil {
+		return nil, 0, err
+	}
+
+	bodySize := int64(binary.BigEndian.Uint32(header[0:4]))
+	if bodySize < 4 || offset+diskSpoolRecordHeaderSize+bodySize > size {
+		return nil, 0, fmt.Errorf("invalid record size %d", bodySize)
+	}
+
+	body := make([]byte, bodySize)
+	if _, err := file.ReadAt(body, offset+diskSpoolRecordHeaderSize); err != nil {
+		return nil, 0, err
+	}
+
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, 0, errors.New("record checksum mismatch")
+	}
+
+	correlationIdSize := int64(binary.BigEndian.Uint16(body))
+	if 2+correlationIdSize+2 > bodySize || 2+correlationIdSize+2+int64(binary.BigEndian.Uint16(body[2+correlationIdSize:])) > bodySize {
+		return nil, 0, errors.New("invalid record")
+	}
+
+	return body, offset + diskSpoolRecordHeaderSize + bodySize, nil
+}
@@ -0,0 +1,243 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+type spooledItem struct {
+	correlationId string
+	contentType   string
+	payload       string
+}
+
+// spoolForwarder records the forwarded items, failing while failures is greater than zero
+type spoolForwarder struct {
+	mutex     sync.Mutex
+	items     []spooledItem
+	failures  int
+	forwarded chan struct{}
+}
+
+func newSpoolForwarder() *spoolForwarder {
+	return &spoolForwarder{forwarded: make(chan struct{}, 100)}
+}
+
+func (f *spoolForwarder) buildContext(correlationId string, contentType string) interfaces.AppFunctionContext {
+	return appfunction.NewContext(correlationId, dic, contentType)
+}
+
+func (f *spoolForwarder) forward(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.failures > 0 {
+		f.failures--
+		return false, errors.New("destination unavailable")
+	}
+
+	f.items = append(f.items, spooledItem{ctx.CorrelationID(), ctx.InputContentType(), string(data.([]byte))})
+	f.forwarded <- struct{}{}
+	return true, nil
+}
+
+func (f *spoolForwarder) waitFor(t *testing.T, count int) []spooledItem {
+	for i := 0; i < count; i++ {
+		select {
+		case <-f.forwarded:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "timed out waiting for forwarded data")
+		}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return append([]spooledItem(nil), f.items...)
+}
+
+func spoolData(t *testing.T, spool *DiskSpool, payloads ...string) {
+	for _, payload := range payloads {
+		spoolCtx := appfunction.NewContext("corr-"+payload, dic, common.ContentTypeJSON)
+		continuePipeline, result := spool.Spool(spoolCtx, payload)
+		require.False(t, continuePipeline)
+		require.Nil(t, result)
+	}
+}
+
+func spoolSegmentFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, diskSpoolSegmentPrefix+"*"+diskSpoolSegmentSuffix))
+	require.NoError(t, err)
+	return files
+}
+
+func TestNewDiskSpool(t *testing.T) {
+	_, err := NewDiskSpool("", 1024)
+	assert.Error(t, err)
+
+	_, err = NewDiskSpool(t.TempDir(), 0)
+	assert.Error(t, err)
+
+	dir := filepath.Join(t.TempDir(), "nested", "spool")
+	spool, err := NewDiskSpool(dir, 1024)
+	require.NoError(t, err)
+	require.NoError(t, spool.Close())
+	assert.DirExists(t, dir)
+}
+
+func TestDiskSpoolAppendAndDrain(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := NewDiskSpool(dir, 1024*1024)
+	require.NoError(t, err)
+	defer func() { _ = spool.Close() }()
+
+	// Small segments so the spool rolls over several segment files
+	spool.segmentBytes = 100
+
+	payloads := []string{"one", "two", "three", "four", "five", "six", "seven", "eight"}
+	spoolData(t, spool, payloads...)
+	assert.Greater(t, len(spoolSegmentFiles(t, dir)), 1)
+
+	continuePipeline, result := spool.Spool(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	forwarder := newSpoolForwarder()
+	require.NoError(t, spool.StartForwarder(context.Background(), forwarder.buildContext, forwarder.forward))
+	require.Error(t, spool.StartForwarder(context.Background(), forwarder.buildContext, forwarder.forward))
+
+	items := forwarder.waitFor(t, len(payloads))
+	for index, payload := range payloads {
+		assert.Equal(t, spooledItem{"corr-" + payload, common.ContentTypeJSON, payload}, items[index])
+	}
+
+	// Data spooled while the forwarder is running is forwarded as well
+	spoolData(t, spool, "nine")
+	items = forwarder.waitFor(t, 1)
+	assert.Equal(t, "nine", items[len(items)-1].payload)
+
+	// Forwarded segments are removed
+	require.NoError(t, spool.Close())
+	assert.Len(t, spoolSegmentFiles(t, dir), 1)
+}
+
+func TestDiskSpoolRetry(t *testing.T) {
+	spool, err := NewDiskSpool(t.TempDir(), 1024*1024)
+	require.NoError(t, err)
+	defer func() { _ = spool.Close() }()
+	spool.SetRetryInterval(10 * time.Millisecond)
+
+	forwarder := newSpoolForwarder()
+	forwarder.failures = 3
+
+	spoolData(t, spool, "first", "second")
+	require.NoError(t, spool.StartForwarder(context.Background(), forwarder.buildContext, forwarder.forward))
+
+	items := forwarder.waitFor(t, 2)
+	require.Len(t, items, 2)
+	assert.Equal(t, "first", items[0].payload)
+	assert.Equal(t, "second", items[1].payload)
+}
+
+func TestDiskSpoolFull(t *testing.T) {
+	record := encodeDiskSpoolRecord("corr-item", common.ContentTypeJSON, []byte("item"))
+	spool, err := NewDiskSpool(t.TempDir(), int64(len(record)*4))
+	require.NoError(t, err)
+	defer func() { _ = spool.Close() }()
+
+	spoolData(t, spool, "item", "item", "item", "item")
+
+	continuePipeline, result := spool.Spool(appfunction.NewContext("corr-item", dic, common.ContentTypeJSON), "item")
+	require.False(t, continuePipeline)
+	require.Error(t, result.(error))
+	assert.Contains(t, result.(error).Error(), "spool is full")
+
+	// Once forwarded, the space is reused
+	forwarder := newSpoolForwarder()
+	require.NoError(t, spool.StartForwarder(context.Background(), forwarder.buildContext, forwarder.forward))
+	forwarder.waitFor(t, 4)
+
+	require.Eventually(t, func() bool {
+		continuePipeline, result = spool.Spool(appfunction.NewContext("corr-item", dic, common.ContentTypeJSON), "item")
+		return result == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	forwarder.waitFor(t, 1)
+}
+
+func TestDiskSpoolRecovery(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := NewDiskSpool(dir, 1024*1024)
+	require.NoError(t, err)
+	spool.segmentBytes = 60
+
+	payloads := []string{"one", "two", "three", "four", "five"}
+	spoolData(t, spool, payloads...)
+
+	// Forward the first two items, then stop the forwarder without closing the spool, i.e. a crash
+	forwarder := newSpoolForwarder()
+	forwarderCtx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, spool.StartForwarder(forwarderCtx, forwarder.buildContext, func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		forwarder.mutex.Lock()
+		done := len(forwarder.items) == 2
+		forwarder.mutex.Unlock()
+		if done {
+			cancel()
+			return false, errors.New("crashed")
+		}
+		return forwarder.forward(ctx, data)
+	}))
+	forwarder.waitFor(t, 2)
+	<-spool.done
+
+	// Simulate an item that was partially written when the service crashed
+	files := spoolSegmentFiles(t, dir)
+	lastSegment, err := os.OpenFile(files[len(files)-1], os.O_APPEND|os.O_WRONLY, 0640)
+	require.NoError(t, err)
+	_, err = lastSegment.Write(encodeDiskSpoolRecord("corr-torn", common.ContentTypeJSON, []byte("torn"))[:10])
+	require.NoError(t, err)
+	require.NoError(t, lastSegment.Close())
+
+	recovered, err := NewDiskSpool(dir, 1024*1024)
+	require.NoError(t, err)
+	defer func() { _ = recovered.Close() }()
+
+	spoolData(t, recovered, "six")
+
+	replayed := newSpoolForwarder()
+	require.NoError(t, recovered.StartForwarder(context.Background(), replayed.buildContext, replayed.forward))
+	items := replayed.waitFor(t, 4)
+
+	var payloadsReplayed []string
+	for _, item := range items {
+		payloadsReplayed = append(payloadsReplayed, item.payload)
+	}
+	assert.Equal(t, []string{"three", "four", "five", "six"}, payloadsReplayed)
+	assert.Equal(t, "corr-three", items[0].correlationId)
+}
@@ -30,7 +30,11 @@ import (
 type Filter struct {
 	FilterValues []string
 	FilterOut    bool
-	ctx          interfaces.AppFunctionContext
+	// PassthroughOnNoMatch passes Events which aren't accepted by the filter on unchanged, rather than stopping
+	// the pipeline, so filters can be composed without dropping Events
+	PassthroughOnNoMatch bool
+	ctx                  interfaces.AppFunctionContext
+	patterns             []*regexp.Regexp
 }
 
 // NewFilterFor creates, initializes and returns a new instance of Filter
@@ -45,6 +49,24 @@ func NewFilterOut(filterValues []string) *Filter {
 	return &Filter{FilterValues: filterValues, FilterOut: true}
 }
 
+// NewFilterByResourceNameRegex creates, initializes and returns a new instance of Filter for use with FilterByResourceName
+// that filters for readings whose resource name matches any of the specified regular expressions. The regular
+// expressions are compiled once here, rather than on every invocation, so an error is returned if any are invalid.
+// Patterns are unanchored unless they start with '^' and/or end with '$'.
+func NewFilterByResourceNameRegex(patterns []string) (*Filter, error) {
+	filter := &Filter{FilterValues: patterns, FilterOut: false}
+
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("bad regexp (%s) for resource name filter: %s", pattern, err.Error())
+		}
+		filter.patterns = append(filter.patterns, compiled)
+	}
+
+	return filter, nil
+}
+
 // FilterByProfileName filters based on the specified Device Profile, aka Class of Device.
 // If FilterOut is false, it filters out those Events not associated with the specified Device Profile listed in FilterValues.
 // If FilterOut is true, it out those Events that are associated with the specified Device Profile listed in FilterValues.
@@ -60,7 +82,7 @@ func (f *Filter) FilterByProfileName(ctx interfaces.AppFunctionContext, data int
 		return true, *event
 	}
 
-	return false, nil
+	return f.noMatch(*event)
 
 }
 
@@ -79,7 +101,7 @@ func (f *Filter) FilterByDeviceName(ctx interfaces.AppFunctionContext, data inte
 		return true, *event
 	}
 
-	return false, nil
+	return f.noMatch(*event)
 }
 
 // FilterBySourceName filters based on the specified Source for the Event, aka resource or command name.
@@ -97,7 +119,7 @@ func (f *Filter) FilterBySourceName(ctx interfaces.AppFunctionContext, data inte
 		return true, *event
 	}
 
-	return false, nil
+	return f.noMatch(*event)
 }
 
 // FilterByResourceName filters based on the specified Reading resource names, aka Instance of a Device.
@@ -122,47 +144,26 @@ func (f *Filter) FilterByResourceName(ctx interfaces.AppFunctionContext, data in
 	auxEvent.Origin = existingEvent.Origin
 	auxEvent.Readings = []dtos.BaseReading{}
 
-	if f.FilterOut {
-		for _, reading := range existingEvent.Readings {
-			readingFilteredOut := false
-			for _, name := range f.FilterValues {
-				item, err := regexp.Compile(name)
-				if err != nil {
-					return false, fmt.Errorf("bad regexp (%s) in filtering on pipeline '%s': %s", name, ctx.PipelineId(), err.Error())
-				}
-				if item.MatchString(reading.ResourceName) {
-					readingFilteredOut = true
-					break
-				}
-			}
+	patterns, err := f.compilePatterns()
+	if err != nil {
+		return false, fmt.Errorf("%s in filtering on pipeline '%s'", err.Error(), ctx.PipelineId())
+	}
 
-			if !readingFilteredOut {
-				ctx.LoggingClient().Debugf("Reading accepted in pipeline '%s' for resource %s", f.ctx.PipelineId(), reading.ResourceName)
-				auxEvent.Readings = append(auxEvent.Readings, reading)
-			} else {
-				ctx.LoggingClient().Debugf("Reading not accepted in pipeline '%s' for resource %s", f.ctx.PipelineId(), reading.ResourceName)
+	for _, reading := range existingEvent.Readings {
+		readingMatched := false
+		for _, pattern := range patterns {
+			if pattern.MatchString(reading.ResourceName) {
+				readingMatched = true
+				break
 			}
 		}
-	} else {
-		for _, reading := range existingEvent.Readings {
-			readingFilteredFor := false
-			for _, name := range f.FilterValues {
-				item, err := regexp.Compile(name)
-				if err != nil {
-					return false, fmt.Errorf("bad regexp (%s) in filtering on  pipeline '%s': %s", name, ctx.PipelineId(), err.Error())
-				}
-				if item.MatchString(reading.ResourceName) {
-					readingFilteredFor = true
-					break
-				}
-			}
 
-			if readingFilteredFor {
-				ctx.LoggingClient().Debugf("Reading accepted in pipeline '%s' for resource %s", f.ctx.PipelineId(), reading.ResourceName)
-				auxEvent.Readings = append(auxEvent.Readings, reading)
-			} else {
-				ctx.LoggingClient().Debugf("Reading not accepted in pipeline '%s' for resource %s", f.ctx.PipelineId(), reading.ResourceName)
-			}
+		// Readings which match are kept when filtering for and dropped when filtering out
+		if readingMatched != f.FilterOut {
+			ctx.LoggingClient().Debugf("Reading accepted in pipeline '%s' for resource %s", f.ctx.PipelineId(), reading.ResourceName)
+			auxEvent.Readings = append(auxEvent.Readings, reading)
+		} else {
+			ctx.LoggingClient().Debugf("Reading not accepted in pipeline '%s' for resource %s", f.ctx.PipelineId(), reading.ResourceName)
 		}
 	}
 
@@ -172,9 +173,106 @@ func (f *Filter) FilterByResourceName(ctx interfaces.AppFunctionContext, data in
 	}
 
 	ctx.LoggingClient().Debugf("Event not accepted: 0 remaining readings in pipeline '%s'", f.ctx.PipelineId())
+	return f.noMatch(*existingEvent)
+}
+
+// TagsFilter houses the tag key/values which the FilterByTags transform filters on
+type TagsFilter struct {
+	RequiredTags map[string]string
+	FilterOut    bool
+	// PassthroughOnNoMatch passes Events which aren't accepted by the filter on unchanged, rather than stopping
+	// the pipeline, so filters can be composed without dropping Events
+	PassthroughOnNoMatch bool
+}
+
+// NewTagsFilter creates, initializes and returns a new instance of TagsFilter
+// that defaults FilterOut to false, so it is filtering for Events with the required tags
+func NewTagsFilter(requiredTags map[string]string) *TagsFilter {
+	return &TagsFilter{RequiredTags: requiredTags, FilterOut: false}
+}
+
+// NewTagsFilterOut creates, initializes and returns a new instance of TagsFilter
+// that defaults FilterOut to true, so it is filtering out Events with the required tags
+func NewTagsFilterOut(requiredTags map[string]string) *TagsFilter {
+	return &TagsFilter{RequiredTags: requiredTags, FilterOut: true}
+}
+
+// FilterByTags filters based on the Event's tags, such as those added by the AddTags function.
+// If FilterOut is false, it filters out those Events which don't have all the tag key/values listed in RequiredTags.
+// If FilterOut is true, it filters out those Events which have all the tag key/values listed in RequiredTags.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (f *TagsFilter) FilterByTags(ctx interfaces.AppFunctionContext, data interface{}) (continuePipeline bool, result interface{}) {
+	mode := "For"
+	if f.FilterOut {
+		mode = "Out"
+	}
+	ctx.LoggingClient().Debugf("Filtering %s by Tags in. RequiredTags are: '[%v]'", mode, f.RequiredTags)
+
+	if data == nil {
+		return false, fmt.Errorf("FilterByTags: no Event Received in pipeline '%s'", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("FilterByTags: type received is not an Event in pipeline '%s'", ctx.PipelineId())
+	}
+
+	// No tags to filter for, so pass events through rather than filtering them all out.
+	if len(f.RequiredTags) == 0 {
+		return true, event
+	}
+
+	hasAllTags := true
+	for key, requiredValue := range f.RequiredTags {
+		value, found := event.Tags[key]
+		if !found || fmt.Sprint(value) != requiredValue {
+			hasAllTags = false
+			break
+		}
+	}
+
+	if hasAllTags != f.FilterOut {
+		ctx.LoggingClient().Debugf("Event accepted for Tags=%v in pipeline '%s'", event.Tags, ctx.PipelineId())
+		return true, event
+	}
+
+	ctx.LoggingClient().Debugf("Event not accepted for Tags=%v in pipeline '%s'", event.Tags, ctx.PipelineId())
+	if f.PassthroughOnNoMatch {
+		return true, event
+	}
+
+	return false, nil
+}
+
+// noMatch returns the result for an Event which isn't accepted by the filter, which is the original Event when
+// PassthroughOnNoMatch is set, otherwise the pipeline is stopped
+func (f *Filter) noMatch(event dtos.Event) (bool, interface{}) {
+	if f.PassthroughOnNoMatch {
+		f.ctx.LoggingClient().Debugf("Passing through Event not accepted in pipeline '%s'", f.ctx.PipelineId())
+		return true, event
+	}
+
 	return false, nil
 }
 
+// compilePatterns returns the regular expressions compiled by the constructor or compiles the FilterValues
+func (f *Filter) compilePatterns() ([]*regexp.Regexp, error) {
+	if f.patterns != nil {
+		return f.patterns, nil
+	}
+
+	patterns := make([]*regexp.Regexp, 0, len(f.FilterValues))
+	for _, name := range f.FilterValues {
+		item, err := regexp.Compile(name)
+		if err != nil {
+			return nil, fmt.Errorf("bad regexp (%s): %s", name, err.Error())
+		}
+		patterns = append(patterns, item)
+	}
+
+	return patterns, nil
+}
+
 func (f *Filter) setupForFiltering(funcName string, filterProperty string, lc logger.LoggingClient, data interface{}) (*dtos.Event, error) {
 	mode := "For"
 	if f.FilterOut {
@@ -17,8 +17,11 @@
 package transforms
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/requests"
@@ -301,3 +304,169 @@ func TestFilter_FilterByResourceName(t *testing.T) {
 		})
 	}
 }
+
+func TestTagsFilter_FilterByTags(t *testing.T) {
+	taggedEvent := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	taggedEvent.Tags = map[string]interface{}{"site": "plant-1", "line": "a", "priority": 2}
+	untaggedEvent := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+
+	tests := []struct {
+		Name              string
+		RequiredTags      map[string]string
+		FilterOut         bool
+		EventIn           *dtos.Event
+		ExpectedNilResult bool
+	}{
+		{"filter for - no event", map[string]string{"site": "plant-1"}, false, nil, true},
+		{"filter for - no required tags", map[string]string{}, false, &taggedEvent, false},
+		{"filter for - single tag match", map[string]string{"site": "plant-1"}, false, &taggedEvent, false},
+		{"filter for - all tags match", map[string]string{"site": "plant-1", "line": "a", "priority": "2"}, false, &taggedEvent, false},
+		{"filter for - partial match", map[string]string{"site": "plant-1", "line": "b"}, false, &taggedEvent, true},
+		{"filter for - tag missing", map[string]string{"zone": "north"}, false, &taggedEvent, true},
+		{"filter for - event has no tags", map[string]string{"site": "plant-1"}, false, &untaggedEvent, true},
+
+		{"filter out - no event", map[string]string{"site": "plant-1"}, true, nil, true},
+		{"filter out - no required tags", map[string]string{}, true, &taggedEvent, false},
+		{"filter out - all tags match", map[string]string{"site": "plant-1", "line": "a"}, true, &taggedEvent, true},
+		{"filter out - partial match", map[string]string{"site": "plant-1", "line": "b"}, true, &taggedEvent, false},
+		{"filter out - event has no tags", map[string]string{"site": "plant-1"}, true, &untaggedEvent, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var filter *TagsFilter
+			if test.FilterOut {
+				filter = NewTagsFilterOut(test.RequiredTags)
+			} else {
+				filter = NewTagsFilter(test.RequiredTags)
+			}
+
+			expectedContinue := !test.ExpectedNilResult
+
+			if test.EventIn == nil {
+				continuePipeline, result := filter.FilterByTags(ctx, nil)
+				assert.Contains(t, result.(error).Error(), "FilterByTags: no Event Received")
+				assert.False(t, continuePipeline)
+			} else {
+				continuePipeline, result := filter.FilterByTags(ctx, *test.EventIn)
+				assert.Equal(t, expectedContinue, continuePipeline)
+				assert.Equal(t, test.ExpectedNilResult, result == nil)
+				if result != nil {
+					assert.Equal(t, *test.EventIn, result)
+				}
+			}
+		})
+	}
+}
+
+func TestTagsFilter_FilterByTagsNotAnEvent(t *testing.T) {
+	filter := NewTagsFilter(map[string]string{"site": "plant-1"})
+	continuePipeline, result := filter.FilterByTags(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
+
+func TestFilter_NewFilterByResourceNameRegex(t *testing.T) {
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	for _, resourceName := range []string{"temperature", "temperature-avg", "humidity", "room-temperature"} {
+		err := event.AddSimpleReading(resourceName, common.ValueTypeInt32, int32(123))
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		Name              string
+		Patterns          []string
+		ExpectedResources []string
+	}{
+		{"unanchored - matches anywhere", []string{"temperature"}, []string{"temperature", "temperature-avg", "room-temperature"}},
+		{"anchored start", []string{"^temperature"}, []string{"temperature", "temperature-avg"}},
+		{"anchored start and end", []string{"^temperature$"}, []string{"temperature"}},
+		{"anchored end", []string{"temperature$"}, []string{"temperature", "room-temperature"}},
+		{"multiple patterns", []string{"^humidity$", "-avg$"}, []string{"temperature-avg", "humidity"}},
+		{"no matches", []string{"^pressure$"}, nil},
+		{"no patterns - no change", []string{}, []string{"temperature", "temperature-avg", "humidity", "room-temperature"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			filter, err := NewFilterByResourceNameRegex(test.Patterns)
+			require.NoError(t, err)
+
+			continuePipeline, result := filter.FilterByResourceName(ctx, event)
+			if len(test.ExpectedResources) == 0 {
+				assert.False(t, continuePipeline)
+				assert.Nil(t, result)
+				return
+			}
+
+			require.True(t, continuePipeline)
+			actualEvent, ok := result.(dtos.Event)
+			require.True(t, ok)
+
+			var actualResources []string
+			for _, reading := range actualEvent.Readings {
+				actualResources = append(actualResources, reading.ResourceName)
+			}
+			assert.Equal(t, test.ExpectedResources, actualResources)
+		})
+	}
+}
+
+func TestFilter_NewFilterByResourceNameRegexInvalid(t *testing.T) {
+	filter, err := NewFilterByResourceNameRegex([]string{"^temperature$", "[bad"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "[bad")
+	assert.Nil(t, filter)
+}
+
+func TestFilter_PassthroughOnNoMatch(t *testing.T) {
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	require.NoError(t, event.AddSimpleReading(resource1, common.ValueTypeInt32, int32(1)))
+	event.Tags = map[string]interface{}{"site": "plant-1"}
+
+	filters := map[string]func(filter *Filter) interfaces.AppFunction{
+		"FilterByProfileName":  func(filter *Filter) interfaces.AppFunction { return filter.FilterByProfileName },
+		"FilterByDeviceName":   func(filter *Filter) interfaces.AppFunction { return filter.FilterByDeviceName },
+		"FilterBySourceName":   func(filter *Filter) interfaces.AppFunction { return filter.FilterBySourceName },
+		"FilterByResourceName": func(filter *Filter) interfaces.AppFunction { return filter.FilterByResourceName },
+	}
+
+	for name, getFunction := range filters {
+		for _, passthrough := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s passthrough %t", name, passthrough), func(t *testing.T) {
+				filter := NewFilterFor([]string{"no-match"})
+				filter.PassthroughOnNoMatch = passthrough
+
+				continuePipeline, result := getFunction(filter)(ctx, event)
+				assert.Equal(t, passthrough, continuePipeline)
+				if passthrough {
+					assert.Equal(t, event, result)
+				} else {
+					assert.Nil(t, result)
+				}
+
+				// Matched Events are unaffected by passthrough
+				filter = NewFilterOut([]string{"no-match"})
+				filter.PassthroughOnNoMatch = passthrough
+				continuePipeline, result = getFunction(filter)(ctx, event)
+				require.True(t, continuePipeline)
+				assert.Equal(t, event.Readings, result.(dtos.Event).Readings)
+			})
+		}
+	}
+
+	for _, passthrough := range []bool{false, true} {
+		t.Run(fmt.Sprintf("FilterByTags passthrough %t", passthrough), func(t *testing.T) {
+			filter := NewTagsFilter(map[string]string{"site": "plant-2"})
+			filter.PassthroughOnNoMatch = passthrough
+
+			continuePipeline, result := filter.FilterByTags(ctx, event)
+			assert.Equal(t, passthrough, continuePipeline)
+			if passthrough {
+				assert.Equal(t, event, result)
+			} else {
+				assert.Nil(t, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// DefaultFlattenSeparator is the separator used between the keys of the nested levels by FlattenJSON when none is
+// specified
+const DefaultFlattenSeparator = "."
+
+// JSONFlattener flattens nested JSON objects into a single level object with the keys of the nested levels joined
+// by the separator, i.e. {"a":{"b":{"c":1}}} is flattened to {"a.b.c":1}
+type JSONFlattener struct {
+	separator            string
+	indexArrays          bool
+	passThroughNonObject bool
+}
+
+// flattenEntry is a value still to be flattened and the flattened key it is under
+type flattenEntry struct {
+	key   string
+	value interface{}
+}
+
+// NewJSONFlattener creates, initializes and returns a new instance of JSONFlattener which joins the keys of the
+// nested levels with the separator. DefaultFlattenSeparator is used if separator is empty.
+func NewJSONFlattener(separator string) *JSONFlattener {
+	if len(separator) == 0 {
+		separator = DefaultFlattenSeparator
+	}
+
+	return &JSONFlattener{
+		separator: separator,
+	}
+}
+
+// SetIndexArrays sets whether arrays are also flattened, using the element index as the key, i.e. {"a":[1,2]} is
+// flattened to {"a.0":1,"a.1":2}, rather than kept as array values.
+func (flattener *JSONFlattener) SetIndexArrays(indexArrays bool) {
+	flattener.indexArrays = indexArrays
+}
+
+// SetPassThroughNonObject sets whether data that isn't a JSON object is passed on unchanged, rather than returning
+// an error.
+func (flattener *JSONFlattener) SetPassThroughNonObject(passThrough bool) {
+	flattener.passThroughNonObject = passThrough
+}
+
+// FlattenJSON flattens the nested objects, and optionally arrays, in the JSON object data into a single level JSON
+// object. Empty objects and arrays are kept as values so their keys aren't lost. Numbers are passed through with
+// their original precision. The flattening is iterative so deeply nested data doesn't exhaust the stack.
+// It will return an error and stop the pipeline if the data isn't a JSON object, unless PassThroughNonObject is set,
+// if flattened keys collide, i.e. "a.b" and "a":{"b"}, or if no data is received.
+func (flattener *JSONFlattener) FlattenJSON(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function FlattenJSON in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Flattening JSON in pipeline '%s'", ctx.PipelineId())
+
+	jsonData, err := util.CoerceType(data)
+	if err != nil {
+		return false, fmt.Errorf("function FlattenJSON in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return false, fmt.Errorf("function FlattenJSON in pipeline '%s': unable to unmarshal data as JSON: %s", ctx.PipelineId(), err.Error())
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		if flattener.passThroughNonObject {
+			ctx.LoggingClient().Debugf("Data is not a JSON object, passing it on unchanged in pipeline '%s'", ctx.PipelineId())
+			return true, data
+		}
+
+		return false, fmt.Errorf("function FlattenJSON in pipeline '%s': data is not a JSON object", ctx.PipelineId())
+	}
+
+	flattened, err := flattener.flatten(object)
+	if err != nil {
+		return false, fmt.Errorf("function FlattenJSON in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	result, err := json.Marshal(flattened)
+	if err != nil {
+		return false, fmt.Errorf("function FlattenJSON in pipeline '%s': unable to marshal flattened JSON: %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.SetResponseContentType(common.ContentTypeJSON)
+	return true, result
+}
+
+// flatten flattens the object using a stack of the values still to be flattened rather than recursion
+func (flattener *JSONFlattener) flatten(object map[string]interface{}) (map[string]interface{}, error) {
+	flattened := make(map[string]interface{})
+	stack := make([]flattenEntry, 0, len(object))
+	for key, value := range object {
+		stack = append(stack, flattenEntry{key: key, value: value})
+	}
+
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch value := entry.value.(type) {
+		case map[string]interface{}:
+			if len(value) > 0 {
+				for key, child := range value {
+					stack = append(stack, flattenEntry{key: entry.key + flattener.separator + key, value: child})
+				}
+				continue
+			}
+		case []interface{}:
+			if flattener.indexArrays && len(value) > 0 {
+				for index, child := range value {
+					stack = append(stack, flattenEntry{key: entry.key + flattener.separator + strconv.Itoa(index), value: child})
+				}
+				continue
+			}
+		}
+
+		if _, exists := flattened[entry.key]; exists {
+			return nil, fmt.Errorf("flattened key '%s' is not unique", entry.key)
+		}
+
+		flattened[entry.key] = entry.value
+	}
+
+	return flattened, nil
+}
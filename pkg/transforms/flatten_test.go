@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenJSON(t *testing.T) {
+	nested := `{"device":{"name":"sensor1","location":{"lat":45.5,"long":-122.6}},"values":[1,{"a":2}],"empty":{},"none":[],"big":12345678901234567890}`
+
+	tests := []struct {
+		Name        string
+		Separator   string
+		IndexArrays bool
+		Data        interface{}
+		Expected    string
+	}{
+		{
+			Name:     "nested objects",
+			Data:     nested,
+			Expected: `{"big":12345678901234567890,"device.location.lat":45.5,"device.location.long":-122.6,"device.name":"sensor1","empty":{},"none":[],"values":[1,{"a":2}]}`,
+		},
+		{
+			Name:        "nested objects and arrays",
+			IndexArrays: true,
+			Data:        []byte(nested),
+			Expected:    `{"big":12345678901234567890,"device.location.lat":45.5,"device.location.long":-122.6,"device.name":"sensor1","empty":{},"none":[],"values.0":1,"values.1.a":2}`,
+		},
+		{
+			Name:      "custom separator",
+			Separator: "_",
+			Data:      `{"a":{"b":{"c":true}}}`,
+			Expected:  `{"a_b_c":true}`,
+		},
+		{
+			Name:     "already flat",
+			Data:     map[string]interface{}{"a": "x", "b": nil},
+			Expected: `{"a":"x","b":null}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			flattener := NewJSONFlattener(test.Separator)
+			flattener.SetIndexArrays(test.IndexArrays)
+
+			continuePipeline, result := flattener.FlattenJSON(ctx, test.Data)
+			require.True(t, continuePipeline, result)
+			assert.JSONEq(t, test.Expected, string(result.([]byte)))
+			assert.Equal(t, common.ContentTypeJSON, ctx.ResponseContentType())
+		})
+	}
+}
+
+func TestFlattenJSONDeeplyNested(t *testing.T) {
+	depth := 5000
+	data := strings.Repeat(`{"a":`, depth) + "1" + strings.Repeat("}", depth)
+
+	continuePipeline, result := NewJSONFlattener("").FlattenJSON(ctx, data)
+	require.True(t, continuePipeline, result)
+
+	var flattened map[string]interface{}
+	require.NoError(t, json.Unmarshal(result.([]byte), &flattened))
+	require.Len(t, flattened, 1)
+	assert.Contains(t, flattened, strings.Repeat("a.", depth-1)+"a")
+}
+
+func TestFlattenJSONNonObject(t *testing.T) {
+	flattener := NewJSONFlattener("")
+
+	continuePipeline, result := flattener.FlattenJSON(ctx, `[1, 2]`)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "data is not a JSON object")
+
+	flattener.SetPassThroughNonObject(true)
+	continuePipeline, result = flattener.FlattenJSON(ctx, `[1, 2]`)
+	require.True(t, continuePipeline)
+	assert.Equal(t, `[1, 2]`, result)
+}
+
+func TestFlattenJSONErrors(t *testing.T) {
+	flattener := NewJSONFlattener("")
+
+	continuePipeline, result := flattener.FlattenJSON(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = flattener.FlattenJSON(ctx, `{"a":`)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "unable to unmarshal data as JSON")
+
+	continuePipeline, result = flattener.FlattenJSON(ctx, `{"a.b":1,"a":{"b":2}}`)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "flattened key 'a.b' is not unique")
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	gometrics "github.com/rcrowley/go-metrics"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// FreshnessFilter drops Events and readings whose Origin timestamp is older than the maximum age, such as old
+// buffered readings replayed by a device.
+type FreshnessFilter struct {
+	maxAge        time.Duration
+	clockSkew     time.Duration
+	droppedMetric gometrics.Counter
+	now           func() time.Time
+}
+
+// NewFreshnessFilter creates, initializes and returns a new instance of FreshnessFilter which drops readings whose
+// Origin is older than maxAge.
+func NewFreshnessFilter(maxAge time.Duration) (*FreshnessFilter, error) {
+	if maxAge <= 0 {
+		return nil, errors.New("max age must be greater than zero")
+	}
+
+	return &FreshnessFilter{
+		maxAge:        maxAge,
+		droppedMetric: gometrics.NewCounter(),
+		now:           time.Now,
+	}, nil
+}
+
+// SetClockSkewTolerance sets the additional age allowed for Origin timestamps to account for the device's clock
+// being behind this service's clock.
+func (filter *FreshnessFilter) SetClockSkewTolerance(tolerance time.Duration) error {
+	if tolerance < 0 {
+		return errors.New("clock skew tolerance must not be negative")
+	}
+
+	filter.clockSkew = tolerance
+	return nil
+}
+
+// FilterStale removes the readings whose Origin is older than the maximum age from the Event. The pipeline is stopped
+// if all the Event's readings are stale, or for an Event without readings, if the Event's Origin is stale. Readings
+// without an Origin use the Event's Origin and data without any Origin is never considered stale.
+// The number of stale readings, and Events without readings, dropped is counted by the FreshnessFilterDropped metric.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (filter *FreshnessFilter) FilterStale(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function FilterStale in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function FilterStale in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.FreshnessFilterDroppedName, ctx.PipelineId()) },
+		func() any { return filter.droppedMetric },
+		map[string]string{"pipeline": ctx.PipelineId()})
+
+	oldest := filter.now().Add(-(filter.maxAge + filter.clockSkew)).UnixNano()
+
+	if len(event.Readings) == 0 {
+		if filter.isStale(event.Origin, oldest) {
+			filter.droppedMetric.Inc(1)
+			ctx.LoggingClient().Debugf("Stale Event from %s dropped in pipeline '%s'", event.DeviceName, ctx.PipelineId())
+			return false, nil
+		}
+
+		return true, event
+	}
+
+	freshReadings := make([]dtos.BaseReading, 0, len(event.Readings))
+	for _, reading := range event.Readings {
+		origin := reading.Origin
+		if origin == 0 {
+			origin = event.Origin
+		}
+
+		if filter.isStale(origin, oldest) {
+			continue
+		}
+
+		freshReadings = append(freshReadings, reading)
+	}
+
+	dropped := len(event.Readings) - len(freshReadings)
+	if dropped == 0 {
+		return true, event
+	}
+
+	filter.droppedMetric.Inc(int64(dropped))
+
+	if len(freshReadings) == 0 {
+		ctx.LoggingClient().Debugf("Stale Event from %s dropped in pipeline '%s'", event.DeviceName, ctx.PipelineId())
+		return false, nil
+	}
+
+	ctx.LoggingClient().Debugf("%d stale readings removed from Event from %s in pipeline '%s'", dropped, event.DeviceName, ctx.PipelineId())
+
+	event.Readings = freshReadings
+	return true, event
+}
+
+func (filter *FreshnessFilter) isStale(origin int64, oldest int64) bool {
+	return origin != 0 && origin < oldest
+}
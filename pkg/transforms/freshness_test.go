@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFreshnessFilter(t *testing.T) {
+	_, err := NewFreshnessFilter(time.Minute)
+	require.NoError(t, err)
+
+	_, err = NewFreshnessFilter(0)
+	require.Error(t, err)
+
+	_, err = NewFreshnessFilter(-time.Minute)
+	require.Error(t, err)
+
+	filter, err := NewFreshnessFilter(time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, filter.SetClockSkewTolerance(time.Second))
+	require.Error(t, filter.SetClockSkewTolerance(-time.Second))
+}
+
+func TestFreshnessFilterFilterStale(t *testing.T) {
+	now := time.Now()
+	fresh := now.Add(-time.Second).UnixNano()
+	borderline := now.Add(-time.Minute).UnixNano()
+	stale := now.Add(-time.Minute - time.Nanosecond).UnixNano()
+	skewed := now.Add(-time.Minute - 5*time.Second).UnixNano()
+
+	reading := func(name string, origin int64) dtos.BaseReading {
+		return dtos.BaseReading{ResourceName: name, Origin: origin}
+	}
+
+	tests := []struct {
+		Name              string
+		ClockSkew         time.Duration
+		Event             dtos.Event
+		ExpectContinue    bool
+		ExpectedResources []string
+		ExpectedDropped   int64
+	}{
+		{"All fresh", 0, dtos.Event{Origin: fresh, Readings: []dtos.BaseReading{reading("r1", fresh), reading("r2", fresh)}}, true, []string{"r1", "r2"}, 0},
+		{"Borderline is fresh", 0, dtos.Event{Origin: borderline, Readings: []dtos.BaseReading{reading("r1", borderline)}}, true, []string{"r1"}, 0},
+		{"All stale", 0, dtos.Event{Origin: stale, Readings: []dtos.BaseReading{reading("r1", stale), reading("r2", stale)}}, false, nil, 2},
+		{"Mixed", 0, dtos.Event{Origin: fresh, Readings: []dtos.BaseReading{reading("r1", stale), reading("r2", fresh), reading("r3", borderline)}}, true, []string{"r2", "r3"}, 1},
+		{"Reading without origin uses event origin", 0, dtos.Event{Origin: stale, Readings: []dtos.BaseReading{reading("r1", 0), reading("r2", fresh)}}, true, []string{"r2"}, 1},
+		{"No origin not stale", 0, dtos.Event{Readings: []dtos.BaseReading{reading("r1", 0)}}, true, []string{"r1"}, 0},
+		{"Within clock skew tolerance", 10 * time.Second, dtos.Event{Origin: skewed, Readings: []dtos.BaseReading{reading("r1", skewed)}}, true, []string{"r1"}, 0},
+		{"Beyond clock skew tolerance", time.Second, dtos.Event{Origin: skewed, Readings: []dtos.BaseReading{reading("r1", skewed)}}, false, nil, 1},
+		{"Fresh event without readings", 0, dtos.Event{Origin: fresh}, true, nil, 0},
+		{"Stale event without readings", 0, dtos.Event{Origin: stale}, false, nil, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			filter, err := NewFreshnessFilter(time.Minute)
+			require.NoError(t, err)
+			require.NoError(t, filter.SetClockSkewTolerance(test.ClockSkew))
+			filter.now = func() time.Time { return now }
+
+			continuePipeline, result := filter.FilterStale(ctx, test.Event)
+			assert.Equal(t, test.ExpectContinue, continuePipeline)
+			assert.Equal(t, test.ExpectedDropped, filter.droppedMetric.Count())
+
+			if !test.ExpectContinue {
+				assert.Nil(t, result)
+				return
+			}
+
+			event, ok := result.(dtos.Event)
+			require.True(t, ok)
+
+			var resources []string
+			for _, reading := range event.Readings {
+				resources = append(resources, reading.ResourceName)
+			}
+			assert.Equal(t, test.ExpectedResources, resources)
+		})
+	}
+}
+
+func TestFreshnessFilterMixedDoesNotModifyInput(t *testing.T) {
+	filter, err := NewFreshnessFilter(time.Minute)
+	require.NoError(t, err)
+
+	event := dtos.Event{Readings: []dtos.BaseReading{
+		{ResourceName: "r1", Origin: time.Now().Add(-time.Hour).UnixNano()},
+		{ResourceName: "r2", Origin: time.Now().UnixNano()},
+	}}
+
+	continuePipeline, result := filter.FilterStale(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Len(t, result.(dtos.Event).Readings, 1)
+	assert.Len(t, event.Readings, 2)
+	assert.Equal(t, "r1", event.Readings[0].ResourceName)
+}
+
+func TestFreshnessFilterBadInput(t *testing.T) {
+	filter, err := NewFreshnessFilter(time.Minute)
+	require.NoError(t, err)
+
+	continuePipeline, result := filter.FilterStale(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = filter.FilterStale(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
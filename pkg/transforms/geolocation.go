@@ -0,0 +1,215 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v3/errors"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+const (
+	// DefaultGeoLocationCacheTTL is how long a device's location is cached when no cache TTL is specified.
+	DefaultGeoLocationCacheTTL = 5 * time.Minute
+	// GeoLocationLatitudeTag is the Event tag the device's latitude is added to.
+	GeoLocationLatitudeTag = "latitude"
+	// GeoLocationLongitudeTag is the Event tag the device's longitude is added to.
+	GeoLocationLongitudeTag = "longitude"
+)
+
+// GeoLocation enriches Events with the location of their device, which is read from the device's protocol
+// properties in Core Metadata.
+type GeoLocation struct {
+	protocol     string
+	latitudeKey  string
+	longitudeKey string
+	contextKey   string
+	cacheTTL     time.Duration
+	mutex        sync.Mutex
+	cache        map[string]geoLocationEntry
+}
+
+type geoLocationEntry struct {
+	found     bool
+	latitude  float64
+	longitude float64
+	expires   time.Time
+}
+
+// NewGeoLocation creates, initializes and returns a new instance of GeoLocation. latitudeKey and longitudeKey are
+// the names of the device protocol properties holding the location, which are looked up in the named protocol or in
+// all the device's protocols when protocol is empty. Device locations are cached for cacheTTL, which defaults to
+// DefaultGeoLocationCacheTTL if zero, to avoid a Core Metadata request for every Event.
+func NewGeoLocation(protocol string, latitudeKey string, longitudeKey string, cacheTTL time.Duration) (*GeoLocation, error) {
+	if len(latitudeKey) == 0 || len(longitudeKey) == 0 {
+		return nil, errors.New("latitude and longitude keys must be specified")
+	}
+
+	if cacheTTL < 0 {
+		return nil, errors.New("cache TTL must not be negative")
+	}
+
+	if cacheTTL == 0 {
+		cacheTTL = DefaultGeoLocationCacheTTL
+	}
+
+	return &GeoLocation{
+		protocol:     protocol,
+		latitudeKey:  latitudeKey,
+		longitudeKey: longitudeKey,
+		cacheTTL:     cacheTTL,
+		cache:        make(map[string]geoLocationEntry),
+	}, nil
+}
+
+// SetContextKey sets the context storage key the device's location is stored in as "latitude,longitude",
+// rather than adding it to the Event's tags.
+func (geo *GeoLocation) SetContextKey(contextKey string) {
+	geo.contextKey = contextKey
+}
+
+// AddGeoLocation looks up the location of the Event's device and adds it to the Event's tags or to the context
+// storage when a context key has been set. Events from devices which are unknown or have no location are passed on
+// unchanged with a logged warning.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (geo *GeoLocation) AddGeoLocation(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function AddGeoLocation in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function AddGeoLocation in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	entry, err := geo.lookup(ctx, event.DeviceName, time.Now())
+	if err != nil {
+		ctx.LoggingClient().Warnf("Unable to add location for device '%s' in pipeline '%s': %s", event.DeviceName, ctx.PipelineId(), err.Error())
+		return true, event
+	}
+
+	if !entry.found {
+		ctx.LoggingClient().Debugf("No location found for device '%s' in pipeline '%s'", event.DeviceName, ctx.PipelineId())
+		return true, event
+	}
+
+	if len(geo.contextKey) > 0 {
+		ctx.AddValue(geo.contextKey, fmt.Sprintf("%s,%s",
+			strconv.FormatFloat(entry.latitude, 'f', -1, 64),
+			strconv.FormatFloat(entry.longitude, 'f', -1, 64)))
+		return true, event
+	}
+
+	if event.Tags == nil {
+		event.Tags = make(map[string]interface{})
+	}
+
+	event.Tags[GeoLocationLatitudeTag] = entry.latitude
+	event.Tags[GeoLocationLongitudeTag] = entry.longitude
+
+	return true, event
+}
+
+// lookup returns the cached location for the device, retrieving it from Core Metadata if not cached or expired.
+// Unknown devices and devices without a location are also cached so that Core Metadata isn't repeatedly requested
+// for them. Other lookup failures are not cached so the lookup is retried for the next Event.
+func (geo *GeoLocation) lookup(ctx interfaces.AppFunctionContext, deviceName string, now time.Time) (geoLocationEntry, error) {
+	geo.mutex.Lock()
+	defer geo.mutex.Unlock()
+
+	if entry, found := geo.cache[deviceName]; found && now.Before(entry.expires) {
+		return entry, nil
+	}
+
+	client := ctx.DeviceClient()
+	if client == nil {
+		return geoLocationEntry{}, errors.New("DeviceClient not initialized. Core Metadata is missing from clients configuration")
+	}
+
+	entry := geoLocationEntry{expires: now.Add(geo.cacheTTL)}
+
+	response, err := client.DeviceByName(context.Background(), deviceName)
+	if err != nil {
+		if edgexErrors.Kind(err) != edgexErrors.KindEntityDoesNotExist {
+			return geoLocationEntry{}, fmt.Errorf("failed to retrieve device from Core Metadata: %s", err.Error())
+		}
+
+		ctx.LoggingClient().Warnf("Device '%s' not found in Core Metadata in pipeline '%s'. Event passed on without location", deviceName, ctx.PipelineId())
+		geo.cache[deviceName] = entry
+		return entry, nil
+	}
+
+	var locationErr error
+	entry.latitude, entry.longitude, locationErr = geo.location(response.Device)
+	if locationErr != nil {
+		ctx.LoggingClient().Warnf("Device '%s' has no valid location in pipeline '%s'. Event passed on without location: %s", deviceName, ctx.PipelineId(), locationErr.Error())
+		geo.cache[deviceName] = entry
+		return entry, nil
+	}
+
+	entry.found = true
+	geo.cache[deviceName] = entry
+	return entry, nil
+}
+
+// location reads the latitude and longitude from the device's protocol properties.
+func (geo *GeoLocation) location(device dtos.Device) (float64, float64, error) {
+	for name, properties := range device.Protocols {
+		if len(geo.protocol) > 0 && name != geo.protocol {
+			continue
+		}
+
+		latitudeValue, hasLatitude := properties[geo.latitudeKey]
+		longitudeValue, hasLongitude := properties[geo.longitudeKey]
+		if !hasLatitude || !hasLongitude {
+			continue
+		}
+
+		latitude, err := geoCoordinate(latitudeValue)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid '%s' property: %s", geo.latitudeKey, err.Error())
+		}
+
+		longitude, err := geoCoordinate(longitudeValue)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid '%s' property: %s", geo.longitudeKey, err.Error())
+		}
+
+		return latitude, longitude, nil
+	}
+
+	return 0, 0, fmt.Errorf("'%s' and '%s' protocol properties not found", geo.latitudeKey, geo.longitudeKey)
+}
+
+func geoCoordinate(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return strconv.ParseFloat(fmt.Sprint(v), 64)
+	}
+}
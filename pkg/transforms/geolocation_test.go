@@ -0,0 +1,199 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+	"time"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/responses"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v3/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+)
+
+func geoLocationContext(client *mocks.DeviceClient) *appfunction.Context {
+	geoDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.DeviceClientName: func(get di.Get) interface{} {
+			return client
+		},
+	})
+
+	return appfunction.NewContext("123", geoDic, "")
+}
+
+func geoLocationDevice(name string, protocols map[string]dtos.ProtocolProperties) responses.DeviceResponse {
+	return responses.DeviceResponse{Device: dtos.Device{Name: name, Protocols: protocols}}
+}
+
+func TestNewGeoLocation(t *testing.T) {
+	tests := []struct {
+		name         string
+		latitudeKey  string
+		longitudeKey string
+		cacheTTL     time.Duration
+		expectError  bool
+	}{
+		{"Valid", "Latitude", "Longitude", time.Minute, false},
+		{"Valid default TTL", "Latitude", "Longitude", 0, false},
+		{"Missing latitude key", "", "Longitude", 0, true},
+		{"Missing longitude key", "Latitude", "", 0, true},
+		{"Negative TTL", "Latitude", "Longitude", -time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			geo, err := NewGeoLocation("", tt.latitudeKey, tt.longitudeKey, tt.cacheTTL)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			if tt.cacheTTL == 0 {
+				assert.Equal(t, DefaultGeoLocationCacheTTL, geo.cacheTTL)
+			}
+		})
+	}
+}
+
+func TestGeoLocationAddGeoLocation(t *testing.T) {
+	notFound := edgexErrors.NewCommonEdgeX(edgexErrors.KindEntityDoesNotExist, "device not found", nil)
+	unavailable := edgexErrors.NewCommonEdgeX(edgexErrors.KindServiceUnavailable, "metadata unavailable", nil)
+
+	tests := []struct {
+		name              string
+		protocol          string
+		contextKey        string
+		device            responses.DeviceResponse
+		lookupErr         edgexErrors.EdgeX
+		expectedLatitude  interface{}
+		expectedLongitude interface{}
+		expectedContext   string
+	}{
+		{"Float properties", "", "", geoLocationDevice("device1", map[string]dtos.ProtocolProperties{
+			"gps": {"Latitude": 45.5, "Longitude": -122.25}}), nil, 45.5, -122.25, ""},
+		{"String properties", "", "", geoLocationDevice("device1", map[string]dtos.ProtocolProperties{
+			"gps": {"Latitude": "45.5", "Longitude": "-122.25"}}), nil, 45.5, -122.25, ""},
+		{"Named protocol", "gps", "", geoLocationDevice("device1", map[string]dtos.ProtocolProperties{
+			"other": {"Latitude": 1.0, "Longitude": 2.0},
+			"gps":   {"Latitude": 45.5, "Longitude": -122.25}}), nil, 45.5, -122.25, ""},
+		{"Context key", "", "location", geoLocationDevice("device1", map[string]dtos.ProtocolProperties{
+			"gps": {"Latitude": 45.5, "Longitude": -122.25}}), nil, nil, nil, "45.5,-122.25"},
+		{"No location properties", "", "", geoLocationDevice("device1", map[string]dtos.ProtocolProperties{
+			"modbus": {"Address": "localhost"}}), nil, nil, nil, ""},
+		{"Invalid location properties", "", "", geoLocationDevice("device1", map[string]dtos.ProtocolProperties{
+			"gps": {"Latitude": "north", "Longitude": "-122.25"}}), nil, nil, nil, ""},
+		{"Unknown device", "", "", responses.DeviceResponse{}, notFound, nil, nil, ""},
+		{"Metadata unavailable", "", "", responses.DeviceResponse{}, unavailable, nil, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.DeviceClient{}
+			client.On("DeviceByName", mock.Anything, deviceName1).Return(tt.device, tt.lookupErr)
+			geoContext := geoLocationContext(client)
+
+			geo, err := NewGeoLocation(tt.protocol, "Latitude", "Longitude", 0)
+			require.NoError(t, err)
+			geo.SetContextKey(tt.contextKey)
+
+			continuePipeline, result := geo.AddGeoLocation(geoContext, dtos.Event{DeviceName: deviceName1})
+			require.True(t, continuePipeline)
+			event, ok := result.(dtos.Event)
+			require.True(t, ok)
+
+			assert.Equal(t, tt.expectedLatitude, event.Tags[GeoLocationLatitudeTag])
+			assert.Equal(t, tt.expectedLongitude, event.Tags[GeoLocationLongitudeTag])
+
+			if len(tt.contextKey) > 0 {
+				value, found := geoContext.GetValue(tt.contextKey)
+				require.True(t, found)
+				assert.Equal(t, tt.expectedContext, value)
+			}
+		})
+	}
+}
+
+func TestGeoLocationCache(t *testing.T) {
+	tests := []struct {
+		name            string
+		lookupErr       edgexErrors.EdgeX
+		expectedLookups int
+	}{
+		{"Location cached", nil, 1},
+		{"Unknown device cached", edgexErrors.NewCommonEdgeX(edgexErrors.KindEntityDoesNotExist, "device not found", nil), 1},
+		{"Lookup failure not cached", edgexErrors.NewCommonEdgeX(edgexErrors.KindServiceUnavailable, "unavailable", nil), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := geoLocationDevice(deviceName1, map[string]dtos.ProtocolProperties{
+				"gps": {"Latitude": 45.5, "Longitude": -122.25}})
+
+			client := &mocks.DeviceClient{}
+			client.On("DeviceByName", mock.Anything, deviceName1).Return(device, tt.lookupErr)
+			geoContext := geoLocationContext(client)
+
+			geo, err := NewGeoLocation("", "Latitude", "Longitude", time.Minute)
+			require.NoError(t, err)
+
+			now := time.Now()
+			_, err = geo.lookup(geoContext, deviceName1, now)
+			require.Equal(t, tt.lookupErr != nil && edgexErrors.Kind(tt.lookupErr) != edgexErrors.KindEntityDoesNotExist, err != nil)
+			_, _ = geo.lookup(geoContext, deviceName1, now.Add(time.Second))
+			client.AssertNumberOfCalls(t, "DeviceByName", tt.expectedLookups)
+
+			// Cached entry is refreshed once expired
+			_, _ = geo.lookup(geoContext, deviceName1, now.Add(2*time.Minute))
+			client.AssertNumberOfCalls(t, "DeviceByName", tt.expectedLookups+1)
+		})
+	}
+}
+
+func TestGeoLocationNoDeviceClient(t *testing.T) {
+	geo, err := NewGeoLocation("", "Latitude", "Longitude", 0)
+	require.NoError(t, err)
+
+	event := dtos.Event{DeviceName: deviceName1}
+	continuePipeline, result := geo.AddGeoLocation(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, event, result)
+}
+
+func TestGeoLocationBadInput(t *testing.T) {
+	geo, err := NewGeoLocation("", "Latitude", "Longitude", 0)
+	require.NoError(t, err)
+
+	continuePipeline, result := geo.AddGeoLocation(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+
+	continuePipeline, result = geo.AddGeoLocation(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Error(t, result.(error))
+}
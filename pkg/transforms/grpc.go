@@ -0,0 +1,361 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	coreCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	gometrics "github.com/rcrowley/go-metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// GRPCSenderOptions contains all options available to the GRPCSender
+type GRPCSenderOptions struct {
+	// Target is the address of the gRPC server, i.e. "host:port"
+	Target string
+	// Method is the full name of the unary RPC method to invoke, i.e. "/package.Service/Method"
+	Method string
+	// NewRequest returns a new instance of the request message, which the data is unmarshaled into from JSON.
+	// If nil, the data is sent as the already serialized request message.
+	NewRequest func() proto.Message
+	// NewResponse returns a new instance of the response message, which is returned marshaled to JSON.
+	// If nil, the serialized response message is returned.
+	NewResponse func() proto.Message
+	// Timeout is the deadline for each RPC. Zero means no deadline.
+	Timeout time.Duration
+	// PersistOnError enables use of store & forward loop if true
+	PersistOnError bool
+	// ContinueOnSendError allows execution of subsequent chained senders after errors if true
+	ContinueOnSendError bool
+	// ReturnInputData enables chaining multiple senders if true
+	ReturnInputData bool
+	// UseTLS enables TLS for the connection. The server's certificate is verified using the system CAs unless
+	// CACertKey is specified.
+	UseTLS bool
+	// TLSSecretName is the name of the secret in the SecretStore containing the PEM encoded client certificate,
+	// key and CA bundle used for TLS
+	TLSSecretName string
+	// ClientCertKey is the optional key for the client certificate, used for mutual TLS, in the TLSSecretName secret data
+	ClientCertKey string
+	// ClientKeyKey is the optional key for the client private key in the TLSSecretName secret data
+	ClientKeyKey string
+	// CACertKey is the optional key for the CA bundle used to verify the server in the TLSSecretName secret data
+	CACertKey string
+	// TokenSecretName is the optional name of the secret in the SecretStore containing a bearer token sent in the
+	// 'authorization' metadata of each RPC
+	TokenSecretName string
+	// TokenSecretKey is the key for the bearer token in the TokenSecretName secret data
+	TokenSecretKey string
+	// Context is the parent of the context used for each RPC so that in-flight RPCs are aborted when it is cancelled,
+	// i.e. set to the ApplicationService's AppContext(). Defaults to context.Background() if nil.
+	Context context.Context
+}
+
+// GRPCSender invokes a unary RPC with the pipeline data as the request message. The connection is reused for all
+// RPCs and is re-created when the secrets it uses have been updated.
+type GRPCSender struct {
+	options GRPCSenderOptions
+	// connLock is held for reading by each RPC while it uses the connection, so the connection is only closed,
+	// when re-created or by Close, once the RPCs in flight have completed.
+	connLock           sync.RWMutex
+	conn               *grpc.ClientConn
+	closed             bool
+	connSecretsUpdated time.Time
+	grpcSizeMetrics    gometrics.Histogram
+	grpcErrorMetric    gometrics.Counter
+}
+
+// grpcRawCodec passes already serialized messages through as is. It is named "proto" so the content sub-type
+// matches what servers expect for protobuf messages.
+type grpcRawCodec struct{}
+
+func (grpcRawCodec) Marshal(v any) ([]byte, error) {
+	data, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return *data, nil
+}
+
+func (grpcRawCodec) Unmarshal(data []byte, v any) error {
+	target, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*target = append([]byte(nil), data...)
+	return nil
+}
+
+func (grpcRawCodec) Name() string {
+	return "proto"
+}
+
+// NewGRPCSender creates, initializes and returns a new instance of GRPCSender
+func NewGRPCSender(options GRPCSenderOptions) (*GRPCSender, error) {
+	if len(options.Target) == 0 || len(options.Method) == 0 {
+		return nil, errors.New("target and method must be specified")
+	}
+
+	if len(options.TokenSecretName) > 0 && len(options.TokenSecretKey) == 0 {
+		return nil, errors.New("TokenSecretKey must be specified when TokenSecretName is specified")
+	}
+
+	if (len(options.ClientCertKey) > 0 || len(options.ClientKeyKey) > 0 || len(options.CACertKey) > 0) && len(options.TLSSecretName) == 0 {
+		return nil, errors.New("TLSSecretName must be specified when ClientCertKey, ClientKeyKey or CACertKey are specified")
+	}
+
+	if (len(options.ClientCertKey) > 0) != (len(options.ClientKeyKey) > 0) {
+		return nil, errors.New("ClientCertKey & ClientKeyKey must both be specified for mutual TLS")
+	}
+
+	if options.PersistOnError && options.ContinueOnSendError {
+		return nil, errors.New("persistOnError & continueOnSendError can not both be set to true")
+	}
+
+	if options.ContinueOnSendError && !options.ReturnInputData {
+		return nil, errors.New("continueOnSendError can only be used in conjunction returnInputData")
+	}
+
+	return &GRPCSender{
+		options:         options,
+		grpcErrorMetric: gometrics.NewCounter(),
+		grpcSizeMetrics: gometrics.NewHistogram(gometrics.NewUniformSample(internal.MetricsReservoirSize)),
+	}, nil
+}
+
+// GRPCSend invokes the RPC method with the data from the previous function as the request message. The response,
+// or the input data when ReturnInputData is set, is passed to the next function.
+// If no previous function exists, then the event that triggered the pipeline will be used.
+func (sender *GRPCSender) GRPCSend(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function GRPCSend in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	exportData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	request := exportData
+	if sender.options.NewRequest != nil {
+		message := sender.options.NewRequest()
+		if err := protojson.Unmarshal(exportData, message); err != nil {
+			return false, fmt.Errorf("in pipeline '%s', unable to unmarshal data to gRPC request message: %s", ctx.PipelineId(), err.Error())
+		}
+
+		request, err = proto.Marshal(message)
+		if err != nil {
+			return false, fmt.Errorf("in pipeline '%s', unable to marshal gRPC request message: %s", ctx.PipelineId(), err.Error())
+		}
+	}
+
+	tagValue := sender.options.Target + sender.options.Method
+	tag := map[string]string{"target/method": tagValue}
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.GrpcExportErrorsName, tagValue) },
+		func() any { return sender.grpcErrorMetric },
+		tag)
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.GrpcExportSizeName, tagValue) },
+		func() any { return sender.grpcSizeMetrics },
+		tag)
+
+	conn, release, err := sender.getConnection(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var response []byte
+	err = sender.invoke(ctx, conn, request, &response)
+	release()
+	if err != nil {
+		sender.grpcErrorMetric.Inc(1)
+		err = fmt.Errorf("in pipeline '%s', gRPC export to %s%s failed: %w", ctx.PipelineId(), sender.options.Target, sender.options.Method, err)
+
+		// If continuing on send error then can't be persisting on error since Store and Forward retries starting
+		// with the function that failed and stopped the execution of the pipeline.
+		if !sender.options.ContinueOnSendError {
+			if sender.options.PersistOnError {
+				ctx.SetRetryData(exportData)
+			}
+			return false, err
+		}
+
+		ctx.LoggingClient().Errorf("Continuing pipeline on error in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		return true, data
+	}
+
+	// Data successfully sent, so retry any failed data, if Store and Forward enabled and data has been saved
+	if sender.options.PersistOnError {
+		ctx.TriggerRetryFailedData()
+	}
+
+	sender.grpcSizeMetrics.Update(int64(len(request)))
+
+	ctx.LoggingClient().Debugf("Sent %d bytes of data to gRPC %s%s in pipeline '%s'", len(request), sender.options.Target, sender.options.Method, ctx.PipelineId())
+	ctx.LoggingClient().Tracef("Data exported to gRPC in pipeline '%s': %s=%s", ctx.PipelineId(), coreCommon.CorrelationHeader, ctx.CorrelationID())
+
+	if sender.options.ReturnInputData {
+		return true, data
+	}
+
+	if sender.options.NewResponse != nil {
+		message := sender.options.NewResponse()
+		if err := proto.Unmarshal(response, message); err != nil {
+			return false, fmt.Errorf("in pipeline '%s', unable to unmarshal gRPC response message: %s", ctx.PipelineId(), err.Error())
+		}
+
+		responseJSON, err := protojson.Marshal(message)
+		if err != nil {
+			return false, fmt.Errorf("in pipeline '%s', unable to marshal gRPC response message to JSON: %s", ctx.PipelineId(), err.Error())
+		}
+
+		return true, responseJSON
+	}
+
+	return true, response
+}
+
+// invoke invokes the RPC with the configured deadline and the bearer token, if any, in the metadata
+func (sender *GRPCSender) invoke(ctx interfaces.AppFunctionContext, conn *grpc.ClientConn, request []byte, response *[]byte) error {
+	rpcContext := sender.options.Context
+	if rpcContext == nil {
+		rpcContext = context.Background()
+	}
+
+	if sender.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		rpcContext, cancel = context.WithTimeout(rpcContext, sender.options.Timeout)
+		defer cancel()
+	}
+
+	if len(sender.options.TokenSecretName) > 0 {
+		secrets, err := ctx.SecretProvider().GetSecret(sender.options.TokenSecretName, sender.options.TokenSecretKey)
+		if err != nil {
+			return err
+		}
+
+		rpcContext = metadata.AppendToOutgoingContext(rpcContext, "authorization", "Bearer "+secrets[sender.options.TokenSecretKey])
+	}
+
+	return conn.Invoke(rpcContext, sender.options.Method, &request, response, grpc.ForceCodec(grpcRawCodec{}))
+}
+
+// Close closes the connection once the RPCs in flight have completed. Sends after Close fail.
+func (sender *GRPCSender) Close() error {
+	sender.connLock.Lock()
+	defer sender.connLock.Unlock()
+
+	sender.closed = true
+	if sender.conn == nil {
+		return nil
+	}
+
+	err := sender.conn.Close()
+	sender.conn = nil
+	return err
+}
+
+// getConnection returns the connection, creating it on first use, with the read lock held so it isn't closed while
+// in use. The caller must call the returned release function once done with the connection.
+func (sender *GRPCSender) getConnection(ctx interfaces.AppFunctionContext) (*grpc.ClientConn, func(), error) {
+	sender.connLock.RLock()
+	if sender.isConnectionStale(ctx) {
+		sender.connLock.RUnlock()
+		if err := sender.createConnection(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		sender.connLock.RLock()
+	}
+
+	// The sender may have been closed while the connection was being created
+	if sender.conn == nil {
+		sender.connLock.RUnlock()
+		return nil, nil, fmt.Errorf("in pipeline '%s', gRPC sender is closed", ctx.PipelineId())
+	}
+
+	return sender.conn, sender.connLock.RUnlock, nil
+}
+
+// isConnectionStale returns true if the connection needs to be created, i.e. on first use or if TLS secrets are in
+// use and the secrets have been updated since it was created. The lock must be held by the caller.
+func (sender *GRPCSender) isConnectionStale(ctx interfaces.AppFunctionContext) bool {
+	return sender.conn == nil ||
+		(sender.usingSecrets() && sender.connSecretsUpdated.Before(ctx.SecretProvider().SecretsLastUpdated()))
+}
+
+func (sender *GRPCSender) usingSecrets() bool {
+	return sender.options.UseTLS && len(sender.options.TLSSecretName) > 0
+}
+
+// createConnection creates the connection, closing the one it replaces once the RPCs using it have completed,
+// unless another send has already re-created it.
+func (sender *GRPCSender) createConnection(ctx interfaces.AppFunctionContext) error {
+	sender.connLock.Lock()
+	defer sender.connLock.Unlock()
+
+	if sender.closed {
+		return fmt.Errorf("in pipeline '%s', gRPC sender is closed", ctx.PipelineId())
+	}
+
+	if !sender.isConnectionStale(ctx) {
+		return nil
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if sender.options.UseTLS {
+		tlsConfig, err := loadTLSConfig(ctx, sender.options.TLSSecretName, sender.options.ClientCertKey, sender.options.ClientKeyKey, sender.options.CACertKey)
+		if err != nil {
+			return err
+		}
+
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(sender.options.Target, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return fmt.Errorf("in pipeline '%s', unable to create gRPC connection to %s: %s", ctx.PipelineId(), sender.options.Target, err.Error())
+	}
+
+	if sender.conn != nil {
+		_ = sender.conn.Close()
+	}
+
+	sender.conn = conn
+	if sender.usingSecrets() {
+		sender.connSecretsUpdated = time.Now()
+	}
+
+	return nil
+}
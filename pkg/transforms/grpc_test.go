@@ -0,0 +1,280 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const testGRPCMethod = "/test.Ingest/Send"
+
+// startTestGRPCServer starts an in-process gRPC server which acknowledges StringValue requests to any method,
+// failing those with the value "fail" or "slow" (after a delay) and echoing the 'authorization' metadata.
+func startTestGRPCServer(t *testing.T) (string, *atomic.Int64) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var received atomic.Int64
+	server := grpc.NewServer(grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+		if method != testGRPCMethod {
+			return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+		}
+
+		request := &wrapperspb.StringValue{}
+		if err := stream.RecvMsg(request); err != nil {
+			return err
+		}
+
+		received.Add(1)
+
+		switch request.Value {
+		case "fail":
+			return status.Error(codes.Unavailable, "ingest unavailable")
+		case "slow":
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		response := "ack: " + request.Value
+		if md, ok := metadata.FromIncomingContext(stream.Context()); ok && len(md.Get("authorization")) > 0 {
+			response += " " + md.Get("authorization")[0]
+		}
+
+		return stream.SendMsg(&wrapperspb.StringValue{Value: response})
+	}))
+
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), &received
+}
+
+func newStringValue() proto.Message {
+	return &wrapperspb.StringValue{}
+}
+
+func TestNewGRPCSender(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Options     GRPCSenderOptions
+		ExpectError bool
+	}{
+		{"Valid", GRPCSenderOptions{Target: "localhost:50051", Method: testGRPCMethod}, false},
+		{"Valid TLS", GRPCSenderOptions{Target: "localhost:50051", Method: testGRPCMethod, UseTLS: true, TLSSecretName: "tls", CACertKey: "ca"}, false},
+		{"Missing target", GRPCSenderOptions{Method: testGRPCMethod}, true},
+		{"Missing method", GRPCSenderOptions{Target: "localhost:50051"}, true},
+		{"Missing token key", GRPCSenderOptions{Target: "localhost:50051", Method: testGRPCMethod, TokenSecretName: "token"}, true},
+		{"Missing TLS secret name", GRPCSenderOptions{Target: "localhost:50051", Method: testGRPCMethod, UseTLS: true, CACertKey: "ca"}, true},
+		{"Missing client key", GRPCSenderOptions{Target: "localhost:50051", Method: testGRPCMethod, UseTLS: true, TLSSecretName: "tls", ClientCertKey: "cert"}, true},
+		{"Persist and continue", GRPCSenderOptions{Target: "localhost:50051", Method: testGRPCMethod, PersistOnError: true, ContinueOnSendError: true, ReturnInputData: true}, true},
+		{"Continue without return input", GRPCSenderOptions{Target: "localhost:50051", Method: testGRPCMethod, ContinueOnSendError: true}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender, err := NewGRPCSender(test.Options)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, sender)
+		})
+	}
+}
+
+func TestGRPCSend(t *testing.T) {
+	target, received := startTestGRPCServer(t)
+
+	sender, err := NewGRPCSender(GRPCSenderOptions{
+		Target:      target,
+		Method:      testGRPCMethod,
+		NewRequest:  newStringValue,
+		NewResponse: newStringValue,
+		Timeout:     time.Second,
+	})
+	require.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		continuePipeline, result := sender.GRPCSend(ctx, `"hello"`)
+		require.True(t, continuePipeline, result)
+		assert.Equal(t, []byte(`"ack: hello"`), result)
+		assert.Equal(t, int64(i), received.Load())
+	}
+
+	// The connection is reused
+	firstConn := sender.conn
+	_, _ = sender.GRPCSend(ctx, `"hello"`)
+	assert.Same(t, firstConn, sender.conn)
+	assert.Equal(t, int64(4), sender.grpcSizeMetrics.Count())
+}
+
+func TestGRPCSenderClose(t *testing.T) {
+	target, received := startTestGRPCServer(t)
+
+	sender, err := NewGRPCSender(GRPCSenderOptions{
+		Target:      target,
+		Method:      testGRPCMethod,
+		NewRequest:  newStringValue,
+		NewResponse: newStringValue,
+		Timeout:     5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	// Closing waits for the RPC in flight rather than aborting it
+	done := make(chan bool)
+	go func() {
+		continuePipeline, _ := sender.GRPCSend(ctx, `"slow"`)
+		done <- continuePipeline
+	}()
+	require.Eventually(t, func() bool { return received.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	// The server takes 200ms to respond to the slow RPC
+	started := time.Now()
+	require.NoError(t, sender.Close())
+	assert.GreaterOrEqual(t, time.Since(started), 100*time.Millisecond, "Close returned before the RPC in flight completed")
+	assert.True(t, <-done, "RPC in flight failed when the sender was closed")
+
+	continuePipeline, result := sender.GRPCSend(ctx, `"hello"`)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "gRPC sender is closed")
+	assert.Equal(t, int64(1), received.Load())
+}
+
+func TestGRPCSendSerializedMessage(t *testing.T) {
+	target, _ := startTestGRPCServer(t)
+
+	sender, err := NewGRPCSender(GRPCSenderOptions{Target: target, Method: testGRPCMethod})
+	require.NoError(t, err)
+
+	request, err := proto.Marshal(&wrapperspb.StringValue{Value: "raw"})
+	require.NoError(t, err)
+
+	continuePipeline, result := sender.GRPCSend(ctx, request)
+	require.True(t, continuePipeline, result)
+
+	response := &wrapperspb.StringValue{}
+	require.NoError(t, proto.Unmarshal(result.([]byte), response))
+	assert.Equal(t, "ack: raw", response.Value)
+}
+
+func TestGRPCSendWithToken(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "ingest", "token").Return(map[string]string{"token": "my-token"}, nil)
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	target, _ := startTestGRPCServer(t)
+
+	sender, err := NewGRPCSender(GRPCSenderOptions{
+		Target:          target,
+		Method:          testGRPCMethod,
+		NewRequest:      newStringValue,
+		NewResponse:     newStringValue,
+		TokenSecretName: "ingest",
+		TokenSecretKey:  "token",
+	})
+	require.NoError(t, err)
+
+	continuePipeline, result := sender.GRPCSend(ctx, `"hello"`)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, []byte(`"ack: hello Bearer my-token"`), result)
+}
+
+func TestGRPCSendErrors(t *testing.T) {
+	target, _ := startTestGRPCServer(t)
+
+	tests := []struct {
+		Name                 string
+		Options              GRPCSenderOptions
+		Data                 interface{}
+		ExpectedContinue     bool
+		ExpectedRetryData    bool
+		ExpectedErrorMessage string
+	}{
+		{"Non-OK status", GRPCSenderOptions{}, `"fail"`, false, false, "code = Unavailable"},
+		{"Non-OK status persisted", GRPCSenderOptions{PersistOnError: true}, `"fail"`, false, true, "code = Unavailable"},
+		{"Non-OK status continued", GRPCSenderOptions{ContinueOnSendError: true, ReturnInputData: true}, `"fail"`, true, false, ""},
+		{"Deadline exceeded", GRPCSenderOptions{Timeout: 50 * time.Millisecond, PersistOnError: true}, `"slow"`, false, true, "code = DeadlineExceeded"},
+		{"Invalid request JSON", GRPCSenderOptions{}, `{not json`, false, false, "unable to unmarshal data to gRPC request message"},
+		{"No data", GRPCSenderOptions{}, nil, false, false, "No Data Received"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.SetRetryData(nil)
+
+			test.Options.Target = target
+			test.Options.Method = testGRPCMethod
+			test.Options.NewRequest = newStringValue
+			sender, err := NewGRPCSender(test.Options)
+			require.NoError(t, err)
+
+			continuePipeline, result := sender.GRPCSend(ctx, test.Data)
+			require.Equal(t, test.ExpectedContinue, continuePipeline)
+			assert.Equal(t, test.ExpectedRetryData, ctx.RetryData() != nil)
+			if test.ExpectedContinue {
+				assert.Equal(t, test.Data, result)
+				assert.Equal(t, int64(1), sender.grpcErrorMetric.Count())
+				return
+			}
+
+			assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+		})
+	}
+
+	ctx.SetRetryData(nil)
+}
+
+func TestGRPCSendCancelledContext(t *testing.T) {
+	target, _ := startTestGRPCServer(t)
+
+	cancelledContext, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sender, err := NewGRPCSender(GRPCSenderOptions{
+		Target:     target,
+		Method:     testGRPCMethod,
+		NewRequest: newStringValue,
+		Context:    cancelledContext,
+	})
+	require.NoError(t, err)
+
+	continuePipeline, result := sender.GRPCSend(ctx, `"hello"`)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "code = Canceled")
+}
@@ -17,6 +17,11 @@
 package transforms
 
 import (
+	"strconv"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
 )
 
@@ -47,3 +52,37 @@ func registerMetric(ctx interfaces.AppFunctionContext, fullNameFunc func() strin
 		lc.Infof("%s metric has been registered and will be reported (if enabled)", fullName)
 	}
 }
+
+// executeFunctions executes the functions on the data in order, the same as the pipeline runtime does, stopping at
+// the first function which doesn't continue. The continue flag and result of the last function executed are returned.
+func executeFunctions(ctx interfaces.AppFunctionContext, functions []interfaces.AppFunction, data interface{}) (bool, interface{}) {
+	result := data
+	for _, function := range functions {
+		continuePipeline, functionResult := function(ctx, result)
+		if !continuePipeline {
+			return false, functionResult
+		}
+
+		if functionResult != nil {
+			result = functionResult
+		}
+	}
+
+	return true, result
+}
+
+// numericReadingValue returns the reading's value as a float if the reading is numeric
+func numericReadingValue(reading dtos.BaseReading) (float64, bool) {
+	switch reading.ValueType {
+	case common.ValueTypeFloat32, common.ValueTypeFloat64,
+		common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64,
+		common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64:
+		value, err := strconv.ParseFloat(reading.Value, 64)
+		if err != nil {
+			return 0, false
+		}
+		return value, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,67 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultHMACSignatureHeader is the header the HMAC signature is sent in when no header name is specified
+const DefaultHMACSignatureHeader = "X-Signature"
+
+// hmacSigner signs requests with an HMAC-SHA256 signature using a key retrieved from the SecretStore.
+type hmacSigner struct {
+	secretName     string
+	secretValueKey string
+	headerName     string
+}
+
+func (signer *hmacSigner) validate() error {
+	if len(signer.secretValueKey) == 0 {
+		return errors.New("HMACSecretKey must be specified when HMACSecretName is specified")
+	}
+
+	return nil
+}
+
+// sign sets the Date header, if not already set, and the signature header to the hex encoded HMAC-SHA256 of
+// the canonical form of the request. The canonical form is the method, request URI, Date header and body,
+// each separated by a newline. The body must be exactly as sent, i.e. after any compression.
+func (signer *hmacSigner) sign(req *http.Request, body []byte, key string) {
+	if len(req.Header.Get("Date")) == 0 {
+		req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}
+
+	canonical := strings.Join([]string{req.Method, req.URL.RequestURI(), req.Header.Get("Date"), ""}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(canonical))
+	mac.Write(body)
+
+	headerName := signer.headerName
+	if len(headerName) == 0 {
+		headerName = DefaultHMACSignatureHeader
+	}
+
+	req.Header.Set(headerName, hex.EncodeToString(mac.Sum(nil)))
+}
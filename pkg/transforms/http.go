@@ -18,33 +18,174 @@ package transforms
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
 
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
 	gometrics "github.com/rcrowley/go-metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 )
 
+// HTTPResponseHeaderKeyPrefix is the prefix of the context storage key under which a captured response header is
+// stored. The full key is the prefix followed by the lower case header name, i.e. 'http-response-header-location'
+const HTTPResponseHeaderKeyPrefix = "http-response-header-"
+
+// ContentTypeFormURLEncoded is the mime type for form encoded data. When used as the HTTPSender MimeType,
+// data received as url.Values, map[string]string or map[string]interface{} is form encoded.
+const ContentTypeFormURLEncoded = "application/x-www-form-urlencoded"
+
+// DefaultMaxPayloadHeaderBytes is the maximum size of the data sent in the HTTPSender's PayloadHeader when no
+// maximum is specified. It is well within the header size limits of common servers and proxies.
+const DefaultMaxPayloadHeaderBytes = 4096
+
+// DefaultMaxRetryAfter is the longest Retry-After delay the HTTPSender honors when no maximum is specified, so a
+// destination can't hold up the pipeline indefinitely.
+const DefaultMaxRetryAfter = time.Minute
+
+// LoggedResponseBodyBytes is the maximum size of the response body logged when the HTTPSender's LogRequestResponse
+// is enabled. Larger response bodies are truncated in the log.
+const LoggedResponseBodyBytes = 1024
+
+// UserAgentProduct is the product name in the default User-Agent header sent by the HTTPSender, which is followed
+// by the SDK version, i.e. 'edgex-app-functions-sdk-go/3.1.0'.
+const UserAgentProduct = "edgex-app-functions-sdk-go"
+
+// Secret environment variable modes for the HTTPSender's SecretEnvMode
+const (
+	// SecretEnvModeNone retrieves secrets only from the SecretStore
+	SecretEnvModeNone = ""
+	// SecretEnvModeFallback retrieves secrets from the environment variables when the SecretStore lookup fails
+	SecretEnvModeFallback = "fallback"
+	// SecretEnvModeOnly retrieves secrets only from the environment variables, without using the SecretStore
+	SecretEnvModeOnly = "env"
+)
+
+// JSON serializations for the HTTPSender's JSONSerialization
+const (
+	// JSONSerializationCompact serializes data as compact JSON, without any insignificant whitespace
+	JSONSerializationCompact = "compact"
+	// JSONSerializationIndented serializes data as indented JSON using the JSONIndent, for human readability
+	JSONSerializationIndented = "indented"
+)
+
+// DefaultJSONIndent is the indent used for each JSON nesting level when the HTTPSender's JSONSerialization is
+// JSONSerializationIndented and no JSONIndent is specified.
+const DefaultJSONIndent = "  "
+
 // HTTPSender ...
 type HTTPSender struct {
-	url                 string
-	mimeType            string
-	persistOnError      bool
-	continueOnSendError bool
-	returnInputData     bool
-	httpHeaderName      string
-	secretValueKey      string
-	secretName          string
-	urlFormatter        StringValuesFormatter
-	httpSizeMetrics     gometrics.Histogram
-	httpErrorMetric     gometrics.Counter
-	httpRequestHeaders  map[string]string
+	url                    string
+	failoverURLs           []string
+	mimeType               string
+	persistOnError         bool
+	continueOnSendError    bool
+	returnInputData        bool
+	streamResponse         bool
+	responseContextKey     string
+	maxResponseBytes       int64
+	secretHeaders          []SecretHeader
+	oauth2                 *oauth2ClientCredentials
+	hmac                   *hmacSigner
+	multipart              *multipartEncoder
+	breaker                *circuitBreaker
+	userAgent              string
+	skipWhenContextKey     string
+	defaultHeaders         map[string]string
+	basicAuthSecret        string
+	basicAuthUserKey       string
+	basicAuthPassKey       string
+	tracer                 *httpTracer
+	bodyTemplate           *template.Template
+	idempotencyHeader      string
+	idempotencyCtxKey      string
+	bodyTemplateErr        error
+	successStatusCodes     []int
+	toleratedCodes         []int
+	requestContext         context.Context
+	queryParams            map[string]string
+	followRedirects        bool
+	sendCorrelationID      bool
+	correlationIDHeader    string
+	proxyURL               string
+	proxySecretName        string
+	proxyUsernameKey       string
+	proxyPasswordKey       string
+	hostMappings           map[string]string
+	payloadHeader          string
+	maxPayloadHeader       int
+	cloudEventHeaders      bool
+	secretEnvMode          string
+	jsonSerialization      string
+	jsonIndent             string
+	pingMethod             string
+	pingPath               string
+	setAcceptHeader        bool
+	responseMimeType       string
+	logRequestResponse     bool
+	resolver               *net.Resolver
+	localAddress           string
+	urlFormatter           StringValuesFormatter
+	httpSizeMetrics        gometrics.Histogram
+	httpErrorMetric        gometrics.Counter
+	httpSuccessMetric      gometrics.Counter
+	httpLatencyMetric      gometrics.Timer
+	httpRequestHeaders     map[string]string
+	httpRequestTimeout     time.Duration
+	maxIdleConnsPerHost    int
+	maxIdleConns           int
+	idleConnTimeout        time.Duration
+	disableKeepAlives      bool
+	forceHTTP2             bool
+	http2Fallback          bool
+	maxRetries             int
+	retryInterval          time.Duration
+	maxRetryAfter          time.Duration
+	clientCertSecret       string
+	clientCertKey          string
+	clientKeyKey           string
+	caCertKey              string
+	compressBody           bool
+	storeRespHeaders       []string
+	client                 *http.Client
+	customClient           *http.Client
+	clientLock             sync.Mutex
+	clientSecretsRetrieved time.Time
+	secretCache            map[string]*cachedSecret
+	secretCallbacks        map[string]bool
+	secretCacheLock        sync.Mutex
+}
+
+// cachedSecret holds the secret header values retrieved from a single secret and when they were retrieved.
+type cachedSecret struct {
+	values    map[string]string
+	retrieved time.Time
 }
 
 // NewHTTPSender creates, initializes and returns a new instance of HTTPSender
@@ -70,25 +211,156 @@ func NewHTTPSenderWithSecretHeader(url string, mimeType string, persistOnError b
 
 // NewHTTPSenderWithOptions creates, initializes and returns a new instance of HTTPSender configured with provided options
 func NewHTTPSenderWithOptions(options HTTPSenderOptions) *HTTPSender {
-	return &HTTPSender{
+	sender := &HTTPSender{
 		url:                 options.URL,
+		failoverURLs:        options.FailoverURLs,
 		mimeType:            options.MimeType,
 		persistOnError:      options.PersistOnError,
 		continueOnSendError: options.ContinueOnSendError,
 		returnInputData:     options.ReturnInputData,
-		httpHeaderName:      options.HTTPHeaderName,
-		secretValueKey:      options.SecretValueKey,
-		secretName:          options.SecretName,
+		streamResponse:      options.StreamResponse,
+		responseContextKey:  options.ResponseContextKey,
+		maxResponseBytes:    options.MaxResponseBytes,
+		idempotencyHeader:   options.IdempotencyKeyHeader,
+		idempotencyCtxKey:   options.IdempotencyKeyContextKey,
 		urlFormatter:        options.URLFormatter,
+		httpRequestTimeout:  options.Timeout,
+		maxIdleConnsPerHost: options.MaxIdleConnsPerHost,
+		maxIdleConns:        options.MaxIdleConns,
+		idleConnTimeout:     options.IdleConnTimeout,
+		disableKeepAlives:   options.DisableKeepAlives,
+		forceHTTP2:          options.ForceHTTP2,
+		http2Fallback:       options.HTTP2Fallback,
+		customClient:        options.Client,
+		maxRetries:          options.MaxRetries,
+		retryInterval:       options.RetryInterval,
+		maxRetryAfter:       options.MaxRetryAfter,
+		clientCertSecret:    options.ClientCertSecretName,
+		clientCertKey:       options.ClientCertKey,
+		clientKeyKey:        options.ClientKeyKey,
+		caCertKey:           options.CACertKey,
+		compressBody:        options.CompressBody,
+		storeRespHeaders:    options.StoreResponseHeaders,
+		successStatusCodes:  options.SuccessStatusCodes,
+		toleratedCodes:      options.TolerateStatusCodes,
+		requestContext:      options.Context,
+		queryParams:         options.QueryParams,
+		followRedirects:     options.FollowRedirects == nil || *options.FollowRedirects,
+		sendCorrelationID:   options.SendCorrelationID == nil || *options.SendCorrelationID,
+		correlationIDHeader: options.CorrelationIDHeader,
+		proxyURL:            options.ProxyURL,
+		proxySecretName:     options.ProxySecretName,
+		proxyUsernameKey:    options.ProxyUsernameKey,
+		proxyPasswordKey:    options.ProxyPasswordKey,
+		basicAuthSecret:     options.BasicAuthSecretName,
+		basicAuthUserKey:    options.BasicAuthUsernameKey,
+		basicAuthPassKey:    options.BasicAuthPasswordKey,
+		hostMappings:        options.HostMappings,
+		localAddress:        options.LocalAddress,
+		payloadHeader:       options.PayloadHeader,
+		maxPayloadHeader:    options.MaxPayloadHeaderBytes,
+		cloudEventHeaders:   options.CloudEventHeaders,
+		secretEnvMode:       options.SecretEnvMode,
+		jsonSerialization:   options.JSONSerialization,
+		jsonIndent:          options.JSONIndent,
+		pingMethod:          options.PingMethod,
+		pingPath:            options.PingPath,
+		setAcceptHeader:     options.SetAcceptHeader,
+		responseMimeType:    options.ResponseMimeType,
+		logRequestResponse:  options.LogRequestResponse,
+		userAgent:           options.UserAgent,
+		skipWhenContextKey:  options.SkipWhenContextKey,
+		defaultHeaders:      options.DefaultHeaders,
+		resolver:            options.Resolver,
 		httpErrorMetric:     gometrics.NewCounter(),
+		httpSuccessMetric:   gometrics.NewCounter(),
 		httpSizeMetrics:     gometrics.NewHistogram(gometrics.NewUniformSample(internal.MetricsReservoirSize)),
+		httpLatencyMetric:   gometrics.NewTimer(),
+	}
+
+	// The single secret header options are treated as the first of the secret headers
+	if len(options.HTTPHeaderName) > 0 || len(options.SecretName) > 0 || len(options.SecretValueKey) > 0 {
+		sender.secretHeaders = append(sender.secretHeaders, SecretHeader{
+			HeaderName:     options.HTTPHeaderName,
+			SecretName:     options.SecretName,
+			SecretValueKey: options.SecretValueKey,
+		})
+	}
+
+	sender.secretHeaders = append(sender.secretHeaders, options.SecretHeaders...)
+
+	if len(options.OAuth2TokenURL) > 0 {
+		sender.oauth2 = &oauth2ClientCredentials{
+			tokenURL:       options.OAuth2TokenURL,
+			clientID:       options.OAuth2ClientID,
+			secretName:     options.OAuth2SecretName,
+			secretValueKey: options.OAuth2ClientSecretKey,
+			scopes:         options.OAuth2Scopes,
+		}
+	}
+
+	if len(options.HMACSecretName) > 0 {
+		sender.hmac = &hmacSigner{
+			secretName:     options.HMACSecretName,
+			secretValueKey: options.HMACSecretKey,
+			headerName:     options.HMACSignatureHeader,
+		}
+	}
+
+	if options.CircuitBreakerThreshold > 0 {
+		sender.breaker = newCircuitBreaker(options.CircuitBreakerThreshold, options.CircuitBreakerCooldown)
+	}
+
+	if len(options.MultipartFieldName) > 0 {
+		sender.multipart = &multipartEncoder{
+			fieldName: options.MultipartFieldName,
+			fileName:  options.MultipartFileName,
+			fields:    options.MultipartFields,
+		}
+	}
+
+	if len(options.BodyTemplate) > 0 {
+		// Missing context values are treated as errors rather than rendered as '<no value>'
+		sender.bodyTemplate, sender.bodyTemplateErr = template.New("body").Option("missingkey=error").Parse(options.BodyTemplate)
+	}
+
+	if options.TracePropagation {
+		sender.tracer = &httpTracer{propagator: options.TracePropagator}
+		if sender.tracer.propagator == nil {
+			sender.tracer.propagator = propagation.TraceContext{}
+		}
+
+		if options.TraceClientSpan {
+			tracerProvider := options.TracerProvider
+			if tracerProvider == nil {
+				tracerProvider = otel.GetTracerProvider()
+			}
+
+			sender.tracer.tracer = tracerProvider.Tracer(tracerName)
+		}
 	}
+
+	return sender
+}
+
+// SecretHeader specifies an HTTP header whose value is retrieved from the SecretStore
+type SecretHeader struct {
+	// HeaderName is the name of the HTTP header to set
+	HeaderName string
+	// SecretName is the name of the secret in the SecretStore
+	SecretName string
+	// SecretValueKey is the key for the value in the secret data from the SecretStore
+	SecretValueKey string
 }
 
 // HTTPSenderOptions contains all options available to the sender
 type HTTPSenderOptions struct {
 	// URL of destination
 	URL string
+	// FailoverURLs are the destinations tried in order when sending to the URL fails, until one succeeds.
+	// The URLFormatter, query parameters and headers apply to each. Store and Forward is only used when all fail.
+	// Streamed data can't be re-sent, so is only sent to the URL.
+	FailoverURLs []string
 	// MimeType to send to destination
 	MimeType string
 	// PersistOnError enables use of store & forward loop if true
@@ -107,6 +379,245 @@ type HTTPSenderOptions struct {
 	ContinueOnSendError bool
 	// ReturnInputData enables chaining multiple HTTP senders if true
 	ReturnInputData bool
+	// StreamResponse passes the response body to the next function as an io.ReadCloser, which it must close, rather
+	// than reading the whole response into memory. Since the response is read after the sender has returned, errors
+	// reading it don't result in the data being persisted for Store and Forward.
+	StreamResponse bool
+	// ResponseContextKey is the context storage key the response body is stored under, in which case the data from
+	// the previous function is passed to the next function rather than the response. This allows the response to
+	// be used to enrich the data, i.e. with HTTPGet. Not used if empty.
+	ResponseContextKey string
+	// MaxResponseBytes is the maximum size of the response body read into memory. The send fails, and the data is
+	// persisted if PersistOnError is set, if the response is larger. Not used for streamed responses or when the
+	// input data is returned rather than the response. Zero means no limit.
+	MaxResponseBytes int64
+	// Timeout is the time limit for the complete HTTP request, including reading the response body.
+	// Zero means no timeout.
+	Timeout time.Duration
+	// MaxIdleConnsPerHost is the maximum idle (keep-alive) connections to keep per-host.
+	// Zero means the http.DefaultTransport setting is used.
+	MaxIdleConnsPerHost int
+	// MaxIdleConns is the maximum idle (keep-alive) connections to keep across all hosts.
+	// Zero means the http.DefaultTransport setting is used.
+	MaxIdleConns int
+	// IdleConnTimeout is how long an idle (keep-alive) connection is kept before it is closed.
+	// Zero means the http.DefaultTransport setting is used.
+	IdleConnTimeout time.Duration
+	// DisableKeepAlives disables connection reuse so that a new connection is used for each request and closed
+	// afterwards, i.e. when the URL is templated so that each send targets a different host.
+	DisableKeepAlives bool
+	// ForceHTTP2 sends all requests using HTTP/2, negotiated via ALPN for https URLs and with prior knowledge (h2c)
+	// for http URLs, so that requests are multiplexed over a single connection. Requests fail if the destination
+	// doesn't support HTTP/2, unless HTTP2Fallback is set. Not supported with ProxyURL.
+	ForceHTTP2 bool
+	// HTTP2Fallback allows falling back to HTTP/1.1 for destinations which don't support HTTP/2 when ForceHTTP2 is set.
+	HTTP2Fallback bool
+	// Client is an optional fully configured client used as is, in place of the client the sender builds, for
+	// customization the other options don't cover. The client's own settings apply, so Timeout, FollowRedirects
+	// and the transport, client certificate, proxy, host mapping and HTTP/2 options are ignored. The caller owns
+	// the client's lifecycle, including closing its idle connections, and is responsible for setting its timeouts.
+	Client *http.Client
+	// MaxRetries is the number of times a failed send is retried before giving up. Only network errors, 429 and
+	// 5xx responses are retried. The delay in the Retry-After header of 429 and 503 responses, up to MaxRetryAfter,
+	// is honored in place of the RetryInterval based delay. Zero means no retries.
+	MaxRetries int
+	// RetryInterval is the initial wait between retries, which is doubled (plus jitter) on each subsequent retry.
+	RetryInterval time.Duration
+	// MaxRetryAfter is the longest Retry-After delay honored. When the destination asks for a longer delay the send
+	// isn't retried and fails, so the data is persisted for Store and Forward when PersistOnError is enabled.
+	// Defaults to DefaultMaxRetryAfter if zero.
+	MaxRetryAfter time.Duration
+	// ClientCertSecretName is the name of the secret in the SecretStore containing the PEM encoded client
+	// certificate and key used for mutual TLS. Client certificate authentication is not used if empty.
+	ClientCertSecretName string
+	// ClientCertKey is the key for the PEM encoded client certificate in the ClientCertSecretName secret data
+	ClientCertKey string
+	// ClientKeyKey is the key for the PEM encoded client private key in the ClientCertSecretName secret data
+	ClientKeyKey string
+	// CACertKey is the optional key for a PEM encoded CA bundle in the ClientCertSecretName secret data
+	// used to verify the server's certificate. The system CAs are used if empty.
+	CACertKey string
+	// CompressBody enables gzip compression of the request body, which is sent with the 'Content-Encoding: gzip' header
+	CompressBody bool
+	// StoreResponseHeaders is the list of response headers to store in the context storage on a successful send.
+	// Each header value is stored under the HTTPResponseHeaderKeyPrefix followed by the lower case header name.
+	StoreResponseHeaders []string
+	// SecretHeaders specifies additional HTTP headers whose values are retrieved from the SecretStore.
+	// These are in addition to the header specified by HTTPHeaderName, SecretName & SecretValueKey.
+	SecretHeaders []SecretHeader
+	// OAuth2TokenURL is the token endpoint used to obtain a bearer token via the OAuth2 client credentials grant.
+	// The token is cached, refreshed before it expires and sent in the Authorization header. Not used if empty.
+	OAuth2TokenURL string
+	// OAuth2ClientID is the client id used to obtain the OAuth2 access token
+	OAuth2ClientID string
+	// OAuth2SecretName is the name of the secret in the SecretStore containing the OAuth2 client secret
+	OAuth2SecretName string
+	// OAuth2ClientSecretKey is the key for the OAuth2 client secret in the OAuth2SecretName secret data
+	OAuth2ClientSecretKey string
+	// OAuth2Scopes is the optional list of scopes to request for the OAuth2 access token
+	OAuth2Scopes []string
+	// BasicAuthSecretName is the name of the secret in the SecretStore containing the credentials sent in the
+	// 'Authorization: Basic' header. Can't be used with OAuth2 or a secret header named 'Authorization'.
+	// Basic authentication isn't used if empty.
+	BasicAuthSecretName string
+	// BasicAuthUsernameKey is the key for the username in the BasicAuthSecretName secret data
+	BasicAuthUsernameKey string
+	// BasicAuthPasswordKey is the key for the password in the BasicAuthSecretName secret data
+	BasicAuthPasswordKey string
+	// SuccessStatusCodes is the list of HTTP status codes considered a successful send. Any other status code
+	// is treated as a failure. If empty, any 2xx status code is considered a success.
+	SuccessStatusCodes []int
+	// TolerateStatusCodes is the list of HTTP status codes, such as 409 Conflict when the data already exists, which
+	// are logged but not treated as a failure, so the pipeline continues with the input data without the data being
+	// persisted, even when ContinueOnSendError isn't set. These responses aren't retried or failed over.
+	TolerateStatusCodes []int
+	// Context is used for all requests so that in-flight requests are aborted when it is cancelled, i.e. set to the
+	// ApplicationService's AppContext() so exports are aborted on shutdown. Defaults to context.Background() if nil.
+	Context context.Context
+	// QueryParams are query parameters added to the URL. Each value is formatted using the URLFormatter the same as
+	// the URL, i.e. '{some-context-key}' placeholders are replaced. Parameters in the configured URL are preserved
+	// unless a parameter with the same name is specified here.
+	QueryParams map[string]string
+	// FollowRedirects specifies whether redirect responses are followed. When false, the 3xx response is treated
+	// as the final response and evaluated against the success status codes. Defaults to true if nil.
+	FollowRedirects *bool
+	// SendCorrelationID specifies whether the pipeline's correlation id is sent in the CorrelationIDHeader so the
+	// destination can correlate the request with the originating event. Defaults to true if nil.
+	SendCorrelationID *bool
+	// CorrelationIDHeader is the name of the header the correlation id is sent in.
+	// Defaults to the standard X-Correlation-ID header if empty.
+	CorrelationIDHeader string
+	// ProxyURL is the URL of the HTTP/HTTPS proxy all requests are sent through. If empty, the proxy is determined
+	// from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL string
+	// ProxySecretName is the optional name of the secret in the SecretStore containing the proxy credentials
+	ProxySecretName string
+	// ProxyUsernameKey is the key for the proxy username in the ProxySecretName secret data
+	ProxyUsernameKey string
+	// ProxyPasswordKey is the key for the proxy password in the ProxySecretName secret data
+	ProxyPasswordKey string
+	// HostMappings maps destination host names to the address dialed in their place, either an IP address or
+	// host, optionally with a port, i.e. to override DNS without editing /etc/hosts. The original host name is still
+	// used for the Host header and TLS server name verification. The port from the URL is used if none is mapped.
+	HostMappings map[string]string
+	// Resolver is the resolver used to look up host names not in HostMappings.
+	// Defaults to the system resolver if nil.
+	Resolver *net.Resolver
+	// LocalAddress is the local IP address the connections to the destination are bound to, i.e. to egress from a
+	// specific interface on a multi-homed host. The local port is always chosen by the OS.
+	// The OS chooses the local address if empty.
+	LocalAddress string
+	// HMACSecretName is the name of the secret in the SecretStore containing the key used to sign requests with an
+	// HMAC-SHA256 signature over the method, request URI, Date header and body as sent, i.e. after compression.
+	// Requests are not signed if empty. Signing is not supported for streamed data.
+	HMACSecretName string
+	// HMACSecretKey is the key for the signing key in the HMACSecretName secret data
+	HMACSecretKey string
+	// HMACSignatureHeader is the name of the header the hex encoded signature is sent in.
+	// Defaults to DefaultHMACSignatureHeader if empty.
+	HMACSignatureHeader string
+	// TracePropagation enables propagation of the trace context to the destination in the request headers.
+	// The trace context is taken from the 'traceparent' and 'tracestate' values in the context storage, if present,
+	// otherwise from the span in the Context.
+	TracePropagation bool
+	// TracePropagator is the propagator used to inject the trace context into the request headers.
+	// Defaults to the W3C Trace Context propagator, i.e. the 'traceparent' and 'tracestate' headers, if nil.
+	TracePropagator propagation.TextMapPropagator
+	// TraceClientSpan enables creating a client span around each request when TracePropagation is enabled
+	TraceClientSpan bool
+	// TracerProvider provides the tracer used to create client spans.
+	// Defaults to the global TracerProvider if nil.
+	TracerProvider trace.TracerProvider
+	// BodyTemplate is an optional Go text/template used to render the request body from the data, i.e. to wrap the
+	// data in an envelope such as '{"payload": {{.Payload}}, "source": "edgex"}'. The template is executed with
+	// HTTPBodyTemplateData. Not supported for streamed data. The data is sent as is if empty.
+	BodyTemplate string
+	// IdempotencyKeyHeader is the name of the header, i.e. 'Idempotency-Key', the idempotency key is sent in. The key
+	// is the hex encoded SHA-256 of the body as sent, i.e. after compression, so the same data always has the same key,
+	// including when retried. Not supported for streamed data. The key isn't sent if empty.
+	IdempotencyKeyHeader string
+	// IdempotencyKeyContextKey is the context storage key the idempotency key is stored under so it can be used by
+	// subsequent functions in the pipeline. The key isn't stored if empty.
+	IdempotencyKeyContextKey string
+	// PayloadHeader is the name of the header the data is sent in, rather than in the body, which is left empty.
+	// The data must be valid as a header value, i.e. no line breaks, and no larger than MaxPayloadHeaderBytes.
+	// Not supported for streamed data or with CompressBody. The data is sent in the body if empty.
+	PayloadHeader string
+	// MaxPayloadHeaderBytes is the maximum size of the data sent in the PayloadHeader.
+	// Defaults to DefaultMaxPayloadHeaderBytes if zero.
+	MaxPayloadHeaderBytes int
+	// CloudEventHeaders enables sending the CloudEvent attributes stored in the context by ConvertToCloudEvent in
+	// binary mode as the CloudEvents 'ce-' headers.
+	CloudEventHeaders bool
+	// SecretEnvMode specifies whether the secret header values, the basic authentication credentials and the HMAC
+	// signing key are retrieved from environment variables, named as returned by SecretEnvVarName, as well as, or
+	// instead of, the SecretStore. Must be one of
+	// SecretEnvModeNone, SecretEnvModeFallback or SecretEnvModeOnly. Intended for local development and testing.
+	// Defaults to SecretEnvModeNone if empty.
+	SecretEnvMode string
+	// JSONSerialization specifies how data, other than strings and byte slices which are sent as is, is serialized
+	// when the MimeType is a JSON type, i.e. 'application/json' or a '+json' type. Must be JSONSerializationCompact
+	// or JSONSerializationIndented. Defaults to JSONSerializationCompact if empty, to minimize the size sent.
+	JSONSerialization string
+	// JSONIndent is the indent used for each nesting level when JSONSerialization is JSONSerializationIndented.
+	// Defaults to DefaultJSONIndent if empty.
+	JSONIndent string
+	// PingMethod is the HTTP method of the request sent by Ping to check the destination is reachable.
+	// Defaults to HEAD if empty.
+	PingMethod string
+	// PingPath is the path, relative to the URL, or absolute URL the Ping request is sent to, i.e. a health endpoint.
+	// The Ping request is sent to the URL, without its placeholders being formatted, if empty.
+	PingPath string
+	// SetAcceptHeader enables sending the Accept header with the ResponseMimeType, so that request/response style
+	// destinations, i.e. used with HTTPGet or ResponseContextKey, return the expected response type. An Accept header
+	// set by SetHttpRequestHeaders takes precedence.
+	SetAcceptHeader bool
+	// ResponseMimeType is the mime type sent in the Accept header when SetAcceptHeader is set.
+	// Defaults to the MimeType if empty.
+	ResponseMimeType string
+	// LogRequestResponse enables logging, at trace level, of each request's method, redacted URL, body size and
+	// headers, along with the response status and up to LoggedResponseBodyBytes of the response body. The values of
+	// the secret, authorization and HMAC signature headers are redacted. Intended for diagnosing integration issues.
+	LogRequestResponse bool
+	// MultipartFieldName is the name of the form field the data is sent in as a multipart/form-data body, i.e. for
+	// file upload APIs. The data is sent as the part's content with MimeType as its content type, and the request is
+	// sent with the multipart/form-data content type. Not supported for streamed data or with PayloadHeader.
+	// The data is sent as the body if empty.
+	MultipartFieldName string
+	// MultipartFileName is the optional file name of the multipart data part. It is formatted using the URLFormatter
+	// the same as the URL. The part is sent without a file name if empty.
+	MultipartFileName string
+	// MultipartFields are additional form fields sent in the multipart body before the data part. Each value is
+	// formatted using the URLFormatter the same as the URL, i.e. '{some-context-key}' placeholders are replaced.
+	MultipartFields map[string]string
+	// CircuitBreakerThreshold is the number of consecutive failed sends after which the circuit breaker opens. While
+	// open, sends fail immediately without being attempted, and the data is persisted if PersistOnError is set. After
+	// the CircuitBreakerCooldown a single probe send is attempted, which closes the breaker if it succeeds. The state is
+	// reported by the HttpExportCircuitState metric, where 0 is closed, 1 is open and 2 is half-open, i.e. probing.
+	// The circuit breaker isn't used if zero.
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is how long the circuit breaker stays open before a probe send is attempted.
+	// Defaults to DefaultCircuitBreakerCooldown if zero.
+	CircuitBreakerCooldown time.Duration
+	// UserAgent is the User-Agent header sent with each request, identifying the service to the destination and
+	// any proxies. Defaults to UserAgentProduct followed by the SDK version if empty.
+	UserAgent string
+	// DefaultHeaders are static headers sent with each request. They are set before all other headers, so are
+	// overridden by any header of the same name, such as those set by SetHttpRequestHeaders. A User-Agent default
+	// header is only overridden by the UserAgent option.
+	DefaultHeaders map[string]string
+	// SkipWhenContextKey is the context storage key of a flag, set earlier in the pipeline, which gates the send.
+	// When the value is false, i.e. 'false' or '0', or empty, the data is passed to the next function without being
+	// sent. The data is sent when the value isn't stored or is any other value. Not used if empty.
+	SkipWhenContextKey string
+}
+
+// HTTPBodyTemplateData is the data the HTTPSender's BodyTemplate is executed with
+type HTTPBodyTemplateData struct {
+	// Payload is the data as it would otherwise be sent, i.e. the JSON of an Event
+	Payload string
+	// Values are the values in the context storage, keyed by lower case key, i.e. {{.Values.devicename}}
+	Values map[string]string
 }
 
 // HTTPPost will send data from the previous function to the specified Endpoint via http POST.
@@ -123,12 +634,34 @@ func (sender *HTTPSender) HTTPPut(ctx interfaces.AppFunctionContext, data interf
 	return sender.httpSend(ctx, data, http.MethodPut)
 }
 
+// HTTPPatch will send data from the previous function to the specified Endpoint via http PATCH.
+// If no previous function exists, then the event that triggered the pipeline will be used.
+// An empty string for the mimetype will default to application/json.
+func (sender *HTTPSender) HTTPPatch(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	return sender.httpSend(ctx, data, http.MethodPatch)
+}
+
+// HTTPGet will send an http GET to the specified Endpoint. The request is sent without a body, so data from the
+// previous function, if any, is only used to format the URL. Since there is no data to persist, failed requests are not
+// retried by Store and Forward. The response is passed to the next function, streamed if StreamResponse is set,
+// unless ResponseContextKey is set, in which case it is stored in the context and the data is passed on unchanged.
+func (sender *HTTPSender) HTTPGet(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	return sender.httpSend(ctx, data, http.MethodGet)
+}
+
+// HTTPDelete will send an http DELETE to the specified Endpoint. Data from the previous function, if any, is sent
+// as the request body, otherwise the request is sent with an empty body.
+func (sender *HTTPSender) HTTPDelete(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	return sender.httpSend(ctx, data, http.MethodDelete)
+}
+
 func (sender *HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data interface{}, method string) (bool, interface{}) {
 	lc := ctx.LoggingClient()
 
 	lc.Debugf("HTTP Exporting in pipeline '%s'", ctx.PipelineId())
 
-	if data == nil {
+	// GET and DELETE requests commonly have no body, so no data is allowed in those cases
+	if data == nil && method != http.MethodGet && method != http.MethodDelete {
 		// We didn't receive a result
 		return false, fmt.Errorf("function HTTP%s in pipeline '%s': No Data Received", method, ctx.PipelineId())
 	}
@@ -137,6 +670,11 @@ func (sender *HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data inter
 		return false, fmt.Errorf("in pipeline '%s' persistOnError & continueOnSendError can not both be set to true for HTTP Export", ctx.PipelineId())
 	}
 
+	if sender.isSkipped(ctx) {
+		lc.Debugf("HTTP Export skipped since '%s' is false in pipeline '%s'", sender.skipWhenContextKey, ctx.PipelineId())
+		return true, data
+	}
+
 	if sender.continueOnSendError && !sender.returnInputData {
 		return false, fmt.Errorf("in pipeline '%s' continueOnSendError can only be used in conjunction returnInputData for multiple HTTP Export", ctx.PipelineId())
 	}
@@ -145,9 +683,40 @@ func (sender *HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data inter
 		sender.mimeType = "application/json"
 	}
 
-	exportData, err := util.CoerceType(data)
-	if err != nil {
-		return false, err
+	// Data that is already an io.Reader is streamed to the destination rather than read into memory.
+	// Streamed data can't be re-sent, so it isn't retried or persisted for Store and Forward on failure.
+	var exportData []byte
+	streamData, isStream := data.(io.Reader)
+	if method == http.MethodGet {
+		// GET requests are sent without a body, so the data is only used to format the URL
+		isStream = false
+	} else if data != nil && !isStream {
+		var err error
+		if isFormURLEncoded(sender.mimeType) {
+			exportData, err = formEncode(data)
+			if err != nil {
+				return false, fmt.Errorf("in pipeline '%s', %s", ctx.PipelineId(), err.Error())
+			}
+		} else {
+			exportData, err = sender.serialize(ctx, data)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// The retry data is the data before rendering so that it isn't rendered again when retried
+	bodyData := exportData
+	if (sender.bodyTemplate != nil || sender.bodyTemplateErr != nil) && method != http.MethodGet {
+		if isStream {
+			return false, fmt.Errorf("in pipeline '%s', body template is not supported for streamed data", ctx.PipelineId())
+		}
+
+		var err error
+		bodyData, err = sender.renderBody(ctx, exportData)
+		if err != nil {
+			return false, fmt.Errorf("unable to render HTTP export body template in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		}
 	}
 
 	usingSecrets, err := sender.determineIfUsingSecrets(ctx)
@@ -155,64 +724,185 @@ func (sender *HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data inter
 		return false, err
 	}
 
-	formattedUrl, err := sender.urlFormatter.invoke(sender.url, ctx, data)
-	if err != nil {
-		return false, err
+	if sender.oauth2 != nil {
+		if err := sender.oauth2.validate(); err != nil {
+			return false, fmt.Errorf("in pipeline '%s', %s", ctx.PipelineId(), err.Error())
+		}
 	}
 
-	parsedUrl, err := url.Parse(formattedUrl)
-	if err != nil {
-		return false, err
+	if err := sender.validateBasicAuth(); err != nil {
+		return false, fmt.Errorf("in pipeline '%s', %s", ctx.PipelineId(), err.Error())
 	}
 
-	registerMetric(ctx,
-		func() string { return fmt.Sprintf("%s-%s", internal.HttpExportErrorsName, parsedUrl.Redacted()) },
-		func() any { return sender.httpErrorMetric },
-		map[string]string{"url": parsedUrl.Redacted()})
+	if sender.hmac != nil {
+		if err := sender.hmac.validate(); err != nil {
+			return false, fmt.Errorf("in pipeline '%s', %s", ctx.PipelineId(), err.Error())
+		}
 
-	registerMetric(ctx,
-		func() string { return fmt.Sprintf("%s-%s", internal.HttpExportSizeName, parsedUrl.Redacted()) },
-		func() any { return sender.httpSizeMetrics },
-		map[string]string{"url": parsedUrl.Redacted()})
+		if isStream {
+			return false, fmt.Errorf("in pipeline '%s', HMAC signing is not supported for streamed data", ctx.PipelineId())
+		}
+	}
+
+	if isStream && (len(sender.idempotencyHeader) > 0 || len(sender.idempotencyCtxKey) > 0) {
+		return false, fmt.Errorf("in pipeline '%s', idempotency key is not supported for streamed data", ctx.PipelineId())
+	}
+
+	contentType := sender.mimeType
+	if sender.multipart != nil && method != http.MethodGet {
+		if isStream {
+			return false, fmt.Errorf("in pipeline '%s', multipart body is not supported for streamed data", ctx.PipelineId())
+		}
+
+		if sender.usingPayloadHeader(method) {
+			return false, fmt.Errorf("in pipeline '%s', multipart body is not supported with payload header", ctx.PipelineId())
+		}
+
+		bodyData, contentType, err = sender.multipart.encode(ctx, data, sender.urlFormatter, bodyData, sender.mimeType)
+		if err != nil {
+			return false, fmt.Errorf("unable to build multipart HTTP export body in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		}
+	}
+
+	if sender.usingPayloadHeader(method) {
+		if isStream {
+			return false, fmt.Errorf("in pipeline '%s', payload header is not supported for streamed data", ctx.PipelineId())
+		}
+
+		if sender.compressBody {
+			return false, fmt.Errorf("in pipeline '%s', payload header is not supported with body compression", ctx.PipelineId())
+		}
+
+		if err := sender.validatePayloadHeader(bodyData); err != nil {
+			return false, fmt.Errorf("unable to send data in '%s' header in pipeline '%s': %s", sender.payloadHeader, ctx.PipelineId(), err.Error())
+		}
+	}
 
-	client := &http.Client{}
-	req, err := http.NewRequest(method, parsedUrl.String(), bytes.NewReader(exportData))
+	client, err := sender.getClient(ctx)
 	if err != nil {
 		return false, err
 	}
-	var theSecrets map[string]string
-	if usingSecrets {
-		theSecrets, err = ctx.SecretProvider().GetSecret(sender.secretName, sender.secretValueKey)
+
+	var requestData []byte
+	var streamCounter *countingReader
+	if isStream {
+		if sender.compressBody {
+			streamData = gzipCompressStream(streamData)
+		}
+
+		streamCounter = &countingReader{reader: streamData}
+	} else {
+		if sender.compressBody && method != http.MethodGet {
+			compressedData, err := gzipCompress(bodyData)
+			if err != nil {
+				return false, fmt.Errorf("unable to compress HTTP export data in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+			}
+
+			lc.Debugf("Compressed HTTP export data from %d to %d bytes in pipeline '%s'", len(bodyData), len(compressedData), ctx.PipelineId())
+			requestData = compressedData
+		} else {
+			requestData = bodyData
+		}
+	}
+
+	// The failover URLs are tried in order until one succeeds. Streamed data can't be re-sent, so is only sent to the URL.
+	targetUrls := []string{sender.url}
+	if !isStream {
+		targetUrls = append(targetUrls, sender.failoverURLs...)
+	}
+
+	if sender.breaker != nil && !sender.breaker.allow(ctx) {
+		sender.httpErrorMetric.Inc(1)
+		err = fmt.Errorf("export skipped in pipeline '%s': circuit breaker is open", ctx.PipelineId())
+
+		if !sender.continueOnSendError {
+			sender.setRetryData(ctx, exportData)
+			return false, err
+		}
+
+		ctx.LoggingClient().Errorf("Continuing pipeline on error in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		return true, data
+	}
+
+	var req *http.Request
+	var parsedUrl *url.URL
+	var response *http.Response
+	for index, targetUrl := range targetUrls {
+		var requestBody io.Reader = streamCounter
+		if !isStream {
+			requestBody = bytes.NewReader(requestData)
+			if sender.usingPayloadHeader(method) {
+				// An empty reader rather than http.NoBody so the request has GetBody set and can be retried
+				requestBody = bytes.NewReader(nil)
+			}
+		}
+
+		req, parsedUrl, err = sender.createRequest(ctx, method, targetUrl, data, isStream, requestBody, requestData, contentType, usingSecrets)
 		if err != nil {
+			if sender.breaker != nil {
+				sender.breaker.release()
+			}
 			return false, err
 		}
 
-		lc.Debugf("Setting HTTP Header '%s' with secret value from SecretStore at secretName='%s' & secretKeyValue='%s in pipeline '%s'",
-			sender.httpHeaderName,
-			sender.secretName,
-			sender.secretValueKey,
-			ctx.PipelineId())
+		sender.registerHttpMetric(ctx, internal.HttpExportLatencyName, parsedUrl, func() any { return sender.httpLatencyMetric })
+
+		ctx.LoggingClient().Debugf("Sending %s request to %s in pipeline '%s'", method, parsedUrl.Redacted(), ctx.PipelineId())
+
+		response, err = sender.sendRequest(ctx, client, req)
+		sender.logRequestAndResponse(ctx, req, response, err)
+		if index == len(targetUrls)-1 || req.Context().Err() != nil ||
+			(err == nil && (sender.isSuccessStatusCode(response.StatusCode) || sender.isToleratedStatusCode(response.StatusCode))) {
+			break
+		}
 
-		req.Header.Set(sender.httpHeaderName, theSecrets[sender.secretValueKey])
+		if err == nil {
+			discardResponse(response)
+			err = fmt.Errorf("%d HTTP status code", response.StatusCode)
+		}
+
+		ctx.LoggingClient().Warnf("Export to %s failed in pipeline '%s', failing over to next URL: %s",
+			parsedUrl.Redacted(), ctx.PipelineId(), err.Error())
 	}
 
-	req.Header.Set("Content-Type", sender.mimeType)
+	// The metrics are registered for the URL the send ended with, i.e. the URL that succeeded or last failed
+	sender.registerHttpMetric(ctx, internal.HttpExportErrorsName, parsedUrl, func() any { return sender.httpErrorMetric })
+	sender.registerHttpMetric(ctx, internal.HttpExportSuccessesName, parsedUrl, func() any { return sender.httpSuccessMetric })
+	sender.registerHttpMetric(ctx, internal.HttpExportSizeName, parsedUrl, func() any { return sender.httpSizeMetrics })
 
-	// Set all the http request headers
-	for key, element := range sender.httpRequestHeaders {
-		req.Header.Set(key, element)
+	// A tolerated response shows the destination is handling requests, so it isn't a failure for the circuit breaker
+	tolerated := err == nil && sender.isToleratedStatusCode(response.StatusCode)
+
+	if sender.breaker != nil {
+		sender.registerHttpMetric(ctx, internal.HttpExportCircuitStateName, parsedUrl, func() any { return sender.breaker.stateMetric })
+		sender.registerHttpMetric(ctx, internal.HttpExportCircuitOpenedName, parsedUrl, func() any { return sender.breaker.openedMetric })
 
+		// Cancelled sends, i.e. on shutdown, aren't a failure of the destination
+		if errors.Is(err, context.Canceled) {
+			sender.breaker.release()
+		} else {
+			sender.breaker.record(ctx, tolerated || (err == nil && sender.isSuccessStatusCode(response.StatusCode)))
+		}
 	}
 
-	ctx.LoggingClient().Debugf("POSTing data to %s in pipeline '%s'", parsedUrl.Redacted(), ctx.PipelineId())
+	if tolerated {
+		discardResponse(response)
 
-	response, err := client.Do(req)
-	// Pipeline continues if we get a 2xx response, non-2xx response may stop pipeline
-	if err != nil || response.StatusCode < 200 || response.StatusCode >= 300 {
+		ctx.LoggingClient().Warnf("Export to %s returned tolerated %d HTTP status code in pipeline '%s'. Continuing pipeline with the input data",
+			parsedUrl.Redacted(), response.StatusCode, ctx.PipelineId())
+
+		return true, data
+	}
+
+	// Pipeline continues if we get a success response (2xx by default), other responses may stop pipeline
+	if err != nil || !sender.isSuccessStatusCode(response.StatusCode) {
 		if err == nil {
+			discardResponse(response)
 			err = fmt.Errorf("export failed with %d HTTP status code in pipeline '%s'", response.StatusCode, ctx.PipelineId())
+		} else if errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("export to %s cancelled in pipeline '%s': %w", parsedUrl.Redacted(), ctx.PipelineId(), err)
 		} else {
-			err = fmt.Errorf("export failed in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+			err = fmt.Errorf("export to %s failed in pipeline '%s': %w", parsedUrl.Redacted(), ctx.PipelineId(), err)
 		}
 
 		sender.httpErrorMetric.Inc(1)
@@ -232,72 +922,1180 @@ func (sender *HTTPSender) httpSend(ctx interfaces.AppFunctionContext, data inter
 		return true, data
 	}
 
+	sender.httpSuccessMetric.Inc(1)
+
 	// Data successfully sent, so retry any failed data, if Store and Forward enabled and data has been saved
 	if sender.persistOnError {
 		ctx.TriggerRetryFailedData()
 	}
 
-	// capture the size into metrics
-	exportDataBytes := len(exportData)
-	sender.httpSizeMetrics.Update(int64(exportDataBytes))
+	// capture the size into metrics, which is the compressed size when compression is enabled
+	exportDataBytes := req.ContentLength
+	if streamCounter != nil {
+		exportDataBytes = streamCounter.count.Load()
+	}
+	sender.httpSizeMetrics.Update(exportDataBytes)
 
 	ctx.LoggingClient().Debugf("Sent %d bytes of data in pipeline '%s'. Response status is %s", exportDataBytes, ctx.PipelineId(), response.Status)
 	ctx.LoggingClient().Tracef("Data exported for pipeline '%s' (%s=%s)", ctx.PipelineId(), common.CorrelationHeader, ctx.CorrelationID())
 
+	sender.storeResponseHeaders(ctx, response)
+
 	// This allows multiple HTTP Exports to be chained in the pipeline to send the same data to different destinations
-	// Don't need to read the response data since not going to return it so just return now.
-	if sender.returnInputData {
+	// Don't need to read the response data since not going to return it so just discard it and return now.
+	if sender.returnInputData && sender.responseContextKey == "" {
+		discardResponse(response)
 		return true, data
 	}
 
+	if sender.streamResponse && sender.responseContextKey == "" {
+		// The next function is responsible for closing the response body
+		return true, response.Body
+	}
+
 	defer func() { _ = response.Body.Close() }()
-	responseData, errReadingBody := io.ReadAll(response.Body)
+	responseData, errReadingBody := sender.readResponseBody(response)
 	if errReadingBody != nil {
 		// Can't have continueOnSendError=true when returnInputData=false, so no need to check for it here
 		sender.setRetryData(ctx, exportData)
-		return false, errReadingBody
+		return false, fmt.Errorf("in pipeline '%s', %s", ctx.PipelineId(), errReadingBody.Error())
+	}
+
+	if sender.responseContextKey != "" {
+		ctx.AddValue(sender.responseContextKey, string(responseData))
+		ctx.LoggingClient().Debugf("Stored HTTP response in context as '%s' in pipeline '%s'", sender.responseContextKey, ctx.PipelineId())
+		return true, data
 	}
 
 	return true, responseData
 }
 
-// SetHttpRequestHeaders will set all the header parameters for the http request
-func (sender *HTTPSender) SetHttpRequestHeaders(httpRequestHeaders map[string]string) {
+// readResponseBody reads the response body, failing if it is larger than the configured maximum size
+func (sender *HTTPSender) readResponseBody(response *http.Response) ([]byte, error) {
+	if sender.maxResponseBytes <= 0 {
+		return io.ReadAll(response.Body)
+	}
 
-	if httpRequestHeaders != nil {
-		sender.httpRequestHeaders = httpRequestHeaders
+	// Read one byte more than the maximum so a response larger than the maximum can be detected
+	responseData, err := io.ReadAll(io.LimitReader(response.Body, sender.maxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(responseData)) > sender.maxResponseBytes {
+		return nil, fmt.Errorf("HTTP response from %s exceeds the maximum size of %d bytes", response.Request.URL.Redacted(), sender.maxResponseBytes)
 	}
 
+	return responseData, nil
 }
 
-func (sender *HTTPSender) determineIfUsingSecrets(ctx interfaces.AppFunctionContext) (bool, error) {
-	// not using secrets if both are empty
-	if len(sender.secretName) == 0 && len(sender.secretValueKey) == 0 {
-		if len(sender.httpHeaderName) == 0 {
-			return false, nil
+// discardResponse drains and closes the response body so the connection can be reused by the next send
+func discardResponse(response *http.Response) {
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+}
+
+// renderBody executes the body template with the data as the payload and the context storage values
+func (sender *HTTPSender) renderBody(ctx interfaces.AppFunctionContext, exportData []byte) ([]byte, error) {
+	if sender.bodyTemplateErr != nil {
+		return nil, sender.bodyTemplateErr
+	}
+
+	var body bytes.Buffer
+	err := sender.bodyTemplate.Execute(&body, HTTPBodyTemplateData{
+		Payload: string(exportData),
+		Values:  ctx.GetAllValues(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return body.Bytes(), nil
+}
+
+// createRequest creates the request to the formatted URL with all the configured headers set.
+// requestData is the body as sent, which is signed when HMAC signing is enabled, and contentType is its content type.
+func (sender *HTTPSender) createRequest(
+	ctx interfaces.AppFunctionContext,
+	method string,
+	targetUrl string,
+	data interface{},
+	isStream bool,
+	requestBody io.Reader,
+	requestData []byte,
+	contentType string,
+	usingSecrets bool) (*http.Request, *url.URL, error) {
+	formattedUrl, err := sender.urlFormatter.invoke(targetUrl, ctx, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsedUrl, err := url.Parse(formattedUrl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(sender.queryParams) > 0 {
+		query := parsedUrl.Query()
+		for name, value := range sender.queryParams {
+			formattedValue, err := sender.urlFormatter.invoke(value, ctx, data)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			query.Set(name, formattedValue)
 		}
 
-		return false, fmt.Errorf("in pipeline '%s', secretName & secretValueKey must be specified when HTTP Header Name is specified", ctx.PipelineId())
+		parsedUrl.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(sender.getRequestContext(), method, parsedUrl.String(), requestBody)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Content-Length is set when the length of the streamed data is known, otherwise the data is sent chunked
+	if lengthReader, ok := data.(interface{ Len() int }); ok && isStream && !sender.compressBody {
+		req.ContentLength = int64(lengthReader.Len())
+	}
+
+	if err := sender.setCommonHeaders(ctx, req, usingSecrets); err != nil {
+		return nil, nil, err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	if sender.setAcceptHeader {
+		req.Header.Set("Accept", sender.acceptMimeType())
 	}
 
-	//check if one field but not others are provided for secrets
-	if len(sender.secretName) != 0 && len(sender.secretValueKey) == 0 {
-		return false, fmt.Errorf("in pipeline '%s', secretName was specified but no secretName was provided", ctx.PipelineId())
+	if sender.compressBody && method != http.MethodGet {
+		req.Header.Set("Content-Encoding", "gzip")
 	}
-	if len(sender.secretValueKey) != 0 && len(sender.secretName) == 0 {
-		return false, fmt.Errorf("in pipeline '%s', HTTP Header secretName was provided but no secretName was provided", ctx.PipelineId())
+
+	// The body as sent, which is empty when the data is sent in the payload header
+	bodyData := requestData
+	if sender.usingPayloadHeader(method) {
+		req.Header.Set(sender.payloadHeader, string(requestData))
+		bodyData = nil
+	}
+
+	if len(sender.idempotencyHeader) > 0 || len(sender.idempotencyCtxKey) > 0 {
+		hash := sha256.Sum256(requestData)
+		idempotencyKey := hex.EncodeToString(hash[:])
+
+		if len(sender.idempotencyHeader) > 0 {
+			req.Header.Set(sender.idempotencyHeader, idempotencyKey)
+		}
+
+		if len(sender.idempotencyCtxKey) > 0 {
+			ctx.AddValue(sender.idempotencyCtxKey, idempotencyKey)
+		}
+	}
+
+	if sender.cloudEventHeaders {
+		for key, value := range ctx.GetAllValues() {
+			if strings.HasPrefix(key, CloudEventHeaderPrefix) {
+				req.Header.Set(key, value)
+			}
+		}
 	}
 
-	if len(sender.httpHeaderName) == 0 {
-		return false, fmt.Errorf("in pipeline '%s', HTTP Header Name required when using secrets", ctx.PipelineId())
+	if err := sender.setRequestHeadersAndSign(ctx, req, bodyData); err != nil {
+		return nil, nil, err
 	}
 
-	// using secrets, all required fields are provided
-	return true, nil
+	return req, parsedUrl, nil
 }
 
-func (sender *HTTPSender) setRetryData(ctx interfaces.AppFunctionContext, exportData []byte) {
-	if sender.persistOnError {
+// setCommonHeaders sets the headers sent with both exports and pings, which are the default headers, the User-Agent,
+// the correlation id and the secret and basic authentication headers.
+func (sender *HTTPSender) setCommonHeaders(ctx interfaces.AppFunctionContext, req *http.Request, usingSecrets bool) error {
+	lc := ctx.LoggingClient()
+
+	// The defaults are set first so they are overridden by any other header with the same name
+	for key, value := range sender.defaultHeaders {
+		req.Header.Set(key, value)
+	}
+
+	if len(sender.userAgent) > 0 {
+		req.Header.Set("User-Agent", sender.userAgent)
+	} else if len(req.Header.Get("User-Agent")) == 0 {
+		req.Header.Set("User-Agent", defaultUserAgent())
+	}
+
+	// The correlation id is set before the other headers so it doesn't overwrite a header explicitly set to the same name
+	if sender.sendCorrelationID && len(ctx.CorrelationID()) > 0 {
+		headerName := sender.correlationIDHeader
+		if len(headerName) == 0 {
+			headerName = common.CorrelationHeader
+		}
+
+		req.Header.Set(headerName, ctx.CorrelationID())
+	}
+
+	if usingSecrets {
+		for _, secretHeader := range sender.secretHeaders {
+			secretValue, err := sender.getSecretValue(ctx, secretHeader.SecretName, secretHeader.SecretValueKey)
+			if err != nil {
+				return err
+			}
+
+			lc.Debugf("Setting HTTP Header '%s' with secret value from SecretStore at secretName='%s' & secretKeyValue='%s in pipeline '%s'",
+				secretHeader.HeaderName,
+				secretHeader.SecretName,
+				secretHeader.SecretValueKey,
+				ctx.PipelineId())
+
+			req.Header.Set(secretHeader.HeaderName, secretValue)
+		}
+	}
+
+	if len(sender.basicAuthSecret) > 0 {
+		username, err := sender.getSecretValue(ctx, sender.basicAuthSecret, sender.basicAuthUserKey)
+		if err != nil {
+			return err
+		}
+
+		password, err := sender.getSecretValue(ctx, sender.basicAuthSecret, sender.basicAuthPassKey)
+		if err != nil {
+			return err
+		}
+
+		lc.Debugf("Setting HTTP Basic Authorization with credentials from SecretStore at secretName='%s' in pipeline '%s'",
+			sender.basicAuthSecret,
+			ctx.PipelineId())
+
+		req.SetBasicAuth(username, password)
+	}
+
+	return nil
+}
+
+// setRequestHeadersAndSign sets the http request headers, which take precedence over all the other headers, and then
+// signs the request, if HMAC signing is enabled, so the signature covers the final headers and the body as sent.
+func (sender *HTTPSender) setRequestHeadersAndSign(ctx interfaces.AppFunctionContext, req *http.Request, bodyData []byte) error {
+	for key, element := range sender.httpRequestHeaders {
+		req.Header.Set(key, element)
+	}
+
+	if sender.hmac == nil {
+		return nil
+	}
+
+	signingKey, err := sender.getSecretValue(ctx, sender.hmac.secretName, sender.hmac.secretValueKey)
+	if err != nil {
+		return err
+	}
+
+	if len(signingKey) == 0 {
+		return fmt.Errorf("in pipeline '%s', HMAC signing key '%s' in secret '%s' is empty",
+			ctx.PipelineId(), sender.hmac.secretValueKey, sender.hmac.secretName)
+	}
+
+	sender.hmac.sign(req, bodyData, signingKey)
+	return nil
+}
+
+// acceptMimeType returns the mime type sent in the Accept header, which is the ResponseMimeType or the MimeType if not set
+func (sender *HTTPSender) acceptMimeType() string {
+	if len(sender.responseMimeType) > 0 {
+		return sender.responseMimeType
+	}
+
+	return sender.mimeType
+}
+
+// defaultUserAgent returns the User-Agent sent when no UserAgent is specified, which identifies the SDK and its version
+func defaultUserAgent() string {
+	return fmt.Sprintf("%s/%s", UserAgentProduct, internal.SDKVersion)
+}
+
+// isSkipped returns whether the send is gated off by a false value for the SkipWhenContextKey in the context
+func (sender *HTTPSender) isSkipped(ctx interfaces.AppFunctionContext) bool {
+	if len(sender.skipWhenContextKey) == 0 {
+		return false
+	}
+
+	value, found := ctx.GetValue(sender.skipWhenContextKey)
+	if !found {
+		return false
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return true
+	}
+
+	send, err := strconv.ParseBool(value)
+	return err == nil && !send
+}
+
+func (sender *HTTPSender) usingPayloadHeader(method string) bool {
+	return len(sender.payloadHeader) > 0 && method != http.MethodGet
+}
+
+// validatePayloadHeader checks that the data fits in the payload header and is a valid header value
+func (sender *HTTPSender) validatePayloadHeader(data []byte) error {
+	maxBytes := sender.maxPayloadHeader
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxPayloadHeaderBytes
+	}
+
+	if len(data) > maxBytes {
+		return fmt.Errorf("data size of %d bytes exceeds the maximum header size of %d bytes", len(data), maxBytes)
+	}
+
+	// Control characters other than tab aren't allowed in header values, since they could split the header
+	for index, b := range data {
+		if (b < ' ' && b != '\t') || b == 0x7f {
+			return fmt.Errorf("data contains a character (0x%02x at offset %d) not allowed in a header value", b, index)
+		}
+	}
+
+	return nil
+}
+
+// validateBasicAuth checks that the basic authentication options are complete and that the 'Authorization' header
+// isn't also set by OAuth2 or a secret header.
+func (sender *HTTPSender) validateBasicAuth() error {
+	if len(sender.basicAuthSecret) == 0 {
+		if len(sender.basicAuthUserKey) > 0 || len(sender.basicAuthPassKey) > 0 {
+			return errors.New("BasicAuthSecretName must be specified when BasicAuthUsernameKey or BasicAuthPasswordKey is specified")
+		}
+
+		return nil
+	}
+
+	if len(sender.basicAuthUserKey) == 0 || len(sender.basicAuthPassKey) == 0 {
+		return errors.New("BasicAuthUsernameKey & BasicAuthPasswordKey must be specified when BasicAuthSecretName is specified")
+	}
+
+	if sender.oauth2 != nil {
+		return errors.New("basic authentication and OAuth2 can not both be used")
+	}
+
+	for _, secretHeader := range sender.secretHeaders {
+		if strings.EqualFold(secretHeader.HeaderName, "Authorization") {
+			return errors.New("basic authentication and a secret header named 'Authorization' can not both be used")
+		}
+	}
+
+	return nil
+}
+
+// sendRequest sends the request with the authorization token and trace context, if any, retrying as configured.
+func (sender *HTTPSender) sendRequest(ctx interfaces.AppFunctionContext, client *http.Client, req *http.Request) (response *http.Response, err error) {
+	if sender.tracer != nil {
+		var endSpan func(*http.Response, error)
+		req, endSpan = sender.tracer.start(ctx, req)
+		defer func() { endSpan(response, err) }()
+	}
+
+	err = sender.setAuthorizationToken(ctx, client, req)
+	if err == nil {
+		response, err = sender.doWithRetries(ctx, client, req)
+	}
+
+	if sender.oauth2 != nil && err == nil && response.StatusCode == http.StatusUnauthorized {
+		// Token may have been revoked, so force fetching a new one for the next send
+		sender.oauth2.invalidate()
+	}
+
+	return response, err
+}
+
+// SetHttpRequestHeaders will set all the header parameters for the http request
+func (sender *HTTPSender) SetHttpRequestHeaders(httpRequestHeaders map[string]string) {
+
+	if httpRequestHeaders != nil {
+		sender.httpRequestHeaders = httpRequestHeaders
+	}
+
+}
+
+// SetHttpRequestTimeout will set the time limit for the http request. Zero means no timeout.
+// Must be called prior to the first send since the http client is created once and then reused.
+func (sender *HTTPSender) SetHttpRequestTimeout(timeout time.Duration) {
+	sender.httpRequestTimeout = timeout
+}
+
+// SetContext will set the context used for all requests so that in-flight requests are aborted when it is cancelled.
+func (sender *HTTPSender) SetContext(requestContext context.Context) {
+	sender.requestContext = requestContext
+}
+
+func (sender *HTTPSender) getRequestContext() context.Context {
+	if sender.requestContext == nil {
+		return context.Background()
+	}
+
+	return sender.requestContext
+}
+
+// getClient returns the http client for this sender, creating it on first use so that the underlying
+// transport and its keep-alive connections are reused across all sends. The client is re-created if the
+// client certificate or proxy credentials are in use and the secrets have been updated since they were last retrieved.
+// The client provided in the options, if any, is always used instead.
+func (sender *HTTPSender) getClient(ctx interfaces.AppFunctionContext) (*http.Client, error) {
+	if sender.customClient != nil {
+		return sender.customClient, nil
+	}
+
+	sender.clientLock.Lock()
+	defer sender.clientLock.Unlock()
+
+	usingClientCert := len(sender.clientCertSecret) > 0
+	usingSecrets := usingClientCert || len(sender.proxySecretName) > 0
+	if sender.client != nil &&
+		(!usingSecrets || !sender.clientSecretsRetrieved.Before(ctx.SecretProvider().SecretsLastUpdated())) {
+		return sender.client, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if sender.maxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = sender.maxIdleConnsPerHost
+	}
+	if sender.maxIdleConns > 0 {
+		transport.MaxIdleConns = sender.maxIdleConns
+	}
+	if sender.idleConnTimeout > 0 {
+		transport.IdleConnTimeout = sender.idleConnTimeout
+	}
+	transport.DisableKeepAlives = sender.disableKeepAlives
+
+	if usingClientCert {
+		tlsConfig, err := sender.loadClientCertTLSConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	proxy, err := sender.loadProxy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transport.Proxy = proxy
+
+	localAddr, err := sender.parseLocalAddress()
+	if err != nil {
+		return nil, fmt.Errorf("in pipeline '%s', %s", ctx.PipelineId(), err.Error())
+	}
+
+	if len(sender.hostMappings) > 0 || sender.resolver != nil || localAddr != nil {
+		transport.DialContext = sender.dialContext(localAddr)
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if sender.forceHTTP2 {
+		if len(sender.proxyURL) > 0 {
+			return nil, fmt.Errorf("in pipeline '%s', ForceHTTP2 is not supported with ProxyURL", ctx.PipelineId())
+		}
+
+		roundTripper, err = newHTTP2Transport(transport, sender.http2Fallback)
+		if err != nil {
+			return nil, fmt.Errorf("in pipeline '%s', %s", ctx.PipelineId(), err.Error())
+		}
+	}
+
+	if usingSecrets {
+		sender.clientSecretsRetrieved = time.Now()
+	}
+
+	if sender.client != nil {
+		sender.client.CloseIdleConnections()
+	}
+
+	sender.client = &http.Client{
+		Timeout:   sender.httpRequestTimeout,
+		Transport: roundTripper,
+	}
+
+	if !sender.followRedirects {
+		sender.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	return sender.client, nil
+}
+
+// registerHttpMetric registers the metric with the name and tags identifying the URL and pipeline, so the metrics of
+// senders in different pipelines exporting to the same URL are reported separately.
+func (sender *HTTPSender) registerHttpMetric(ctx interfaces.AppFunctionContext, name string, parsedUrl *url.URL, getMetric func() any) {
+	redactedUrl := parsedUrl.Redacted()
+	tags := map[string]string{"url": redactedUrl}
+
+	pipelineId := ctx.PipelineId()
+	if len(pipelineId) > 0 {
+		name = fmt.Sprintf("%s-%s", name, pipelineId)
+		tags["pipeline"] = pipelineId
+	}
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", name, redactedUrl) },
+		getMetric,
+		tags)
+}
+
+// dialContext returns the function used by the transport to dial the mapped address in place of the host if it is
+// in the host mappings, using the custom resolver, if any, to resolve the address and binding the connection to the
+// local address, if not nil. The other settings match those of the http.DefaultTransport dialer.
+func (sender *HTTPSender) dialContext(localAddr net.Addr) func(ctx context.Context, network string, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Resolver:  sender.resolver,
+		LocalAddr: localAddr,
+	}
+
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if mapped, found := sender.hostMappings[host]; found {
+			address = net.JoinHostPort(mapped, port)
+			if _, _, err := net.SplitHostPort(mapped); err == nil {
+				address = mapped
+			}
+		}
+
+		return dialer.DialContext(ctx, network, address)
+	}
+}
+
+// parseLocalAddress returns the TCP address connections are bound to, with the port left for the OS to choose,
+// or nil if no local address is configured.
+func (sender *HTTPSender) parseLocalAddress() (net.Addr, error) {
+	if len(sender.localAddress) == 0 {
+		return nil, nil
+	}
+
+	ip := net.ParseIP(sender.localAddress)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid LocalAddress '%s', must be an IP address", sender.localAddress)
+	}
+
+	return &net.TCPAddr{IP: ip}, nil
+}
+
+// loadClientCertTLSConfig builds the TLS configuration for mutual TLS from the client certificate, key and
+// optional CA bundle stored in the SecretStore.
+func (sender *HTTPSender) loadClientCertTLSConfig(ctx interfaces.AppFunctionContext) (*tls.Config, error) {
+	if len(sender.clientCertKey) == 0 || len(sender.clientKeyKey) == 0 {
+		return nil, fmt.Errorf("in pipeline '%s', ClientCertKey & ClientKeyKey must be specified when ClientCertSecretName is specified", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Loading HTTP export client certificate from SecretStore at secretName='%s' in pipeline '%s'",
+		sender.clientCertSecret, ctx.PipelineId())
+
+	return loadTLSConfig(ctx, sender.clientCertSecret, sender.clientCertKey, sender.clientKeyKey, sender.caCertKey)
+}
+
+// loadTLSConfig builds a TLS configuration from the PEM encoded client certificate, key and CA bundle stored in the
+// secret. The client certificate is only loaded when certKey and keyKey are specified and the system CAs are used
+// when caKey is empty.
+func loadTLSConfig(ctx interfaces.AppFunctionContext, secretName string, certKey string, keyKey string, caKey string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	var keys []string
+	for _, key := range []string{certKey, keyKey, caKey} {
+		if len(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return tlsConfig, nil
+	}
+
+	secrets, err := ctx.SecretProvider().GetSecret(secretName, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("in pipeline '%s', unable to retrieve client certificate secret '%s': %w", ctx.PipelineId(), secretName, err)
+	}
+
+	if len(certKey) > 0 && len(keyKey) > 0 {
+		cert, err := tls.X509KeyPair([]byte(secrets[certKey]), []byte(secrets[keyKey]))
+		if err != nil {
+			return nil, fmt.Errorf("in pipeline '%s', unable to load client certificate from secret '%s': %w", ctx.PipelineId(), secretName, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(caKey) > 0 {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM([]byte(secrets[caKey])) {
+			return nil, fmt.Errorf("in pipeline '%s', unable to load CA certificate from secret '%s': no valid PEM certificates found",
+				ctx.PipelineId(), secretName)
+		}
+
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	return tlsConfig, nil
+}
+
+// loadProxy returns the transport's proxy function for the configured proxy URL, including the proxy credentials
+// from the SecretStore if specified. Falls back to the proxy environment variables if no proxy URL is configured.
+func (sender *HTTPSender) loadProxy(ctx interfaces.AppFunctionContext) (func(*http.Request) (*url.URL, error), error) {
+	if len(sender.proxyURL) == 0 {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(sender.proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("in pipeline '%s', unable to parse proxy URL: %w", ctx.PipelineId(), err)
+	}
+
+	if len(sender.proxySecretName) > 0 {
+		if len(sender.proxyUsernameKey) == 0 || len(sender.proxyPasswordKey) == 0 {
+			return nil, fmt.Errorf("in pipeline '%s', ProxyUsernameKey & ProxyPasswordKey must be specified when ProxySecretName is specified", ctx.PipelineId())
+		}
+
+		ctx.LoggingClient().Debugf("Retrieving proxy credentials from secret '%s' in pipeline '%s'", sender.proxySecretName, ctx.PipelineId())
+
+		secrets, err := ctx.SecretProvider().GetSecret(sender.proxySecretName, sender.proxyUsernameKey, sender.proxyPasswordKey)
+		if err != nil {
+			return nil, fmt.Errorf("in pipeline '%s', unable to retrieve proxy credentials secret '%s': %w", ctx.PipelineId(), sender.proxySecretName, err)
+		}
+
+		proxyURL.User = url.UserPassword(secrets[sender.proxyUsernameKey], secrets[sender.proxyPasswordKey])
+	}
+
+	return http.ProxyURL(proxyURL), nil
+}
+
+// setAuthorizationToken sets the OAuth2 bearer token on the request, if OAuth2 is configured
+func (sender *HTTPSender) setAuthorizationToken(ctx interfaces.AppFunctionContext, client *http.Client, req *http.Request) error {
+	if sender.oauth2 == nil {
+		return nil
+	}
+
+	token, err := sender.oauth2.token(ctx, client, req.Context())
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// doWithRetries sends the request, retrying with exponential backoff on network errors, 429 and 5xx responses
+// until the configured number of retries is exhausted. The Retry-After delay is used when the response has one.
+func (sender *HTTPSender) doWithRetries(ctx interfaces.AppFunctionContext, client *http.Client, req *http.Request) (*http.Response, error) {
+	wait := sender.retryInterval
+
+	for attempt := 1; ; attempt++ {
+		// Latency is recorded for failed attempts as well, so slow destinations are visible even when erroring
+		started := time.Now()
+		response, err := client.Do(req)
+		sender.httpLatencyMetric.UpdateSince(started)
+
+		// Requests without GetBody have streamed bodies which can't be re-sent
+		if attempt > sender.maxRetries || req.GetBody == nil || !sender.isRetryableResponse(response, err) {
+			return response, err
+		}
+
+		retryAfter, hasRetryAfter := getRetryAfter(response, time.Now())
+		if hasRetryAfter && retryAfter > sender.getMaxRetryAfter() {
+			ctx.LoggingClient().Warnf("Retry-After of %s from HTTP export destination exceeds the maximum of %s in pipeline '%s'. Not retrying",
+				retryAfter.String(), sender.getMaxRetryAfter().String(), ctx.PipelineId())
+			return response, err
+		}
+
+		if err == nil {
+			discardResponse(response)
+		}
+
+		delay := wait
+		if wait > 0 {
+			// Jitter only spreads out retries, so a cryptographically secure random number isn't needed.
+			// nolint: gosec
+			delay += time.Duration(rand.Int63n(int64(wait)/2 + 1))
+		}
+
+		if hasRetryAfter {
+			ctx.LoggingClient().Infof("Honoring Retry-After of %s from HTTP export destination in pipeline '%s'",
+				retryAfter.String(), ctx.PipelineId())
+			delay = retryAfter
+		}
+
+		ctx.LoggingClient().Debugf("HTTP export attempt %d of %d failed in pipeline '%s'. Retrying in %s",
+			attempt, sender.maxRetries+1, ctx.PipelineId(), delay.String())
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+
+		wait *= 2
+
+		req.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getMaxRetryAfter returns the longest Retry-After delay honored, which is DefaultMaxRetryAfter if not specified
+func (sender *HTTPSender) getMaxRetryAfter() time.Duration {
+	if sender.maxRetryAfter <= 0 {
+		return DefaultMaxRetryAfter
+	}
+
+	return sender.maxRetryAfter
+}
+
+func (sender *HTTPSender) isRetryableResponse(response *http.Response, err error) bool {
+	return err != nil ||
+		((response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests) &&
+			!sender.isSuccessStatusCode(response.StatusCode) && !sender.isToleratedStatusCode(response.StatusCode))
+}
+
+// getRetryAfter returns the delay specified by the Retry-After header of 429 and 503 responses, which is either
+// a number of seconds or an HTTP-date. A date in the past results in no delay.
+func getRetryAfter(response *http.Response, now time.Time) (time.Duration, bool) {
+	if response == nil ||
+		(response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(response.Header.Get("Retry-After"))
+	if len(value) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	retryTime, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(retryTime.Sub(now), 0), true
+}
+
+// isSuccessStatusCode returns true if the status code is one of the configured success status codes,
+// or is a 2xx status code when none are configured.
+func (sender *HTTPSender) isSuccessStatusCode(statusCode int) bool {
+	if len(sender.successStatusCodes) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+
+	for _, successCode := range sender.successStatusCodes {
+		if statusCode == successCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isToleratedStatusCode returns true if the status code isn't a success status code but is one of the configured
+// tolerated status codes.
+func (sender *HTTPSender) isToleratedStatusCode(statusCode int) bool {
+	if sender.isSuccessStatusCode(statusCode) {
+		return false
+	}
+
+	for _, toleratedCode := range sender.toleratedCodes {
+		if statusCode == toleratedCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (sender *HTTPSender) determineIfUsingSecrets(ctx interfaces.AppFunctionContext) (bool, error) {
+	usingSecrets := false
+
+	for index, secretHeader := range sender.secretHeaders {
+		// not using secrets for this entry if all are empty
+		if len(secretHeader.SecretName) == 0 && len(secretHeader.SecretValueKey) == 0 {
+			if len(secretHeader.HeaderName) == 0 {
+				continue
+			}
+
+			return false, fmt.Errorf("in pipeline '%s', secret header entry %d: secretName & secretValueKey must be specified when HTTP Header Name is specified", ctx.PipelineId(), index)
+		}
+
+		//check if one field but not others are provided for secrets
+		if len(secretHeader.SecretName) != 0 && len(secretHeader.SecretValueKey) == 0 {
+			return false, fmt.Errorf("in pipeline '%s', secret header entry %d: secretName was specified but no secretName was provided", ctx.PipelineId(), index)
+		}
+		if len(secretHeader.SecretValueKey) != 0 && len(secretHeader.SecretName) == 0 {
+			return false, fmt.Errorf("in pipeline '%s', secret header entry %d: HTTP Header secretName was provided but no secretName was provided", ctx.PipelineId(), index)
+		}
+
+		if len(secretHeader.HeaderName) == 0 {
+			return false, fmt.Errorf("in pipeline '%s', secret header entry %d: HTTP Header Name required when using secrets", ctx.PipelineId(), index)
+		}
+
+		usingSecrets = true
+	}
+
+	// all required fields are provided for any secret headers in use
+	return usingSecrets, nil
+}
+
+// getSecretValue returns the value for the secret key from the SecretStore, or from the environment variable
+// depending on the SecretEnvMode.
+func (sender *HTTPSender) getSecretValue(ctx interfaces.AppFunctionContext, secretName string, secretKey string) (string, error) {
+	switch sender.secretEnvMode {
+	case SecretEnvModeNone:
+		return sender.getStoredSecretValue(ctx, secretName, secretKey)
+	case SecretEnvModeFallback:
+		value, err := sender.getStoredSecretValue(ctx, secretName, secretKey)
+		if err == nil {
+			return value, nil
+		}
+
+		envName := SecretEnvVarName(secretName, secretKey)
+		envValue, found := os.LookupEnv(envName)
+		if !found {
+			return "", err
+		}
+
+		ctx.LoggingClient().Debugf("Unable to retrieve secret '%s' from the SecretStore in pipeline '%s', using environment variable '%s': %s",
+			secretName, ctx.PipelineId(), envName, err.Error())
+		return envValue, nil
+	case SecretEnvModeOnly:
+		envName := SecretEnvVarName(secretName, secretKey)
+		value, found := os.LookupEnv(envName)
+		if !found {
+			return "", fmt.Errorf("in pipeline '%s', environment variable '%s' for secret '%s' key '%s' is not set",
+				ctx.PipelineId(), envName, secretName, secretKey)
+		}
+
+		return value, nil
+	default:
+		return "", fmt.Errorf("in pipeline '%s', invalid secret environment variable mode '%s', must be '%s' or '%s'",
+			ctx.PipelineId(), sender.secretEnvMode, SecretEnvModeFallback, SecretEnvModeOnly)
+	}
+}
+
+// SecretEnvVarName returns the name of the environment variable the HTTPSender retrieves the secret key's value from
+// when SecretEnvMode is set. The name is the secret name and key joined by an underscore, upper cased, with any
+// character other than a letter, digit or underscore replaced by an underscore, i.e. secret name 'my-api' and key
+// 'api-key' is 'MY_API_API_KEY'.
+func SecretEnvVarName(secretName string, secretKey string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, secretName+"_"+secretKey)
+}
+
+// getStoredSecretValue returns the value for the secret key from the cache, retrieving it from the SecretStore
+// when not cached. Cached values for a secret are invalidated when the SecretProvider reports the secret has been
+// updated. The SecretProvider only allows one callback per secret name, so if another component has already
+// registered for the secret, the cache falls back to being invalidated whenever any secret has been updated.
+func (sender *HTTPSender) getStoredSecretValue(ctx interfaces.AppFunctionContext, secretName string, secretKey string) (string, error) {
+	sender.secretCacheLock.Lock()
+	defer sender.secretCacheLock.Unlock()
+
+	secretProvider := ctx.SecretProvider()
+
+	cached, found := sender.secretCache[secretName]
+	if found && !cached.retrieved.Before(secretProvider.SecretsLastUpdated()) {
+		if value, found := cached.values[secretKey]; found {
+			return value, nil
+		}
+	} else {
+		cached = nil
+	}
+
+	retrieved := time.Now()
+	secrets, err := secretProvider.GetSecret(secretName, secretKey)
+	if err != nil {
+		return "", err
+	}
+
+	if cached == nil {
+		cached = &cachedSecret{values: make(map[string]string)}
+		if sender.secretCache == nil {
+			sender.secretCache = make(map[string]*cachedSecret)
+		}
+		sender.secretCache[secretName] = cached
+	}
+
+	cached.values[secretKey] = secrets[secretKey]
+	cached.retrieved = retrieved
+
+	if !sender.secretCallbacks[secretName] {
+		if sender.secretCallbacks == nil {
+			sender.secretCallbacks = make(map[string]bool)
+		}
+		sender.secretCallbacks[secretName] = true
+
+		err = secretProvider.RegisterSecretUpdatedCallback(secretName, sender.invalidateSecret)
+		if err != nil {
+			ctx.LoggingClient().Debugf("Unable to register for updates to secret '%s' in pipeline '%s', relying on secrets last updated time: %s",
+				secretName, ctx.PipelineId(), err.Error())
+		}
+	}
+
+	return secrets[secretKey], nil
+}
+
+// invalidateSecret removes the cached values for the secret so they are retrieved again on the next send.
+func (sender *HTTPSender) invalidateSecret(secretName string) {
+	sender.secretCacheLock.Lock()
+	defer sender.secretCacheLock.Unlock()
+
+	delete(sender.secretCache, secretName)
+}
+
+// storeResponseHeaders stores the values of the configured response headers, if present, in the context storage
+// so they can be used by subsequent functions in the pipeline.
+func (sender *HTTPSender) storeResponseHeaders(ctx interfaces.AppFunctionContext, response *http.Response) {
+	for _, name := range sender.storeRespHeaders {
+		value := response.Header.Get(name)
+		if len(value) == 0 {
+			continue
+		}
+
+		key := HTTPResponseHeaderKeyPrefix + strings.ToLower(name)
+		ctx.AddValue(key, value)
+		ctx.LoggingClient().Debugf("Stored HTTP response header '%s' in context as '%s' in pipeline '%s'", name, key, ctx.PipelineId())
+	}
+}
+
+// logRequestAndResponse logs the request and the response, or the error sending the request, at trace level when
+// enabled. The logged part of the response body is put back so the whole body can still be read.
+func (sender *HTTPSender) logRequestAndResponse(ctx interfaces.AppFunctionContext, req *http.Request, response *http.Response, err error) {
+	lc := ctx.LoggingClient()
+	if !sender.logRequestResponse || lc.LogLevel() != models.TraceLog {
+		return
+	}
+
+	bodySize := "unknown"
+	if req.ContentLength >= 0 {
+		bodySize = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	lc.Tracef("HTTP export request in pipeline '%s': %s %s, body size %s bytes, headers [%s]",
+		ctx.PipelineId(), req.Method, req.URL.Redacted(), bodySize, sender.redactedHeaders(req.Header))
+
+	if err != nil {
+		lc.Tracef("HTTP export response in pipeline '%s': request failed: %s", ctx.PipelineId(), err.Error())
+		return
+	}
+
+	// Read one byte more than is logged so a truncated body can be detected
+	loggedBody, readErr := io.ReadAll(io.LimitReader(response.Body, LoggedResponseBodyBytes+1))
+	remainder := io.Reader(response.Body)
+	if readErr != nil {
+		remainder = &errorReader{err: readErr}
+	}
+	response.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(loggedBody), remainder), Closer: response.Body}
+
+	truncated := ""
+	if len(loggedBody) > LoggedResponseBodyBytes {
+		loggedBody = loggedBody[:LoggedResponseBodyBytes]
+		truncated = " (truncated)"
+	}
+
+	lc.Tracef("HTTP export response in pipeline '%s': status %s, body%s: %s", ctx.PipelineId(), response.Status, truncated, loggedBody)
+}
+
+// redactedHeaders formats the headers for logging, sorted by name, with the values of sensitive headers redacted
+func (sender *HTTPSender) redactedHeaders(header http.Header) string {
+	sensitive := map[string]bool{
+		"Authorization":       true,
+		"Proxy-Authorization": true,
+		"Cookie":              true,
+	}
+
+	for _, secretHeader := range sender.secretHeaders {
+		sensitive[http.CanonicalHeaderKey(secretHeader.HeaderName)] = true
+	}
+
+	if sender.hmac != nil {
+		headerName := sender.hmac.headerName
+		if len(headerName) == 0 {
+			headerName = DefaultHMACSignatureHeader
+		}
+		sensitive[http.CanonicalHeaderKey(headerName)] = true
+	}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	formatted := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header.Values(name), ", ")
+		if sensitive[http.CanonicalHeaderKey(name)] {
+			value = "[REDACTED]"
+		}
+		formatted = append(formatted, fmt.Sprintf("%s: %s", name, value))
+	}
+
+	return strings.Join(formatted, "; ")
+}
+
+// readCloser reads from the Reader and closes the Closer, i.e. to put back data read from a response body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// errorReader always fails with the error, i.e. the error that occurred reading the data put back into a body
+type errorReader struct {
+	err error
+}
+
+func (reader *errorReader) Read([]byte) (int, error) {
+	return 0, reader.err
+}
+
+func gzipCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// serialize converts the data to bytes the same as util.CoerceType, except that the JSON is indented when
+// JSONSerialization is JSONSerializationIndented and the MimeType is a JSON type.
+func (sender *HTTPSender) serialize(ctx interfaces.AppFunctionContext, data interface{}) ([]byte, error) {
+	switch sender.jsonSerialization {
+	case "", JSONSerializationCompact:
+		return util.CoerceType(data)
+	case JSONSerializationIndented:
+		switch data.(type) {
+		case string, []byte:
+			return util.CoerceType(data)
+		}
+
+		if !isJSONMimeType(sender.mimeType) {
+			return util.CoerceType(data)
+		}
+
+		indent := sender.jsonIndent
+		if len(indent) == 0 {
+			indent = DefaultJSONIndent
+		}
+
+		exportData, err := json.MarshalIndent(data, "", indent)
+		if err != nil {
+			return nil, fmt.Errorf("in pipeline '%s', marshaling input data to indented JSON failed: %s", ctx.PipelineId(), err.Error())
+		}
+
+		return exportData, nil
+	default:
+		return nil, fmt.Errorf("in pipeline '%s', invalid JSON serialization '%s', must be '%s' or '%s'",
+			ctx.PipelineId(), sender.jsonSerialization, JSONSerializationCompact, JSONSerializationIndented)
+	}
+}
+
+// isJSONMimeType returns whether the mime type is 'application/json' or a structured syntax '+json' type, such as
+// 'application/cloudevents+json'.
+func isJSONMimeType(mimeType string) bool {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == common.ContentTypeJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+func isFormURLEncoded(mimeType string) bool {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), ContentTypeFormURLEncoded)
+}
+
+// formEncode encodes the data as a form. Data received as a string or []byte is assumed to already be encoded.
+func formEncode(data interface{}) ([]byte, error) {
+	var values url.Values
+
+	switch formData := data.(type) {
+	case string, []byte:
+		return util.CoerceType(data)
+	case url.Values:
+		values = formData
+	case map[string]string:
+		values = url.Values{}
+		for key, value := range formData {
+			values.Set(key, value)
+		}
+	case map[string]interface{}:
+		values = url.Values{}
+		for key, value := range formData {
+			switch fieldValue := value.(type) {
+			case []string:
+				values[key] = fieldValue
+			case []interface{}:
+				for _, item := range fieldValue {
+					values.Add(key, fmt.Sprint(item))
+				}
+			case map[string]interface{}, map[string]string:
+				return nil, fmt.Errorf("unable to form encode field '%s', nested objects are not supported", key)
+			default:
+				values.Set(key, fmt.Sprint(fieldValue))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unable to form encode data of type %T, must be url.Values, map[string]string, map[string]interface{}, string or []byte", data)
+	}
+
+	return []byte(values.Encode()), nil
+}
+
+// gzipCompressStream returns a reader which provides the gzip compressed data read from the source reader
+func gzipCompressStream(source io.Reader) io.Reader {
+	reader, writer := io.Pipe()
+
+	go func() {
+		gzipWriter := gzip.NewWriter(writer)
+		_, err := io.Copy(gzipWriter, source)
+		if err == nil {
+			err = gzipWriter.Close()
+		}
+		_ = writer.CloseWithError(err)
+	}()
+
+	return reader
+}
+
+// countingReader counts the bytes read from the wrapped reader
+type countingReader struct {
+	reader io.Reader
+	count  atomic.Int64
+}
+
+func (counter *countingReader) Read(p []byte) (int, error) {
+	n, err := counter.reader.Read(p)
+	counter.count.Add(int64(n))
+	return n, err
+}
+
+func (sender *HTTPSender) setRetryData(ctx interfaces.AppFunctionContext, exportData []byte) {
+	if sender.persistOnError && exportData != nil {
 		ctx.SetRetryData(exportData)
 	}
 }
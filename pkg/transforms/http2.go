@@ -0,0 +1,159 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/http2"
+)
+
+// http2Transport sends requests using HTTP/2 only, negotiated via ALPN for https URLs and with prior knowledge (h2c)
+// for http URLs. When fallback is enabled, HTTP/1.1 is used for destinations which don't support HTTP/2.
+type http2Transport struct {
+	tls      http.RoundTripper
+	h2c      *http2.Transport
+	http1    *http.Transport
+	fallback bool
+	// http1Hosts are the plaintext destinations which have fallen back to HTTP/1.1
+	http1Hosts sync.Map
+}
+
+// newHTTP2Transport creates the HTTP/2 transport using the dialer, TLS and idle connection settings of the
+// HTTP/1.1 transport, which is also used when falling back.
+func newHTTP2Transport(transport *http.Transport, fallback bool) (*http2Transport, error) {
+	dial := transport.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	result := &http2Transport{
+		http1:    transport,
+		fallback: fallback,
+		h2c: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network string, address string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx, network, address)
+			},
+			IdleConnTimeout:    transport.IdleConnTimeout,
+			DisableCompression: transport.DisableCompression,
+		},
+	}
+
+	if fallback {
+		// ALPN negotiates HTTP/2 if the server supports it, otherwise HTTP/1.1 is used
+		h2, err := http2.ConfigureTransports(transport)
+		if err != nil {
+			return nil, fmt.Errorf("unable to configure HTTP/2: %s", err.Error())
+		}
+
+		h2.IdleConnTimeout = transport.IdleConnTimeout
+		result.tls = transport
+		return result, nil
+	}
+
+	result.tls = &http2.Transport{
+		TLSClientConfig: transport.TLSClientConfig,
+		DialTLSContext: func(ctx context.Context, network string, address string, config *tls.Config) (net.Conn, error) {
+			conn, err := dial(ctx, network, address)
+			if err != nil {
+				return nil, err
+			}
+
+			tlsConn := tls.Client(conn, config)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				_ = conn.Close()
+				return nil, err
+			}
+
+			if tlsConn.ConnectionState().NegotiatedProtocol != http2.NextProtoTLS {
+				_ = conn.Close()
+				return nil, fmt.Errorf("server at %s does not support HTTP/2", address)
+			}
+
+			return tlsConn, nil
+		},
+		IdleConnTimeout:    transport.IdleConnTimeout,
+		DisableCompression: transport.DisableCompression,
+	}
+
+	return result, nil
+}
+
+// RoundTrip sends the request using HTTP/2. When fallback is enabled and a plaintext destination fails with HTTP/2,
+// the request is resent using HTTP/1.1, which is then used for all subsequent requests to the destination.
+// Requests whose body can't be resent, i.e. streamed data, aren't resent.
+func (transport *http2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "http" {
+		return transport.tls.RoundTrip(req)
+	}
+
+	if _, found := transport.http1Hosts.Load(req.URL.Host); found {
+		return transport.http1.RoundTrip(req)
+	}
+
+	response, err := transport.h2c.RoundTrip(req)
+	if err == nil || !transport.fallback || req.Context().Err() != nil {
+		return response, err
+	}
+
+	// The destination can't be reached, regardless of protocol
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return nil, err
+	}
+
+	fallbackReq := req
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, err
+		}
+
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return nil, err
+		}
+
+		fallbackReq = req.Clone(req.Context())
+		fallbackReq.Body = body
+	}
+
+	response, fallbackErr := transport.http1.RoundTrip(fallbackReq)
+	if fallbackErr != nil {
+		return nil, fallbackErr
+	}
+
+	// Only remembered once HTTP/1.1 has succeeded so that the destination being unavailable isn't mistaken for
+	// it not supporting HTTP/2
+	transport.http1Hosts.Store(req.URL.Host, true)
+	return response, nil
+}
+
+// CloseIdleConnections closes the idle connections of all the transports
+func (transport *http2Transport) CloseIdleConnections() {
+	transport.h2c.CloseIdleConnections()
+	transport.http1.CloseIdleConnections()
+	if h2, ok := transport.tls.(*http2.Transport); ok {
+		h2.CloseIdleConnections()
+	}
+}
@@ -17,20 +17,57 @@
 package transforms
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
+	"fmt"
 	"io"
+	"math/big"
+	"mime"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"regexp"
+	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
 	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
 	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
 	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/logger"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 const (
@@ -145,6 +182,8 @@ func TestHTTPPostPutWithSecrets(t *testing.T) {
 	mockSP := &mocks2.SecretProvider{}
 	mockSP.On("GetSecret", "my-secret", "my-secret-key").Return(map[string]string{"Secret-Header-Name": expectedValue}, nil)
 	mockSP.On("GetSecret", "my-secret", "bogus").Return(nil, errors.New("FAKE NOT FOUND ERROR"))
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
 
 	dic.Update(di.ServiceConstructorMap{
 		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
@@ -240,6 +279,80 @@ func TestHTTPPostPutWithSecrets(t *testing.T) {
 	}
 }
 
+func TestHTTPPatchDelete(t *testing.T) {
+	var methodUsed string
+	var bodyReceived string
+
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "my-secret", "my-secret-key").Return(map[string]string{"my-secret-key": "my-API-key"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		methodUsed = request.Method
+		readMsg, _ := io.ReadAll(request.Body)
+		bodyReceived = string(readMsg)
+
+		if request.URL.EscapedPath() != path {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if request.Header.Get("Secret-Header") != "my-API-key" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name             string
+		Method           string
+		Data             interface{}
+		ExpectedBody     string
+		ExpectToContinue bool
+	}{
+		{"PATCH with data", http.MethodPatch, msgStr, msgStr, true},
+		{"PATCH no data", http.MethodPatch, nil, "", false},
+		{"DELETE with data", http.MethodDelete, msgStr, msgStr, true},
+		{"DELETE no data", http.MethodDelete, nil, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.AddValue("test", "foo")
+			methodUsed = ""
+			bodyReceived = ""
+
+			sender := NewHTTPSenderWithSecretHeader(ts.URL+formatPath, "", false, "Secret-Header", "my-secret", "my-secret-key")
+
+			var continuePipeline bool
+			if test.Method == http.MethodPatch {
+				continuePipeline, _ = sender.HTTPPatch(ctx, test.Data)
+			} else {
+				continuePipeline, _ = sender.HTTPDelete(ctx, test.Data)
+			}
+
+			assert.Equal(t, test.ExpectToContinue, continuePipeline)
+			if test.ExpectToContinue {
+				assert.Equal(t, test.Method, methodUsed)
+				assert.Equal(t, test.ExpectedBody, bodyReceived)
+			} else {
+				assert.Empty(t, methodUsed)
+			}
+			ctx.RemoveValue("test")
+		})
+	}
+}
+
 func TestHTTPPostNoParameterPassed(t *testing.T) {
 	sender := NewHTTPSender("", "", false)
 	continuePipeline, result := sender.HTTPPost(ctx, nil)
@@ -321,3 +434,3679 @@ func TestHTTPPostWithHTTPRequestHeaders(t *testing.T) {
 	_, ok := errPipeline.(error)
 	assert.False(t, ok)
 }
+
+func TestHTTPPostWithTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name                      string
+		Timeout                   time.Duration
+		PersistOnError            bool
+		ExpectedContinueExecuting bool
+		ExpectedRetryDataSet      bool
+	}{
+		{"No timeout", 0, false, true, false},
+		{"Timeout not exceeded", time.Second, false, true, false},
+		{"Timeout exceeded", 50 * time.Millisecond, false, false, false},
+		{"Timeout exceeded with persist", 50 * time.Millisecond, true, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.SetRetryData(nil)
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:            ts.URL,
+				PersistOnError: test.PersistOnError,
+				Timeout:        test.Timeout,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			assert.Equal(t, test.ExpectedContinueExecuting, continuePipeline)
+			assert.Equal(t, test.ExpectedRetryDataSet, ctx.RetryData() != nil)
+
+			if !test.ExpectedContinueExecuting {
+				require.IsType(t, &url.Error{}, errors.Unwrap(result.(error)))
+				assert.True(t, errors.Unwrap(result.(error)).(*url.Error).Timeout())
+				assert.Contains(t, result.(error).Error(), ts.URL)
+				assert.Contains(t, result.(error).Error(), ctx.PipelineId())
+			}
+		})
+	}
+}
+
+func TestSetHttpRequestTimeout(t *testing.T) {
+	sender := NewHTTPSender("http://localhost", "", false)
+	assert.Zero(t, sender.httpRequestTimeout)
+
+	sender.SetHttpRequestTimeout(5 * time.Second)
+	assert.Equal(t, 5*time.Second, sender.httpRequestTimeout)
+}
+
+func TestHTTPSenderClientReused(t *testing.T) {
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:                 "http://localhost",
+		MaxIdleConnsPerHost: 25,
+		Timeout:             5 * time.Second,
+	})
+
+	client, err := sender.getClient(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, client)
+	nextClient, err := sender.getClient(ctx)
+	require.NoError(t, err)
+	assert.Same(t, client, nextClient)
+	assert.Equal(t, 5*time.Second, client.Timeout)
+
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.Equal(t, 25, transport.MaxIdleConnsPerHost)
+
+	// The connection is reused across sends only when every response body is drained and closed
+	tests := []struct {
+		Name            string
+		StatusCode      int
+		ReturnInputData bool
+		ExpectContinue  bool
+	}{
+		{"Success", http.StatusOK, false, true},
+		{"Success with ReturnInputData", http.StatusOK, true, true},
+		{"4xx response", http.StatusBadRequest, false, false},
+		{"5xx response", http.StatusInternalServerError, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var connections atomic.Int32
+			ts := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				writer.WriteHeader(test.StatusCode)
+				_, _ = writer.Write([]byte("response body"))
+			}))
+			ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					connections.Add(1)
+				}
+			}
+			ts.Start()
+			defer ts.Close()
+
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:             ts.URL,
+				ReturnInputData: test.ReturnInputData,
+			})
+
+			for i := 0; i < 5; i++ {
+				continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+				require.Equal(t, test.ExpectContinue, continuePipeline)
+			}
+
+			assert.Equal(t, int32(1), connections.Load())
+		})
+	}
+}
+
+// roundTripperFunc adapts a function to an http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// traceLogger captures the trace messages logged at the configured log level
+type traceLogger struct {
+	logger.LoggingClient
+	level  string
+	traces []string
+}
+
+func (l *traceLogger) LogLevel() string {
+	return l.level
+}
+
+func (l *traceLogger) Tracef(msg string, args ...interface{}) {
+	l.traces = append(l.traces, fmt.Sprintf(msg, args...))
+}
+
+func TestHTTPPostLogRequestResponse(t *testing.T) {
+	responseBody := strings.Repeat("r", LoggedResponseBodyBytes) + "-truncated"
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+		_, _ = writer.Write([]byte(responseBody))
+	}))
+	defer ts.Close()
+
+	tsURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	tsURL.User = url.UserPassword("user", "url-password")
+
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "api-secret", "api-key").Return(map[string]string{"api-key": "my-API-key"}, nil)
+	mockSP.On("GetSecret", "basic-secret", "username").Return(map[string]string{"username": "edgex"}, nil)
+	mockSP.On("GetSecret", "basic-secret", "password").Return(map[string]string{"password": "basic-password"}, nil)
+	mockSP.On("GetSecret", "hmac-secret", "key").Return(map[string]string{"key": "signing-key"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
+
+	options := HTTPSenderOptions{
+		URL:                  tsURL.String(),
+		MimeType:             common.ContentTypeJSON,
+		HTTPHeaderName:       "x-api-key",
+		SecretName:           "api-secret",
+		SecretValueKey:       "api-key",
+		BasicAuthSecretName:  "basic-secret",
+		BasicAuthUsernameKey: "username",
+		BasicAuthPasswordKey: "password",
+		HMACSecretName:       "hmac-secret",
+		HMACSecretKey:        "key",
+		LogRequestResponse:   true,
+	}
+
+	tests := []struct {
+		Name          string
+		Enabled       bool
+		LogLevel      string
+		ExpectLogging bool
+	}{
+		{"Enabled at trace level", true, models.TraceLog, true},
+		{"Enabled at debug level", true, models.DebugLog, false},
+		{"Disabled", false, models.TraceLog, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			testLogger := &traceLogger{LoggingClient: lc, level: test.LogLevel}
+			logCtx := appfunction.NewContext("123", di.NewContainer(di.ServiceConstructorMap{
+				bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+					return testLogger
+				},
+				bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+					return mockSP
+				},
+			}), "")
+
+			options.LogRequestResponse = test.Enabled
+			sender := NewHTTPSenderWithOptions(options)
+			continuePipeline, result := sender.HTTPPost(logCtx, msgStr)
+			require.True(t, continuePipeline, result)
+			// The whole response is still returned when part of it has been logged
+			assert.Equal(t, responseBody, string(result.([]byte)))
+
+			var logged []string
+			for _, trace := range testLogger.traces {
+				if strings.HasPrefix(trace, "HTTP export") {
+					logged = append(logged, trace)
+				}
+			}
+
+			if !test.ExpectLogging {
+				assert.Empty(t, logged)
+				return
+			}
+
+			require.Len(t, logged, 2)
+			request := logged[0]
+			assert.Contains(t, request, "POST "+tsURL.Redacted())
+			assert.Contains(t, request, fmt.Sprintf("body size %d bytes", len(msgStr)))
+			assert.Contains(t, request, "Content-Type: "+common.ContentTypeJSON)
+			assert.Contains(t, request, "X-Api-Key: [REDACTED]")
+			assert.Contains(t, request, "Authorization: [REDACTED]")
+			assert.Contains(t, request, DefaultHMACSignatureHeader+": [REDACTED]")
+
+			response := logged[1]
+			assert.Contains(t, response, "status 200 OK")
+			assert.Contains(t, response, "(truncated)")
+			assert.Contains(t, response, strings.Repeat("r", LoggedResponseBodyBytes))
+			assert.NotContains(t, response, "-truncated")
+
+			for _, sensitive := range []string{
+				"my-API-key",
+				"basic-password",
+				base64.StdEncoding.EncodeToString([]byte("edgex:basic-password")),
+				"signing-key",
+				"url-password",
+			} {
+				assert.NotContains(t, request, sensitive)
+			}
+		})
+	}
+}
+
+func TestHTTPPostLogRequestResponseError(t *testing.T) {
+	testLogger := &traceLogger{LoggingClient: lc, level: models.TraceLog}
+	logCtx := appfunction.NewContext("123", di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return testLogger
+		},
+	}), "")
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:                "http://localhost:1/bad",
+		LogRequestResponse: true,
+	})
+
+	continuePipeline, _ := sender.HTTPPost(logCtx, msgStr)
+	require.False(t, continuePipeline)
+	require.GreaterOrEqual(t, len(testLogger.traces), 2)
+	assert.Contains(t, testLogger.traces[0], "POST http://localhost:1/bad")
+	assert.Contains(t, testLogger.traces[1], "request failed")
+}
+
+func TestHTTPPostWithCustomClient(t *testing.T) {
+	var receivedHeader string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeader = request.Header.Get("X-Custom-Transport")
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var roundTrips int
+	customClient := &http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			roundTrips++
+			req.Header.Set("X-Custom-Transport", "used")
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}
+	defer customClient.CloseIdleConnections()
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:     ts.URL,
+		Client:  customClient,
+		Timeout: time.Second,
+	})
+
+	client, err := sender.getClient(ctx)
+	require.NoError(t, err)
+	assert.Same(t, customClient, client)
+	assert.Zero(t, client.Timeout, "client provided should be used as is")
+
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, 1, roundTrips)
+	assert.Equal(t, "used", receivedHeader)
+}
+
+func TestHTTPSenderTransportOptions(t *testing.T) {
+	defaultTransport := http.DefaultTransport.(*http.Transport)
+
+	tests := []struct {
+		Name                      string
+		Options                   HTTPSenderOptions
+		ExpectedMaxIdleConns      int
+		ExpectedIdleConnTimeout   time.Duration
+		ExpectedDisableKeepAlives bool
+	}{
+		{"Defaults", HTTPSenderOptions{}, defaultTransport.MaxIdleConns, defaultTransport.IdleConnTimeout, false},
+		{"Tuned", HTTPSenderOptions{MaxIdleConns: 10, IdleConnTimeout: 5 * time.Second}, 10, 5 * time.Second, false},
+		{"Keep alives disabled", HTTPSenderOptions{DisableKeepAlives: true}, defaultTransport.MaxIdleConns, defaultTransport.IdleConnTimeout, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Options.URL = "http://localhost"
+			sender := NewHTTPSenderWithOptions(test.Options)
+
+			client, err := sender.getClient(ctx)
+			require.NoError(t, err)
+
+			transport, ok := client.Transport.(*http.Transport)
+			require.True(t, ok)
+			assert.Equal(t, test.ExpectedMaxIdleConns, transport.MaxIdleConns)
+			assert.Equal(t, test.ExpectedIdleConnTimeout, transport.IdleConnTimeout)
+			assert.Equal(t, test.ExpectedDisableKeepAlives, transport.DisableKeepAlives)
+		})
+	}
+}
+
+func TestHTTPPostDisableKeepAlives(t *testing.T) {
+	tests := []struct {
+		Name                string
+		DisableKeepAlives   bool
+		ExpectedConnections int
+		ExpectedClose       bool
+	}{
+		{"Keep alives enabled", false, 1, false},
+		{"Keep alives disabled", true, 3, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var connectionHeaders []bool
+			ts := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				connectionHeaders = append(connectionHeaders, request.Close)
+				writer.WriteHeader(http.StatusOK)
+			}))
+
+			var connections atomic.Int32
+			ts.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					connections.Add(1)
+				}
+			}
+			ts.Start()
+			defer ts.Close()
+
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:               ts.URL,
+				DisableKeepAlives: test.DisableKeepAlives,
+			})
+
+			for i := 0; i < 3; i++ {
+				continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+				require.True(t, continuePipeline, result)
+			}
+
+			// request.Close is true when the request was sent with the 'Connection: close' header
+			for _, requestClose := range connectionHeaders {
+				assert.Equal(t, test.ExpectedClose, requestClose)
+			}
+			assert.Equal(t, int32(test.ExpectedConnections), connections.Load())
+		})
+	}
+}
+
+func BenchmarkHTTPPostReusedClient(b *testing.B) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = io.Copy(io.Discard, request.Body)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSender(ts.URL, "", false)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sender.HTTPPost(ctx, msgStr)
+	}
+	b.StopTimer()
+}
+
+func BenchmarkHTTPPostNewClient(b *testing.B) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = io.Copy(io.Discard, request.Body)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewHTTPSender(ts.URL, "", false).HTTPPost(ctx, msgStr)
+	}
+	b.StopTimer()
+}
+
+func TestHTTPPostWithRetries(t *testing.T) {
+	tests := []struct {
+		Name                      string
+		MaxRetries                int
+		FailureCount              int
+		FailureStatus             int
+		ExpectedAttempts          int
+		ExpectedContinueExecuting bool
+		ExpectedErrorCount        int64
+		ExpectedSuccessCount      int64
+	}{
+		{"No retries, success", 0, 0, http.StatusServiceUnavailable, 1, true, 0, 1},
+		{"No retries, failure", 0, 1, http.StatusServiceUnavailable, 1, false, 1, 0},
+		{"Succeeds after retries", 3, 2, http.StatusServiceUnavailable, 3, true, 0, 1},
+		{"Succeeds on last retry", 3, 3, http.StatusServiceUnavailable, 4, true, 0, 1},
+		{"Retries exhausted", 3, 10, http.StatusServiceUnavailable, 4, false, 1, 0},
+		{"No retry on 4xx", 3, 10, http.StatusBadRequest, 1, false, 1, 0},
+		{"Succeeds after retries on 429", 3, 2, http.StatusTooManyRequests, 3, true, 0, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			attempts := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				attempts++
+				readMsg, _ := io.ReadAll(request.Body)
+				assert.Equal(t, msgStr, string(readMsg))
+
+				if attempts <= test.FailureCount {
+					writer.WriteHeader(test.FailureStatus)
+					return
+				}
+
+				writer.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:           ts.URL,
+				MaxRetries:    test.MaxRetries,
+				RetryInterval: time.Millisecond,
+			})
+
+			continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+			assert.Equal(t, test.ExpectedContinueExecuting, continuePipeline)
+			assert.Equal(t, test.ExpectedAttempts, attempts)
+			assert.Equal(t, test.ExpectedErrorCount, sender.httpErrorMetric.Count())
+			assert.Equal(t, test.ExpectedSuccessCount, sender.httpSuccessMetric.Count())
+		})
+	}
+}
+
+func TestHTTPPostWithRetryAfter(t *testing.T) {
+	var attemptTimes []time.Time
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		attemptTimes = append(attemptTimes, time.Now())
+		if len(attemptTimes) == 1 {
+			writer.Header().Set("Retry-After", "1")
+			writer.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:           ts.URL,
+		MaxRetries:    1,
+		RetryInterval: time.Millisecond,
+	})
+
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	require.Len(t, attemptTimes, 2)
+	// The Retry-After delay is used rather than the much shorter retry interval
+	assert.GreaterOrEqual(t, attemptTimes[1].Sub(attemptTimes[0]), time.Second)
+}
+
+func TestHTTPPostWithRetryAfterExceedsMax(t *testing.T) {
+	tests := []struct {
+		Name          string
+		RetryAfter    string
+		MaxRetryAfter time.Duration
+	}{
+		{"Large seconds value", "4294967295", 0},
+		{"Far future HTTP-date", time.Now().AddDate(10, 0, 0).UTC().Format(http.TimeFormat), 0},
+		{"Exceeds configured maximum", "2", time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var attempts atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				attempts.Add(1)
+				writer.Header().Set("Retry-After", test.RetryAfter)
+				writer.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer ts.Close()
+
+			ctx.SetRetryData(nil)
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:            ts.URL,
+				PersistOnError: true,
+				MaxRetries:     3,
+				RetryInterval:  time.Millisecond,
+				MaxRetryAfter:  test.MaxRetryAfter,
+			})
+
+			started := time.Now()
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			require.False(t, continuePipeline)
+			assert.Contains(t, result.(error).Error(), "429 HTTP status code")
+			assert.Less(t, time.Since(started), time.Second)
+			assert.Equal(t, int32(1), attempts.Load())
+			// The data is handed to Store and Forward rather than held up waiting for the retry
+			assert.Equal(t, []byte(msgStr), ctx.RetryData())
+			ctx.SetRetryData(nil)
+		})
+	}
+}
+
+func TestGetRetryAfter(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name          string
+		StatusCode    int
+		RetryAfter    string
+		ExpectedDelay time.Duration
+		ExpectedFound bool
+	}{
+		{"Seconds on 429", http.StatusTooManyRequests, "120", 2 * time.Minute, true},
+		{"Seconds on 503", http.StatusServiceUnavailable, " 5 ", 5 * time.Second, true},
+		{"Zero seconds", http.StatusTooManyRequests, "0", 0, true},
+		{"HTTP-date", http.StatusServiceUnavailable, now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"HTTP-date in the past", http.StatusServiceUnavailable, now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"No header", http.StatusTooManyRequests, "", 0, false},
+		{"Negative seconds", http.StatusTooManyRequests, "-5", 0, false},
+		{"Invalid value", http.StatusTooManyRequests, "soon", 0, false},
+		{"Ignored on 500", http.StatusInternalServerError, "120", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			response := &http.Response{StatusCode: test.StatusCode, Header: http.Header{}}
+			if len(test.RetryAfter) > 0 {
+				response.Header.Set("Retry-After", test.RetryAfter)
+			}
+
+			delay, found := getRetryAfter(response, now)
+			assert.Equal(t, test.ExpectedFound, found)
+			assert.Equal(t, test.ExpectedDelay, delay)
+		})
+	}
+}
+
+func TestHTTPPostWithRetriesNetworkError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	targetUrl := ts.URL
+	// Close the server so every attempt fails with a connection error
+	ts.Close()
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:           targetUrl,
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+	})
+
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	assert.False(t, continuePipeline)
+	require.Error(t, result.(error))
+	assert.Equal(t, int64(1), sender.httpErrorMetric.Count())
+}
+
+func TestHTTPPostWithClientCert(t *testing.T) {
+	certPEM, keyPEM, clientCert := generateTestClientCert(t)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "client-cert", "cert", "key", "ca").Return(map[string]string{
+		"cert": string(certPEM),
+		"key":  string(keyPEM),
+		"ca":   string(caPEM),
+	}, nil)
+	mockSP.On("GetSecret", "bad-client-cert", "cert", "key", "ca").Return(map[string]string{
+		"cert": "bogus",
+		"key":  "bogus",
+		"ca":   string(caPEM),
+	}, nil)
+	mockSP.On("GetSecret", "bad-ca-cert", "cert", "key", "ca").Return(map[string]string{
+		"cert": string(certPEM),
+		"key":  string(keyPEM),
+		"ca":   "bogus",
+	}, nil)
+	mockSP.On("GetSecret", "missing", "cert", "key", "ca").Return(nil, errors.New("FAKE NOT FOUND ERROR"))
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	tests := []struct {
+		Name                 string
+		SecretName           string
+		CertKey              string
+		KeyKey               string
+		CAKey                string
+		ExpectToContinue     bool
+		ExpectedErrorMessage string
+	}{
+		{"Valid client cert", "client-cert", "cert", "key", "ca", true, ""},
+		{"No client cert", "", "", "", "", false, "certificate"},
+		{"Missing client key key", "client-cert", "cert", "", "ca", false, "ClientCertKey & ClientKeyKey must be specified"},
+		{"Secret not found", "missing", "cert", "key", "ca", false, "FAKE NOT FOUND ERROR"},
+		{"Bad client cert", "bad-client-cert", "cert", "key", "ca", false, "unable to load client certificate"},
+		{"Bad CA cert", "bad-ca-cert", "cert", "key", "ca", false, "unable to load CA certificate"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                  ts.URL,
+				ClientCertSecretName: test.SecretName,
+				ClientCertKey:        test.CertKey,
+				ClientKeyKey:         test.KeyKey,
+				CACertKey:            test.CAKey,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			assert.Equal(t, test.ExpectToContinue, continuePipeline)
+			if !test.ExpectToContinue {
+				require.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+			}
+		})
+	}
+}
+
+func TestHTTPPostWithClientCertRotation(t *testing.T) {
+	certPEM, keyPEM, clientCert := generateTestClientCert(t)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+
+	secrets := map[string]string{
+		"cert": string(certPEM),
+		"key":  string(keyPEM),
+		"ca":   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})),
+	}
+
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "client-cert", "cert", "key", "ca").Return(secrets, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:                  ts.URL,
+		ClientCertSecretName: "client-cert",
+		ClientCertKey:        "cert",
+		ClientKeyKey:         "key",
+		CACertKey:            "ca",
+	})
+
+	continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	continuePipeline, _ = sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	// Cert is cached, so only retrieved once
+	mockSP.AssertNumberOfCalls(t, "GetSecret", 1)
+
+	rotatedSP := &mocks2.SecretProvider{}
+	rotatedSP.On("GetSecret", "client-cert", "cert", "key", "ca").Return(secrets, nil)
+	rotatedSP.On("SecretsLastUpdated").Return(time.Now().Add(time.Hour))
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return rotatedSP
+		},
+	})
+
+	continuePipeline, _ = sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	// Secrets updated since last retrieved, so cert is reloaded
+	rotatedSP.AssertNumberOfCalls(t, "GetSecret", 1)
+}
+
+func generateTestClientCert(t *testing.T) ([]byte, []byte, *x509.Certificate) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(certDER)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM, cert
+}
+
+func TestHTTPPostWithCompressBody(t *testing.T) {
+	payload := strings.Repeat(msgStr, 100)
+
+	var receivedEncoding string
+	var receivedBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedEncoding = request.Header.Get("Content-Encoding")
+
+		var reader io.Reader = request.Body
+		if receivedEncoding == "gzip" {
+			gzipReader, err := gzip.NewReader(request.Body)
+			require.NoError(t, err)
+			reader = gzipReader
+		}
+
+		readMsg, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		receivedBody = string(readMsg)
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name             string
+		CompressBody     bool
+		ExpectedEncoding string
+	}{
+		{"Compressed", true, "gzip"},
+		{"Not compressed", false, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:          ts.URL,
+				CompressBody: test.CompressBody,
+			})
+
+			continuePipeline, _ := sender.HTTPPost(ctx, payload)
+			require.True(t, continuePipeline)
+			assert.Equal(t, test.ExpectedEncoding, receivedEncoding)
+			assert.Equal(t, payload, receivedBody)
+
+			if test.CompressBody {
+				assert.Less(t, sender.httpSizeMetrics.Max(), int64(len(payload)))
+			} else {
+				assert.Equal(t, int64(len(payload)), sender.httpSizeMetrics.Max())
+			}
+		})
+	}
+}
+
+func TestHTTPPostWithCompressBodyRetryDataNotCompressed(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx.SetRetryData(nil)
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:            ts.URL,
+		PersistOnError: true,
+		CompressBody:   true,
+	})
+
+	continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Equal(t, []byte(msgStr), ctx.RetryData())
+	ctx.SetRetryData(nil)
+}
+
+func TestHTTPPostStoreResponseHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Location", "/some-path/123")
+		writer.Header().Set("ETag", `"abc"`)
+		writer.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name            string
+		ReturnInputData bool
+	}{
+		{"Return response data", false},
+		{"Return input data", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                  ts.URL,
+				ReturnInputData:      test.ReturnInputData,
+				StoreResponseHeaders: []string{"Location", "ETag", "X-Not-Present"},
+			})
+
+			continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+			require.True(t, continuePipeline)
+
+			value, found := ctx.GetValue(HTTPResponseHeaderKeyPrefix + "location")
+			assert.True(t, found)
+			assert.Equal(t, "/some-path/123", value)
+
+			value, found = ctx.GetValue(HTTPResponseHeaderKeyPrefix + "etag")
+			assert.True(t, found)
+			assert.Equal(t, `"abc"`, value)
+
+			_, found = ctx.GetValue(HTTPResponseHeaderKeyPrefix + "x-not-present")
+			assert.False(t, found)
+
+			ctx.RemoveValue(HTTPResponseHeaderKeyPrefix + "location")
+			ctx.RemoveValue(HTTPResponseHeaderKeyPrefix + "etag")
+		})
+	}
+}
+
+func TestHTTPPostWithMultipleSecretHeaders(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "api-secret", "api-key").Return(map[string]string{"api-key": "my-API-key"}, nil)
+	mockSP.On("GetSecret", "tenant-secret", "tenant").Return(map[string]string{"tenant": "my-tenant"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name                 string
+		Options              HTTPSenderOptions
+		ExpectedHeaders      map[string]string
+		ExpectedErrorMessage string
+	}{
+		{
+			Name: "Two secret headers",
+			Options: HTTPSenderOptions{
+				SecretHeaders: []SecretHeader{
+					{HeaderName: "X-Api-Key", SecretName: "api-secret", SecretValueKey: "api-key"},
+					{HeaderName: "X-Tenant", SecretName: "tenant-secret", SecretValueKey: "tenant"},
+				},
+			},
+			ExpectedHeaders: map[string]string{"X-Api-Key": "my-API-key", "X-Tenant": "my-tenant"},
+		},
+		{
+			Name: "Single secret header plus secret headers",
+			Options: HTTPSenderOptions{
+				HTTPHeaderName: "X-Api-Key",
+				SecretName:     "api-secret",
+				SecretValueKey: "api-key",
+				SecretHeaders: []SecretHeader{
+					{HeaderName: "X-Tenant", SecretName: "tenant-secret", SecretValueKey: "tenant"},
+				},
+			},
+			ExpectedHeaders: map[string]string{"X-Api-Key": "my-API-key", "X-Tenant": "my-tenant"},
+		},
+		{
+			Name: "Second entry missing header name",
+			Options: HTTPSenderOptions{
+				SecretHeaders: []SecretHeader{
+					{HeaderName: "X-Api-Key", SecretName: "api-secret", SecretValueKey: "api-key"},
+					{SecretName: "tenant-secret", SecretValueKey: "tenant"},
+				},
+			},
+			ExpectedErrorMessage: "secret header entry 1: HTTP Header Name required when using secrets",
+		},
+		{
+			Name: "Second entry missing secret value key",
+			Options: HTTPSenderOptions{
+				SecretHeaders: []SecretHeader{
+					{HeaderName: "X-Api-Key", SecretName: "api-secret", SecretValueKey: "api-key"},
+					{HeaderName: "X-Tenant", SecretName: "tenant-secret"},
+				},
+			},
+			ExpectedErrorMessage: "secret header entry 1: secretName was specified",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedHeaders = nil
+			test.Options.URL = ts.URL
+			sender := NewHTTPSenderWithOptions(test.Options)
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			if len(test.ExpectedErrorMessage) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+				assert.Nil(t, receivedHeaders)
+				return
+			}
+
+			require.True(t, continuePipeline)
+			for name, expected := range test.ExpectedHeaders {
+				assert.Equal(t, expected, receivedHeaders.Get(name))
+			}
+		})
+	}
+}
+
+func TestHTTPPostWithSecretEnvMode(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "api-secret", "api-key").Return(map[string]string{"api-key": "stored-key"}, nil)
+	mockSP.On("GetSecret", "missing-secret", "api-key").Return(nil, errors.New("secret not found"))
+	mockSP.On("GetSecret", "missing-secret-unset", "api-key").Return(nil, errors.New("secret not found"))
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	t.Setenv("API_SECRET_API_KEY", "env-key")
+	t.Setenv("MISSING_SECRET_API_KEY", "env-fallback-key")
+	// The env-secret isn't in the SecretStore mock so the test fails if the SecretStore is used
+	t.Setenv("ENV_SECRET_API_KEY", "env-only-key")
+
+	tests := []struct {
+		Name                 string
+		Mode                 string
+		SecretName           string
+		ExpectedValue        string
+		ExpectedErrorMessage string
+	}{
+		{"SecretStore only", SecretEnvModeNone, "api-secret", "stored-key", ""},
+		{"Fallback not needed", SecretEnvModeFallback, "api-secret", "stored-key", ""},
+		{"Fallback to env var", SecretEnvModeFallback, "missing-secret", "env-fallback-key", ""},
+		{"Fallback env var not set", SecretEnvModeFallback, "missing-secret-unset", "", "secret not found"},
+		{"Env var only", SecretEnvModeOnly, "env-secret", "env-only-key", ""},
+		{"Env var only not set", SecretEnvModeOnly, "unset-secret", "", "environment variable 'UNSET_SECRET_API_KEY' for secret 'unset-secret' key 'api-key' is not set"},
+		{"Invalid mode", "bogus", "api-secret", "", "invalid secret environment variable mode 'bogus'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedHeaders = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:            ts.URL,
+				HTTPHeaderName: "X-Api-Key",
+				SecretName:     test.SecretName,
+				SecretValueKey: "api-key",
+				SecretEnvMode:  test.Mode,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			if len(test.ExpectedErrorMessage) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+				assert.Nil(t, receivedHeaders)
+				return
+			}
+
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, test.ExpectedValue, receivedHeaders.Get("X-Api-Key"))
+		})
+	}
+}
+
+func TestHTTPPostWithBasicAuthSecretEnvMode(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "missing-basic", "username").Return(nil, errors.New("secret not found"))
+	mockSP.On("GetSecret", "missing-basic", "password").Return(nil, errors.New("secret not found"))
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	t.Setenv("MISSING_BASIC_USERNAME", "fallback-user")
+	t.Setenv("MISSING_BASIC_PASSWORD", "fallback-pass")
+	// The env-basic secret isn't in the SecretStore mock so the test fails if the SecretStore is used
+	t.Setenv("ENV_BASIC_USERNAME", "env-user")
+	t.Setenv("ENV_BASIC_PASSWORD", "env-pass")
+
+	tests := []struct {
+		Name                 string
+		Mode                 string
+		SecretName           string
+		ExpectedCredentials  string
+		ExpectedErrorMessage string
+	}{
+		{"Fallback to env vars", SecretEnvModeFallback, "missing-basic", "fallback-user:fallback-pass", ""},
+		{"Env vars only", SecretEnvModeOnly, "env-basic", "env-user:env-pass", ""},
+		{"No fallback", SecretEnvModeNone, "missing-basic", "", "secret not found"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedHeaders = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                  ts.URL,
+				BasicAuthSecretName:  test.SecretName,
+				BasicAuthUsernameKey: "username",
+				BasicAuthPasswordKey: "password",
+				SecretEnvMode:        test.Mode,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			if len(test.ExpectedErrorMessage) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+				assert.Nil(t, receivedHeaders)
+				return
+			}
+
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte(test.ExpectedCredentials)), receivedHeaders.Get("Authorization"))
+		})
+	}
+}
+
+func TestSecretEnvVarName(t *testing.T) {
+	assert.Equal(t, "MY_API_API_KEY", SecretEnvVarName("my-api", "api-key"))
+	assert.Equal(t, "CREDENTIALS_TOKEN", SecretEnvVarName("credentials", "token"))
+	assert.Equal(t, "A_B_C_D_9", SecretEnvVarName("a.b/c", "d_9"))
+}
+
+func TestHTTPPostWithBasicAuth(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "basic-secret", "username").Return(map[string]string{"username": "edgex"}, nil)
+	mockSP.On("GetSecret", "basic-secret", "password").Return(map[string]string{"password": "p@ss:word"}, nil)
+	mockSP.On("GetSecret", "api-secret", "api-key").Return(map[string]string{"api-key": "my-API-key"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	basicAuth := HTTPSenderOptions{
+		BasicAuthSecretName:  "basic-secret",
+		BasicAuthUsernameKey: "username",
+		BasicAuthPasswordKey: "password",
+	}
+
+	tests := []struct {
+		Name                 string
+		Options              HTTPSenderOptions
+		ExpectedHeaders      map[string]string
+		ExpectedErrorMessage string
+	}{
+		{
+			Name:    "Basic auth",
+			Options: basicAuth,
+			ExpectedHeaders: map[string]string{
+				"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("edgex:p@ss:word")),
+			},
+		},
+		{
+			Name: "Basic auth with other secret header",
+			Options: HTTPSenderOptions{
+				BasicAuthSecretName:  "basic-secret",
+				BasicAuthUsernameKey: "username",
+				BasicAuthPasswordKey: "password",
+				HTTPHeaderName:       "X-Api-Key",
+				SecretName:           "api-secret",
+				SecretValueKey:       "api-key",
+			},
+			ExpectedHeaders: map[string]string{
+				"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("edgex:p@ss:word")),
+				"X-Api-Key":     "my-API-key",
+			},
+		},
+		{
+			Name: "Basic auth with Authorization secret header",
+			Options: HTTPSenderOptions{
+				BasicAuthSecretName:  "basic-secret",
+				BasicAuthUsernameKey: "username",
+				BasicAuthPasswordKey: "password",
+				HTTPHeaderName:       "authorization",
+				SecretName:           "api-secret",
+				SecretValueKey:       "api-key",
+			},
+			ExpectedErrorMessage: "basic authentication and a secret header named 'Authorization' can not both be used",
+		},
+		{
+			Name: "Basic auth with OAuth2",
+			Options: HTTPSenderOptions{
+				BasicAuthSecretName:   "basic-secret",
+				BasicAuthUsernameKey:  "username",
+				BasicAuthPasswordKey:  "password",
+				OAuth2TokenURL:        "http://localhost/token",
+				OAuth2ClientID:        "client",
+				OAuth2SecretName:      "oauth-secret",
+				OAuth2ClientSecretKey: "client-secret",
+			},
+			ExpectedErrorMessage: "basic authentication and OAuth2 can not both be used",
+		},
+		{
+			Name:                 "Missing password key",
+			Options:              HTTPSenderOptions{BasicAuthSecretName: "basic-secret", BasicAuthUsernameKey: "username"},
+			ExpectedErrorMessage: "BasicAuthUsernameKey & BasicAuthPasswordKey must be specified",
+		},
+		{
+			Name:                 "Missing secret name",
+			Options:              HTTPSenderOptions{BasicAuthUsernameKey: "username", BasicAuthPasswordKey: "password"},
+			ExpectedErrorMessage: "BasicAuthSecretName must be specified",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedHeaders = nil
+			test.Options.URL = ts.URL
+			sender := NewHTTPSenderWithOptions(test.Options)
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			if len(test.ExpectedErrorMessage) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+				assert.Nil(t, receivedHeaders)
+				return
+			}
+
+			require.True(t, continuePipeline, result)
+			for name, expected := range test.ExpectedHeaders {
+				assert.Equal(t, expected, receivedHeaders.Get(name))
+			}
+		})
+	}
+}
+
+func TestHTTPPostSecretHeaderRotation(t *testing.T) {
+	var secretUpdated func(secretName string)
+
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "api-secret", "api-key").Return(map[string]string{"api-key": "old-API-key"}, nil).Once()
+	mockSP.On("GetSecret", "api-secret", "api-key").Return(map[string]string{"api-key": "new-API-key"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", "api-secret", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		secretUpdated = args.Get(1).(func(secretName string))
+	})
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	var receivedKey string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedKey = request.Header.Get("X-Api-Key")
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSenderWithSecretHeader(ts.URL, "", false, "X-Api-Key", "api-secret", "api-key")
+
+	continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	assert.Equal(t, "old-API-key", receivedKey)
+	continuePipeline, _ = sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	assert.Equal(t, "old-API-key", receivedKey)
+	// Secret value is cached, so only retrieved once
+	mockSP.AssertNumberOfCalls(t, "GetSecret", 1)
+	mockSP.AssertNumberOfCalls(t, "RegisterSecretUpdatedCallback", 1)
+
+	require.NotNil(t, secretUpdated)
+	secretUpdated("api-secret")
+
+	continuePipeline, _ = sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	assert.Equal(t, "new-API-key", receivedKey)
+	mockSP.AssertNumberOfCalls(t, "GetSecret", 2)
+	mockSP.AssertNumberOfCalls(t, "RegisterSecretUpdatedCallback", 1)
+}
+
+func TestHTTPPostSecretHeaderCallbackUnavailable(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "api-secret", "api-key").Return(map[string]string{"api-key": "my-API-key"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", "api-secret", mock.Anything).Return(errors.New("callback already registered"))
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSenderWithSecretHeader(ts.URL, "", false, "X-Api-Key", "api-secret", "api-key")
+
+	continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	mockSP.AssertNumberOfCalls(t, "GetSecret", 1)
+
+	// Secrets updated since last retrieved, so value is retrieved again
+	continuePipeline, _ = sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	mockSP.AssertNumberOfCalls(t, "GetSecret", 2)
+	mockSP.AssertNumberOfCalls(t, "RegisterSecretUpdatedCallback", 1)
+}
+
+func TestHTTPPostWithHMACSignature(t *testing.T) {
+	signingKey := "my-signing-key"
+
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "hmac", "key").Return(map[string]string{"key": signingKey}, nil)
+	mockSP.On("GetSecret", "hmac", "empty").Return(map[string]string{"empty": ""}, nil)
+	mockSP.On("GetSecret", "missing", "key").Return(nil, errors.New("FAKE NOT FOUND ERROR"))
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	var signatureValid bool
+	var receivedBody string
+	var receivedSignatureHeader string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		// Reconstruct the signature from the body exactly as received
+		mac := hmac.New(sha256.New, []byte(signingKey))
+		mac.Write([]byte(request.Method + "\n" + request.URL.RequestURI() + "\n" + request.Header.Get("Date") + "\n"))
+		mac.Write(body)
+		expected := hex.EncodeToString(mac.Sum(nil))
+
+		receivedSignatureHeader = DefaultHMACSignatureHeader
+		signature := request.Header.Get(DefaultHMACSignatureHeader)
+		if len(signature) == 0 {
+			receivedSignatureHeader = "X-Custom-Signature"
+			signature = request.Header.Get(receivedSignatureHeader)
+		}
+
+		signatureValid = len(request.Header.Get("Date")) > 0 && hmac.Equal([]byte(expected), []byte(signature))
+
+		if request.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+			require.NoError(t, err)
+			body, err = io.ReadAll(gzipReader)
+			require.NoError(t, err)
+		}
+		receivedBody = string(body)
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name                 string
+		URL                  string
+		Options              HTTPSenderOptions
+		Data                 interface{}
+		ExpectedHeader       string
+		ExpectedErrorMessage string
+	}{
+		{"Signed", ts.URL, HTTPSenderOptions{HMACSecretName: "hmac", HMACSecretKey: "key"}, msgStr, DefaultHMACSignatureHeader, ""},
+		{"Signed with query", ts.URL + "/path?a=b", HTTPSenderOptions{HMACSecretName: "hmac", HMACSecretKey: "key"}, msgStr, DefaultHMACSignatureHeader, ""},
+		{"Signed compressed", ts.URL, HTTPSenderOptions{HMACSecretName: "hmac", HMACSecretKey: "key", CompressBody: true}, msgStr, DefaultHMACSignatureHeader, ""},
+		{"Signed custom header", ts.URL, HTTPSenderOptions{HMACSecretName: "hmac", HMACSecretKey: "key", HMACSignatureHeader: "X-Custom-Signature"}, msgStr, "X-Custom-Signature", ""},
+		{"Missing secret key", ts.URL, HTTPSenderOptions{HMACSecretName: "hmac"}, msgStr, "", "HMACSecretKey must be specified"},
+		{"Secret not found", ts.URL, HTTPSenderOptions{HMACSecretName: "missing", HMACSecretKey: "key"}, msgStr, "", "FAKE NOT FOUND ERROR"},
+		{"Empty signing key", ts.URL, HTTPSenderOptions{HMACSecretName: "hmac", HMACSecretKey: "empty"}, msgStr, "", "HMAC signing key 'empty' in secret 'hmac' is empty"},
+		{"Streamed data", ts.URL, HTTPSenderOptions{HMACSecretName: "hmac", HMACSecretKey: "key"}, strings.NewReader(msgStr), "", "HMAC signing is not supported for streamed data"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			signatureValid = false
+			receivedBody = ""
+			receivedSignatureHeader = ""
+			test.Options.URL = test.URL
+			sender := NewHTTPSenderWithOptions(test.Options)
+
+			continuePipeline, result := sender.HTTPPost(ctx, test.Data)
+			if len(test.ExpectedErrorMessage) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+				assert.Empty(t, receivedBody)
+				return
+			}
+
+			require.True(t, continuePipeline)
+			assert.True(t, signatureValid)
+			assert.Equal(t, test.ExpectedHeader, receivedSignatureHeader)
+			assert.Equal(t, msgStr, receivedBody)
+		})
+	}
+}
+
+func TestHTTPPostWithOAuth2(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "oauth2", "client-secret").Return(map[string]string{"client-secret": "my-client-secret"}, nil)
+	mockSP.On("GetSecret", "missing", "client-secret").Return(nil, errors.New("FAKE NOT FOUND ERROR"))
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	tokenRequests := 0
+	expiresIn := 3600
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		clientId, clientSecret, ok := request.BasicAuth()
+		if !ok || clientId != "my-client" || clientSecret != "my-client-secret" ||
+			request.FormValue("grant_type") != "client_credentials" {
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		tokenRequests++
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", tokenRequests),
+			"token_type":   "Bearer",
+			"expires_in":   expiresIn,
+		})
+	}))
+	defer tokenServer.Close()
+
+	var receivedAuthorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedAuthorization = request.Header.Get("Authorization")
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	newSender := func(tokenURL string, secretName string, persistOnError bool) *HTTPSender {
+		return NewHTTPSenderWithOptions(HTTPSenderOptions{
+			URL:                   ts.URL,
+			PersistOnError:        persistOnError,
+			OAuth2TokenURL:        tokenURL,
+			OAuth2ClientID:        "my-client",
+			OAuth2SecretName:      secretName,
+			OAuth2ClientSecretKey: "client-secret",
+		})
+	}
+
+	t.Run("Token cached", func(t *testing.T) {
+		tokenRequests = 0
+		expiresIn = 3600
+		sender := newSender(tokenServer.URL, "oauth2", false)
+
+		for i := 0; i < 3; i++ {
+			continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+			require.True(t, continuePipeline)
+			assert.Equal(t, "Bearer token-1", receivedAuthorization)
+		}
+
+		assert.Equal(t, 1, tokenRequests)
+	})
+
+	t.Run("Expiring token refreshed", func(t *testing.T) {
+		tokenRequests = 0
+		// Less than the expiry delta, so token is always considered about to expire
+		expiresIn = 5
+		sender := newSender(tokenServer.URL, "oauth2", false)
+
+		continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+		require.True(t, continuePipeline)
+		assert.Equal(t, "Bearer token-1", receivedAuthorization)
+
+		continuePipeline, _ = sender.HTTPPost(ctx, msgStr)
+		require.True(t, continuePipeline)
+		assert.Equal(t, "Bearer token-2", receivedAuthorization)
+	})
+
+	t.Run("Client secret not found", func(t *testing.T) {
+		ctx.SetRetryData(nil)
+		sender := newSender(tokenServer.URL, "missing", true)
+
+		continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+		require.False(t, continuePipeline)
+		assert.Contains(t, result.(error).Error(), "FAKE NOT FOUND ERROR")
+		assert.NotNil(t, ctx.RetryData())
+		ctx.SetRetryData(nil)
+	})
+
+	t.Run("Token endpoint failure", func(t *testing.T) {
+		failingTokenServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failingTokenServer.Close()
+
+		ctx.SetRetryData(nil)
+		sender := newSender(failingTokenServer.URL, "oauth2", true)
+
+		continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+		require.False(t, continuePipeline)
+		assert.Contains(t, result.(error).Error(), "token endpoint returned 500")
+		assert.NotNil(t, ctx.RetryData())
+		assert.Equal(t, int64(1), sender.httpErrorMetric.Count())
+		ctx.SetRetryData(nil)
+	})
+
+	t.Run("Missing client id", func(t *testing.T) {
+		sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+			URL:            ts.URL,
+			OAuth2TokenURL: tokenServer.URL,
+		})
+
+		continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+		require.False(t, continuePipeline)
+		assert.Contains(t, result.(error).Error(), "OAuth2ClientID, OAuth2SecretName & OAuth2ClientSecretKey must be specified")
+	})
+}
+
+func TestHTTPPostWithSuccessStatusCodes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.EscapedPath() {
+		case "/moved":
+			writer.WriteHeader(http.StatusMovedPermanently)
+		case "/accepted":
+			writer.WriteHeader(http.StatusAccepted)
+		default:
+			writer.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name                      string
+		Path                      string
+		SuccessStatusCodes        []int
+		ExpectedContinueExecuting bool
+	}{
+		{"Default 200 is success", "/ok", nil, true},
+		{"Default 301 is failure", "/moved", nil, false},
+		{"Configured 301 is success", "/moved", []int{http.StatusMovedPermanently}, true},
+		{"Configured 202 only, 200 is failure", "/ok", []int{http.StatusAccepted}, false},
+		{"Configured 202 only, 202 is success", "/accepted", []int{http.StatusAccepted}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.SetRetryData(nil)
+			// Don't follow redirects so the 301 is the final response
+			followRedirects := false
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                ts.URL + test.Path,
+				PersistOnError:     true,
+				SuccessStatusCodes: test.SuccessStatusCodes,
+				FollowRedirects:    &followRedirects,
+			})
+
+			continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+			assert.Equal(t, test.ExpectedContinueExecuting, continuePipeline)
+			assert.Equal(t, !test.ExpectedContinueExecuting, ctx.RetryData() != nil)
+
+			expectedErrorCount := int64(0)
+			if !test.ExpectedContinueExecuting {
+				expectedErrorCount = 1
+			}
+			assert.Equal(t, expectedErrorCount, sender.httpErrorMetric.Count())
+			ctx.SetRetryData(nil)
+		})
+	}
+}
+
+func TestHTTPPostWithTolerateStatusCodes(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		switch request.URL.EscapedPath() {
+		case "/conflict":
+			writer.WriteHeader(http.StatusConflict)
+		case "/error":
+			writer.WriteHeader(http.StatusInternalServerError)
+		default:
+			writer.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name                      string
+		Path                      string
+		TolerateStatusCodes       []int
+		ContinueOnSendError       bool
+		ExpectedContinueExecuting bool
+		ExpectedPersisted         bool
+		ExpectedErrorCount        int64
+	}{
+		{"Tolerated 409 continues", "/conflict", []int{http.StatusConflict}, false, true, false, 0},
+		{"Not tolerated 409 fails", "/conflict", nil, false, false, true, 1},
+		{"500 still fails", "/error", []int{http.StatusConflict}, false, false, true, 1},
+		{"500 still continues on send error", "/error", []int{http.StatusConflict}, true, true, false, 1},
+		{"Tolerated 200 is success", "/ok", []int{http.StatusOK}, false, true, false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.SetRetryData(nil)
+			requests.Store(0)
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                 ts.URL + test.Path,
+				PersistOnError:      !test.ContinueOnSendError,
+				ContinueOnSendError: test.ContinueOnSendError,
+				ReturnInputData:     true,
+				TolerateStatusCodes: test.TolerateStatusCodes,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			assert.Equal(t, test.ExpectedContinueExecuting, continuePipeline)
+			if continuePipeline {
+				assert.Equal(t, msgStr, result)
+			}
+			assert.Equal(t, test.ExpectedPersisted, ctx.RetryData() != nil)
+			assert.Equal(t, test.ExpectedErrorCount, sender.httpErrorMetric.Count())
+			assert.Equal(t, int32(1), requests.Load())
+			ctx.SetRetryData(nil)
+		})
+	}
+
+	t.Run("Tolerated response not retried or failed over", func(t *testing.T) {
+		requests.Store(0)
+		sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+			URL:                 ts.URL + "/error",
+			FailoverURLs:        []string{ts.URL + "/ok"},
+			MaxRetries:          2,
+			RetryInterval:       time.Millisecond,
+			TolerateStatusCodes: []int{http.StatusInternalServerError},
+		})
+
+		continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+		require.True(t, continuePipeline)
+		assert.Equal(t, msgStr, result)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestHTTPPostJSONSerialization(t *testing.T) {
+	var received []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		received, _ = io.ReadAll(request.Body)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	data := map[string]interface{}{"device": "sensor1", "reading": map[string]interface{}{"value": 21}}
+
+	tests := []struct {
+		Name              string
+		Serialization     string
+		Indent            string
+		MimeType          string
+		Data              interface{}
+		ExpectedBody      string
+		ExpectedErrorText string
+	}{
+		{"Default is compact", "", "", common.ContentTypeJSON, data, `{"device":"sensor1","reading":{"value":21}}`, ""},
+		{"Compact", JSONSerializationCompact, "", common.ContentTypeJSON, data, `{"device":"sensor1","reading":{"value":21}}`, ""},
+		{"Indented with default indent", JSONSerializationIndented, "", common.ContentTypeJSON, data,
+			"{\n  \"device\": \"sensor1\",\n  \"reading\": {\n    \"value\": 21\n  }\n}", ""},
+		{"Indented with tab indent", JSONSerializationIndented, "\t", "application/cloudevents+json; charset=utf-8", data,
+			"{\n\t\"device\": \"sensor1\",\n\t\"reading\": {\n\t\t\"value\": 21\n\t}\n}", ""},
+		{"Indented not applied to non-JSON mime type", JSONSerializationIndented, "", common.ContentTypeText, data,
+			`{"device":"sensor1","reading":{"value":21}}`, ""},
+		{"Indented not applied to string data", JSONSerializationIndented, "", common.ContentTypeJSON, `{"a":1}`, `{"a":1}`, ""},
+		{"Indented not applied to byte data", JSONSerializationIndented, "", common.ContentTypeJSON, []byte(`{"a":1}`), `{"a":1}`, ""},
+		{"Invalid serialization", "pretty", "", common.ContentTypeJSON, data, "", "invalid JSON serialization 'pretty'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			received = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:               ts.URL,
+				MimeType:          test.MimeType,
+				JSONSerialization: test.Serialization,
+				JSONIndent:        test.Indent,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, test.Data)
+			if len(test.ExpectedErrorText) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedErrorText)
+				assert.Nil(t, received)
+				return
+			}
+
+			require.True(t, continuePipeline)
+			assert.Equal(t, test.ExpectedBody, string(received))
+		})
+	}
+}
+
+func TestHTTPSenderPing(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "my-secret", "my-secret-key").Return(map[string]string{"my-secret-key": "my-API-key"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	mockSP.On("RegisterSecretUpdatedCallback", mock.Anything, mock.Anything).Return(nil)
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	var received *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		received = request
+		if request.URL.EscapedPath() == "/unhealthy" {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name                 string
+		URL                  string
+		PingMethod           string
+		PingPath             string
+		ExpectedMethod       string
+		ExpectedPath         string
+		ExpectedErrorMessage string
+	}{
+		{"Reachable base URL", ts.URL + "/api/{devicename}", "", "", http.MethodHead, "/api/%7Bdevicename%7D", ""},
+		{"Reachable health path", ts.URL + "/api/events", http.MethodGet, "/health", http.MethodGet, "/health", ""},
+		{"Relative ping path", ts.URL + "/api/events", "", "status", http.MethodHead, "/api/status", ""},
+		{"Unhealthy destination", ts.URL + "/api/events", "", "/unhealthy", http.MethodHead, "/unhealthy", "failed with 503 HTTP status code"},
+		{"Unreachable destination", "http://127.0.0.1:1/api", "", "", "", "", "connection refused"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			received = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:            test.URL,
+				PersistOnError: true,
+				HTTPHeaderName: "Secret-Header",
+				SecretName:     "my-secret",
+				SecretValueKey: "my-secret-key",
+				PingMethod:     test.PingMethod,
+				PingPath:       test.PingPath,
+			})
+
+			err := sender.Ping(ctx)
+
+			// Pings don't affect the export metrics or Store and Forward
+			assert.Zero(t, sender.httpErrorMetric.Count())
+			assert.Zero(t, sender.httpSuccessMetric.Count())
+			assert.Nil(t, ctx.RetryData())
+
+			if len(test.ExpectedErrorMessage) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.ExpectedErrorMessage)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if len(test.ExpectedMethod) == 0 {
+				assert.Nil(t, received)
+				return
+			}
+
+			require.NotNil(t, received)
+			assert.Equal(t, test.ExpectedMethod, received.Method)
+			assert.Equal(t, test.ExpectedPath, received.URL.EscapedPath())
+			assert.Equal(t, "my-API-key", received.Header.Get("Secret-Header"))
+			assert.Empty(t, received.Header.Get("Content-Type"))
+		})
+	}
+}
+
+func TestHTTPSenderAcceptHeader(t *testing.T) {
+	var accept []string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		accept = request.Header.Values("Accept")
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name             string
+		Method           string
+		SetAcceptHeader  bool
+		MimeType         string
+		ResponseMimeType string
+		RequestHeaders   map[string]string
+		ExpectedAccept   []string
+	}{
+		{"Not set by default", http.MethodPost, false, common.ContentTypeJSON, "", nil, nil},
+		{"Defaults to MimeType", http.MethodPost, true, common.ContentTypeCBOR, "", nil, []string{common.ContentTypeCBOR}},
+		{"Defaults to JSON for GET", http.MethodGet, true, "", "", nil, []string{common.ContentTypeJSON}},
+		{"Response mime type", http.MethodPost, true, common.ContentTypeText, common.ContentTypeJSON, nil, []string{common.ContentTypeJSON}},
+		{"Request header wins", http.MethodGet, true, "", common.ContentTypeJSON, map[string]string{"Accept": "application/xml"}, []string{"application/xml"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			accept = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:              ts.URL,
+				MimeType:         test.MimeType,
+				SetAcceptHeader:  test.SetAcceptHeader,
+				ResponseMimeType: test.ResponseMimeType,
+			})
+			sender.SetHttpRequestHeaders(test.RequestHeaders)
+
+			var continuePipeline bool
+			if test.Method == http.MethodGet {
+				continuePipeline, _ = sender.HTTPGet(ctx, msgStr)
+			} else {
+				continuePipeline, _ = sender.HTTPPost(ctx, msgStr)
+			}
+
+			require.True(t, continuePipeline)
+			assert.Equal(t, test.ExpectedAccept, accept)
+		})
+	}
+}
+
+// repeatingReader provides size bytes of the same value without allocating the full payload
+type repeatingReader struct {
+	size int64
+	read int64
+}
+
+func (reader *repeatingReader) Read(p []byte) (int, error) {
+	if reader.read >= reader.size {
+		return 0, io.EOF
+	}
+
+	n := int64(len(p))
+	if remaining := reader.size - reader.read; remaining < n {
+		n = remaining
+	}
+
+	for i := int64(0); i < n; i++ {
+		p[i] = 'a'
+	}
+
+	reader.read += n
+	return int(n), nil
+}
+
+func TestHTTPPostStreamLargePayload(t *testing.T) {
+	const payloadSize = 50 * 1024 * 1024
+
+	var receivedBytes int64
+	var receivedContentLength int64
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedContentLength = request.ContentLength
+		receivedBytes, _ = io.Copy(io.Discard, request.Body)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:             ts.URL,
+		ReturnInputData: true,
+	})
+
+	var before runtime.MemStats
+	var after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	continuePipeline, _ := sender.HTTPPost(ctx, &repeatingReader{size: payloadSize})
+
+	runtime.ReadMemStats(&after)
+
+	require.True(t, continuePipeline)
+	assert.Equal(t, int64(payloadSize), receivedBytes)
+	// Length of stream not known, so sent chunked
+	assert.Equal(t, int64(-1), receivedContentLength)
+	assert.Equal(t, int64(payloadSize), sender.httpSizeMetrics.Max())
+	// Total allocated by both client and server should be a small fraction of the payload
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(payloadSize/10))
+}
+
+func TestHTTPPostStreamKnownLength(t *testing.T) {
+	var receivedBody string
+	var receivedContentLength int64
+	var receivedEncoding string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedContentLength = request.ContentLength
+		receivedEncoding = request.Header.Get("Content-Encoding")
+
+		var reader io.Reader = request.Body
+		if receivedEncoding == "gzip" {
+			gzipReader, err := gzip.NewReader(request.Body)
+			require.NoError(t, err)
+			reader = gzipReader
+		}
+
+		readMsg, _ := io.ReadAll(reader)
+		receivedBody = string(readMsg)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	t.Run("Not compressed", func(t *testing.T) {
+		sender := NewHTTPSender(ts.URL, "", false)
+		continuePipeline, _ := sender.HTTPPost(ctx, strings.NewReader(msgStr))
+		require.True(t, continuePipeline)
+		assert.Equal(t, msgStr, receivedBody)
+		assert.Equal(t, int64(len(msgStr)), receivedContentLength)
+		assert.Equal(t, int64(len(msgStr)), sender.httpSizeMetrics.Max())
+	})
+
+	t.Run("Compressed", func(t *testing.T) {
+		sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+			URL:          ts.URL,
+			CompressBody: true,
+		})
+		continuePipeline, _ := sender.HTTPPost(ctx, strings.NewReader(msgStr))
+		require.True(t, continuePipeline)
+		assert.Equal(t, "gzip", receivedEncoding)
+		assert.Equal(t, msgStr, receivedBody)
+		assert.Equal(t, int64(-1), receivedContentLength)
+	})
+}
+
+func TestHTTPPostStreamNotRetried(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		attempts++
+		_, _ = io.Copy(io.Discard, request.Body)
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	ctx.SetRetryData(nil)
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:            ts.URL,
+		PersistOnError: true,
+		MaxRetries:     3,
+		RetryInterval:  time.Millisecond,
+	})
+
+	continuePipeline, _ := sender.HTTPPost(ctx, &repeatingReader{size: 1024})
+	require.False(t, continuePipeline)
+	assert.Equal(t, 1, attempts)
+	assert.Nil(t, ctx.RetryData())
+}
+
+func TestHTTPPostWithContextCancelled(t *testing.T) {
+	requestReceived := make(chan struct{})
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		close(requestReceived)
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	requestContext, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctx.SetRetryData(nil)
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:            ts.URL,
+		PersistOnError: true,
+		Context:        requestContext,
+	})
+
+	go func() {
+		<-requestReceived
+		cancel()
+	}()
+
+	start := time.Now()
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	elapsed := time.Since(start)
+
+	require.False(t, continuePipeline)
+	assert.Less(t, elapsed, time.Second)
+	assert.ErrorIs(t, result.(error), context.Canceled)
+	assert.Contains(t, result.(error).Error(), "cancelled")
+	assert.Equal(t, []byte(msgStr), ctx.RetryData())
+	ctx.SetRetryData(nil)
+}
+
+func TestHTTPPostWithContextCancelledDuringRetryWait(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	requestContext, cancel := context.WithCancel(context.Background())
+	sender := NewHTTPSender(ts.URL, "", false)
+	sender.maxRetries = 3
+	sender.retryInterval = 10 * time.Second
+	sender.SetContext(requestContext)
+
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+
+	require.False(t, continuePipeline)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.ErrorIs(t, result.(error), context.Canceled)
+}
+
+func TestHTTPPostWithQueryParams(t *testing.T) {
+	var receivedQuery url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedQuery = request.URL.Query()
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx.AddValue("device", "my device&more")
+	defer ctx.RemoveValue("device")
+
+	tests := []struct {
+		Name          string
+		URL           string
+		QueryParams   map[string]string
+		ExpectedQuery url.Values
+		ExpectError   bool
+	}{
+		{"No query params", ts.URL + path, nil, url.Values{}, false},
+		{"Static value", ts.URL + path, map[string]string{"static": "value"}, url.Values{"static": {"value"}}, false},
+		{"Placeholder value encoded", ts.URL + path, map[string]string{"deviceName": "{device}"}, url.Values{"deviceName": {"my device&more"}}, false},
+		{"Existing params preserved", ts.URL + path + "?existing=1", map[string]string{"deviceName": "{device}"}, url.Values{"existing": {"1"}, "deviceName": {"my device&more"}}, false},
+		{"Existing param overridden", ts.URL + path + "?deviceName=old", map[string]string{"deviceName": "{device}"}, url.Values{"deviceName": {"my device&more"}}, false},
+		{"Missing placeholder", ts.URL + path, map[string]string{"deviceName": "{bogus}"}, nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedQuery = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:         test.URL,
+				QueryParams: test.QueryParams,
+			})
+
+			continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+			if test.ExpectError {
+				require.False(t, continuePipeline)
+				assert.Nil(t, receivedQuery)
+				return
+			}
+
+			require.True(t, continuePipeline)
+			assert.Equal(t, test.ExpectedQuery, receivedQuery)
+		})
+	}
+}
+
+func TestHTTPPostFormURLEncoded(t *testing.T) {
+	var receivedContentType string
+	var receivedForm url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedContentType = request.Header.Get("Content-Type")
+		require.NoError(t, request.ParseForm())
+		receivedForm = request.PostForm
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name                 string
+		MimeType             string
+		Data                 interface{}
+		ExpectedForm         url.Values
+		ExpectedErrorMessage string
+	}{
+		{"map[string]interface{}", ContentTypeFormURLEncoded,
+			map[string]interface{}{"name": "my device", "value": 12.5, "enabled": true, "tags": []interface{}{"a", "b"}},
+			url.Values{"name": {"my device"}, "value": {"12.5"}, "enabled": {"true"}, "tags": {"a", "b"}}, ""},
+		{"map[string]string", ContentTypeFormURLEncoded,
+			map[string]string{"name": "a&b=c"},
+			url.Values{"name": {"a&b=c"}}, ""},
+		{"url.Values", ContentTypeFormURLEncoded + "; charset=utf-8",
+			url.Values{"name": {"one", "two"}},
+			url.Values{"name": {"one", "two"}}, ""},
+		{"already encoded string", ContentTypeFormURLEncoded,
+			"name=value",
+			url.Values{"name": {"value"}}, ""},
+		{"nested object", ContentTypeFormURLEncoded,
+			map[string]interface{}{"nested": map[string]interface{}{"a": 1}},
+			nil, "nested objects are not supported"},
+		{"incompatible type", ContentTypeFormURLEncoded,
+			[]int{1, 2, 3},
+			nil, "unable to form encode data of type []int"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedForm = nil
+			sender := NewHTTPSender(ts.URL, test.MimeType, false)
+
+			continuePipeline, result := sender.HTTPPost(ctx, test.Data)
+			if len(test.ExpectedErrorMessage) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+				assert.Nil(t, receivedForm)
+				return
+			}
+
+			require.True(t, continuePipeline)
+			assert.Equal(t, test.MimeType, receivedContentType)
+			assert.Equal(t, test.ExpectedForm, receivedForm)
+		})
+	}
+}
+
+func TestHTTPPostLatencyMetric(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/fail" {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name             string
+		Path             string
+		ContinueExpected bool
+	}{
+		{"Success", "/ok", true},
+		{"Failure", "/fail", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender := NewHTTPSender(ts.URL+test.Path, "", false)
+
+			for i := 1; i <= 3; i++ {
+				continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+				require.Equal(t, test.ContinueExpected, continuePipeline)
+				assert.Equal(t, int64(i), sender.httpLatencyMetric.Count())
+			}
+
+			assert.Greater(t, sender.httpLatencyMetric.Max(), int64(0))
+		})
+	}
+}
+
+func TestHTTPPostSuccessMetric(t *testing.T) {
+	status := http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name            string
+		ReturnInputData bool
+	}{
+		{"Response data returned", false},
+		{"Input data returned", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                 ts.URL,
+				ReturnInputData:     test.ReturnInputData,
+				ContinueOnSendError: test.ReturnInputData,
+			})
+
+			status = http.StatusOK
+			for i := 1; i <= 2; i++ {
+				continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+				require.True(t, continuePipeline)
+				assert.Equal(t, int64(i), sender.httpSuccessMetric.Count())
+				assert.Equal(t, int64(0), sender.httpErrorMetric.Count())
+			}
+
+			status = http.StatusInternalServerError
+			_, _ = sender.HTTPPost(ctx, msgStr)
+			assert.Equal(t, int64(2), sender.httpSuccessMetric.Count())
+			assert.Equal(t, int64(1), sender.httpErrorMetric.Count())
+		})
+	}
+}
+
+func TestHTTPPostWithFailoverURLs(t *testing.T) {
+	requests := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests[request.URL.Path]++
+		readMsg, _ := io.ReadAll(request.Body)
+		assert.Equal(t, msgStr, string(readMsg))
+
+		if strings.HasPrefix(request.URL.Path, "/fail") {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	closed := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	closedUrl := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		Name                 string
+		URL                  string
+		FailoverURLs         []string
+		ExpectedContinue     bool
+		ExpectedRequests     map[string]int
+		ExpectedSuccessCount int64
+		ExpectedErrorCount   int64
+	}{
+		{"First fails, second succeeds", ts.URL + "/fail", []string{ts.URL + "/ok"}, true, map[string]int{"/fail": 1, "/ok": 1}, 1, 0},
+		{"First succeeds", ts.URL + "/ok", []string{ts.URL + "/fail"}, true, map[string]int{"/ok": 1}, 1, 0},
+		{"Network error fails over", closedUrl, []string{ts.URL + "/fail", ts.URL + "/ok"}, true, map[string]int{"/fail": 1, "/ok": 1}, 1, 0},
+		{"All fail", ts.URL + "/fail", []string{ts.URL + "/fail2", ts.URL + "/fail3"}, false, map[string]int{"/fail": 1, "/fail2": 1, "/fail3": 1}, 0, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			for path := range requests {
+				delete(requests, path)
+			}
+			ctx.SetRetryData(nil)
+
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:            test.URL,
+				FailoverURLs:   test.FailoverURLs,
+				PersistOnError: true,
+			})
+
+			continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+			require.Equal(t, test.ExpectedContinue, continuePipeline)
+			assert.Equal(t, test.ExpectedRequests, requests)
+			assert.Equal(t, test.ExpectedSuccessCount, sender.httpSuccessMetric.Count())
+			assert.Equal(t, test.ExpectedErrorCount, sender.httpErrorMetric.Count())
+			// Store and Forward is only used when all URLs fail
+			assert.Equal(t, !test.ExpectedContinue, ctx.RetryData() != nil)
+		})
+	}
+
+	ctx.SetRetryData(nil)
+}
+
+func TestHTTPPostWithFailoverURLsErrorMetricURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	var registered []string
+	mockMetricsMgr := &mocks2.MetricsManager{}
+	mockMetricsMgr.On("IsRegistered", mock.Anything).Return(false)
+	mockMetricsMgr.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		registered = append(registered, args.String(0))
+	})
+
+	metricsCtx := appfunction.NewContext("123", di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.MetricsManagerInterfaceName: func(get di.Get) interface{} {
+			return mockMetricsMgr
+		},
+	}), "")
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:          ts.URL + "/primary",
+		FailoverURLs: []string{ts.URL + "/secondary"},
+	})
+
+	continuePipeline, _ := sender.HTTPPost(metricsCtx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Contains(t, registered, fmt.Sprintf("%s-%s/secondary", internal.HttpExportErrorsName, ts.URL))
+	assert.NotContains(t, registered, fmt.Sprintf("%s-%s/primary", internal.HttpExportErrorsName, ts.URL))
+	assert.Contains(t, registered, fmt.Sprintf("%s-%s/primary", internal.HttpExportLatencyName, ts.URL))
+	assert.Contains(t, registered, fmt.Sprintf("%s-%s/secondary", internal.HttpExportLatencyName, ts.URL))
+}
+
+func TestHTTPPostMetricsPipelineTag(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	registered := make(map[string]map[string]string)
+	mockMetricsMgr := &mocks2.MetricsManager{}
+	mockMetricsMgr.On("IsRegistered", mock.Anything).Return(func(name string) bool {
+		_, found := registered[name]
+		return found
+	})
+	mockMetricsMgr.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		registered[args.String(0)] = args.Get(2).(map[string]string)
+	})
+
+	metricsDic := di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.MetricsManagerInterfaceName: func(get di.Get) interface{} {
+			return mockMetricsMgr
+		},
+	})
+
+	for _, pipelineId := range []string{"pipeline-1", "pipeline-2"} {
+		pipelineCtx := appfunction.NewContext("123", metricsDic, "")
+		pipelineCtx.AddValue(interfaces.PIPELINEID, pipelineId)
+
+		// Each pipeline has its own sender to the same URL
+		sender := NewHTTPSender(ts.URL, "", false)
+		continuePipeline, result := sender.HTTPPost(pipelineCtx, msgStr)
+		require.True(t, continuePipeline, result)
+	}
+
+	for _, metricName := range []string{internal.HttpExportErrorsName, internal.HttpExportSuccessesName, internal.HttpExportSizeName, internal.HttpExportLatencyName} {
+		for _, pipelineId := range []string{"pipeline-1", "pipeline-2"} {
+			tags, found := registered[fmt.Sprintf("%s-%s-%s", metricName, pipelineId, ts.URL)]
+			require.True(t, found, "%s metric not registered for %s", metricName, pipelineId)
+			assert.Equal(t, map[string]string{"url": ts.URL, "pipeline": pipelineId}, tags)
+		}
+	}
+}
+
+func TestHTTPGet(t *testing.T) {
+	var receivedBody []byte
+	var receivedPath string
+	var receivedEncoding string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedPath = request.URL.Path
+		receivedEncoding = request.Header.Get("Content-Encoding")
+		receivedBody, _ = io.ReadAll(request.Body)
+		if receivedPath == badPath {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = writer.Write([]byte("config blob"))
+	}))
+	defer ts.Close()
+
+	ctx.AddValue("test", "foo")
+	defer ctx.RemoveValue("test")
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{URL: ts.URL + formatPath, CompressBody: true})
+	continuePipeline, result := sender.HTTPGet(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, []byte("config blob"), result)
+	assert.Equal(t, "/some-path/foo", receivedPath)
+	// GET is sent without a body, even when compression is enabled
+	assert.Empty(t, receivedBody)
+	assert.Empty(t, receivedEncoding)
+
+	// Failed GET isn't persisted since there is no data to send
+	ctx.SetRetryData(nil)
+	sender = NewHTTPSenderWithOptions(HTTPSenderOptions{URL: ts.URL + badPath, PersistOnError: true})
+	continuePipeline, _ = sender.HTTPGet(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Nil(t, ctx.RetryData())
+}
+
+func TestHTTPGetResponseContextKey(t *testing.T) {
+	const contextKey = "device-metadata"
+
+	var receivedPath string
+	var receivedHeader string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedPath = request.URL.Path
+		receivedHeader = request.Header.Get("X-Api-Key")
+		_, _ = writer.Write([]byte(`{"location":"building-1"}`))
+	}))
+	defer ts.Close()
+
+	ctx.AddValue("test", "foo")
+	defer ctx.RemoveValue("test")
+	defer ctx.RemoveValue(contextKey)
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:                ts.URL + formatPath,
+		ResponseContextKey: contextKey,
+		ReturnInputData:    true,
+	})
+	sender.SetHttpRequestHeaders(map[string]string{"X-Api-Key": "my-key"})
+
+	continuePipeline, result := sender.HTTPGet(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	// The incoming data is passed on and the response is stored in the context for enrichment
+	assert.Equal(t, msgStr, result)
+	assert.Equal(t, "/some-path/foo", receivedPath)
+	assert.Equal(t, "my-key", receivedHeader)
+
+	value, found := ctx.GetValue(contextKey)
+	require.True(t, found)
+	assert.Equal(t, `{"location":"building-1"}`, value)
+}
+
+func TestHTTPGetStreamResponse(t *testing.T) {
+	const chunkSize = 64 * 1024
+	const chunkCount = 32
+
+	firstChunkRead := make(chan struct{})
+	var serverFinished atomic.Bool
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		chunk := bytes.Repeat([]byte("x"), chunkSize)
+		for i := 0; i < chunkCount; i++ {
+			_, _ = writer.Write(chunk)
+			writer.(http.Flusher).Flush()
+
+			// Only send the rest once the client has read the first chunk, which proves it is consumed incrementally
+			if i == 0 {
+				select {
+				case <-firstChunkRead:
+				case <-time.After(5 * time.Second):
+				}
+			}
+		}
+		serverFinished.Store(true)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{URL: ts.URL, StreamResponse: true})
+
+	continuePipeline, result := sender.HTTPGet(ctx, nil)
+	require.True(t, continuePipeline, result)
+	require.False(t, serverFinished.Load())
+
+	body, ok := result.(io.ReadCloser)
+	require.True(t, ok)
+	defer func() { _ = body.Close() }()
+
+	_, err := io.ReadFull(body, make([]byte, chunkSize))
+	require.NoError(t, err)
+	assert.False(t, serverFinished.Load())
+	close(firstChunkRead)
+
+	remaining, err := io.Copy(io.Discard, body)
+	require.NoError(t, err)
+	assert.Equal(t, int64(chunkSize*(chunkCount-1)), remaining)
+	assert.True(t, serverFinished.Load())
+}
+
+func TestHTTPPostFollowRedirects(t *testing.T) {
+	var redirectedRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == "/moved" {
+			http.Redirect(writer, request, "/new", http.StatusTemporaryRedirect)
+			return
+		}
+		redirectedRequests++
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	followRedirects := true
+	doNotFollowRedirects := false
+
+	tests := []struct {
+		Name                       string
+		FollowRedirects            *bool
+		SuccessStatusCodes         []int
+		ExpectedContinueExecuting  bool
+		ExpectedRedirectedRequests int
+	}{
+		{"Default follows redirect", nil, nil, true, 1},
+		{"Follows redirect", &followRedirects, nil, true, 1},
+		{"Does not follow redirect", &doNotFollowRedirects, nil, false, 0},
+		{"Does not follow redirect, 307 is success", &doNotFollowRedirects, []int{http.StatusTemporaryRedirect}, true, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			redirectedRequests = 0
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                ts.URL + "/moved",
+				FollowRedirects:    test.FollowRedirects,
+				SuccessStatusCodes: test.SuccessStatusCodes,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			assert.Equal(t, test.ExpectedContinueExecuting, continuePipeline)
+			assert.Equal(t, test.ExpectedRedirectedRequests, redirectedRequests)
+			if !test.ExpectedContinueExecuting {
+				assert.Contains(t, result.(error).Error(), "307")
+			}
+		})
+	}
+}
+
+func TestHTTPPostCorrelationIDHeader(t *testing.T) {
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sendCorrelationID := true
+	doNotSendCorrelationID := false
+
+	tests := []struct {
+		Name              string
+		SendCorrelationID *bool
+		HeaderName        string
+		RequestHeaders    map[string]string
+		ExpectedHeader    string
+		ExpectedValue     string
+	}{
+		{"Default sends correlation id", nil, "", nil, common.CorrelationHeader, ctx.CorrelationID()},
+		{"Sends correlation id", &sendCorrelationID, "", nil, common.CorrelationHeader, ctx.CorrelationID()},
+		{"Custom header name", nil, "X-Trace-Id", nil, "X-Trace-Id", ctx.CorrelationID()},
+		{"Does not send correlation id", &doNotSendCorrelationID, "", nil, common.CorrelationHeader, ""},
+		{"Does not overwrite request header", nil, "", map[string]string{common.CorrelationHeader: "user-value"}, common.CorrelationHeader, "user-value"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedHeaders = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                 ts.URL,
+				SendCorrelationID:   test.SendCorrelationID,
+				CorrelationIDHeader: test.HeaderName,
+			})
+			sender.SetHttpRequestHeaders(test.RequestHeaders)
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			require.True(t, continuePipeline, result)
+			require.NotNil(t, receivedHeaders)
+			assert.Equal(t, test.ExpectedValue, receivedHeaders.Get(test.ExpectedHeader))
+		})
+	}
+}
+
+func TestHTTPPostTracePropagation(t *testing.T) {
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	const traceState = "vendor=value"
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		SpanID:     trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31},
+		TraceFlags: trace.FlagsSampled,
+	})
+	spanRequestContext := trace.ContextWithSpanContext(context.Background(), spanContext)
+
+	tests := []struct {
+		Name                string
+		TracePropagation    bool
+		StoredTraceContext  bool
+		RequestContext      context.Context
+		ExpectedTraceParent string
+		ExpectedTraceState  string
+	}{
+		{"Disabled", false, true, nil, "", ""},
+		{"No trace context", true, false, nil, "", ""},
+		{"Trace context from context storage", true, true, nil, traceParent, traceState},
+		{"Trace context from request context", true, false, spanRequestContext, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", ""},
+		{"Context storage takes precedence", true, true, spanRequestContext, traceParent, traceState},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.StoredTraceContext {
+				ctx.AddValue("traceparent", traceParent)
+				ctx.AddValue("tracestate", traceState)
+				defer ctx.RemoveValue("traceparent")
+				defer ctx.RemoveValue("tracestate")
+			}
+
+			receivedHeaders = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:              ts.URL,
+				TracePropagation: test.TracePropagation,
+				Context:          test.RequestContext,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			require.True(t, continuePipeline, result)
+			require.NotNil(t, receivedHeaders)
+			assert.Equal(t, test.ExpectedTraceParent, receivedHeaders.Get("traceparent"))
+			assert.Equal(t, test.ExpectedTraceState, receivedHeaders.Get("tracestate"))
+		})
+	}
+}
+
+func TestHTTPPostTraceCustomPropagator(t *testing.T) {
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	propagator := &testPropagator{}
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:              ts.URL,
+		TracePropagation: true,
+		TracePropagator:  propagator,
+	})
+
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, 1, propagator.extracted)
+	assert.Equal(t, 1, propagator.injected)
+	assert.Equal(t, "injected", receivedHeaders.Get(testPropagatorHeader))
+	assert.Empty(t, receivedHeaders.Get("traceparent"))
+}
+
+func TestHTTPPostTraceClientSpan(t *testing.T) {
+	var receivedTraceParent string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedTraceParent = request.Header.Get("traceparent")
+		if request.URL.Path == badPath {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx.AddValue("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	defer ctx.RemoveValue("traceparent")
+
+	tests := []struct {
+		Name                string
+		Path                string
+		ExpectedContinue    bool
+		ExpectedStatusCode  int
+		ExpectedErrorStatus bool
+	}{
+		{"Successful send", path, true, http.StatusOK, false},
+		{"Failed send", badPath, false, http.StatusNotFound, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tracer := &testTracer{}
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:              ts.URL + test.Path,
+				TracePropagation: true,
+				TraceClientSpan:  true,
+				TracerProvider:   testTracerProvider{tracer: tracer},
+			})
+
+			continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+			assert.Equal(t, test.ExpectedContinue, continuePipeline)
+
+			require.Len(t, tracer.spans, 1)
+			span := tracer.spans[0]
+			assert.Equal(t, "HTTP POST", span.name)
+			assert.Equal(t, trace.SpanKindClient, span.kind)
+			assert.True(t, span.ended)
+			assert.Equal(t, test.ExpectedErrorStatus, span.errorStatus)
+			assert.Contains(t, span.attributes, attribute.Int("http.response.status_code", test.ExpectedStatusCode))
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.parent.TraceID().String())
+			assert.Equal(t, "00f067aa0ba902b7", span.parent.SpanID().String())
+
+			// The destination receives the client span as the parent
+			assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+span.SpanContext().SpanID().String()+"-01", receivedTraceParent)
+		})
+	}
+}
+
+const testPropagatorHeader = "X-Test-Trace"
+
+type testPropagator struct {
+	extracted int
+	injected  int
+}
+
+func (p *testPropagator) Inject(_ context.Context, carrier propagation.TextMapCarrier) {
+	p.injected++
+	carrier.Set(testPropagatorHeader, "injected")
+}
+
+func (p *testPropagator) Extract(ctx context.Context, _ propagation.TextMapCarrier) context.Context {
+	p.extracted++
+	return ctx
+}
+
+func (p *testPropagator) Fields() []string {
+	return []string{testPropagatorHeader}
+}
+
+type testTracerProvider struct {
+	noop.TracerProvider
+	tracer *testTracer
+}
+
+func (p testTracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return p.tracer
+}
+
+type testTracer struct {
+	noop.Tracer
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	config := trace.NewSpanStartConfig(opts...)
+	parent := trace.SpanContextFromContext(ctx)
+	span := &testSpan{
+		name:       name,
+		kind:       config.SpanKind(),
+		parent:     parent,
+		attributes: config.Attributes(),
+		sc:         parent.WithSpanID(trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, byte(len(t.spans) + 1)}),
+	}
+	t.spans = append(t.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type testSpan struct {
+	noop.Span
+	name        string
+	kind        trace.SpanKind
+	parent      trace.SpanContext
+	sc          trace.SpanContext
+	attributes  []attribute.KeyValue
+	errorStatus bool
+	ended       bool
+}
+
+func (s *testSpan) SpanContext() trace.SpanContext { return s.sc }
+
+func (s *testSpan) IsRecording() bool { return !s.ended }
+
+func (s *testSpan) End(...trace.SpanEndOption) { s.ended = true }
+
+func (s *testSpan) SetStatus(code codes.Code, _ string) { s.errorStatus = code == codes.Error }
+
+func (s *testSpan) SetAttributes(attributes ...attribute.KeyValue) {
+	s.attributes = append(s.attributes, attributes...)
+}
+
+func TestHTTPPostBodyTemplate(t *testing.T) {
+	var receivedBody []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedBody, _ = io.ReadAll(request.Body)
+		if request.URL.Path == badPath {
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx.AddValue(interfaces.DEVICENAME, deviceName1)
+	defer ctx.RemoveValue(interfaces.DEVICENAME)
+
+	const payload = `{"id":"1234"}`
+
+	tests := []struct {
+		Name             string
+		BodyTemplate     string
+		ExpectedContinue bool
+		ExpectedBody     string
+		ExpectedError    string
+	}{
+		{"No template", "", true, payload, ""},
+		{"Envelope", `{"payload": {{.Payload}}, "source": "edgex"}`, true, `{"payload": {"id":"1234"}, "source": "edgex"}`, ""},
+		{"Context value", `{"device": "{{.Values.devicename}}", "payload": {{.Payload}}}`, true, `{"device": "device1", "payload": {"id":"1234"}}`, ""},
+		{"Missing context value", `{"site": "{{.Values.site}}"}`, false, "", "unable to render HTTP export body template"},
+		{"Bad template", `{"payload": {{.Payload}`, false, "", "unable to render HTTP export body template"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedBody = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:          ts.URL,
+				BodyTemplate: test.BodyTemplate,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, payload)
+			require.Equal(t, test.ExpectedContinue, continuePipeline, result)
+			if !test.ExpectedContinue {
+				require.NotNil(t, result)
+				assert.Contains(t, result.(error).Error(), test.ExpectedError)
+				assert.Nil(t, receivedBody)
+				return
+			}
+
+			assert.Equal(t, test.ExpectedBody, string(receivedBody))
+		})
+	}
+}
+
+func TestHTTPPostBodyTemplateRetryData(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:            ts.URL,
+		PersistOnError: true,
+		BodyTemplate:   `{"payload": {{.Payload}}}`,
+	})
+
+	ctx.SetRetryData(nil)
+	continuePipeline, _ := sender.HTTPPost(ctx, msgStr)
+	require.False(t, continuePipeline)
+	// The data is persisted before rendering so it isn't wrapped again when retried
+	assert.Equal(t, []byte(msgStr), ctx.RetryData())
+	ctx.SetRetryData(nil)
+}
+
+func TestHTTPPostMaxResponseBytes(t *testing.T) {
+	const maxResponseBytes = 1024
+
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		size := maxResponseBytes
+		if request.URL.Path == badPath {
+			size = 10 * maxResponseBytes
+		}
+		_, _ = writer.Write(bytes.Repeat([]byte("x"), size))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name              string
+		Path              string
+		MaxResponseBytes  int64
+		PersistOnError    bool
+		ExpectedContinue  bool
+		ExpectedRetryData []byte
+	}{
+		{"Unlimited", badPath, 0, false, true, nil},
+		{"Within limit", path, maxResponseBytes, false, true, nil},
+		{"Exceeds limit", badPath, maxResponseBytes, false, false, nil},
+		{"Exceeds limit, persisted", badPath, maxResponseBytes, true, false, []byte(msgStr)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.SetRetryData(nil)
+			defer ctx.SetRetryData(nil)
+
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:              ts.URL + test.Path,
+				PersistOnError:   test.PersistOnError,
+				MaxResponseBytes: test.MaxResponseBytes,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			require.Equal(t, test.ExpectedContinue, continuePipeline, result)
+			assert.Equal(t, test.ExpectedRetryData, ctx.RetryData())
+			if test.ExpectedContinue {
+				assert.NotEmpty(t, result)
+				return
+			}
+
+			require.NotNil(t, result)
+			assert.Contains(t, result.(error).Error(), "exceeds the maximum size of 1024 bytes")
+		})
+	}
+}
+
+func TestHTTPPostIdempotencyKey(t *testing.T) {
+	const idempotencyHeader = "Idempotency-Key"
+	const contextKey = "idempotency-key"
+
+	var receivedKeys []string
+	var receivedBodies [][]byte
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, _ := io.ReadAll(request.Body)
+		receivedBodies = append(receivedBodies, body)
+		receivedKeys = append(receivedKeys, request.Header.Get(idempotencyHeader))
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+	defer ctx.RemoveValue(contextKey)
+
+	for _, compressBody := range []bool{false, true} {
+		t.Run(fmt.Sprintf("CompressBody=%t", compressBody), func(t *testing.T) {
+			receivedKeys = nil
+			receivedBodies = nil
+
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                      ts.URL,
+				CompressBody:             compressBody,
+				IdempotencyKeyHeader:     idempotencyHeader,
+				IdempotencyKeyContextKey: contextKey,
+			})
+
+			for _, payload := range []string{msgStr, msgStr, "different message"} {
+				continuePipeline, result := sender.HTTPPost(ctx, payload)
+				require.True(t, continuePipeline, result)
+			}
+
+			require.Len(t, receivedKeys, 3)
+			// The key is the SHA-256 of the body as sent
+			expectedKey := sha256.Sum256(receivedBodies[0])
+			assert.Equal(t, hex.EncodeToString(expectedKey[:]), receivedKeys[0])
+			assert.Equal(t, receivedKeys[0], receivedKeys[1])
+			assert.NotEqual(t, receivedKeys[0], receivedKeys[2])
+
+			value, found := ctx.GetValue(contextKey)
+			require.True(t, found)
+			assert.Equal(t, receivedKeys[2], value)
+		})
+	}
+}
+
+func TestHTTPPostIdempotencyKeyStream(t *testing.T) {
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:                  "http://localhost",
+		IdempotencyKeyHeader: "Idempotency-Key",
+	})
+
+	continuePipeline, result := sender.HTTPPost(ctx, strings.NewReader(msgStr))
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "idempotency key is not supported for streamed data")
+}
+
+func TestHTTPPostWithProxy(t *testing.T) {
+	var proxiedURL string
+	var proxyAuthorization string
+	proxy := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// Requests sent via a proxy have the absolute destination URL as the request URI
+		proxiedURL = request.RequestURI
+		proxyAuthorization = request.Header.Get("Proxy-Authorization")
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "proxy", "username", "password").Return(map[string]string{"username": "user", "password": "pass"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	destination := "http://destination.example/api"
+
+	tests := []struct {
+		Name                       string
+		ProxySecretName            string
+		ProxyUsernameKey           string
+		ProxyPasswordKey           string
+		ExpectedProxyAuthorization string
+		ExpectedErrorMessage       string
+	}{
+		{"No credentials", "", "", "", "", ""},
+		{"With credentials", "proxy", "username", "password", "Basic " + base64.StdEncoding.EncodeToString([]byte("user:pass")), ""},
+		{"Missing credential keys", "proxy", "", "", "", "ProxyUsernameKey & ProxyPasswordKey must be specified"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			proxiedURL = ""
+			proxyAuthorization = ""
+
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:              destination,
+				ProxyURL:         proxy.URL,
+				ProxySecretName:  test.ProxySecretName,
+				ProxyUsernameKey: test.ProxyUsernameKey,
+				ProxyPasswordKey: test.ProxyPasswordKey,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			if len(test.ExpectedErrorMessage) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+				assert.Empty(t, proxiedURL)
+				return
+			}
+
+			require.True(t, continuePipeline)
+			assert.Equal(t, destination, proxiedURL)
+			assert.Equal(t, test.ExpectedProxyAuthorization, proxyAuthorization)
+		})
+	}
+}
+
+func TestHTTPPostWithHostMappings(t *testing.T) {
+	var receivedHost string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHost = request.Host
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	serverURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name         string
+		URL          string
+		HostMappings map[string]string
+		ExpectedHost string
+	}{
+		{"Not mapped", ts.URL, nil, serverURL.Host},
+		{"Mapped to IP", "http://export.test:" + serverURL.Port(), map[string]string{"export.test": serverURL.Hostname()}, "export.test:" + serverURL.Port()},
+		{"Mapped to IP and port", "http://export.test", map[string]string{"export.test": serverURL.Host}, "export.test"},
+		{"Other host mapped", ts.URL, map[string]string{"other.test": "192.0.2.1"}, serverURL.Host},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedHost = ""
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:          test.URL,
+				HostMappings: test.HostMappings,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, test.ExpectedHost, receivedHost)
+		})
+	}
+}
+
+func TestHTTPPostWithResolver(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	serverURL, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	resolverUsed := false
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			resolverUsed = true
+			return nil, errors.New("no DNS available")
+		},
+	}
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:          "http://unmapped.test:" + serverURL.Port(),
+		HostMappings: map[string]string{"export.test": serverURL.Hostname()},
+		Resolver:     resolver,
+	})
+
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	require.False(t, continuePipeline)
+	require.Error(t, result.(error))
+	assert.True(t, resolverUsed, "custom resolver not used")
+
+	// Mapped hosts bypass the resolver
+	resolverUsed = false
+	sender = NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:          "http://export.test:" + serverURL.Port(),
+		HostMappings: map[string]string{"export.test": serverURL.Hostname()},
+		Resolver:     resolver,
+	})
+
+	continuePipeline, result = sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	assert.False(t, resolverUsed, "resolver used for mapped host")
+}
+
+func TestHTTPPostWithLocalAddress(t *testing.T) {
+	// 127.0.0.2 is only available where the whole loopback range is local, i.e. Linux
+	probe, err := net.Listen("tcp", "127.0.0.2:0")
+	if err != nil {
+		t.Skipf("second loopback address not available: %s", err.Error())
+	}
+	_ = probe.Close()
+
+	var remoteHost string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		remoteHost, _, _ = net.SplitHostPort(request.RemoteAddr)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name         string
+		LocalAddress string
+		ExpectedHost string
+	}{
+		{"Not specified", "", "127.0.0.1"},
+		{"Bound to local address", "127.0.0.2", "127.0.0.2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			remoteHost = ""
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:          ts.URL,
+				LocalAddress: test.LocalAddress,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, test.ExpectedHost, remoteHost)
+		})
+	}
+}
+
+func TestHTTPPostWithLocalAddressErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name          string
+		LocalAddress  string
+		ExpectedError string
+	}{
+		{"Not an IP address", "eth0", "invalid LocalAddress 'eth0', must be an IP address"},
+		{"Address not on this host", "192.0.2.1", "192.0.2.1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:          ts.URL,
+				LocalAddress: test.LocalAddress,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			require.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			assert.Contains(t, result.(error).Error(), test.ExpectedError)
+		})
+	}
+}
+
+func TestHTTPPostWithPayloadHeader(t *testing.T) {
+	var receivedHeader string
+	var receivedBody []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeader = request.Header.Get("X-Payload")
+		receivedBody, _ = io.ReadAll(request.Body)
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name           string
+		Data           interface{}
+		MaxBytes       int
+		CompressBody   bool
+		ExpectedHeader string
+		ExpectedError  string
+	}{
+		{"Payload sent in header", `{"temperature":21.5}`, 0, false, `{"temperature":21.5}`, ""},
+		{"Payload at max size", "12345", 5, false, "12345", ""},
+		{"Payload too large", "123456", 5, false, "", "exceeds the maximum header size of 5 bytes"},
+		{"Payload too large for default", strings.Repeat("a", DefaultMaxPayloadHeaderBytes+1), 0, false, "", "exceeds the maximum header size"},
+		{"Payload with line break", "line1\nline2", 0, false, "", "not allowed in a header value"},
+		{"Compression not supported", "data", 0, true, "", "not supported with body compression"},
+		{"Stream not supported", strings.NewReader("data"), 0, false, "", "not supported for streamed data"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedHeader = ""
+			receivedBody = nil
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                   ts.URL,
+				PayloadHeader:         "X-Payload",
+				MaxPayloadHeaderBytes: test.MaxBytes,
+				CompressBody:          test.CompressBody,
+			})
+
+			continuePipeline, result := sender.HTTPPost(ctx, test.Data)
+			if len(test.ExpectedError) > 0 {
+				require.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), test.ExpectedError)
+				assert.Nil(t, receivedBody, "request should not have been sent")
+				return
+			}
+
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, test.ExpectedHeader, receivedHeader)
+			assert.Empty(t, receivedBody)
+		})
+	}
+}
+
+func TestHTTPPostWithPayloadHeaderRetries(t *testing.T) {
+	var attempts atomic.Int32
+	var receivedHeader string
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if attempts.Add(1) <= 3 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		receivedHeader = request.Header.Get("X-Payload")
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:           ts.URL,
+		PayloadHeader: "X-Payload",
+		MaxRetries:    3,
+		RetryInterval: time.Millisecond,
+	})
+
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, int32(4), attempts.Load())
+	assert.Equal(t, msgStr, receivedHeader)
+}
+
+func TestHTTPPostWithCloudEventHeaders(t *testing.T) {
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	cloudEvent, err := NewCloudEvent("/edgex/app", "com.example.reading")
+	require.NoError(t, err)
+	cloudEvent.SetBinaryMode(true)
+
+	event := dtos.NewEvent("profile1", deviceName1, "source1")
+
+	for _, enabled := range []bool{true, false} {
+		receivedHeaders = nil
+		cloudEventCtx := appfunction.NewContext("123", dic, "")
+		continuePipeline, result := cloudEvent.ConvertToCloudEvent(cloudEventCtx, event)
+		require.True(t, continuePipeline, result)
+
+		sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+			URL:               ts.URL,
+			MimeType:          common.ContentTypeJSON,
+			CloudEventHeaders: enabled,
+		})
+		continuePipeline, result = sender.HTTPPost(cloudEventCtx, result)
+		require.True(t, continuePipeline, result)
+		require.NotNil(t, receivedHeaders)
+
+		if enabled {
+			assert.Equal(t, CloudEventSpecVersion, receivedHeaders.Get("ce-specversion"))
+			assert.Equal(t, event.Id, receivedHeaders.Get("ce-id"))
+			assert.Equal(t, "/edgex/app", receivedHeaders.Get("ce-source"))
+			assert.Equal(t, "com.example.reading", receivedHeaders.Get("ce-type"))
+			assert.Equal(t, common.ContentTypeJSON, receivedHeaders.Get("Content-Type"))
+		} else {
+			assert.Empty(t, receivedHeaders.Get("ce-id"))
+		}
+	}
+}
+
+func TestHTTPPostForceHTTP2(t *testing.T) {
+	var receivedProtoMajor int
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedProtoMajor = request.ProtoMajor
+		_, _ = io.ReadAll(request.Body)
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	h2cServer := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer h2cServer.Close()
+	http1Server := httptest.NewServer(handler)
+	defer http1Server.Close()
+
+	tests := []struct {
+		Name               string
+		URL                string
+		Fallback           bool
+		ProxyURL           string
+		ExpectedProtoMajor int
+		ExpectedError      string
+	}{
+		{"h2c", h2cServer.URL, false, "", 2, ""},
+		{"h2c with fallback", h2cServer.URL, true, "", 2, ""},
+		{"HTTP/1.1 only without fallback", http1Server.URL, false, "", 0, "export to " + http1Server.URL + " failed"},
+		{"HTTP/1.1 only with fallback", http1Server.URL, true, "", 1, ""},
+		{"Proxy not supported", h2cServer.URL, false, "http://proxy:3128", 0, "ForceHTTP2 is not supported with ProxyURL"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:           test.URL,
+				ForceHTTP2:    true,
+				HTTP2Fallback: test.Fallback,
+				ProxyURL:      test.ProxyURL,
+			})
+
+			// Sent twice to verify the protocol used once HTTP/2 has been negotiated, or fallen back from
+			for i := 0; i < 2; i++ {
+				receivedProtoMajor = 0
+				continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+				if len(test.ExpectedError) > 0 {
+					require.False(t, continuePipeline)
+					assert.Contains(t, result.(error).Error(), test.ExpectedError)
+					return
+				}
+
+				require.True(t, continuePipeline, result)
+				assert.Equal(t, test.ExpectedProtoMajor, receivedProtoMajor)
+			}
+		})
+	}
+}
+
+func TestHTTP2TransportTLS(t *testing.T) {
+	var receivedProtoMajor int
+	handler := http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedProtoMajor = request.ProtoMajor
+		writer.WriteHeader(http.StatusOK)
+	})
+
+	h2Server := httptest.NewUnstartedServer(handler)
+	h2Server.EnableHTTP2 = true
+	h2Server.StartTLS()
+	defer h2Server.Close()
+
+	http1Server := httptest.NewTLSServer(handler)
+	defer http1Server.Close()
+
+	tests := []struct {
+		Name               string
+		Server             *httptest.Server
+		Fallback           bool
+		ExpectedProtoMajor int
+		ExpectedError      string
+	}{
+		{"HTTP/2", h2Server, false, 2, ""},
+		{"HTTP/2 with fallback", h2Server, true, 2, ""},
+		{"HTTP/1.1 only without fallback", http1Server, false, 0, "no application protocol"},
+		{"HTTP/1.1 only with fallback", http1Server, true, 1, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			receivedProtoMajor = 0
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = test.Server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+
+			h2Transport, err := newHTTP2Transport(transport, test.Fallback)
+			require.NoError(t, err)
+			client := &http.Client{Transport: h2Transport}
+			defer client.CloseIdleConnections()
+
+			response, err := client.Get(test.Server.URL)
+			if len(test.ExpectedError) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.ExpectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			_ = response.Body.Close()
+			assert.Equal(t, test.ExpectedProtoMajor, response.ProtoMajor)
+			assert.Equal(t, test.ExpectedProtoMajor, receivedProtoMajor)
+		})
+	}
+}
+
+func TestHTTPPostMultipart(t *testing.T) {
+	type receivedPart struct {
+		fieldName   string
+		fileName    string
+		contentType string
+		content     string
+	}
+
+	var receivedContentType string
+	var receivedParts []receivedPart
+	var receivedBodies [][]byte
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedContentType = request.Header.Get("Content-Type")
+		body, _ := io.ReadAll(request.Body)
+		receivedBodies = append(receivedBodies, body)
+
+		receivedParts = nil
+		request.Body = io.NopCloser(bytes.NewReader(body))
+		reader, err := request.MultipartReader()
+		if err != nil {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				writer.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			content, _ := io.ReadAll(part)
+			receivedParts = append(receivedParts, receivedPart{
+				fieldName:   part.FormName(),
+				fileName:    part.FileName(),
+				contentType: part.Header.Get("Content-Type"),
+				content:     string(content),
+			})
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	ctx.AddValue("upload-source", "edgex")
+	defer ctx.RemoveValue("upload-source")
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:                ts.URL,
+		MimeType:           common.ContentTypeJSON,
+		MultipartFieldName: "file",
+		MultipartFileName:  "{upload-source}.json",
+		MultipartFields:    map[string]string{"source": "{upload-source}", "kind": "event"},
+	})
+
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+
+	mediaType, params, err := mime.ParseMediaType(receivedContentType)
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/form-data", mediaType)
+	assert.NotEmpty(t, params["boundary"])
+
+	// The form fields are sent in name order followed by the data part
+	require.Len(t, receivedParts, 3)
+	assert.Equal(t, receivedPart{fieldName: "kind", content: "event"}, receivedParts[0])
+	assert.Equal(t, receivedPart{fieldName: "source", content: "edgex"}, receivedParts[1])
+	assert.Equal(t, receivedPart{fieldName: "file", fileName: "edgex.json", contentType: common.ContentTypeJSON, content: msgStr}, receivedParts[2])
+
+	// The same data results in the same body, i.e. when retried
+	continuePipeline, result = sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	require.Len(t, receivedBodies, 2)
+	assert.Equal(t, receivedBodies[0], receivedBodies[1])
+}
+
+func TestHTTPPostMultipartErrors(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Options       HTTPSenderOptions
+		Data          interface{}
+		ExpectedError string
+	}{
+		{"Streamed data", HTTPSenderOptions{MultipartFieldName: "file"}, strings.NewReader(msgStr),
+			"multipart body is not supported for streamed data"},
+		{"Payload header", HTTPSenderOptions{MultipartFieldName: "file", PayloadHeader: "X-Payload"}, msgStr,
+			"multipart body is not supported with payload header"},
+		{"Unresolved field", HTTPSenderOptions{MultipartFieldName: "file", MultipartFields: map[string]string{"source": "{missing}"}}, msgStr,
+			"unable to format value for form field 'source'"},
+		{"Unresolved file name", HTTPSenderOptions{MultipartFieldName: "file", MultipartFileName: "{missing}.json"}, msgStr,
+			"unable to format file name"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Options.URL = "http://localhost"
+			test.Options.URLFormatter = EventValuesFormatter
+			sender := NewHTTPSenderWithOptions(test.Options)
+
+			continuePipeline, result := sender.HTTPPost(ctx, test.Data)
+			require.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			assert.Contains(t, result.(error).Error(), test.ExpectedError)
+		})
+	}
+}
+
+func TestHTTPPostCircuitBreaker(t *testing.T) {
+	var failing atomic.Bool
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:                     ts.URL,
+		PersistOnError:          true,
+		CircuitBreakerThreshold: 2,
+		CircuitBreakerCooldown:  time.Minute,
+	})
+
+	now := time.Now()
+	sender.breaker.now = func() time.Time { return now }
+
+	send := func() (bool, interface{}, *appfunction.Context) {
+		sendCtx := appfunction.NewContext("123", dic, "")
+		continuePipeline, result := sender.HTTPPost(sendCtx, msgStr)
+		return continuePipeline, result, sendCtx
+	}
+
+	// Closed - the breaker opens after the threshold of consecutive failures
+	failing.Store(true)
+	for i := 0; i < 2; i++ {
+		continuePipeline, _, _ := send()
+		require.False(t, continuePipeline)
+	}
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, circuitOpen, sender.breaker.stateMetric.Value())
+	assert.Equal(t, int64(1), sender.breaker.openedMetric.Count())
+
+	// Open - sends fail fast without being attempted and the data is persisted
+	continuePipeline, result, sendCtx := send()
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "circuit breaker is open")
+	assert.Equal(t, []byte(msgStr), sendCtx.RetryData())
+	assert.Equal(t, int32(2), requests.Load())
+
+	// Half-open - a failed probe re-opens the breaker for another cooldown
+	now = now.Add(time.Minute)
+	continuePipeline, result, _ = send()
+	require.False(t, continuePipeline)
+	assert.NotContains(t, result.(error).Error(), "circuit breaker is open")
+	assert.Equal(t, int32(3), requests.Load())
+	assert.Equal(t, circuitOpen, sender.breaker.stateMetric.Value())
+	assert.Equal(t, int64(2), sender.breaker.openedMetric.Count())
+
+	continuePipeline, _, _ = send()
+	require.False(t, continuePipeline)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// Half-open - a successful probe closes the breaker
+	failing.Store(false)
+	now = now.Add(time.Minute)
+	continuePipeline, result, _ = send()
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, int32(4), requests.Load())
+	assert.Equal(t, circuitClosed, sender.breaker.stateMetric.Value())
+
+	// Closed - a single failure no longer opens the breaker
+	failing.Store(true)
+	continuePipeline, _, _ = send()
+	require.False(t, continuePipeline)
+	failing.Store(false)
+	continuePipeline, result, _ = send()
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, int32(6), requests.Load())
+	assert.Equal(t, int64(2), sender.breaker.openedMetric.Count())
+}
+
+func TestHTTPPostCircuitBreakerSingleProbe(t *testing.T) {
+	breaker := newCircuitBreaker(1, 0)
+	assert.Equal(t, DefaultCircuitBreakerCooldown, breaker.cooldown)
+
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+
+	breaker.record(ctx, false)
+	require.False(t, breaker.allow(ctx))
+
+	// Only one probe is allowed while half-open
+	now = now.Add(DefaultCircuitBreakerCooldown)
+	require.True(t, breaker.allow(ctx))
+	assert.Equal(t, circuitHalfOpen, breaker.stateMetric.Value())
+	require.False(t, breaker.allow(ctx))
+
+	// A released probe, i.e. a cancelled send, allows another probe
+	breaker.release()
+	require.True(t, breaker.allow(ctx))
+	breaker.record(ctx, true)
+	assert.Equal(t, circuitClosed, breaker.stateMetric.Value())
+	require.True(t, breaker.allow(ctx))
+}
+
+func TestHTTPPostCircuitBreakerContinueOnSendError(t *testing.T) {
+	sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+		URL:                     "http://localhost:1",
+		ContinueOnSendError:     true,
+		ReturnInputData:         true,
+		CircuitBreakerThreshold: 1,
+	})
+
+	continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	assert.Equal(t, msgStr, result)
+	assert.Equal(t, circuitOpen, sender.breaker.stateMetric.Value())
+
+	continuePipeline, result = sender.HTTPPost(ctx, msgStr)
+	require.True(t, continuePipeline)
+	assert.Equal(t, msgStr, result)
+}
+
+func TestHTTPPostUserAgentAndDefaultHeaders(t *testing.T) {
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header.Clone()
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name              string
+		UserAgent         string
+		DefaultHeaders    map[string]string
+		RequestHeaders    map[string]string
+		ExpectedUserAgent string
+		ExpectedHeaders   map[string]string
+	}{
+		{"SDK default", "", nil, nil, fmt.Sprintf("%s/%s", UserAgentProduct, internal.SDKVersion), nil},
+		{"Custom user agent", "my-service/1.2.3", nil, nil, "my-service/1.2.3", nil},
+		{"Default headers", "", map[string]string{"X-Site": "plant-1", "X-Region": "eu"}, nil,
+			fmt.Sprintf("%s/%s", UserAgentProduct, internal.SDKVersion), map[string]string{"X-Site": "plant-1", "X-Region": "eu"}},
+		{"Request headers override defaults", "", map[string]string{"X-Site": "plant-1", "X-Region": "eu"},
+			map[string]string{"X-Site": "plant-2", "User-Agent": "explicit/1.0"}, "explicit/1.0",
+			map[string]string{"X-Site": "plant-2", "X-Region": "eu"}},
+		{"Default User-Agent header", "", map[string]string{"User-Agent": "from-defaults/1.0"}, nil, "from-defaults/1.0", nil},
+		{"User agent option overrides default header", "option/1.0", map[string]string{"User-Agent": "from-defaults/1.0"}, nil, "option/1.0", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:            ts.URL,
+				UserAgent:      test.UserAgent,
+				DefaultHeaders: test.DefaultHeaders,
+			})
+			sender.SetHttpRequestHeaders(test.RequestHeaders)
+
+			continuePipeline, result := sender.HTTPPost(ctx, msgStr)
+			require.True(t, continuePipeline, result)
+
+			assert.Equal(t, test.ExpectedUserAgent, receivedHeaders.Get("User-Agent"))
+			for name, value := range test.ExpectedHeaders {
+				assert.Equal(t, value, receivedHeaders.Get(name), name)
+			}
+		})
+	}
+}
+
+func TestHTTPPostSkipWhenContextKey(t *testing.T) {
+	const flagKey = "shouldexport"
+
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requests.Add(1)
+		_, _ = writer.Write([]byte("response"))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		Name         string
+		OptionKey    string
+		Present      bool
+		Value        string
+		ExpectedSent bool
+	}{
+		{"Option not set", "", true, "false", true},
+		{"Key absent", flagKey, false, "", true},
+		{"Present true", flagKey, true, "true", true},
+		{"Present false", flagKey, true, "false", false},
+		{"Present zero", flagKey, true, "0", false},
+		{"Present empty", flagKey, true, " ", false},
+		{"Present other value", flagKey, true, "yes please", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			requests.Store(0)
+
+			flagCtx := appfunction.NewContext("123", dic, "")
+			if test.Present {
+				flagCtx.AddValue(flagKey, test.Value)
+			}
+
+			sender := NewHTTPSenderWithOptions(HTTPSenderOptions{
+				URL:                ts.URL,
+				SkipWhenContextKey: test.OptionKey,
+			})
+
+			continuePipeline, result := sender.HTTPPost(flagCtx, msgStr)
+			require.True(t, continuePipeline, result)
+
+			if test.ExpectedSent {
+				assert.Equal(t, int32(1), requests.Load())
+				assert.Equal(t, []byte("response"), result)
+			} else {
+				assert.Zero(t, requests.Load())
+				assert.Equal(t, msgStr, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"sort"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// multipartEncoder builds a multipart/form-data body with the data as the payload part plus additional form fields.
+type multipartEncoder struct {
+	fieldName string
+	fileName  string
+	fields    map[string]string
+}
+
+// encode returns the multipart/form-data body and its content type, including the boundary. The form field values
+// and the file name are formatted using the formatter. The boundary is derived from the content, so the same data
+// always results in the same body, i.e. when retried, which keeps the idempotency key and HMAC signature stable.
+func (encoder *multipartEncoder) encode(
+	ctx interfaces.AppFunctionContext,
+	data interface{},
+	formatter StringValuesFormatter,
+	payload []byte,
+	payloadContentType string) ([]byte, string, error) {
+	names := make([]string, 0, len(encoder.fields))
+	for name := range encoder.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make([]string, len(names))
+	for index, name := range names {
+		value, err := formatter.invoke(encoder.fields[name], ctx, data)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to format value for form field '%s': %s", name, err.Error())
+		}
+		values[index] = value
+	}
+
+	fileName, err := formatter.invoke(encoder.fileName, ctx, data)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to format file name: %s", err.Error())
+	}
+
+	hash := sha256.New()
+	for index, name := range names {
+		_, _ = fmt.Fprintf(hash, "%s=%s\n", name, values[index])
+	}
+	_, _ = hash.Write(payload)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.SetBoundary(hex.EncodeToString(hash.Sum(nil)[:24])); err != nil {
+		return nil, "", err
+	}
+
+	for index, name := range names {
+		if err := writer.WriteField(name, values[index]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	disposition := fmt.Sprintf(`form-data; name="%s"`, escapeQuotes(encoder.fieldName))
+	if len(fileName) > 0 {
+		disposition += fmt.Sprintf(`; filename="%s"`, escapeQuotes(fileName))
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", disposition)
+	header.Set("Content-Type", payloadContentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if _, err := part.Write(payload); err != nil {
+		return nil, "", err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// escapeQuotes escapes the quotes in a Content-Disposition parameter value the same as mime/multipart
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
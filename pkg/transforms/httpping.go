@@ -0,0 +1,135 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// Ping checks the destination is reachable, i.e. for a readiness probe, by sending a request without a body using
+// the PingMethod to the PingPath. The same client, TLS, proxy and authentication configuration, including the secret
+// headers, are used as for an export. The request isn't retried, and the export metrics, circuit breaker and
+// Store and Forward are unaffected. It returns an error if the request fails or the response status code isn't
+// a success status code.
+func (sender *HTTPSender) Ping(ctx interfaces.AppFunctionContext) error {
+	pingUrl, err := sender.pingURL()
+	if err != nil {
+		return fmt.Errorf("unable to determine ping URL: %s", err.Error())
+	}
+
+	method := sender.pingMethod
+	if len(method) == 0 {
+		method = http.MethodHead
+	}
+
+	usingSecrets, err := sender.determineIfUsingSecrets(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sender.oauth2 != nil {
+		if err := sender.oauth2.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := sender.validateBasicAuth(); err != nil {
+		return err
+	}
+
+	if sender.hmac != nil {
+		if err := sender.hmac.validate(); err != nil {
+			return err
+		}
+	}
+
+	client, err := sender.getClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := sender.createPingRequest(ctx, method, pingUrl, usingSecrets)
+	if err != nil {
+		return fmt.Errorf("unable to create ping request: %s", err.Error())
+	}
+
+	if err := sender.setAuthorizationToken(ctx, client, req); err != nil {
+		return err
+	}
+
+	ctx.LoggingClient().Debugf("Sending %s ping request to %s", method, pingUrl.Redacted())
+
+	response, err := client.Do(req)
+	sender.logRequestAndResponse(ctx, req, response, err)
+	if err != nil {
+		return fmt.Errorf("ping of %s failed: %w", pingUrl.Redacted(), err)
+	}
+
+	discardResponse(response)
+
+	if !sender.isSuccessStatusCode(response.StatusCode) {
+		return fmt.Errorf("ping of %s failed with %d HTTP status code", pingUrl.Redacted(), response.StatusCode)
+	}
+
+	return nil
+}
+
+// pingURL returns the URL the Ping request is sent to, which is the PingPath resolved against the URL
+func (sender *HTTPSender) pingURL() (*url.URL, error) {
+	baseUrl, err := url.Parse(sender.url)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sender.pingPath) == 0 {
+		return baseUrl, nil
+	}
+
+	pingPath, err := url.Parse(sender.pingPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return baseUrl.ResolveReference(pingPath), nil
+}
+
+// createPingRequest creates the bodiless ping request with the same common, http request and authentication headers
+// as an export. Headers specific to the data, such as the content type and idempotency key, aren't set.
+func (sender *HTTPSender) createPingRequest(
+	ctx interfaces.AppFunctionContext,
+	method string,
+	pingUrl *url.URL,
+	usingSecrets bool) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(sender.getRequestContext(), method, pingUrl.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sender.setCommonHeaders(ctx, req, usingSecrets); err != nil {
+		return nil, err
+	}
+
+	if err := sender.setRequestHeadersAndSign(ctx, req, nil); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
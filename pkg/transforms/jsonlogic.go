@@ -32,6 +32,10 @@ import (
 // JSONLogic ...
 type JSONLogic struct {
 	Rule string
+	// ResultKey is the context storage key the evaluated result is stored under. The result isn't stored if empty.
+	ResultKey string
+	// ReturnResult when true returns the evaluated result as the new pipeline data rather than filtering on it.
+	ReturnResult bool
 }
 
 // NewJSONLogic creates, initializes and returns a new instance of HTTPSender
@@ -41,6 +45,18 @@ func NewJSONLogic(rule string) *JSONLogic {
 	}
 }
 
+// NewJSONLogicWithResult creates, initializes and returns a new instance of JSONLogic which exposes the evaluated
+// result to the rest of the pipeline. The result is stored in the context under resultKey, if not empty, and
+// returned as the new pipeline data if returnResult is true. Rules producing non-boolean values are only
+// supported by instances created with this constructor.
+func NewJSONLogicWithResult(rule string, resultKey string, returnResult bool) *JSONLogic {
+	return &JSONLogic{
+		Rule:         rule,
+		ResultKey:    resultKey,
+		ReturnResult: returnResult,
+	}
+}
+
 // Evaluate ...
 func (logic *JSONLogic) Evaluate(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
 	if data == nil {
@@ -63,6 +79,10 @@ func (logic *JSONLogic) Evaluate(ctx interfaces.AppFunctionContext, data interfa
 		return false, fmt.Errorf("unable to apply JSONLogic rule in pipeline '%s': %s", ctx.PipelineId(), err.Error())
 	}
 
+	if len(logic.ResultKey) > 0 || logic.ReturnResult {
+		return logic.exposeResult(ctx, logicResult.Bytes(), data)
+	}
+
 	var result bool
 	decoder := json.NewDecoder(&logicResult)
 	err = decoder.Decode(&result)
@@ -74,3 +94,34 @@ func (logic *JSONLogic) Evaluate(ctx interfaces.AppFunctionContext, data interfa
 
 	return result, data
 }
+
+// exposeResult stores the evaluated result in the context and/or returns it as the pipeline data. Boolean results
+// still filter the pipeline when the result isn't returned.
+func (logic *JSONLogic) exposeResult(ctx interfaces.AppFunctionContext, logicResult []byte, data interface{}) (bool, interface{}) {
+	var result interface{}
+	if err := json.Unmarshal(logicResult, &result); err != nil {
+		return false, fmt.Errorf("unable to decode JSONLogic result in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	if len(logic.ResultKey) > 0 {
+		// Context values are strings, so string results are stored as is and all others as JSON
+		value, isString := result.(string)
+		if !isString {
+			value = strings.TrimSpace(string(logicResult))
+		}
+
+		ctx.AddValue(logic.ResultKey, value)
+		ctx.LoggingClient().Debugf("JSONLogic result stored as '%s' in pipeline '%s': %s", logic.ResultKey, ctx.PipelineId(), value)
+	}
+
+	if logic.ReturnResult {
+		return true, result
+	}
+
+	if condition, isBool := result.(bool); isBool {
+		ctx.LoggingClient().Debugf("Condition met in pipeline '%s': %s", ctx.PipelineId(), strconv.FormatBool(condition))
+		return condition, data
+	}
+
+	return true, data
+}
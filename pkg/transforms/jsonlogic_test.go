@@ -92,3 +92,63 @@ func TestJSONLogicNonJSONData(t *testing.T) {
 	assert.False(t, continuePipeline)
 	assert.Error(t, result.(error))
 }
+
+func TestJSONLogicWithResult(t *testing.T) {
+	resultKey := "jsonlogic-result"
+	data := `{ "temp" : 100, "offset" : 5 }`
+
+	tests := []struct {
+		Name                   string
+		Rule                   string
+		ResultKey              string
+		ReturnResult           bool
+		ExpectedContinue       bool
+		ExpectedResult         interface{}
+		ExpectedStoredValue    string
+		ExpectedValueNotStored bool
+	}{
+		{"computed number returned", `{"+": [{"var": "temp"}, {"var": "offset"}]}`, resultKey, true, true, float64(105), "105", false},
+		{"computed number stored", `{"+": [{"var": "temp"}, {"var": "offset"}]}`, resultKey, false, true, data, "105", false},
+		{"computed string stored", `{"cat": ["temp-", {"var": "temp"}]}`, resultKey, false, true, data, "temp-100", false},
+		{"computed object returned only", `{"merge": [1, 2]}`, "", true, true, []interface{}{float64(1), float64(2)}, "", true},
+		{"false condition still filters", `{">": [{"var": "temp"}, 110]}`, resultKey, false, false, data, "false", false},
+		{"false condition returned", `{">": [{"var": "temp"}, 110]}`, resultKey, true, true, false, "false", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.RemoveValue(resultKey)
+			defer ctx.RemoveValue(resultKey)
+
+			jsonLogic := NewJSONLogicWithResult(test.Rule, test.ResultKey, test.ReturnResult)
+
+			continuePipeline, result := jsonLogic.Evaluate(ctx, data)
+
+			assert.Equal(t, test.ExpectedContinue, continuePipeline)
+			assert.Equal(t, test.ExpectedResult, result)
+
+			// Confirm the result is available to downstream functions via the context
+			value, found := ctx.GetValue(resultKey)
+			if test.ExpectedValueNotStored {
+				assert.False(t, found)
+				return
+			}
+			require.True(t, found)
+			assert.Equal(t, test.ExpectedStoredValue, value)
+
+			applied, err := ctx.ApplyValues("result={" + resultKey + "}")
+			require.NoError(t, err)
+			assert.Equal(t, "result="+test.ExpectedStoredValue, applied)
+		})
+	}
+}
+
+func TestJSONLogicNonBooleanWithoutResult(t *testing.T) {
+	jsonLogic := NewJSONLogic(`{"+": [1, 1]}`)
+
+	continuePipeline, result := jsonLogic.Evaluate(ctx, `{}`)
+
+	assert.False(t, continuePipeline)
+	require.IsType(t, fmt.Errorf(""), result)
+	assert.Contains(t, result.(error).Error(), "unable to decode JSONLogic result")
+}
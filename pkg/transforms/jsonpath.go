@@ -0,0 +1,227 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// jsonPath is a parsed JSONPath expression which selects a single value. The supported subset of JSONPath is the
+// root ($), dot child (.name), bracket child (['name'] or ["name"]) and array index ([n], negative from the end).
+type jsonPath []jsonPathSegment
+
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the JSONPath expression. The leading "$." is optional, i.e. "sensor.type" is the same as
+// "$.sensor.type".
+func parseJSONPath(expression string) (jsonPath, error) {
+	expression = strings.TrimSpace(expression)
+	if len(expression) == 0 {
+		return nil, errors.New("JSONPath expression is empty")
+	}
+
+	remaining := strings.TrimPrefix(expression, "$")
+	if remaining == expression && !strings.HasPrefix(remaining, "[") {
+		remaining = "." + remaining
+	}
+
+	var path jsonPath
+	for len(remaining) > 0 {
+		switch remaining[0] {
+		case '.':
+			remaining = remaining[1:]
+			end := strings.IndexAny(remaining, ".[")
+			if end == -1 {
+				end = len(remaining)
+			}
+
+			name := remaining[:end]
+			if len(name) == 0 || name == "*" {
+				return nil, fmt.Errorf("invalid or unsupported JSONPath expression '%s': expected a name after '.'", expression)
+			}
+
+			path = append(path, jsonPathSegment{key: name})
+			remaining = remaining[end:]
+
+		case '[':
+			end := strings.Index(remaining, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid JSONPath expression '%s': missing ']'", expression)
+			}
+
+			selector := strings.TrimSpace(remaining[1:end])
+			if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+				path = append(path, jsonPathSegment{key: selector[1 : len(selector)-1]})
+			} else {
+				index, err := strconv.Atoi(selector)
+				if err != nil {
+					return nil, fmt.Errorf("invalid or unsupported JSONPath expression '%s': '[%s]' is not a name or index", expression, selector)
+				}
+				path = append(path, jsonPathSegment{index: index, isIndex: true})
+			}
+
+			remaining = remaining[end+1:]
+
+		default:
+			return nil, fmt.Errorf("invalid JSONPath expression '%s': unexpected '%c'", expression, remaining[0])
+		}
+	}
+
+	return path, nil
+}
+
+// lookup returns the value selected by the path from the unmarshalled JSON value
+func (path jsonPath) lookup(value interface{}) (interface{}, bool) {
+	for _, segment := range path {
+		switch current := value.(type) {
+		case map[string]interface{}:
+			if segment.isIndex {
+				return nil, false
+			}
+
+			var found bool
+			if value, found = current[segment.key]; !found {
+				return nil, false
+			}
+		case []interface{}:
+			if !segment.isIndex {
+				return nil, false
+			}
+
+			index := segment.index
+			if index < 0 {
+				index += len(current)
+			}
+
+			if index < 0 || index >= len(current) {
+				return nil, false
+			}
+
+			value = current[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// lookupData returns the value selected by the path from the data, which is coerced to JSON
+func (path jsonPath) lookupData(data interface{}) (interface{}, bool, error) {
+	jsonData, err := util.CoerceType(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(jsonData, &value); err != nil {
+		return nil, false, fmt.Errorf("unable to unmarshal data as JSON: %s", err.Error())
+	}
+
+	result, found := path.lookup(value)
+	return result, found, nil
+}
+
+// jsonValueString returns string values as is and all other values in their JSON form
+func jsonValueString(value interface{}) (string, error) {
+	if stringValue, ok := value.(string); ok {
+		return stringValue, nil
+	}
+
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(jsonValue), nil
+}
+
+// JSONPathExtractor extracts a value from the JSON data using a JSONPath expression and stores it in the context
+type JSONPathExtractor struct {
+	expression     string
+	path           jsonPath
+	contextKey     string
+	emptyOnMissing bool
+}
+
+// NewJSONPathExtractor creates, initializes and returns a new instance of JSONPathExtractor which stores the value
+// selected by the JSONPath expression in the context under contextKey. The supported subset of JSONPath is the
+// root ($), dot child (.name), bracket child (['name']) and array index ([n]). An error is returned if the
+// expression is invalid or unsupported.
+func NewJSONPathExtractor(expression string, contextKey string) (*JSONPathExtractor, error) {
+	path, err := parseJSONPath(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contextKey) == 0 {
+		return nil, errors.New("context key must be specified")
+	}
+
+	return &JSONPathExtractor{
+		expression: expression,
+		path:       path,
+		contextKey: contextKey,
+	}, nil
+}
+
+// SetEmptyOnMissing sets whether an empty value is stored when the path isn't found in the data,
+// rather than returning an error.
+func (extractor *JSONPathExtractor) SetEmptyOnMissing(emptyOnMissing bool) {
+	extractor.emptyOnMissing = emptyOnMissing
+}
+
+// Extract stores the value selected by the JSONPath expression in the context and passes the original data on
+// to the next function. String values are stored as is and all other values in their JSON form.
+// It will return an error and stop the pipeline if no data is received, the data isn't JSON or the path
+// isn't found in the data, unless set to store an empty value.
+func (extractor *JSONPathExtractor) Extract(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Extract in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	value, found, err := extractor.path.lookupData(data)
+	if err != nil {
+		return false, fmt.Errorf("function Extract in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	result := ""
+	if found {
+		result, err = jsonValueString(value)
+		if err != nil {
+			return false, fmt.Errorf("function Extract in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		}
+	} else if !extractor.emptyOnMissing {
+		return false, fmt.Errorf("function Extract in pipeline '%s': path '%s' not found in data", ctx.PipelineId(), extractor.expression)
+	}
+
+	ctx.AddValue(extractor.contextKey, result)
+	ctx.LoggingClient().Debugf("Value extracted from '%s' stored as '%s' in pipeline '%s'", extractor.expression, extractor.contextKey, ctx.PipelineId())
+
+	return true, data
+}
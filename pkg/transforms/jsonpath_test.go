@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSONPathExtractor(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Expression  string
+		ContextKey  string
+		ExpectError bool
+	}{
+		{"Valid - dot notation", "$.sensor.type", "type", false},
+		{"Valid - no root", "sensor.type", "type", false},
+		{"Valid - bracket notation", "$['sensor']['type']", "type", false},
+		{"Valid - array index", "$.readings[0].value", "value", false},
+		{"Valid - negative array index", "$.readings[-1].value", "value", false},
+		{"Invalid - empty", "", "key", true},
+		{"Invalid - empty name", "$.sensor..type", "key", true},
+		{"Invalid - wildcard", "$.readings[*].value", "key", true},
+		{"Invalid - filter", "$.readings[?(@.value > 1)]", "key", true},
+		{"Invalid - missing bracket", "$.readings[0", "key", true},
+		{"Invalid - no context key", "$.sensor", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			extractor, err := NewJSONPathExtractor(test.Expression, test.ContextKey)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, extractor)
+		})
+	}
+}
+
+func TestJSONPathExtractorExtract(t *testing.T) {
+	data := `{
+		"sensor": {"type": "temperature", "location": {"site": "plant1", "line": 4}},
+		"readings": [{"value": 20.5}, {"value": 21}, {"value": "high"}],
+		"active": true
+	}`
+
+	event := dtos.Event{
+		DeviceName: deviceName1,
+		Readings:   []dtos.BaseReading{{ResourceName: resource1}, {ResourceName: resource2}},
+	}
+
+	tests := []struct {
+		Name           string
+		Expression     string
+		Data           interface{}
+		EmptyOnMissing bool
+		ExpectedValue  string
+		ExpectedError  string
+	}{
+		{"nested object string", "$.sensor.location.site", data, false, "plant1", ""},
+		{"nested object number", "$.sensor.location.line", data, false, "4", ""},
+		{"bracket notation", "$['sensor']['type']", data, false, "temperature", ""},
+		{"array element", "$.readings[1].value", data, false, "21", ""},
+		{"negative array index", "$.readings[-1].value", data, false, "high", ""},
+		{"object value as JSON", "$.sensor.location", data, false, `{"line":4,"site":"plant1"}`, ""},
+		{"array value as JSON", "$.readings[0]", data, false, `{"value":20.5}`, ""},
+		{"bool", "$.active", []byte(data), false, "true", ""},
+		{"event", "$.readings[1].resourceName", event, false, resource2, ""},
+		{"missing key errors", "$.sensor.bogus", data, false, "", "path '$.sensor.bogus' not found"},
+		{"index out of range errors", "$.readings[3].value", data, false, "", "not found"},
+		{"missing key stores empty", "$.sensor.bogus", data, true, "", ""},
+		{"not json", "$.sensor", "not json", false, "", "unable to unmarshal data as JSON"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			contextKey := "extracted"
+			ctx.AddValue(contextKey, "previous")
+			defer ctx.RemoveValue(contextKey)
+
+			extractor, err := NewJSONPathExtractor(test.Expression, contextKey)
+			require.NoError(t, err)
+			extractor.SetEmptyOnMissing(test.EmptyOnMissing)
+
+			continuePipeline, result := extractor.Extract(ctx, test.Data)
+
+			if len(test.ExpectedError) > 0 {
+				require.False(t, continuePipeline)
+				require.Error(t, result.(error))
+				assert.Contains(t, result.(error).Error(), test.ExpectedError)
+				return
+			}
+
+			require.True(t, continuePipeline)
+			assert.Equal(t, test.Data, result)
+
+			value, found := ctx.GetValue(contextKey)
+			require.True(t, found)
+			assert.Equal(t, test.ExpectedValue, value)
+		})
+	}
+}
+
+func TestJSONPathExtractorNoData(t *testing.T) {
+	extractor, err := NewJSONPathExtractor("$.sensor", "key")
+	require.NoError(t, err)
+
+	continuePipeline, result := extractor.Extract(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+}
@@ -0,0 +1,122 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	gometrics "github.com/rcrowley/go-metrics"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// JSONSchemaValidator validates the JSON representation of the data against a JSON Schema (draft 4)
+type JSONSchemaValidator struct {
+	validator     *validate.SchemaValidator
+	deadLetter    []interfaces.AppFunction
+	invalidMetric gometrics.Counter
+}
+
+// NewJSONSchemaValidator creates, initializes and returns a new instance of JSONSchemaValidator which validates
+// against the JSON Schema. The schema is compiled once, so an error is returned if it is not a valid JSON Schema
+// or contains references which can't be resolved.
+func NewJSONSchemaValidator(schema string) (*JSONSchemaValidator, error) {
+	var rawSchema interface{}
+	if err := json.Unmarshal([]byte(schema), &rawSchema); err != nil {
+		return nil, fmt.Errorf("unable to parse JSON schema: %s", err.Error())
+	}
+
+	if err := validate.AgainstSchema(spec.MustLoadJSONSchemaDraft04(), rawSchema, strfmt.Default); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %s", err.Error())
+	}
+
+	compiledSchema := &spec.Schema{}
+	if err := json.Unmarshal([]byte(schema), compiledSchema); err != nil {
+		return nil, fmt.Errorf("unable to parse JSON schema: %s", err.Error())
+	}
+
+	if err := spec.ExpandSchema(compiledSchema, nil, nil); err != nil {
+		return nil, fmt.Errorf("unable to resolve JSON schema references: %s", err.Error())
+	}
+
+	return &JSONSchemaValidator{
+		validator:     validate.NewSchemaValidator(compiledSchema, nil, "", strfmt.Default),
+		invalidMetric: gometrics.NewCounter(),
+	}, nil
+}
+
+// SetDeadLetter sets the functions executed for invalid data, i.e. to export it to a dead-letter destination.
+// The pipeline is still stopped after they are executed, unless one of them fails, in which case its error is returned.
+func (validator *JSONSchemaValidator) SetDeadLetter(functions ...interfaces.AppFunction) {
+	validator.deadLetter = functions
+}
+
+// Validate passes the data on to the next function if it is valid against the JSON Schema. Data which isn't valid,
+// including data which isn't JSON, is counted by the JSONSchemaValidationFailures metric, passed to the dead-letter
+// functions, if any, and stops the pipeline. It will return an error and stop the pipeline if no data is received.
+func (validator *JSONSchemaValidator) Validate(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Validate in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	registerMetric(ctx,
+		func() string {
+			return fmt.Sprintf("%s-%s", internal.JSONSchemaValidationFailuresName, ctx.PipelineId())
+		},
+		func() any { return validator.invalidMetric },
+		map[string]string{"pipeline": ctx.PipelineId()})
+
+	coercedData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	var document interface{}
+	var validationErrors []string
+	if err := json.Unmarshal(coercedData, &document); err != nil {
+		validationErrors = append(validationErrors, fmt.Sprintf("not valid JSON: %s", err.Error()))
+	} else if result := validator.validator.Validate(document); !result.IsValid() {
+		for _, validationErr := range result.Errors {
+			validationErrors = append(validationErrors, validationErr.Error())
+		}
+	}
+
+	if len(validationErrors) == 0 {
+		ctx.LoggingClient().Debugf("Data is valid against JSON schema in pipeline '%s'", ctx.PipelineId())
+		return true, data
+	}
+
+	validator.invalidMetric.Inc(1)
+	ctx.LoggingClient().Warnf("Data is not valid against JSON schema in pipeline '%s': %s", ctx.PipelineId(), strings.Join(validationErrors, "; "))
+
+	if len(validator.deadLetter) > 0 {
+		if continuePipeline, result := executeFunctions(ctx, validator.deadLetter, data); !continuePipeline {
+			if err, isError := result.(error); isError {
+				return false, fmt.Errorf("function Validate in pipeline '%s': dead-letter failed: %s", ctx.PipelineId(), err.Error())
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+const testEventSchema = `{
+	"type": "object",
+	"required": ["deviceName", "readings"],
+	"properties": {
+		"deviceName": {"type": "string", "minLength": 1},
+		"readings": {
+			"type": "array",
+			"minItems": 1,
+			"items": {"$ref": "#/definitions/reading"}
+		}
+	},
+	"definitions": {
+		"reading": {
+			"type": "object",
+			"required": ["resourceName", "value"],
+			"properties": {
+				"resourceName": {"type": "string"},
+				"value": {"type": "string"}
+			}
+		}
+	}
+}`
+
+func TestNewJSONSchemaValidator(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Schema      string
+		ExpectError bool
+	}{
+		{"Valid", testEventSchema, false},
+		{"Valid - empty schema", `{}`, false},
+		{"Invalid - not JSON", `{"type":`, true},
+		{"Invalid - bad type", `{"type": "bogus"}`, true},
+		{"Invalid - bad required", `{"required": "deviceName"}`, true},
+		{"Invalid - unresolved reference", `{"$ref": "#/definitions/missing"}`, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			validator, err := NewJSONSchemaValidator(test.Schema)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, validator)
+		})
+	}
+}
+
+func TestJSONSchemaValidatorValidate(t *testing.T) {
+	validEvent := dtos.NewEvent("profile", "device", "source")
+	require.NoError(t, validEvent.AddSimpleReading("resource", common.ValueTypeInt32, int32(12)))
+
+	tests := []struct {
+		Name          string
+		Data          interface{}
+		ExpectValid   bool
+		ExpectedError string
+	}{
+		{"Valid event", validEvent, true, ""},
+		{"Valid JSON", `{"deviceName":"device","readings":[{"resourceName":"resource","value":"12"}]}`, true, ""},
+		{"Missing required field", `{"readings":[{"resourceName":"resource","value":"12"}]}`, false, ""},
+		{"Empty device name", `{"deviceName":"","readings":[{"resourceName":"resource","value":"12"}]}`, false, ""},
+		{"No readings", `{"deviceName":"device","readings":[]}`, false, ""},
+		{"Invalid reading", `{"deviceName":"device","readings":[{"resourceName":"resource","value":12}]}`, false, ""},
+		{"Not JSON", []byte("not json"), false, ""},
+		{"No data", nil, false, "No Data Received"},
+	}
+
+	validator, err := NewJSONSchemaValidator(testEventSchema)
+	require.NoError(t, err)
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := validator.Validate(ctx, test.Data)
+			assert.Equal(t, test.ExpectValid, continuePipeline)
+
+			switch {
+			case test.ExpectValid:
+				assert.Equal(t, test.Data, result)
+			case len(test.ExpectedError) > 0:
+				require.NotNil(t, result)
+				assert.Contains(t, result.(error).Error(), test.ExpectedError)
+			default:
+				assert.Nil(t, result)
+			}
+		})
+	}
+
+	assert.Equal(t, int64(5), validator.invalidMetric.Count())
+}
+
+func TestJSONSchemaValidatorDeadLetter(t *testing.T) {
+	const invalidData = `{"readings":[]}`
+
+	validator, err := NewJSONSchemaValidator(testEventSchema)
+	require.NoError(t, err)
+
+	var deadLettered []interface{}
+	deadLetter := func(_ interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		deadLettered = append(deadLettered, data)
+		return true, data
+	}
+	validator.SetDeadLetter(deadLetter)
+
+	continuePipeline, result := validator.Validate(ctx, `{"deviceName":"device","readings":[{"resourceName":"resource","value":"12"}]}`)
+	require.True(t, continuePipeline)
+	require.NotNil(t, result)
+	assert.Empty(t, deadLettered)
+
+	continuePipeline, result = validator.Validate(ctx, invalidData)
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+	assert.Equal(t, []interface{}{invalidData}, deadLettered)
+
+	validator.SetDeadLetter(func(_ interfaces.AppFunctionContext, _ interface{}) (bool, interface{}) {
+		return false, errors.New("dead-letter unavailable")
+	})
+
+	continuePipeline, result = validator.Validate(ctx, invalidData)
+	assert.False(t, continuePipeline)
+	require.NotNil(t, result)
+	assert.Contains(t, result.(error).Error(), "dead-letter unavailable")
+}
@@ -0,0 +1,380 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	coreCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// SASL mechanisms supported by the KafkaSender
+const (
+	KafkaSASLPlain       = "plain"
+	KafkaSASLScramSHA256 = "scram-sha-256"
+	KafkaSASLScramSHA512 = "scram-sha-512"
+)
+
+// KafkaSenderOptions contains all options available to the KafkaSender
+type KafkaSenderOptions struct {
+	// Brokers are the addresses of the Kafka brokers used to discover the cluster, i.e. "kafka:9092"
+	Brokers []string
+	// Topic is the topic the messages are produced to. Placeholders in the form '{some-context-key}' are replaced
+	// using the TopicFormatter, i.e. 'edgex.{devicename}'.
+	Topic string
+	// Key is the optional message key, i.e. '{devicename}' so all the messages for a device are produced to the same
+	// partition, and is formatted the same as the Topic. Messages are produced without a key if empty.
+	Key string
+	// TopicFormatter specifies custom formatting behavior to be applied to the Topic and Key. If nil, the default
+	// behavior is to replace placeholders with the values found in the context storage.
+	TopicFormatter StringValuesFormatter
+	// Timeout is the time limit for producing each message, including waiting for the brokers to acknowledge it.
+	// Zero means no timeout.
+	Timeout time.Duration
+	// PersistOnError enables use of store & forward loop if true
+	PersistOnError bool
+	// ContinueOnSendError allows execution of subsequent chained senders after errors if true
+	ContinueOnSendError bool
+	// ReturnInputData enables chaining multiple senders if true
+	ReturnInputData bool
+	// UseTLS enables TLS for the broker connections. The brokers' certificates are verified using the system CAs
+	// unless CACertKey is specified.
+	UseTLS bool
+	// TLSSecretName is the name of the secret in the SecretStore containing the PEM encoded client certificate,
+	// key and CA bundle used for TLS
+	TLSSecretName string
+	// ClientCertKey is the optional key for the client certificate, used for mutual TLS, in the TLSSecretName secret data
+	ClientCertKey string
+	// ClientKeyKey is the optional key for the client private key in the TLSSecretName secret data
+	ClientKeyKey string
+	// CACertKey is the optional key for the CA bundle used to verify the brokers in the TLSSecretName secret data
+	CACertKey string
+	// SASLMechanism is the SASL mechanism used to authenticate with the brokers. Must be one of KafkaSASLPlain,
+	// KafkaSASLScramSHA256 or KafkaSASLScramSHA512. SASL authentication isn't used if empty.
+	SASLMechanism string
+	// SASLSecretName is the name of the secret in the SecretStore containing the SASL credentials
+	SASLSecretName string
+	// SASLUsernameKey is the key for the username in the SASLSecretName secret data
+	SASLUsernameKey string
+	// SASLPasswordKey is the key for the password in the SASLSecretName secret data
+	SASLPasswordKey string
+	// Context is the parent of the context used to produce each message so that in-flight sends are aborted when it
+	// is cancelled, i.e. set to the ApplicationService's AppContext(). Defaults to context.Background() if nil.
+	Context context.Context
+}
+
+// kafkaProducer produces messages to Kafka, i.e. a kafka.Writer
+type kafkaProducer interface {
+	WriteMessages(ctx context.Context, messages ...kafka.Message) error
+	Close() error
+}
+
+// KafkaSender produces the pipeline data as Kafka messages. The producer is reused for all messages and is
+// re-created when the secrets it uses have been updated.
+type KafkaSender struct {
+	options KafkaSenderOptions
+	// producerLock is held for reading by each send while it uses the producer, so the producer is only closed,
+	// when re-created or by Close, once the sends in flight have completed.
+	producerLock           sync.RWMutex
+	producer               kafkaProducer
+	closed                 bool
+	producerSecretsUpdated time.Time
+	newProducer            func(brokers []string, transport *kafka.Transport) kafkaProducer
+	kafkaSizeMetrics       gometrics.Histogram
+	kafkaErrorMetric       gometrics.Counter
+}
+
+// NewKafkaSender creates, initializes and returns a new instance of KafkaSender
+func NewKafkaSender(options KafkaSenderOptions) (*KafkaSender, error) {
+	if len(options.Brokers) == 0 || len(options.Topic) == 0 {
+		return nil, errors.New("brokers and topic must be specified")
+	}
+
+	options.SASLMechanism = strings.ToLower(options.SASLMechanism)
+	switch options.SASLMechanism {
+	case "":
+	case KafkaSASLPlain, KafkaSASLScramSHA256, KafkaSASLScramSHA512:
+		if len(options.SASLSecretName) == 0 || len(options.SASLUsernameKey) == 0 || len(options.SASLPasswordKey) == 0 {
+			return nil, errors.New("SASLSecretName, SASLUsernameKey & SASLPasswordKey must be specified when SASLMechanism is specified")
+		}
+	default:
+		return nil, fmt.Errorf("invalid SASL mechanism '%s', must be '%s', '%s' or '%s'",
+			options.SASLMechanism, KafkaSASLPlain, KafkaSASLScramSHA256, KafkaSASLScramSHA512)
+	}
+
+	if (len(options.ClientCertKey) > 0 || len(options.ClientKeyKey) > 0 || len(options.CACertKey) > 0) && len(options.TLSSecretName) == 0 {
+		return nil, errors.New("TLSSecretName must be specified when ClientCertKey, ClientKeyKey or CACertKey are specified")
+	}
+
+	if (len(options.ClientCertKey) > 0) != (len(options.ClientKeyKey) > 0) {
+		return nil, errors.New("ClientCertKey & ClientKeyKey must both be specified for mutual TLS")
+	}
+
+	if options.PersistOnError && options.ContinueOnSendError {
+		return nil, errors.New("persistOnError & continueOnSendError can not both be set to true")
+	}
+
+	if options.ContinueOnSendError && !options.ReturnInputData {
+		return nil, errors.New("continueOnSendError can only be used in conjunction returnInputData")
+	}
+
+	return &KafkaSender{
+		options:          options,
+		newProducer:      newKafkaWriter,
+		kafkaErrorMetric: gometrics.NewCounter(),
+		kafkaSizeMetrics: gometrics.NewHistogram(gometrics.NewUniformSample(internal.MetricsReservoirSize)),
+	}, nil
+}
+
+// newKafkaWriter creates a writer which produces each message as soon as it is written, waiting for all in-sync
+// replicas to acknowledge it, and partitions keyed messages the same as the Java client's default partitioner.
+func newKafkaWriter(brokers []string, transport *kafka.Transport) kafkaProducer {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Balancer:     &kafka.Murmur2Balancer{},
+		RequiredAcks: kafka.RequireAll,
+		BatchSize:    1,
+		Transport:    transport,
+	}
+}
+
+// KafkaSend produces the data from the previous function as a Kafka message to the formatted topic with the
+// formatted key. The input data is passed to the next function when ReturnInputData is set, otherwise there is no
+// data for the next function. If no previous function exists, then the event that triggered the pipeline will be used.
+func (sender *KafkaSender) KafkaSend(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function KafkaSend in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	exportData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	topic, err := sender.options.TopicFormatter.invoke(sender.options.Topic, ctx, data)
+	if err != nil {
+		return false, fmt.Errorf("in pipeline '%s', Kafka topic formatting failed: %s", ctx.PipelineId(), err.Error())
+	}
+
+	message := kafka.Message{Topic: topic, Value: exportData}
+	if len(sender.options.Key) > 0 {
+		key, err := sender.options.TopicFormatter.invoke(sender.options.Key, ctx, data)
+		if err != nil {
+			return false, fmt.Errorf("in pipeline '%s', Kafka key formatting failed: %s", ctx.PipelineId(), err.Error())
+		}
+
+		message.Key = []byte(key)
+	}
+
+	tagValue := fmt.Sprintf("%s/%s", strings.Join(sender.options.Brokers, ","), topic)
+	tag := map[string]string{"brokers/topic": tagValue}
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.KafkaExportErrorsName, tagValue) },
+		func() any { return sender.kafkaErrorMetric },
+		tag)
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.KafkaExportSizeName, tagValue) },
+		func() any { return sender.kafkaSizeMetrics },
+		tag)
+
+	producer, release, err := sender.getProducer(ctx)
+	if err == nil {
+		err = sender.produce(producer, message)
+		release()
+	}
+
+	if err != nil {
+		sender.kafkaErrorMetric.Inc(1)
+		err = fmt.Errorf("in pipeline '%s', Kafka export to topic '%s' failed: %w", ctx.PipelineId(), topic, err)
+
+		// If continuing on send error then can't be persisting on error since Store and Forward retries starting
+		// with the function that failed and stopped the execution of the pipeline.
+		if !sender.options.ContinueOnSendError {
+			if sender.options.PersistOnError {
+				ctx.SetRetryData(exportData)
+			}
+			return false, err
+		}
+
+		ctx.LoggingClient().Errorf("Continuing pipeline on error in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		return true, data
+	}
+
+	// Data successfully sent, so retry any failed data, if Store and Forward enabled and data has been saved
+	if sender.options.PersistOnError {
+		ctx.TriggerRetryFailedData()
+	}
+
+	sender.kafkaSizeMetrics.Update(int64(len(exportData)))
+
+	ctx.LoggingClient().Debugf("Sent %d bytes of data to Kafka topic '%s' in pipeline '%s'", len(exportData), topic, ctx.PipelineId())
+	ctx.LoggingClient().Tracef("Data exported to Kafka in pipeline '%s': %s=%s", ctx.PipelineId(), coreCommon.CorrelationHeader, ctx.CorrelationID())
+
+	if sender.options.ReturnInputData {
+		return true, data
+	}
+
+	return true, nil
+}
+
+// produce writes the message with the configured timeout, waiting for it to be acknowledged
+func (sender *KafkaSender) produce(producer kafkaProducer, message kafka.Message) error {
+	produceContext := sender.options.Context
+	if produceContext == nil {
+		produceContext = context.Background()
+	}
+
+	if sender.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		produceContext, cancel = context.WithTimeout(produceContext, sender.options.Timeout)
+		defer cancel()
+	}
+
+	return producer.WriteMessages(produceContext, message)
+}
+
+// Close closes the producer once the sends in flight have completed. Sends after Close fail.
+func (sender *KafkaSender) Close() error {
+	sender.producerLock.Lock()
+	defer sender.producerLock.Unlock()
+
+	sender.closed = true
+	if sender.producer == nil {
+		return nil
+	}
+
+	err := sender.producer.Close()
+	sender.producer = nil
+	return err
+}
+
+// getProducer returns the producer, creating it on first use, with the read lock held so it isn't closed while in
+// use. The caller must call the returned release function once done with the producer.
+func (sender *KafkaSender) getProducer(ctx interfaces.AppFunctionContext) (kafkaProducer, func(), error) {
+	sender.producerLock.RLock()
+	if sender.isProducerStale(ctx) {
+		sender.producerLock.RUnlock()
+		if err := sender.createProducer(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		sender.producerLock.RLock()
+	}
+
+	// The sender may have been closed while the producer was being created
+	if sender.producer == nil {
+		sender.producerLock.RUnlock()
+		return nil, nil, errors.New("sender is closed")
+	}
+
+	return sender.producer, sender.producerLock.RUnlock, nil
+}
+
+// isProducerStale returns true if the producer needs to be created, i.e. on first use or if TLS or SASL secrets are
+// in use and the secrets have been updated since it was created. The lock must be held by the caller.
+func (sender *KafkaSender) isProducerStale(ctx interfaces.AppFunctionContext) bool {
+	return sender.producer == nil ||
+		(sender.usingSecrets() && sender.producerSecretsUpdated.Before(ctx.SecretProvider().SecretsLastUpdated()))
+}
+
+func (sender *KafkaSender) usingSecrets() bool {
+	return (sender.options.UseTLS && len(sender.options.TLSSecretName) > 0) || len(sender.options.SASLMechanism) > 0
+}
+
+// createProducer creates the producer, closing the one it replaces once the sends using it have completed,
+// unless another send has already re-created it.
+func (sender *KafkaSender) createProducer(ctx interfaces.AppFunctionContext) error {
+	sender.producerLock.Lock()
+	defer sender.producerLock.Unlock()
+
+	if sender.closed {
+		return errors.New("sender is closed")
+	}
+
+	if !sender.isProducerStale(ctx) {
+		return nil
+	}
+
+	transport := &kafka.Transport{}
+	if sender.options.UseTLS {
+		tlsConfig, err := loadTLSConfig(ctx, sender.options.TLSSecretName, sender.options.ClientCertKey, sender.options.ClientKeyKey, sender.options.CACertKey)
+		if err != nil {
+			return err
+		}
+
+		transport.TLS = tlsConfig
+	}
+
+	if len(sender.options.SASLMechanism) > 0 {
+		mechanism, err := sender.loadSASLMechanism(ctx)
+		if err != nil {
+			return err
+		}
+
+		transport.SASL = mechanism
+	}
+
+	if sender.producer != nil {
+		_ = sender.producer.Close()
+	}
+
+	ctx.LoggingClient().Infof("Creating Kafka producer for brokers '%s' in pipeline '%s'", strings.Join(sender.options.Brokers, ","), ctx.PipelineId())
+
+	sender.producer = sender.newProducer(sender.options.Brokers, transport)
+	if sender.usingSecrets() {
+		sender.producerSecretsUpdated = time.Now()
+	}
+
+	return nil
+}
+
+// loadSASLMechanism creates the SASL mechanism with the credentials from the SecretStore
+func (sender *KafkaSender) loadSASLMechanism(ctx interfaces.AppFunctionContext) (sasl.Mechanism, error) {
+	secrets, err := ctx.SecretProvider().GetSecret(sender.options.SASLSecretName, sender.options.SASLUsernameKey, sender.options.SASLPasswordKey)
+	if err != nil {
+		return nil, fmt.Errorf("in pipeline '%s', unable to retrieve Kafka SASL credentials from secret '%s': %s",
+			ctx.PipelineId(), sender.options.SASLSecretName, err.Error())
+	}
+
+	username := secrets[sender.options.SASLUsernameKey]
+	password := secrets[sender.options.SASLPasswordKey]
+
+	switch sender.options.SASLMechanism {
+	case KafkaSASLScramSHA256:
+		return scram.Mechanism(scram.SHA256, username, password)
+	case KafkaSASLScramSHA512:
+		return scram.Mechanism(scram.SHA512, username, password)
+	default:
+		return plain.Mechanism{Username: username, Password: password}, nil
+	}
+}
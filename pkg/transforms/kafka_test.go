@@ -0,0 +1,379 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// mockKafkaProducer records the messages produced, failing with err if set. When release is set, writes signal
+// started and then block until release is closed.
+type mockKafkaProducer struct {
+	messages []kafka.Message
+	err      error
+	closed   atomic.Bool
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func (producer *mockKafkaProducer) WriteMessages(ctx context.Context, messages ...kafka.Message) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if producer.closed.Load() {
+		return errors.New("writer closed")
+	}
+
+	if producer.release != nil {
+		producer.started <- struct{}{}
+		<-producer.release
+	}
+
+	if producer.err != nil {
+		return producer.err
+	}
+
+	producer.messages = append(producer.messages, messages...)
+	return nil
+}
+
+func (producer *mockKafkaProducer) Close() error {
+	producer.closed.Store(true)
+	return nil
+}
+
+// newMockKafkaSender creates a KafkaSender whose producers are mocks, returning the transports they're created with
+func newMockKafkaSender(t *testing.T, options KafkaSenderOptions, err error) (*KafkaSender, *[]*mockKafkaProducer, *[]*kafka.Transport) {
+	sender, senderErr := NewKafkaSender(options)
+	require.NoError(t, senderErr)
+
+	var producers []*mockKafkaProducer
+	var transports []*kafka.Transport
+	sender.newProducer = func(brokers []string, transport *kafka.Transport) kafkaProducer {
+		assert.Equal(t, options.Brokers, brokers)
+		producer := &mockKafkaProducer{err: err}
+		producers = append(producers, producer)
+		transports = append(transports, transport)
+		return producer
+	}
+
+	return sender, &producers, &transports
+}
+
+func TestNewKafkaSender(t *testing.T) {
+	brokers := []string{"kafka:9092"}
+
+	tests := []struct {
+		Name        string
+		Options     KafkaSenderOptions
+		ExpectError bool
+	}{
+		{"Valid", KafkaSenderOptions{Brokers: brokers, Topic: "edgex"}, false},
+		{"Valid SASL", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", SASLMechanism: "SCRAM-SHA-512", SASLSecretName: "kafka", SASLUsernameKey: "username", SASLPasswordKey: "password"}, false},
+		{"Valid TLS", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", UseTLS: true, TLSSecretName: "tls", CACertKey: "ca"}, false},
+		{"Missing brokers", KafkaSenderOptions{Topic: "edgex"}, true},
+		{"Missing topic", KafkaSenderOptions{Brokers: brokers}, true},
+		{"Invalid SASL mechanism", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", SASLMechanism: "gssapi", SASLSecretName: "kafka", SASLUsernameKey: "username", SASLPasswordKey: "password"}, true},
+		{"Missing SASL secret", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", SASLMechanism: KafkaSASLPlain, SASLUsernameKey: "username", SASLPasswordKey: "password"}, true},
+		{"Missing SASL password key", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", SASLMechanism: KafkaSASLPlain, SASLSecretName: "kafka", SASLUsernameKey: "username"}, true},
+		{"Missing TLS secret name", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", UseTLS: true, CACertKey: "ca"}, true},
+		{"Missing client key", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", UseTLS: true, TLSSecretName: "tls", ClientCertKey: "cert"}, true},
+		{"Persist and continue", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", PersistOnError: true, ContinueOnSendError: true, ReturnInputData: true}, true},
+		{"Continue without return input", KafkaSenderOptions{Brokers: brokers, Topic: "edgex", ContinueOnSendError: true}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender, err := NewKafkaSender(test.Options)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, sender)
+		})
+	}
+}
+
+func TestKafkaSend(t *testing.T) {
+	sender, producers, transports := newMockKafkaSender(t, KafkaSenderOptions{
+		Brokers:         []string{"kafka-1:9092", "kafka-2:9092"},
+		Topic:           "edgex.{devicename}",
+		Key:             "{devicename}",
+		ReturnInputData: true,
+	}, nil)
+
+	sendCtx := ctx.Clone()
+	sendCtx.AddValue(interfaces.DEVICENAME, "sensor-1")
+
+	for i := 1; i <= 3; i++ {
+		continuePipeline, result := sender.KafkaSend(sendCtx, msgStr)
+		require.True(t, continuePipeline, result)
+		assert.Equal(t, msgStr, result)
+	}
+
+	// The producer is reused
+	require.Len(t, *producers, 1)
+	assert.Nil(t, (*transports)[0].TLS)
+	assert.Nil(t, (*transports)[0].SASL)
+
+	messages := (*producers)[0].messages
+	require.Len(t, messages, 3)
+	for _, message := range messages {
+		assert.Equal(t, "edgex.sensor-1", message.Topic)
+		assert.Equal(t, []byte("sensor-1"), message.Key)
+		assert.Equal(t, []byte(msgStr), message.Value)
+	}
+	assert.Equal(t, int64(3), sender.kafkaSizeMetrics.Count())
+}
+
+func TestKafkaSendWithoutKey(t *testing.T) {
+	sender, producers, _ := newMockKafkaSender(t, KafkaSenderOptions{
+		Brokers: []string{"kafka:9092"},
+		Topic:   "edgex",
+	}, nil)
+
+	continuePipeline, result := sender.KafkaSend(ctx, []byte(msgStr))
+	require.True(t, continuePipeline, result)
+	assert.Nil(t, result)
+
+	require.Len(t, (*producers)[0].messages, 1)
+	message := (*producers)[0].messages[0]
+	assert.Equal(t, "edgex", message.Topic)
+	assert.Nil(t, message.Key)
+	assert.Equal(t, []byte(msgStr), message.Value)
+}
+
+func TestKafkaSendWithSASL(t *testing.T) {
+	secretsUpdated := time.Now().Add(-time.Hour)
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "kafka", "username", "password").Return(map[string]string{"username": "edgex", "password": "secret"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(func() time.Time { return secretsUpdated })
+
+	saslCtx := appfunction.NewContext("123", di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	}), "")
+
+	tests := []struct {
+		Name              string
+		Mechanism         string
+		ExpectedMechanism string
+	}{
+		{"Plain", KafkaSASLPlain, "PLAIN"},
+		{"SCRAM-SHA-256", KafkaSASLScramSHA256, "SCRAM-SHA-256"},
+		{"SCRAM-SHA-512", "SCRAM-SHA-512", "SCRAM-SHA-512"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sender, producers, transports := newMockKafkaSender(t, KafkaSenderOptions{
+				Brokers:         []string{"kafka:9092"},
+				Topic:           "edgex",
+				SASLMechanism:   test.Mechanism,
+				SASLSecretName:  "kafka",
+				SASLUsernameKey: "username",
+				SASLPasswordKey: "password",
+			}, nil)
+
+			continuePipeline, result := sender.KafkaSend(saslCtx, msgStr)
+			require.True(t, continuePipeline, result)
+			require.Len(t, *transports, 1)
+			require.NotNil(t, (*transports)[0].SASL)
+			assert.Equal(t, test.ExpectedMechanism, (*transports)[0].SASL.Name())
+
+			// The producer is re-created with the new credentials once the secrets have been updated
+			secretsUpdated = time.Now().Add(time.Second)
+			continuePipeline, result = sender.KafkaSend(saslCtx, msgStr)
+			require.True(t, continuePipeline, result)
+			require.Len(t, *producers, 2)
+			assert.True(t, (*producers)[0].closed.Load())
+			assert.Len(t, (*producers)[1].messages, 1)
+			secretsUpdated = time.Now().Add(-time.Hour)
+		})
+	}
+}
+
+func TestKafkaSendProducerRecreatedWhileInFlight(t *testing.T) {
+	secretsUpdated := time.Now().Add(-time.Hour)
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "kafka", "username", "password").Return(map[string]string{"username": "edgex", "password": "secret"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(func() time.Time { return secretsUpdated })
+
+	saslCtx := appfunction.NewContext("123", di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	}), "")
+
+	sender, err := NewKafkaSender(KafkaSenderOptions{
+		Brokers:         []string{"kafka:9092"},
+		Topic:           "edgex",
+		SASLMechanism:   KafkaSASLPlain,
+		SASLSecretName:  "kafka",
+		SASLUsernameKey: "username",
+		SASLPasswordKey: "password",
+	})
+	require.NoError(t, err)
+
+	inFlight := &mockKafkaProducer{started: make(chan struct{}, 1), release: make(chan struct{})}
+	recreated := &mockKafkaProducer{}
+	sender.newProducer = func(brokers []string, transport *kafka.Transport) kafkaProducer {
+		if sender.producer == nil {
+			return inFlight
+		}
+		return recreated
+	}
+
+	wg := sync.WaitGroup{}
+	results := make([]bool, 2)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = sender.KafkaSend(saslCtx, msgStr)
+	}()
+	<-inFlight.started
+
+	// The secrets are updated while the first send is in flight, so the next send re-creates the producer
+	secretsUpdated = time.Now().Add(time.Second)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[1], _ = sender.KafkaSend(saslCtx, msgStr)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, inFlight.closed.Load(), "producer closed while a send was in flight")
+
+	close(inFlight.release)
+	wg.Wait()
+
+	assert.Equal(t, []bool{true, true}, results)
+	assert.True(t, inFlight.closed.Load())
+	assert.Len(t, inFlight.messages, 1)
+	assert.Len(t, recreated.messages, 1)
+}
+
+func TestKafkaSenderClose(t *testing.T) {
+	sender, producers, _ := newMockKafkaSender(t, KafkaSenderOptions{
+		Brokers: []string{"kafka:9092"},
+		Topic:   "edgex",
+	}, nil)
+
+	continuePipeline, result := sender.KafkaSend(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	require.Len(t, *producers, 1)
+
+	require.NoError(t, sender.Close())
+	assert.True(t, (*producers)[0].closed.Load())
+
+	continuePipeline, result = sender.KafkaSend(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "sender is closed")
+	assert.Len(t, *producers, 1)
+
+	// Closing before first use has no producer to close
+	unused, _, _ := newMockKafkaSender(t, KafkaSenderOptions{Brokers: []string{"kafka:9092"}, Topic: "edgex"}, nil)
+	assert.NoError(t, unused.Close())
+}
+
+func TestKafkaSendErrors(t *testing.T) {
+	produceErr := errors.New("leader not available")
+
+	tests := []struct {
+		Name                 string
+		Options              KafkaSenderOptions
+		Data                 interface{}
+		ExpectedContinue     bool
+		ExpectedRetryData    bool
+		ExpectedErrorMessage string
+	}{
+		{"Delivery failed", KafkaSenderOptions{}, msgStr, false, false, "leader not available"},
+		{"Delivery failed persisted", KafkaSenderOptions{PersistOnError: true}, msgStr, false, true, "leader not available"},
+		{"Delivery failed continued", KafkaSenderOptions{ContinueOnSendError: true, ReturnInputData: true}, msgStr, true, false, ""},
+		{"Missing topic value", KafkaSenderOptions{Topic: "edgex.{bogus}"}, msgStr, false, false, "Kafka topic formatting failed"},
+		{"Missing key value", KafkaSenderOptions{Key: "{bogus}"}, msgStr, false, false, "Kafka key formatting failed"},
+		{"No data", KafkaSenderOptions{}, nil, false, false, "No Data Received"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.SetRetryData(nil)
+
+			test.Options.Brokers = []string{"kafka:9092"}
+			if len(test.Options.Topic) == 0 {
+				test.Options.Topic = "edgex"
+			}
+			sender, _, _ := newMockKafkaSender(t, test.Options, produceErr)
+
+			continuePipeline, result := sender.KafkaSend(ctx, test.Data)
+			require.Equal(t, test.ExpectedContinue, continuePipeline)
+			assert.Equal(t, test.ExpectedRetryData, ctx.RetryData() != nil)
+			if test.ExpectedContinue {
+				assert.Equal(t, test.Data, result)
+				assert.Equal(t, int64(1), sender.kafkaErrorMetric.Count())
+				return
+			}
+
+			assert.Contains(t, result.(error).Error(), test.ExpectedErrorMessage)
+		})
+	}
+
+	ctx.SetRetryData(nil)
+}
+
+func TestKafkaSendUnreachableBroker(t *testing.T) {
+	ctx.SetRetryData(nil)
+	defer ctx.SetRetryData(nil)
+
+	sender, err := NewKafkaSender(KafkaSenderOptions{
+		Brokers:        []string{"127.0.0.1:1"},
+		Topic:          "edgex",
+		Timeout:        200 * time.Millisecond,
+		PersistOnError: true,
+	})
+	require.NoError(t, err)
+
+	continuePipeline, result := sender.KafkaSend(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "Kafka export to topic 'edgex' failed")
+	assert.Equal(t, []byte(msgStr), ctx.RetryData())
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	gometrics "github.com/rcrowley/go-metrics"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// MetricEmitter surfaces numeric reading values as service metrics so they are reported along with the SDK's metrics
+type MetricEmitter struct {
+	metricNames map[string]string
+	gauges      map[string]gometrics.GaugeFloat64
+	lock        sync.Mutex
+}
+
+// NewMetricEmitter creates, initializes and returns a new instance of MetricEmitter which reports the values of
+// the readings for the resources in metricNames, a map of resource name to metric name, as gauge metrics.
+// The metric names must be unique across the service.
+func NewMetricEmitter(metricNames map[string]string) (*MetricEmitter, error) {
+	if len(metricNames) == 0 {
+		return nil, errors.New("at least one resource name to metric name mapping must be specified")
+	}
+
+	for resourceName, metricName := range metricNames {
+		if len(resourceName) == 0 {
+			return nil, errors.New("resource name can not be empty")
+		}
+
+		if err := dtos.ValidateMetricName(metricName, "Metric"); err != nil {
+			return nil, fmt.Errorf("invalid metric name for resource '%s': %s", resourceName, err.Error())
+		}
+	}
+
+	return &MetricEmitter{
+		metricNames: metricNames,
+		gauges:      make(map[string]gometrics.GaugeFloat64),
+	}, nil
+}
+
+// EmitMetrics updates the gauge metric for each numeric reading of the mapped resources with the reading's value.
+// The gauge is created and registered when the first reading for the resource is received. The gauge holds the
+// latest value received, regardless of which device the reading is from. Non-numeric and unmapped readings are
+// ignored and the Event is always passed on unchanged.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (emitter *MetricEmitter) EmitMetrics(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function EmitMetrics in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function EmitMetrics in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	for _, reading := range event.Readings {
+		metricName, found := emitter.metricNames[reading.ResourceName]
+		if !found {
+			continue
+		}
+
+		value, ok := numericReadingValue(reading)
+		if !ok {
+			continue
+		}
+
+		emitter.gauge(ctx, metricName).Update(value)
+	}
+
+	return true, event
+}
+
+// gauge returns the gauge for the metric, creating and registering it if it doesn't exist yet
+func (emitter *MetricEmitter) gauge(ctx interfaces.AppFunctionContext, metricName string) gometrics.GaugeFloat64 {
+	emitter.lock.Lock()
+	defer emitter.lock.Unlock()
+
+	gauge, found := emitter.gauges[metricName]
+	if !found {
+		gauge = gometrics.NewGaugeFloat64()
+		emitter.gauges[metricName] = gauge
+	}
+
+	registerMetric(ctx,
+		func() string { return metricName },
+		func() any { return gauge },
+		map[string]string{"pipeline": ctx.PipelineId()})
+
+	return gauge
+}
@@ -0,0 +1,113 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	gometrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+)
+
+func TestNewMetricEmitter(t *testing.T) {
+	_, err := NewMetricEmitter(map[string]string{"temperature": "room_temperature"})
+	require.NoError(t, err)
+
+	_, err = NewMetricEmitter(nil)
+	require.Error(t, err)
+
+	_, err = NewMetricEmitter(map[string]string{"": "room_temperature"})
+	require.Error(t, err)
+
+	_, err = NewMetricEmitter(map[string]string{"temperature": " "})
+	require.Error(t, err)
+}
+
+func TestMetricEmitterEmitMetrics(t *testing.T) {
+	registered := make(map[string]any)
+	mockMetricsMgr := &mocks2.MetricsManager{}
+	mockMetricsMgr.On("IsRegistered", mock.Anything).Return(func(name string) bool {
+		_, found := registered[name]
+		return found
+	})
+	mockMetricsMgr.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		registered[args.String(0)] = args.Get(1)
+	})
+
+	metricsCtx := appfunction.NewContext("123", di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.MetricsManagerInterfaceName: func(get di.Get) interface{} {
+			return mockMetricsMgr
+		},
+	}), "")
+
+	emitter, err := NewMetricEmitter(map[string]string{
+		"temperature": "room_temperature",
+		"humidity":    "room_humidity",
+		"status":      "room_status",
+	})
+	require.NoError(t, err)
+
+	event := dtos.NewEvent("profile1", deviceName1, "source1")
+	require.NoError(t, event.AddSimpleReading("temperature", common.ValueTypeFloat64, 21.5))
+	require.NoError(t, event.AddSimpleReading("status", common.ValueTypeString, "ok"))
+	require.NoError(t, event.AddSimpleReading("pressure", common.ValueTypeInt32, int32(1013)))
+
+	continuePipeline, result := emitter.EmitMetrics(metricsCtx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, event, result)
+
+	require.Contains(t, registered, "room_temperature")
+	assert.Equal(t, 21.5, registered["room_temperature"].(gometrics.GaugeFloat64).Value())
+	assert.NotContains(t, registered, "room_status", "non-numeric reading should be ignored")
+	assert.NotContains(t, registered, "room_humidity", "gauge should only be created when a reading is received")
+	assert.Len(t, registered, 1)
+
+	event = dtos.NewEvent("profile1", deviceName1, "source1")
+	require.NoError(t, event.AddSimpleReading("temperature", common.ValueTypeInt16, int16(-4)))
+	require.NoError(t, event.AddSimpleReading("humidity", common.ValueTypeUint8, uint8(45)))
+
+	continuePipeline, _ = emitter.EmitMetrics(metricsCtx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, float64(-4), registered["room_temperature"].(gometrics.GaugeFloat64).Value())
+	assert.Equal(t, float64(45), registered["room_humidity"].(gometrics.GaugeFloat64).Value())
+	mockMetricsMgr.AssertNumberOfCalls(t, "Register", 2)
+}
+
+func TestMetricEmitterEmitMetricsInvalidData(t *testing.T) {
+	emitter, err := NewMetricEmitter(map[string]string{"temperature": "room_temperature"})
+	require.NoError(t, err)
+
+	continuePipeline, result := emitter.EmitMetrics(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = emitter.EmitMetrics(ctx, "not an event")
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
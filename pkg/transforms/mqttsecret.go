@@ -48,6 +48,8 @@ type MQTTSecretSender struct {
 	mqttSizeMetrics      gometrics.Histogram
 	mqttErrorMetric      gometrics.Counter
 	preConnected         bool
+	// configErr is the error found validating the MQTTSecretConfig, which is returned when sending
+	configErr error
 }
 
 // MQTTSecretConfig ...
@@ -68,9 +70,9 @@ type MQTTSecretConfig struct {
 	MaxReconnectInterval string
 	// Topic that you wish to publish to
 	Topic string
-	// QoS for MQTT Connection
+	// QoS used when publishing messages. Must be 0, 1 or 2.
 	QoS byte
-	// Retain setting for MQTT Connection
+	// Retain indicates whether the broker should retain the published messages
 	Retain bool
 	// SkipCertVerify
 	SkipCertVerify bool
@@ -81,7 +83,8 @@ type MQTTSecretConfig struct {
 	Will common.WillConfig
 }
 
-// NewMQTTSecretSender ...
+// NewMQTTSecretSender creates, initializes and returns a new instance of MQTTSecretSender. If the QoS in the
+// mqttConfig is invalid, MQTTSend returns an error and stops the pipeline.
 func NewMQTTSecretSender(mqttConfig MQTTSecretConfig, persistOnError bool) *MQTTSecretSender {
 	opts := MQTT.NewClientOptions()
 
@@ -102,6 +105,10 @@ func NewMQTTSecretSender(mqttConfig MQTTSecretConfig, persistOnError bool) *MQTT
 	opts.OnReconnecting = sender.onReconnecting
 	sender.opts = opts
 
+	if mqttConfig.QoS > 2 {
+		sender.configErr = fmt.Errorf("invalid MQTT Export QoS value of '%d', must be 0, 1 or 2", mqttConfig.QoS)
+	}
+
 	sender.mqttErrorMetric = gometrics.NewCounter()
 	sender.mqttSizeMetrics = gometrics.NewHistogram(gometrics.NewUniformSample(internal.MetricsReservoirSize))
 
@@ -228,6 +235,10 @@ func (sender *MQTTSecretSender) MQTTSend(ctx interfaces.AppFunctionContext, data
 		return false, fmt.Errorf("function MQTTSend in pipeline '%s': No Data Received", ctx.PipelineId())
 	}
 
+	if sender.configErr != nil {
+		return false, fmt.Errorf("in pipeline '%s', %s", ctx.PipelineId(), sender.configErr.Error())
+	}
+
 	exportData, err := util.CoerceType(data)
 	if err != nil {
 		return false, err
@@ -21,9 +21,17 @@ package transforms
 
 import (
 	"testing"
+	"time"
 
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+
+	mqttMocks "github.com/edgexfoundry/app-functions-sdk-go/v3/internal/trigger/mqtt/mocks"
 )
 
 func TestMQTTSecretSender_setRetryDataPersistFalse(t *testing.T) {
@@ -49,3 +57,109 @@ func TestMQTTSecretSender_MQTTSendNodata(t *testing.T) {
 	require.False(t, continuePipeline)
 	require.Error(t, result.(error))
 }
+
+func TestNewMQTTSecretSenderInvalidQoS(t *testing.T) {
+	sender := NewMQTTSecretSender(MQTTSecretConfig{QoS: 3}, false)
+
+	continuePipeline, result := sender.MQTTSend(ctx, []byte("data"))
+	require.False(t, continuePipeline)
+	require.Error(t, result.(error))
+	assert.Contains(t, result.(error).Error(), "invalid MQTT Export QoS value of '3'")
+}
+
+func TestMQTTSecretSender_MQTTSendQoSAndRetain(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	tests := []struct {
+		Name   string
+		QoS    byte
+		Retain bool
+	}{
+		{"QoS 0 no retain", 0, false},
+		{"QoS 1 with retain", 1, true},
+		{"QoS 2 no retain", 2, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			token := &mqttMocks.Token{}
+			token.On("Wait").Return(true)
+			token.On("Error").Return(nil)
+
+			client := &mqttMocks.Client{}
+			client.On("IsConnected").Return(true)
+			client.On("IsConnectionOpen").Return(true)
+			client.On("Publish", "topic", mock.Anything, mock.Anything, []byte("data")).Return(token)
+
+			sender := NewMQTTSecretSender(MQTTSecretConfig{Topic: "topic", QoS: test.QoS, Retain: test.Retain}, false)
+			sender.client = client
+			sender.secretsLastRetrieved = time.Now()
+
+			continuePipeline, result := sender.MQTTSend(ctx, []byte("data"))
+			require.True(t, continuePipeline, result)
+
+			client.AssertNumberOfCalls(t, "Publish", 1)
+			publishCall := client.Calls[len(client.Calls)-1]
+			assert.Equal(t, test.QoS, publishCall.Arguments.Get(1))
+			assert.Equal(t, test.Retain, publishCall.Arguments.Get(2))
+		})
+	}
+}
+
+func TestMQTTSecretSender_MQTTSendTopicFormatting(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	event := dtos.Event{DeviceName: "thermostat-1", SourceName: "source"}
+
+	tests := []struct {
+		Name          string
+		Topic         string
+		ExpectedTopic string
+		ErrorContains string
+	}{
+		{"device name substituted", "sensors/{deviceName}/data", "sensors/thermostat-1/data", ""},
+		{"missing key", "sensors/{deviceName}/{bogus}", "", "unable to resolve placeholders {bogus}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			token := &mqttMocks.Token{}
+			token.On("Wait").Return(true)
+			token.On("Error").Return(nil)
+
+			client := &mqttMocks.Client{}
+			client.On("IsConnected").Return(true)
+			client.On("IsConnectionOpen").Return(true)
+			client.On("Publish", test.ExpectedTopic, mock.Anything, mock.Anything, mock.Anything).Return(token)
+
+			sender := NewMQTTSecretSenderWithTopicFormatter(MQTTSecretConfig{Topic: test.Topic}, false, EventValuesFormatter)
+			sender.client = client
+			sender.secretsLastRetrieved = time.Now()
+
+			continuePipeline, result := sender.MQTTSend(ctx, event)
+
+			if len(test.ErrorContains) > 0 {
+				require.False(t, continuePipeline)
+				require.Error(t, result.(error))
+				assert.Contains(t, result.(error).Error(), test.ErrorContains)
+				client.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			require.True(t, continuePipeline, result)
+			client.AssertCalled(t, "Publish", test.ExpectedTopic, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
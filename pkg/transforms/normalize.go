@@ -0,0 +1,117 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+)
+
+// ReadingTypeNormalizer converts an Event to JSON in which the values of the numeric readings are JSON numbers
+// rather than the quoted strings EdgeX encodes all simple reading values as.
+type ReadingTypeNormalizer struct {
+}
+
+// NewReadingTypeNormalizer creates, initializes and returns a new instance of ReadingTypeNormalizer
+func NewReadingTypeNormalizer() *ReadingTypeNormalizer {
+	return &ReadingTypeNormalizer{}
+}
+
+// NormalizeReadingTypes converts the Event received to JSON with the value of each Int, Uint and Float reading
+// written as a JSON number based on the reading's declared value type. Readings of all other value types, and
+// numeric readings whose value can't be parsed or isn't a finite number, keep their string values.
+// It will return an error and stop the pipeline if a non-Event is received or if no data is received.
+func (n *ReadingTypeNormalizer) NormalizeReadingTypes(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function NormalizeReadingTypes in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Normalizing reading types in pipeline '%s'", ctx.PipelineId())
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function NormalizeReadingTypes in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	result, err := n.normalize(event)
+	if err != nil {
+		return false, fmt.Errorf("unable to normalize reading types in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.SetResponseContentType(common.ContentTypeJSON)
+	return true, result
+}
+
+// normalize returns the JSON of the Event with the numeric reading values replaced by numbers
+func (n *ReadingTypeNormalizer) normalize(event dtos.Event) (string, error) {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	var eventMap map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(eventJSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&eventMap); err != nil {
+		return "", err
+	}
+
+	readings, _ := eventMap["readings"].([]interface{})
+	for index, reading := range readings {
+		readingMap, ok := reading.(map[string]interface{})
+		if !ok || index >= len(event.Readings) {
+			continue
+		}
+
+		if value, ok := typedReadingValue(event.Readings[index]); ok {
+			readingMap["value"] = value
+		}
+	}
+
+	result, err := json.Marshal(eventMap)
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// typedReadingValue returns the reading's string value parsed according to its numeric value type. false is
+// returned for non-numeric value types and for values that can't be represented as a JSON number.
+func typedReadingValue(reading dtos.BaseReading) (interface{}, bool) {
+	switch reading.ValueType {
+	case common.ValueTypeInt8, common.ValueTypeInt16, common.ValueTypeInt32, common.ValueTypeInt64:
+		value, err := strconv.ParseInt(reading.Value, 10, 64)
+		return value, err == nil
+	case common.ValueTypeUint8, common.ValueTypeUint16, common.ValueTypeUint32, common.ValueTypeUint64:
+		value, err := strconv.ParseUint(reading.Value, 10, 64)
+		return value, err == nil
+	case common.ValueTypeFloat32, common.ValueTypeFloat64:
+		value, err := strconv.ParseFloat(reading.Value, 64)
+		return value, err == nil && !math.IsNaN(value) && !math.IsInf(value, 0)
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeReadingTypes(t *testing.T) {
+	tests := []struct {
+		Name          string
+		ValueType     string
+		Value         interface{}
+		ExpectedValue interface{}
+	}{
+		{"Int8", common.ValueTypeInt8, int8(-8), json.Number("-8")},
+		{"Int64", common.ValueTypeInt64, int64(math.MinInt64), json.Number("-9223372036854775808")},
+		{"Uint16", common.ValueTypeUint16, uint16(16), json.Number("16")},
+		{"Uint64", common.ValueTypeUint64, uint64(math.MaxUint64), json.Number("18446744073709551615")},
+		{"Float32", common.ValueTypeFloat32, float32(1.5), json.Number("1.5")},
+		{"Float64", common.ValueTypeFloat64, 21.25, json.Number("21.25")},
+		{"Float64 NaN", common.ValueTypeFloat64, math.NaN(), "NaN"},
+		{"Bool", common.ValueTypeBool, true, "true"},
+		{"String", common.ValueTypeString, "42", "42"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+			require.NoError(t, event.AddSimpleReading("reading", test.ValueType, test.Value))
+
+			continuePipeline, result := NewReadingTypeNormalizer().NormalizeReadingTypes(ctx, event)
+			require.True(t, continuePipeline)
+			require.IsType(t, "", result)
+			assert.Equal(t, common.ContentTypeJSON, ctx.ResponseContentType())
+
+			var actual map[string]interface{}
+			decoder := json.NewDecoder(strings.NewReader(result.(string)))
+			decoder.UseNumber()
+			require.NoError(t, decoder.Decode(&actual))
+
+			readings := actual["readings"].([]interface{})
+			require.Len(t, readings, 1)
+			reading := readings[0].(map[string]interface{})
+			assert.Equal(t, test.ExpectedValue, reading["value"])
+			assert.Equal(t, test.ValueType, reading["valueType"])
+			assert.Equal(t, deviceName1, actual["deviceName"])
+		})
+	}
+}
+
+func TestNormalizeReadingTypesInvalidValue(t *testing.T) {
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	event.Readings = append(event.Readings, dtos.BaseReading{
+		ResourceName: "count",
+		ValueType:    common.ValueTypeInt32,
+		SimpleReading: dtos.SimpleReading{
+			Value: "not a number",
+		},
+	})
+
+	continuePipeline, result := NewReadingTypeNormalizer().NormalizeReadingTypes(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Contains(t, result, `"value":"not a number"`)
+}
+
+func TestNormalizeReadingTypesBadData(t *testing.T) {
+	normalizer := NewReadingTypeNormalizer()
+
+	continuePipeline, result := normalizer.NormalizeReadingTypes(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = normalizer.NormalizeReadingTypes(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// oauth2TokenExpiryDelta is how long before the actual expiry a cached access token is considered expired,
+// so that it is refreshed before requests start being rejected.
+const oauth2TokenExpiryDelta = 10 * time.Second
+
+// oauth2ClientCredentials obtains and caches access tokens using the OAuth2 client credentials grant.
+// The client secret is retrieved from the SecretStore.
+type oauth2ClientCredentials struct {
+	tokenURL       string
+	clientID       string
+	secretName     string
+	secretValueKey string
+	scopes         []string
+	lock           sync.Mutex
+	accessToken    string
+	expiry         time.Time
+	retrieved      time.Time
+}
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func (oauth *oauth2ClientCredentials) validate() error {
+	if len(oauth.clientID) == 0 || len(oauth.secretName) == 0 || len(oauth.secretValueKey) == 0 {
+		return errors.New("OAuth2ClientID, OAuth2SecretName & OAuth2ClientSecretKey must be specified when OAuth2TokenURL is specified")
+	}
+
+	return nil
+}
+
+// token returns the cached access token, fetching a new one if there isn't one, it is about to expire or
+// the secrets have been updated since it was fetched.
+func (oauth *oauth2ClientCredentials) token(ctx interfaces.AppFunctionContext, client *http.Client, requestContext context.Context) (string, error) {
+	oauth.lock.Lock()
+	defer oauth.lock.Unlock()
+
+	if len(oauth.accessToken) > 0 &&
+		(oauth.expiry.IsZero() || time.Now().Add(oauth2TokenExpiryDelta).Before(oauth.expiry)) &&
+		!oauth.retrieved.Before(ctx.SecretProvider().SecretsLastUpdated()) {
+		return oauth.accessToken, nil
+	}
+
+	ctx.LoggingClient().Debugf("Fetching OAuth2 access token from %s in pipeline '%s'", oauth.tokenURL, ctx.PipelineId())
+
+	secrets, err := ctx.SecretProvider().GetSecret(oauth.secretName, oauth.secretValueKey)
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve OAuth2 client secret '%s': %w", oauth.secretName, err)
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(oauth.scopes) > 0 {
+		form.Set("scope", strings.Join(oauth.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(requestContext, http.MethodPost, oauth.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", ContentTypeFormURLEncoded)
+	req.SetBasicAuth(url.QueryEscape(oauth.clientID), url.QueryEscape(secrets[oauth.secretValueKey]))
+
+	retrieved := time.Now()
+	response, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch OAuth2 access token: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read OAuth2 token response: %w", err)
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return "", fmt.Errorf("unable to fetch OAuth2 access token, token endpoint returned %d HTTP status code", response.StatusCode)
+	}
+
+	tokenResponse := oauth2TokenResponse{}
+	if err := json.Unmarshal(body, &tokenResponse); err != nil {
+		return "", fmt.Errorf("unable to parse OAuth2 token response: %w", err)
+	}
+
+	if len(tokenResponse.AccessToken) == 0 {
+		return "", errors.New("OAuth2 token response did not contain an access token")
+	}
+
+	oauth.accessToken = tokenResponse.AccessToken
+	oauth.retrieved = retrieved
+	oauth.expiry = time.Time{}
+	if tokenResponse.ExpiresIn > 0 {
+		oauth.expiry = retrieved.Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+	}
+
+	return oauth.accessToken, nil
+}
+
+// invalidate clears the cached access token so a new one is fetched on the next send
+func (oauth *oauth2ClientCredentials) invalidate() {
+	oauth.lock.Lock()
+	defer oauth.lock.Unlock()
+
+	oauth.accessToken = ""
+}
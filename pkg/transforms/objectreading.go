@@ -0,0 +1,199 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// ObjectReadingExploder replaces the Object readings for a resource with a simple reading per top-level field of
+// the object, named as the parent resource and field joined by a '.', i.e. 'location.latitude'.
+type ObjectReadingExploder struct {
+	resourceName string
+}
+
+// NewObjectReadingExploder creates, initializes and returns a new instance of ObjectReadingExploder which explodes
+// the Object readings for the resource name.
+func NewObjectReadingExploder(resourceName string) (*ObjectReadingExploder, error) {
+	if len(resourceName) == 0 {
+		return nil, errors.New("resource name of the Object readings to explode must be specified")
+	}
+
+	return &ObjectReadingExploder{
+		resourceName: resourceName,
+	}, nil
+}
+
+// ExplodeObjectReading replaces each Object reading for the resource with a reading per top-level field, in field
+// name order. The value type is inferred from the field value: booleans are Bool, whole numbers are Int64, other
+// numbers are Float64, strings are String, objects are Object and arrays are ObjectArray when all the elements are
+// objects, otherwise Object. Null fields are dropped. The exploded readings keep the origin and tags of the object
+// reading. Other readings, and Object readings whose value isn't an object, are passed on unchanged.
+// It will return an error and stop the pipeline if a non-Event is received, if no data is received or if the object
+// value can't be parsed.
+func (exploder *ObjectReadingExploder) ExplodeObjectReading(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function ExplodeObjectReading in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function ExplodeObjectReading in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Exploding '%s' Object readings in pipeline '%s'", exploder.resourceName, ctx.PipelineId())
+
+	readings := make([]dtos.BaseReading, 0, len(event.Readings))
+	for _, reading := range event.Readings {
+		if reading.ResourceName != exploder.resourceName || reading.ValueType != common.ValueTypeObject {
+			readings = append(readings, reading)
+			continue
+		}
+
+		object, err := parseObjectValue(reading.ObjectValue)
+		if err != nil {
+			return false, fmt.Errorf("function ExplodeObjectReading in pipeline '%s': unable to parse '%s' Object reading value: %s",
+				ctx.PipelineId(), reading.ResourceName, err.Error())
+		}
+
+		if object == nil {
+			ctx.LoggingClient().Debugf("'%s' Object reading value is not an object, passing it on unchanged in pipeline '%s'",
+				reading.ResourceName, ctx.PipelineId())
+			readings = append(readings, reading)
+			continue
+		}
+
+		exploded, err := explodeObject(reading, object)
+		if err != nil {
+			return false, fmt.Errorf("function ExplodeObjectReading in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		}
+
+		readings = append(readings, exploded...)
+	}
+
+	event.Readings = readings
+	return true, event
+}
+
+// parseObjectValue returns the Object reading value as a JSON object, with numbers kept as json.Number so whole
+// numbers can be distinguished. nil is returned if the value isn't a JSON object.
+func parseObjectValue(value interface{}) (map[string]interface{}, error) {
+	var jsonData []byte
+	switch objectValue := value.(type) {
+	case string:
+		jsonData = []byte(objectValue)
+	case []byte:
+		jsonData = objectValue
+	default:
+		var err error
+		jsonData, err = json.Marshal(objectValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	decoder.UseNumber()
+
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, err
+	}
+
+	object, _ := parsed.(map[string]interface{})
+	return object, nil
+}
+
+// explodeObject creates a reading per field of the object, inferring the value type from the field value
+func explodeObject(parent dtos.BaseReading, object map[string]interface{}) ([]dtos.BaseReading, error) {
+	fields := make([]string, 0, len(object))
+	for field := range object {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	readings := make([]dtos.BaseReading, 0, len(fields))
+	for _, field := range fields {
+		resourceName := parent.ResourceName + "." + field
+
+		var reading dtos.BaseReading
+		var err error
+		switch value := object[field].(type) {
+		case nil:
+			continue
+		case bool:
+			reading, err = dtos.NewSimpleReading(parent.ProfileName, parent.DeviceName, resourceName, common.ValueTypeBool, value)
+		case string:
+			reading, err = dtos.NewSimpleReading(parent.ProfileName, parent.DeviceName, resourceName, common.ValueTypeString, value)
+		case json.Number:
+			if intValue, intErr := value.Int64(); intErr == nil {
+				reading, err = dtos.NewSimpleReading(parent.ProfileName, parent.DeviceName, resourceName, common.ValueTypeInt64, intValue)
+				break
+			}
+
+			var floatValue float64
+			floatValue, err = value.Float64()
+			if err == nil {
+				reading, err = dtos.NewSimpleReading(parent.ProfileName, parent.DeviceName, resourceName, common.ValueTypeFloat64, floatValue)
+			}
+		case []interface{}:
+			if isObjectArray(value) {
+				reading = dtos.NewObjectReadingWithArray(parent.ProfileName, parent.DeviceName, resourceName, value)
+			} else {
+				reading = dtos.NewObjectReading(parent.ProfileName, parent.DeviceName, resourceName, value)
+			}
+		default:
+			reading = dtos.NewObjectReading(parent.ProfileName, parent.DeviceName, resourceName, value)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to create reading for '%s': %s", resourceName, err.Error())
+		}
+
+		reading.Origin = parent.Origin
+		if parent.Tags != nil {
+			reading.Tags = copyTags(parent.Tags)
+		}
+
+		readings = append(readings, reading)
+	}
+
+	return readings, nil
+}
+
+func isObjectArray(values []interface{}) bool {
+	if len(values) == 0 {
+		return false
+	}
+
+	for _, value := range values {
+		if _, ok := value.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+
+	return true
+}
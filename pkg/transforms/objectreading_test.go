@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewObjectReadingExploder(t *testing.T) {
+	_, err := NewObjectReadingExploder("")
+	assert.Error(t, err)
+
+	exploder, err := NewObjectReadingExploder("status")
+	require.NoError(t, err)
+	assert.Equal(t, "status", exploder.resourceName)
+}
+
+func TestExplodeObjectReading(t *testing.T) {
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	_ = event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(21))
+	event.AddObjectReading("status", map[string]interface{}{
+		"running":  true,
+		"mode":     "auto",
+		"speed":    1200,
+		"load":     0.75,
+		"fault":    nil,
+		"position": map[string]interface{}{"x": 1, "y": 2},
+		"alarms":   []interface{}{map[string]interface{}{"code": 7}},
+		"history":  []interface{}{1, 2, 3},
+	})
+	event.Readings[1].Tags = map[string]interface{}{"site": "plant1"}
+	event.Readings[1].Origin = 1700000000000000000
+	event.AddObjectReading("config", map[string]interface{}{"enabled": true})
+
+	exploder, err := NewObjectReadingExploder("status")
+	require.NoError(t, err)
+
+	continuePipeline, result := exploder.ExplodeObjectReading(ctx, event)
+	require.True(t, continuePipeline)
+
+	readings := result.(dtos.Event).Readings
+	require.Len(t, readings, 9)
+
+	// Readings for other resources are passed on unchanged and in place
+	assert.Equal(t, event.Readings[0], readings[0])
+	assert.Equal(t, event.Readings[2], readings[8])
+
+	expected := []struct {
+		resourceName string
+		valueType    string
+		value        string
+	}{
+		{"status.alarms", common.ValueTypeObjectArray, ""},
+		{"status.history", common.ValueTypeObject, ""},
+		{"status.load", common.ValueTypeFloat64, "7.500000e-01"},
+		{"status.mode", common.ValueTypeString, "auto"},
+		{"status.position", common.ValueTypeObject, ""},
+		{"status.running", common.ValueTypeBool, "true"},
+		{"status.speed", common.ValueTypeInt64, "1200"},
+	}
+
+	for index, expected := range expected {
+		reading := readings[index+1]
+		assert.Equal(t, expected.resourceName, reading.ResourceName)
+		assert.Equal(t, expected.valueType, reading.ValueType)
+		assert.Equal(t, expected.value, reading.Value)
+		assert.Equal(t, deviceName1, reading.DeviceName)
+		assert.Equal(t, profileName1, reading.ProfileName)
+		assert.Equal(t, int64(1700000000000000000), reading.Origin)
+		assert.Equal(t, "plant1", reading.Tags["site"])
+	}
+
+	position, err := json.Marshal(readings[5].ObjectValue)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"x":1,"y":2}`, string(position))
+}
+
+func TestExplodeObjectReadingValues(t *testing.T) {
+	exploder, err := NewObjectReadingExploder("status")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		objectValue     interface{}
+		expectedNames   []string
+		expectUnchanged bool
+		expectError     bool
+	}{
+		{"JSON string", `{"b":1,"a":"x"}`, []string{"status.a", "status.b"}, false, false},
+		{"JSON bytes", []byte(`{"a":true}`), []string{"status.a"}, false, false},
+		{"Empty object", map[string]interface{}{}, []string{}, false, false},
+		{"Array value", []interface{}{1, 2}, nil, true, false},
+		{"String value", `"text"`, nil, true, false},
+		{"Invalid JSON", `{"a":`, nil, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+			event.AddObjectReading("status", test.objectValue)
+
+			continuePipeline, result := exploder.ExplodeObjectReading(ctx, event)
+			if test.expectError {
+				assert.False(t, continuePipeline)
+				assert.Contains(t, result.(error).Error(), "unable to parse 'status' Object reading value")
+				return
+			}
+
+			require.True(t, continuePipeline)
+			readings := result.(dtos.Event).Readings
+			if test.expectUnchanged {
+				assert.Equal(t, event.Readings, readings)
+				return
+			}
+
+			names := []string{}
+			for _, reading := range readings {
+				names = append(names, reading.ResourceName)
+			}
+			assert.Equal(t, test.expectedNames, names)
+		})
+	}
+}
+
+func TestExplodeObjectReadingErrors(t *testing.T) {
+	exploder, err := NewObjectReadingExploder("status")
+	require.NoError(t, err)
+
+	continuePipeline, result := exploder.ExplodeObjectReading(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = exploder.ExplodeObjectReading(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// Partitioner splits a slice, such as the batched data from Batch with IsEventData set, into sub-batches grouped by
+// the key extracted from each item, i.e. the tenant, so each sub-batch can be sent to a different destination.
+type Partitioner struct {
+	keyExtractor        RouteKeyExtractor
+	routes              map[string][]interfaces.AppFunction
+	defaultRoute        []interfaces.AppFunction
+	missingKeyPartition string
+}
+
+// NewPartitioner creates, initializes and returns a new instance of Partitioner which uses the keyExtractor to
+// determine the partition of each item. See RouteByDeviceName, RouteByContextValue and RouteByJSONPath for the
+// provided extractors.
+func NewPartitioner(keyExtractor RouteKeyExtractor) (*Partitioner, error) {
+	if keyExtractor == nil {
+		return nil, errors.New("partition key extractor must be specified")
+	}
+
+	return &Partitioner{
+		keyExtractor: keyExtractor,
+		routes:       make(map[string][]interfaces.AppFunction),
+	}, nil
+}
+
+// SetMissingKeyPartition sets the partition items whose key can't be extracted are put in. By default, an item
+// without a key is an error, so that items aren't sent to the wrong destination.
+func (partitioner *Partitioner) SetMissingKeyPartition(key string) {
+	partitioner.missingKeyPartition = key
+}
+
+// AddRoute adds the functions Dispatch executes on the sub-batch for the key, replacing any existing route for the key.
+func (partitioner *Partitioner) AddRoute(key string, functions ...interfaces.AppFunction) error {
+	if len(functions) == 0 {
+		return fmt.Errorf("at least one function must be specified for partition '%s'", key)
+	}
+
+	partitioner.routes[key] = functions
+	return nil
+}
+
+// SetDefaultRoute sets the functions Dispatch executes on the sub-batches whose key doesn't match any route.
+// Sub-batches not matching any route are dropped if there is no default route.
+func (partitioner *Partitioner) SetDefaultRoute(functions ...interfaces.AppFunction) {
+	partitioner.defaultRoute = functions
+}
+
+// Partition groups the items in the slice received by their key, keeping their order, and passes a
+// map[string]interface{} of the key to the sub-batch to the next function. Each sub-batch is a slice of the same
+// type as the slice received, i.e. []dtos.Event.
+// It will return an error and stop the pipeline if a non-slice is received, if no data is received or if the key
+// of an item can't be extracted and there is no missing key partition.
+func (partitioner *Partitioner) Partition(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	partitions, err := partitioner.partition(ctx, "Partition", data)
+	if err != nil {
+		return false, err
+	}
+
+	result := make(map[string]interface{}, len(partitions))
+	for key, partition := range partitions {
+		result[key] = partition.Interface()
+	}
+
+	return true, result
+}
+
+// Dispatch groups the items in the slice received by their key, the same as Partition, and executes the functions
+// for each key's route on its sub-batch, using a clone of the context so the routes don't affect each other. The
+// sub-batches are dispatched in key order and all are dispatched even if some fail. The slice received is passed to
+// the next function.
+// It will return an error and stop the pipeline if a non-slice is received, if no data is received, if the key of an
+// item can't be extracted and there is no missing key partition or if the functions fail for any of the sub-batches.
+// Note that Store and Forward isn't supported for the routes. Since each route is executed using a clone of the
+// context, any retry data set by its functions is discarded, so the failed sub-batches aren't stored for retry.
+func (partitioner *Partitioner) Dispatch(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	partitions, err := partitioner.partition(ctx, "Dispatch", data)
+	if err != nil {
+		return false, err
+	}
+
+	keys := make([]string, 0, len(partitions))
+	for key := range partitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs error
+	for _, key := range keys {
+		functions, found := partitioner.routes[key]
+		if !found {
+			if len(partitioner.defaultRoute) == 0 {
+				ctx.LoggingClient().Debugf("No route for partition '%s' in pipeline '%s', dropping %d items",
+					key, ctx.PipelineId(), partitions[key].Len())
+				continue
+			}
+
+			functions = partitioner.defaultRoute
+		}
+
+		ctx.LoggingClient().Debugf("Dispatching %d items for partition '%s' in pipeline '%s'", partitions[key].Len(), key, ctx.PipelineId())
+
+		completed, result := executeFunctions(ctx.Clone(), functions, partitions[key].Interface())
+		if err, ok := result.(error); ok && !completed {
+			errs = multierror.Append(errs, fmt.Errorf("partition '%s': %w", key, err))
+		}
+	}
+
+	if errs != nil {
+		return false, fmt.Errorf("function Dispatch in pipeline '%s' failed for some partitions: %w", ctx.PipelineId(), errs)
+	}
+
+	return true, data
+}
+
+// partition groups the items by their key into slices of the same type as the slice received
+func (partitioner *Partitioner) partition(ctx interfaces.AppFunctionContext, functionName string, data interface{}) (map[string]reflect.Value, error) {
+	if data == nil {
+		return nil, fmt.Errorf("function %s in pipeline '%s': No Data Received", functionName, ctx.PipelineId())
+	}
+
+	items := reflect.ValueOf(data)
+	if items.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("function %s in pipeline '%s', type received is not a slice", functionName, ctx.PipelineId())
+	}
+
+	partitions := make(map[string]reflect.Value)
+	for index := 0; index < items.Len(); index++ {
+		item := items.Index(index)
+
+		key, err := partitioner.keyExtractor(ctx, item.Interface())
+		if err != nil {
+			if len(partitioner.missingKeyPartition) == 0 {
+				return nil, fmt.Errorf("function %s in pipeline '%s': unable to extract partition key for item #%d: %s",
+					functionName, ctx.PipelineId(), index, err.Error())
+			}
+
+			ctx.LoggingClient().Debugf("Unable to extract partition key for item #%d in pipeline '%s', using partition '%s': %s",
+				index, ctx.PipelineId(), partitioner.missingKeyPartition, err.Error())
+			key = partitioner.missingKeyPartition
+		}
+
+		partition, found := partitions[key]
+		if !found {
+			partition = reflect.MakeSlice(items.Type(), 0, 0)
+		}
+
+		partitions[key] = reflect.Append(partition, item)
+	}
+
+	return partitions, nil
+}
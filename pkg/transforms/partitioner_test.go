@@ -0,0 +1,169 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+func partitionTestEvents() []dtos.Event {
+	tenants := []string{"tenant-a", "tenant-b", "tenant-a"}
+	events := make([]dtos.Event, len(tenants))
+	for index, tenant := range tenants {
+		events[index] = dtos.NewEvent(profileName1, deviceName1, sourceName1)
+		events[index].Tags = map[string]interface{}{"tenant": tenant}
+	}
+	return events
+}
+
+func TestNewPartitioner(t *testing.T) {
+	_, err := NewPartitioner(nil)
+	assert.Error(t, err)
+
+	partitioner, err := NewPartitioner(RouteByDeviceName)
+	require.NoError(t, err)
+	assert.Error(t, partitioner.AddRoute("key"))
+}
+
+func TestPartition(t *testing.T) {
+	partitioner, err := NewPartitioner(RouteByJSONPath("$.tags.tenant"))
+	require.NoError(t, err)
+
+	events := partitionTestEvents()
+	continuePipeline, result := partitioner.Partition(ctx, events)
+	require.True(t, continuePipeline)
+
+	partitions := result.(map[string]interface{})
+	require.Len(t, partitions, 2)
+	assert.Equal(t, []dtos.Event{events[0], events[2]}, partitions["tenant-a"])
+	assert.Equal(t, []dtos.Event{events[1]}, partitions["tenant-b"])
+}
+
+func TestPartitionMissingKey(t *testing.T) {
+	partitioner, err := NewPartitioner(RouteByJSONPath("$.tags.tenant"))
+	require.NoError(t, err)
+
+	events := partitionTestEvents()
+	events[1].Tags = nil
+
+	continuePipeline, result := partitioner.Partition(ctx, events)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "unable to extract partition key for item #1")
+
+	partitioner.SetMissingKeyPartition("unassigned")
+	continuePipeline, result = partitioner.Partition(ctx, events)
+	require.True(t, continuePipeline)
+
+	partitions := result.(map[string]interface{})
+	require.Len(t, partitions, 2)
+	assert.Equal(t, []dtos.Event{events[0], events[2]}, partitions["tenant-a"])
+	assert.Equal(t, []dtos.Event{events[1]}, partitions["unassigned"])
+}
+
+func TestDispatch(t *testing.T) {
+	received := make(map[string][]dtos.Event)
+	recorder := func(name string) interfaces.AppFunction {
+		return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+			received[name] = append(received[name], data.([]dtos.Event)...)
+			return true, data
+		}
+	}
+
+	partitioner, err := NewPartitioner(RouteByJSONPath("$.tags.tenant"))
+	require.NoError(t, err)
+	require.NoError(t, partitioner.AddRoute("tenant-a", recorder("a")))
+
+	events := partitionTestEvents()
+
+	// Without a default route the sub-batch for tenant-b is dropped
+	continuePipeline, result := partitioner.Dispatch(ctx, events)
+	require.True(t, continuePipeline)
+	assert.Equal(t, events, result)
+	assert.Equal(t, []dtos.Event{events[0], events[2]}, received["a"])
+	assert.Empty(t, received["default"])
+
+	partitioner.SetDefaultRoute(recorder("default"))
+	received = make(map[string][]dtos.Event)
+
+	continuePipeline, _ = partitioner.Dispatch(ctx, events)
+	require.True(t, continuePipeline)
+	assert.Equal(t, []dtos.Event{events[0], events[2]}, received["a"])
+	assert.Equal(t, []dtos.Event{events[1]}, received["default"])
+}
+
+func TestDispatchErrors(t *testing.T) {
+	var dispatched []string
+	failing := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		dispatched = append(dispatched, "a")
+		return false, errors.New("destination unavailable")
+	}
+	succeeding := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		dispatched = append(dispatched, "b")
+		return true, data
+	}
+
+	partitioner, err := NewPartitioner(RouteByJSONPath("$.tags.tenant"))
+	require.NoError(t, err)
+	require.NoError(t, partitioner.AddRoute("tenant-a", failing))
+	require.NoError(t, partitioner.AddRoute("tenant-b", succeeding))
+
+	// All partitions are dispatched even though one fails
+	continuePipeline, result := partitioner.Dispatch(ctx, partitionTestEvents())
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "partition 'tenant-a': destination unavailable")
+	assert.Equal(t, []string{"a", "b"}, dispatched)
+}
+
+func TestDispatchRetryDataNotStored(t *testing.T) {
+	ctx.SetRetryData(nil)
+	defer ctx.SetRetryData(nil)
+
+	persistingSend := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		ctx.SetRetryData([]byte("failed sub-batch"))
+		return false, errors.New("destination unavailable")
+	}
+
+	partitioner, err := NewPartitioner(RouteByJSONPath("$.tags.tenant"))
+	require.NoError(t, err)
+	partitioner.SetDefaultRoute(persistingSend)
+
+	continuePipeline, result := partitioner.Dispatch(ctx, partitionTestEvents())
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "destination unavailable")
+	assert.Nil(t, ctx.RetryData())
+}
+
+func TestPartitionBadInput(t *testing.T) {
+	partitioner, err := NewPartitioner(RouteByDeviceName)
+	require.NoError(t, err)
+
+	continuePipeline, result := partitioner.Partition(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = partitioner.Dispatch(ctx, dtos.Event{})
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "function Dispatch in pipeline")
+	assert.Contains(t, result.(error).Error(), "type received is not a slice")
+}
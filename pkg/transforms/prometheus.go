@@ -0,0 +1,255 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+const (
+	// ContentTypePrometheusRemoteWrite is the mime type of Prometheus remote-write requests
+	ContentTypePrometheusRemoteWrite = "application/x-protobuf"
+	// PrometheusRemoteWriteVersion is the version of the Prometheus remote-write protocol sent
+	PrometheusRemoteWriteVersion = "0.1.0"
+
+	prometheusNameLabel     = "__name__"
+	prometheusDeviceLabel   = "device"
+	prometheusProfileLabel  = "profile"
+	prometheusSourceLabel   = "source"
+	prometheusResourceLabel = "resource"
+)
+
+// PrometheusRemoteWriter sends the numeric readings of Events to a Prometheus remote-write endpoint. The requests
+// are sent using an HTTPSender, so the HTTPSender options for authentication, TLS, retries, etc. all apply.
+type PrometheusRemoteWriter struct {
+	sender *HTTPSender
+}
+
+type prometheusLabel struct {
+	name  string
+	value string
+}
+
+type prometheusSample struct {
+	value     float64
+	timestamp int64
+}
+
+type prometheusTimeSeries struct {
+	labels  []prometheusLabel
+	samples []prometheusSample
+}
+
+// NewPrometheusRemoteWriter creates, initializes and returns a new instance of PrometheusRemoteWriter which sends
+// to the options URL. The MimeType option is ignored and the CompressBody option is not supported since remote-write
+// requests are always snappy compressed.
+func NewPrometheusRemoteWriter(options HTTPSenderOptions) (*PrometheusRemoteWriter, error) {
+	if options.CompressBody {
+		return nil, errors.New("CompressBody is not supported since remote-write requests are snappy compressed")
+	}
+
+	options.MimeType = ContentTypePrometheusRemoteWrite
+	sender := NewHTTPSenderWithOptions(options)
+	sender.SetHttpRequestHeaders(map[string]string{
+		"Content-Encoding":                  "snappy",
+		"X-Prometheus-Remote-Write-Version": PrometheusRemoteWriteVersion,
+	})
+
+	return &PrometheusRemoteWriter{sender: sender}, nil
+}
+
+// RemoteWrite converts the numeric readings of the Event or slice of Events, such as from Batch with IsEventData set,
+// to a snappy compressed Prometheus remote-write request and POSTs it to the endpoint. Each reading becomes a sample,
+// with the millisecond timestamp of the reading's origin, in the time series named after the reading's resource and
+// labeled with the device, profile, source, resource and the Event and reading tags. Non-numeric readings are skipped.
+// Data received as []byte is sent as is, which is the case when the export is retried by Store and Forward.
+// This function will return an error and stop the pipeline if no data is received or if the data is not an Event or slice of Events.
+func (writer *PrometheusRemoteWriter) RemoteWrite(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function RemoteWrite in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	var events []dtos.Event
+	switch typedData := data.(type) {
+	case []byte:
+		return writer.sender.HTTPPost(ctx, typedData)
+	case dtos.Event:
+		events = []dtos.Event{typedData}
+	case []dtos.Event:
+		events = typedData
+	default:
+		return false, fmt.Errorf("function RemoteWrite in pipeline '%s', type received is not an Event or slice of Events", ctx.PipelineId())
+	}
+
+	series := prometheusTimeSeriesFromEvents(events)
+	if len(series) == 0 {
+		ctx.LoggingClient().Debugf("No numeric readings to remote-write in pipeline '%s'", ctx.PipelineId())
+		return true, data
+	}
+
+	payload := snappy.Encode(nil, encodePrometheusWriteRequest(series))
+
+	ctx.LoggingClient().Debugf("Remote-writing %d time series in pipeline '%s'", len(series), ctx.PipelineId())
+	return writer.sender.HTTPPost(ctx, payload)
+}
+
+// prometheusTimeSeriesFromEvents groups the samples for the numeric readings by their label set
+func prometheusTimeSeriesFromEvents(events []dtos.Event) []*prometheusTimeSeries {
+	var result []*prometheusTimeSeries
+	seriesByKey := make(map[string]*prometheusTimeSeries)
+	now := time.Now().UnixNano()
+
+	for _, event := range events {
+		for _, reading := range event.Readings {
+			value, ok := numericReadingValue(reading)
+			if !ok {
+				continue
+			}
+
+			labels := prometheusLabels(event, reading)
+
+			var key strings.Builder
+			for _, label := range labels {
+				key.WriteString(label.name + "\xff" + label.value + "\xff")
+			}
+
+			series, found := seriesByKey[key.String()]
+			if !found {
+				series = &prometheusTimeSeries{labels: labels}
+				seriesByKey[key.String()] = series
+				result = append(result, series)
+			}
+
+			// Readings without an origin fall back to the Event's origin and then the current time, since samples at
+			// the epoch are rejected as out of bounds
+			origin := reading.Origin
+			if origin == 0 {
+				origin = event.Origin
+			}
+			if origin == 0 {
+				origin = now
+			}
+
+			// Origin is in nanoseconds and Prometheus timestamps are in milliseconds
+			series.samples = append(series.samples, prometheusSample{value: value, timestamp: origin / 1e6})
+		}
+	}
+
+	// Samples must be in timestamp order within each time series
+	for _, series := range result {
+		sort.SliceStable(series.samples, func(i, j int) bool {
+			return series.samples[i].timestamp < series.samples[j].timestamp
+		})
+	}
+
+	return result
+}
+
+// prometheusLabels returns the labels, sorted by name as required, for the reading. Tags with the same name as the
+// reserved labels are ignored and reading tags take precedence over Event tags.
+func prometheusLabels(event dtos.Event, reading dtos.BaseReading) []prometheusLabel {
+	labelValues := make(map[string]string)
+	for _, tags := range []map[string]interface{}{event.Tags, reading.Tags} {
+		for name, value := range tags {
+			labelValues[sanitizePrometheusName(name, false)] = fmt.Sprint(value)
+		}
+	}
+
+	labelValues[prometheusNameLabel] = sanitizePrometheusName(reading.ResourceName, true)
+	labelValues[prometheusDeviceLabel] = reading.DeviceName
+	labelValues[prometheusProfileLabel] = reading.ProfileName
+	labelValues[prometheusSourceLabel] = event.SourceName
+	labelValues[prometheusResourceLabel] = reading.ResourceName
+
+	labels := make([]prometheusLabel, 0, len(labelValues))
+	for name, value := range labelValues {
+		// Labels with empty values are the same as not being present
+		if len(value) > 0 {
+			labels = append(labels, prometheusLabel{name: name, value: value})
+		}
+	}
+
+	sort.Slice(labels, func(i, j int) bool { return labels[i].name < labels[j].name })
+	return labels
+}
+
+// sanitizePrometheusName replaces the characters not allowed in metric or label names with underscores and
+// prefixes names starting with a digit with an underscore. Colons are only allowed in metric names.
+func sanitizePrometheusName(name string, isMetricName bool) string {
+	sanitized := []rune(name)
+	for index, char := range sanitized {
+		valid := char == '_' ||
+			(char >= 'a' && char <= 'z') ||
+			(char >= 'A' && char <= 'Z') ||
+			(char >= '0' && char <= '9') ||
+			(char == ':' && isMetricName)
+		if !valid {
+			sanitized[index] = '_'
+		}
+	}
+
+	if len(sanitized) > 0 && sanitized[0] >= '0' && sanitized[0] <= '9' {
+		return "_" + string(sanitized)
+	}
+
+	return string(sanitized)
+}
+
+// encodePrometheusWriteRequest encodes the time series as a Prometheus remote-write WriteRequest protobuf message
+func encodePrometheusWriteRequest(series []*prometheusTimeSeries) []byte {
+	var request []byte
+	for _, timeSeries := range series {
+		var encodedSeries []byte
+		for _, label := range timeSeries.labels {
+			var encodedLabel []byte
+			encodedLabel = protowire.AppendTag(encodedLabel, 1, protowire.BytesType)
+			encodedLabel = protowire.AppendString(encodedLabel, label.name)
+			encodedLabel = protowire.AppendTag(encodedLabel, 2, protowire.BytesType)
+			encodedLabel = protowire.AppendString(encodedLabel, label.value)
+
+			encodedSeries = protowire.AppendTag(encodedSeries, 1, protowire.BytesType)
+			encodedSeries = protowire.AppendBytes(encodedSeries, encodedLabel)
+		}
+
+		for _, sample := range timeSeries.samples {
+			var encodedSample []byte
+			encodedSample = protowire.AppendTag(encodedSample, 1, protowire.Fixed64Type)
+			encodedSample = protowire.AppendFixed64(encodedSample, math.Float64bits(sample.value))
+			encodedSample = protowire.AppendTag(encodedSample, 2, protowire.VarintType)
+			encodedSample = protowire.AppendVarint(encodedSample, uint64(sample.timestamp))
+
+			encodedSeries = protowire.AppendTag(encodedSeries, 2, protowire.BytesType)
+			encodedSeries = protowire.AppendBytes(encodedSeries, encodedSample)
+		}
+
+		request = protowire.AppendTag(request, 1, protowire.BytesType)
+		request = protowire.AppendBytes(request, encodedSeries)
+	}
+
+	return request
+}
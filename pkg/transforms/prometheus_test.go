@@ -0,0 +1,287 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/klauspost/compress/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type decodedTimeSeries struct {
+	Labels  map[string]string
+	Samples []prometheusSample
+}
+
+// decodeWriteRequest decodes the WriteRequest protobuf message independently of the encoding under test
+func decodeWriteRequest(t *testing.T, data []byte) []decodedTimeSeries {
+	var result []decodedTimeSeries
+	forEachField(t, data, func(number protowire.Number, value []byte, _ uint64) {
+		require.Equal(t, protowire.Number(1), number)
+		series := decodedTimeSeries{Labels: make(map[string]string)}
+		forEachField(t, value, func(number protowire.Number, value []byte, _ uint64) {
+			switch number {
+			case 1:
+				var name, labelValue string
+				forEachField(t, value, func(number protowire.Number, value []byte, _ uint64) {
+					if number == 1 {
+						name = string(value)
+					} else {
+						labelValue = string(value)
+					}
+				})
+				series.Labels[name] = labelValue
+			case 2:
+				var sample prometheusSample
+				forEachField(t, value, func(number protowire.Number, _ []byte, scalar uint64) {
+					if number == 1 {
+						sample.value = math.Float64frombits(scalar)
+					} else {
+						sample.timestamp = int64(scalar)
+					}
+				})
+				series.Samples = append(series.Samples, sample)
+			}
+		})
+		result = append(result, series)
+	})
+	return result
+}
+
+func forEachField(t *testing.T, data []byte, handle func(number protowire.Number, value []byte, scalar uint64)) {
+	for len(data) > 0 {
+		number, wireType, length := protowire.ConsumeTag(data)
+		require.GreaterOrEqual(t, length, 0)
+		data = data[length:]
+
+		switch wireType {
+		case protowire.BytesType:
+			value, length := protowire.ConsumeBytes(data)
+			require.GreaterOrEqual(t, length, 0)
+			handle(number, value, 0)
+			data = data[length:]
+		case protowire.Fixed64Type:
+			value, length := protowire.ConsumeFixed64(data)
+			require.GreaterOrEqual(t, length, 0)
+			handle(number, nil, value)
+			data = data[length:]
+		case protowire.VarintType:
+			value, length := protowire.ConsumeVarint(data)
+			require.GreaterOrEqual(t, length, 0)
+			handle(number, nil, value)
+			data = data[length:]
+		default:
+			require.Failf(t, "unexpected wire type", "%d", wireType)
+		}
+	}
+}
+
+func prometheusReading(resourceName string, valueType string, value string, origin int64) dtos.BaseReading {
+	return dtos.BaseReading{
+		DeviceName:    deviceName1,
+		ProfileName:   "thermostat",
+		ResourceName:  resourceName,
+		ValueType:     valueType,
+		Origin:        origin,
+		SimpleReading: dtos.SimpleReading{Value: value},
+	}
+}
+
+func TestNewPrometheusRemoteWriter(t *testing.T) {
+	writer, err := NewPrometheusRemoteWriter(HTTPSenderOptions{URL: "http://localhost/write"})
+	require.NoError(t, err)
+	require.NotNil(t, writer)
+
+	_, err = NewPrometheusRemoteWriter(HTTPSenderOptions{URL: "http://localhost/write", CompressBody: true})
+	require.Error(t, err)
+}
+
+func TestPrometheusRemoteWrite(t *testing.T) {
+	var received []decodedTimeSeries
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		receivedHeaders = request.Header
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		decoded, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+
+		received = decodeWriteRequest(t, decoded)
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	writer, err := NewPrometheusRemoteWriter(HTTPSenderOptions{URL: ts.URL})
+	require.NoError(t, err)
+
+	events := []dtos.Event{
+		{
+			DeviceName: deviceName1,
+			SourceName: "readings",
+			Tags:       map[string]interface{}{"site": "plant-1", "__name__": "ignored"},
+			Readings: []dtos.BaseReading{
+				prometheusReading("Temperature", common.ValueTypeFloat64, "2.150000e+01", 2_000_000_000),
+				prometheusReading("Fan-Speed", common.ValueTypeUint16, "1200", 2_000_000_000),
+				prometheusReading("Status", common.ValueTypeString, "ok", 2_000_000_000),
+			},
+		},
+		{
+			DeviceName: deviceName1,
+			SourceName: "readings",
+			Tags:       map[string]interface{}{"site": "plant-1"},
+			Readings: []dtos.BaseReading{
+				prometheusReading("Temperature", common.ValueTypeFloat64, "2.000000e+01", 1_000_000_000),
+			},
+		},
+	}
+
+	continuePipeline, _ := writer.RemoteWrite(ctx, events)
+	require.True(t, continuePipeline)
+
+	assert.Equal(t, "snappy", receivedHeaders.Get("Content-Encoding"))
+	assert.Equal(t, ContentTypePrometheusRemoteWrite, receivedHeaders.Get("Content-Type"))
+	assert.Equal(t, PrometheusRemoteWriteVersion, receivedHeaders.Get("X-Prometheus-Remote-Write-Version"))
+
+	expected := []decodedTimeSeries{
+		{
+			Labels: map[string]string{
+				"__name__": "Temperature",
+				"device":   deviceName1,
+				"profile":  "thermostat",
+				"resource": "Temperature",
+				"site":     "plant-1",
+				"source":   "readings",
+			},
+			// Samples are in timestamp order
+			Samples: []prometheusSample{{value: 20, timestamp: 1000}, {value: 21.5, timestamp: 2000}},
+		},
+		{
+			Labels: map[string]string{
+				"__name__": "Fan_Speed",
+				"device":   deviceName1,
+				"profile":  "thermostat",
+				"resource": "Fan-Speed",
+				"site":     "plant-1",
+				"source":   "readings",
+			},
+			Samples: []prometheusSample{{value: 1200, timestamp: 2000}},
+		},
+	}
+	assert.Equal(t, expected, received)
+}
+
+func TestPrometheusRemoteWriteRetryData(t *testing.T) {
+	var received []byte
+	status := http.StatusInternalServerError
+	ts := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		received, _ = io.ReadAll(request.Body)
+		writer.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	writer, err := NewPrometheusRemoteWriter(HTTPSenderOptions{URL: ts.URL, PersistOnError: true})
+	require.NoError(t, err)
+
+	event := dtos.Event{
+		DeviceName: deviceName1,
+		Readings:   []dtos.BaseReading{prometheusReading("Temperature", common.ValueTypeFloat64, "2.000000e+01", 1_000_000_000)},
+	}
+
+	ctx.SetRetryData(nil)
+	continuePipeline, _ := writer.RemoteWrite(ctx, event)
+	require.False(t, continuePipeline)
+	retryData := ctx.RetryData()
+	require.NotNil(t, retryData)
+	ctx.SetRetryData(nil)
+
+	// Store and Forward retries with the encoded request, which is sent as is
+	status = http.StatusOK
+	continuePipeline, _ = writer.RemoteWrite(ctx, retryData)
+	require.True(t, continuePipeline)
+	assert.Equal(t, retryData, received)
+}
+
+func TestPrometheusTimeSeriesZeroOrigin(t *testing.T) {
+	before := time.Now().UnixMilli()
+	series := prometheusTimeSeriesFromEvents([]dtos.Event{
+		{
+			DeviceName: deviceName1,
+			Origin:     5_000_000_000,
+			Readings: []dtos.BaseReading{
+				prometheusReading("EventOrigin", common.ValueTypeInt32, "1", 0),
+				prometheusReading("ReadingOrigin", common.ValueTypeInt32, "2", 3_000_000_000),
+			},
+		},
+		{
+			DeviceName: deviceName1,
+			Readings:   []dtos.BaseReading{prometheusReading("NoOrigin", common.ValueTypeInt32, "3", 0)},
+		},
+	})
+	after := time.Now().UnixMilli()
+
+	require.Len(t, series, 3)
+	assert.Equal(t, []prometheusSample{{value: 1, timestamp: 5000}}, series[0].samples)
+	assert.Equal(t, []prometheusSample{{value: 2, timestamp: 3000}}, series[1].samples)
+	require.Len(t, series[2].samples, 1)
+	assert.GreaterOrEqual(t, series[2].samples[0].timestamp, before)
+	assert.LessOrEqual(t, series[2].samples[0].timestamp, after)
+}
+
+func TestPrometheusRemoteWriteNoNumericReadings(t *testing.T) {
+	writer, err := NewPrometheusRemoteWriter(HTTPSenderOptions{URL: "http://localhost:0/write"})
+	require.NoError(t, err)
+
+	event := dtos.Event{
+		DeviceName: deviceName1,
+		Readings:   []dtos.BaseReading{prometheusReading("Status", common.ValueTypeString, "ok", 1)},
+	}
+
+	continuePipeline, result := writer.RemoteWrite(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, event, result)
+}
+
+func TestPrometheusRemoteWriteInvalidData(t *testing.T) {
+	writer, err := NewPrometheusRemoteWriter(HTTPSenderOptions{URL: "http://localhost:0/write"})
+	require.NoError(t, err)
+
+	continuePipeline, result := writer.RemoteWrite(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = writer.RemoteWrite(ctx, "not an event")
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event or slice of Events")
+}
+
+func TestSanitizePrometheusName(t *testing.T) {
+	assert.Equal(t, "Fan_Speed", sanitizePrometheusName("Fan-Speed", false))
+	assert.Equal(t, "_1st_floor", sanitizePrometheusName("1st floor", false))
+	assert.Equal(t, "job:temperature", sanitizePrometheusName("job:temperature", true))
+	assert.Equal(t, "job_temperature", sanitizePrometheusName("job:temperature", false))
+}
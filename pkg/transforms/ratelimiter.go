@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gometrics "github.com/rcrowley/go-metrics"
+	"golang.org/x/time/rate"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// RateLimiter caps the rate at which data continues through the pipeline. The limit is shared by all executions
+// of the pipeline function.
+type RateLimiter struct {
+	limiter         *rate.Limiter
+	dropWhenLimited bool
+	limiterContext  context.Context
+	droppedMetric   gometrics.Counter
+}
+
+// NewRateLimiter creates, initializes and returns a new instance of RateLimiter which allows eventsPerSecond on
+// average, with bursts of up to burst. By default, data received when the limit has been reached is blocked
+// until it is allowed. Use SetDropWhenLimited to drop the data instead.
+func NewRateLimiter(eventsPerSecond float64, burst int) (*RateLimiter, error) {
+	if eventsPerSecond <= 0 {
+		return nil, errors.New("events per second must be greater than zero")
+	}
+
+	if burst < 1 {
+		return nil, errors.New("burst must be at least 1")
+	}
+
+	return &RateLimiter{
+		limiter:       rate.NewLimiter(rate.Limit(eventsPerSecond), burst),
+		droppedMetric: gometrics.NewCounter(),
+	}, nil
+}
+
+// SetDropWhenLimited sets whether data received when the limit has been reached is dropped, rather than blocked
+// until it is allowed. Dropped data stops the pipeline and is counted by the RateLimiterDropped metric.
+func (limiter *RateLimiter) SetDropWhenLimited(drop bool) {
+	limiter.dropWhenLimited = drop
+}
+
+// SetContext will set the context used when blocking so that waiting is aborted when it is cancelled,
+// i.e. set to the ApplicationService's AppContext() so waiting is aborted on shutdown.
+func (limiter *RateLimiter) SetContext(limiterContext context.Context) {
+	limiter.limiterContext = limiterContext
+}
+
+// Limit passes the data on to the next function once allowed by the rate limit, or stops the pipeline if the data
+// is dropped due to the limit being reached. It will return an error and stop the pipeline if no data is received
+// or if waiting is aborted.
+func (limiter *RateLimiter) Limit(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Limit in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	if limiter.dropWhenLimited {
+		registerMetric(ctx,
+			func() string { return fmt.Sprintf("%s-%s", internal.RateLimiterDroppedName, ctx.PipelineId()) },
+			func() any { return limiter.droppedMetric },
+			map[string]string{"pipeline": ctx.PipelineId()})
+
+		if !limiter.limiter.Allow() {
+			limiter.droppedMetric.Inc(1)
+			ctx.LoggingClient().Debugf("Rate limit reached, dropping data in pipeline '%s'", ctx.PipelineId())
+			return false, nil
+		}
+
+		return true, data
+	}
+
+	limiterContext := limiter.limiterContext
+	if limiterContext == nil {
+		limiterContext = context.Background()
+	}
+
+	if err := limiter.limiter.Wait(limiterContext); err != nil {
+		return false, fmt.Errorf("function Limit in pipeline '%s': unable to wait for rate limit: %s", ctx.PipelineId(), err.Error())
+	}
+
+	return true, data
+}
@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	tests := []struct {
+		Name            string
+		EventsPerSecond float64
+		Burst           int
+		ExpectError     bool
+	}{
+		{"Valid", 10, 1, false},
+		{"Valid - fractional rate", 0.5, 5, false},
+		{"Invalid - zero rate", 0, 1, true},
+		{"Invalid - negative rate", -1, 1, true},
+		{"Invalid - zero burst", 10, 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			limiter, err := NewRateLimiter(test.EventsPerSecond, test.Burst)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, limiter)
+		})
+	}
+}
+
+func TestRateLimiterDrop(t *testing.T) {
+	limiter, err := NewRateLimiter(1, 5)
+	require.NoError(t, err)
+	limiter.SetDropWhenLimited(true)
+
+	var passed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if continuePipeline, result := limiter.Limit(ctx, "data"); continuePipeline {
+				assert.Equal(t, "data", result)
+				passed.Add(1)
+			} else {
+				assert.Nil(t, result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Only the burst passes since the events are sent much faster than 1 per second
+	assert.Equal(t, int32(5), passed.Load())
+	assert.Equal(t, int64(45), limiter.droppedMetric.Count())
+}
+
+func TestRateLimiterBlock(t *testing.T) {
+	limiter, err := NewRateLimiter(50, 1)
+	require.NoError(t, err)
+
+	var passed atomic.Int32
+	var wg sync.WaitGroup
+	started := time.Now()
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if continuePipeline, _ := limiter.Limit(ctx, "data"); continuePipeline {
+				passed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// All are passed, but the 5 after the first are delayed by 20ms each
+	assert.Equal(t, int32(6), passed.Load())
+	assert.GreaterOrEqual(t, time.Since(started), 90*time.Millisecond)
+	assert.Equal(t, int64(0), limiter.droppedMetric.Count())
+}
+
+func TestRateLimiterBlockCancelled(t *testing.T) {
+	limiter, err := NewRateLimiter(0.1, 1)
+	require.NoError(t, err)
+
+	limiterContext, cancel := context.WithCancel(context.Background())
+	limiter.SetContext(limiterContext)
+
+	continuePipeline, _ := limiter.Limit(ctx, "data")
+	require.True(t, continuePipeline)
+
+	cancel()
+	continuePipeline, result := limiter.Limit(ctx, "data")
+	require.False(t, continuePipeline)
+	require.Error(t, result.(error))
+	assert.Contains(t, result.(error).Error(), "unable to wait for rate limit")
+}
+
+func TestRateLimiterNoData(t *testing.T) {
+	limiter, err := NewRateLimiter(1, 1)
+	require.NoError(t, err)
+
+	continuePipeline, result := limiter.Limit(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+}
@@ -0,0 +1,168 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// Redaction strategies supported by Redactor
+const (
+	// RedactFullMask replaces every character of the value with the mask character
+	RedactFullMask = "fullmask"
+	// RedactPartialMask replaces every character of the value except the last four with the mask character.
+	// Values of four characters or fewer are fully masked.
+	RedactPartialMask = "partialmask"
+	// RedactHash replaces the value with the hex encoded SHA-256 hash of the value, so redacted values can still be
+	// correlated with each other
+	RedactHash = "hash"
+)
+
+const (
+	redactMaskCharacter   = "*"
+	redactPartialKeepSize = 4
+)
+
+// Redactor masks the values of readings for sensitive resources, such as those containing PII, before export
+type Redactor struct {
+	resourceNames map[string]bool
+	strategy      string
+}
+
+// NewRedactor creates, initializes and returns a new instance of Redactor which redacts the values of the readings
+// for the resourceNames using the strategy, which must be one of RedactFullMask, RedactPartialMask or RedactHash.
+func NewRedactor(resourceNames []string, strategy string) (*Redactor, error) {
+	if len(resourceNames) == 0 {
+		return nil, errors.New("at least one resource name must be specified")
+	}
+
+	switch strategy {
+	case RedactFullMask, RedactPartialMask, RedactHash:
+	default:
+		return nil, fmt.Errorf("invalid redaction strategy '%s', must be '%s', '%s' or '%s'",
+			strategy, RedactFullMask, RedactPartialMask, RedactHash)
+	}
+
+	redactor := &Redactor{
+		resourceNames: make(map[string]bool, len(resourceNames)),
+		strategy:      strategy,
+	}
+
+	for _, name := range resourceNames {
+		redactor.resourceNames[name] = true
+	}
+
+	return redactor, nil
+}
+
+// Redact replaces the values of the Event's readings for the configured resources with their redacted values.
+// Numeric values are redacted in their plain decimal form, i.e. without the exponent used to encode float values,
+// binary values in their base64 form and object values in their JSON form. Since the redacted value is no longer
+// of the original type, redacted readings are changed to String readings. Other readings are left unchanged.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (redactor *Redactor) Redact(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Redact in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function Redact in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	for index, reading := range event.Readings {
+		if !redactor.resourceNames[reading.ResourceName] {
+			continue
+		}
+
+		value, err := redactableValue(reading)
+		if err != nil {
+			return false, fmt.Errorf("function Redact in pipeline '%s': unable to redact reading for resource '%s': %s",
+				ctx.PipelineId(), reading.ResourceName, err.Error())
+		}
+
+		redacted := &event.Readings[index]
+		redacted.ValueType = common.ValueTypeString
+		redacted.Value = redactor.redactValue(value)
+		redacted.BinaryValue = nil
+		redacted.MediaType = ""
+		redacted.ObjectValue = nil
+
+		ctx.LoggingClient().Debugf("Redacted '%s' reading value in pipeline '%s'", reading.ResourceName, ctx.PipelineId())
+	}
+
+	return true, event
+}
+
+func (redactor *Redactor) redactValue(value string) string {
+	switch redactor.strategy {
+	case RedactHash:
+		hash := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(hash[:])
+	case RedactPartialMask:
+		runes := []rune(value)
+		if len(runes) <= redactPartialKeepSize {
+			return strings.Repeat(redactMaskCharacter, len(runes))
+		}
+
+		masked := len(runes) - redactPartialKeepSize
+		return strings.Repeat(redactMaskCharacter, masked) + string(runes[masked:])
+	default:
+		return strings.Repeat(redactMaskCharacter, len([]rune(value)))
+	}
+}
+
+// redactableValue returns the reading's value as the string to be redacted
+func redactableValue(reading dtos.BaseReading) (string, error) {
+	switch reading.ValueType {
+	case common.ValueTypeFloat32, common.ValueTypeFloat64:
+		bitSize := 64
+		if reading.ValueType == common.ValueTypeFloat32 {
+			bitSize = 32
+		}
+
+		value, err := strconv.ParseFloat(reading.Value, bitSize)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatFloat(value, 'f', -1, bitSize), nil
+	case common.ValueTypeBinary:
+		return base64.StdEncoding.EncodeToString(reading.BinaryValue), nil
+	case common.ValueTypeObject, common.ValueTypeObjectArray:
+		value, err := json.Marshal(reading.ObjectValue)
+		if err != nil {
+			return "", err
+		}
+
+		return string(value), nil
+	default:
+		return reading.Value, nil
+	}
+}
@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedactor(t *testing.T) {
+	for _, strategy := range []string{RedactFullMask, RedactPartialMask, RedactHash} {
+		_, err := NewRedactor([]string{"badge"}, strategy)
+		require.NoError(t, err)
+	}
+
+	_, err := NewRedactor([]string{"badge"}, "bogus")
+	require.Error(t, err)
+
+	_, err = NewRedactor(nil, RedactHash)
+	require.Error(t, err)
+}
+
+func TestRedactorRedact(t *testing.T) {
+	sha256Hex := func(value string) string {
+		hash := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(hash[:])
+	}
+
+	tests := []struct {
+		Name      string
+		Strategy  string
+		ValueType string
+		Value     interface{}
+		Expected  string
+	}{
+		{"full mask string", RedactFullMask, common.ValueTypeString, "BADGE-123456", "************"},
+		{"full mask int", RedactFullMask, common.ValueTypeInt64, int64(123456), "******"},
+		{"partial mask string", RedactPartialMask, common.ValueTypeString, "BADGE-123456", "********3456"},
+		{"partial mask short string", RedactPartialMask, common.ValueTypeString, "1234", "****"},
+		{"partial mask uint", RedactPartialMask, common.ValueTypeUint32, uint32(98765432), "****5432"},
+		{"partial mask float", RedactPartialMask, common.ValueTypeFloat64, 1234.5, "**34.5"},
+		{"partial mask float32", RedactPartialMask, common.ValueTypeFloat32, float32(42.25), "*2.25"},
+		{"hash string", RedactHash, common.ValueTypeString, "BADGE-123456", sha256Hex("BADGE-123456")},
+		{"hash int", RedactHash, common.ValueTypeInt32, int32(-42), sha256Hex("-42")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			redactor, err := NewRedactor([]string{"badge"}, test.Strategy)
+			require.NoError(t, err)
+
+			event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+			require.NoError(t, event.AddSimpleReading("badge", test.ValueType, test.Value))
+			require.NoError(t, event.AddSimpleReading("door", common.ValueTypeString, "front-door"))
+			require.NoError(t, event.AddSimpleReading("count", common.ValueTypeInt32, int32(7)))
+			unlisted := []dtos.BaseReading{event.Readings[1], event.Readings[2]}
+
+			continuePipeline, result := redactor.Redact(ctx, event)
+			require.True(t, continuePipeline, result)
+
+			redacted := result.(dtos.Event)
+			require.Len(t, redacted.Readings, 3)
+			assert.Equal(t, test.Expected, redacted.Readings[0].Value)
+			assert.Equal(t, common.ValueTypeString, redacted.Readings[0].ValueType)
+			assert.Equal(t, unlisted, redacted.Readings[1:], "unlisted resources should be unmodified")
+		})
+	}
+}
+
+func TestRedactorRedactBinaryAndObject(t *testing.T) {
+	redactor, err := NewRedactor([]string{"photo", "profile"}, RedactFullMask)
+	require.NoError(t, err)
+
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	event.AddBinaryReading("photo", []byte{1, 2, 3}, "image/png")
+	event.AddObjectReading("profile", map[string]interface{}{"name": "x"})
+
+	continuePipeline, result := redactor.Redact(ctx, event)
+	require.True(t, continuePipeline, result)
+
+	for _, reading := range result.(dtos.Event).Readings {
+		assert.Equal(t, common.ValueTypeString, reading.ValueType)
+		assert.Regexp(t, `^\*+$`, reading.Value)
+		assert.Nil(t, reading.BinaryValue)
+		assert.Empty(t, reading.MediaType)
+		assert.Nil(t, reading.ObjectValue)
+	}
+}
+
+func TestRedactorRedactInvalidData(t *testing.T) {
+	redactor, err := NewRedactor([]string{"badge"}, RedactHash)
+	require.NoError(t, err)
+
+	continuePipeline, result := redactor.Redact(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = redactor.Redact(ctx, "not an event")
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
@@ -0,0 +1,190 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v3/errors"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+const (
+	// DefaultResourceMetadataCacheTTL is how long a device profile's resource metadata is cached when no cache TTL
+	// is specified.
+	DefaultResourceMetadataCacheTTL = 5 * time.Minute
+	// ResourceMetadataMinimumTag is the reading tag the resource's declared minimum is added to.
+	ResourceMetadataMinimumTag = "minimum"
+	// ResourceMetadataMaximumTag is the reading tag the resource's declared maximum is added to.
+	ResourceMetadataMaximumTag = "maximum"
+)
+
+// ResourceMetadata enriches readings with the units, minimum and maximum declared for their resource in the device
+// profile, which is read from Core Metadata.
+type ResourceMetadata struct {
+	cacheTTL time.Duration
+	mutex    sync.Mutex
+	cache    map[string]resourceMetadataEntry
+}
+
+type resourceMetadataEntry struct {
+	found     bool
+	resources map[string]dtos.ResourceProperties
+	expires   time.Time
+}
+
+// NewResourceMetadata creates, initializes and returns a new instance of ResourceMetadata. Device profiles are
+// cached for cacheTTL, which defaults to DefaultResourceMetadataCacheTTL if zero, to avoid a Core Metadata request
+// for every Event.
+func NewResourceMetadata(cacheTTL time.Duration) (*ResourceMetadata, error) {
+	if cacheTTL < 0 {
+		return nil, errors.New("cache TTL must not be negative")
+	}
+
+	if cacheTTL == 0 {
+		cacheTTL = DefaultResourceMetadataCacheTTL
+	}
+
+	return &ResourceMetadata{
+		cacheTTL: cacheTTL,
+		cache:    make(map[string]resourceMetadataEntry),
+	}, nil
+}
+
+// AddResourceMetadata looks up the resources of each reading's device profile and annotates the reading with the
+// resource's declared units, if the reading has none, and its minimum and maximum, which are added to the reading's
+// tags as ResourceMetadataMinimumTag and ResourceMetadataMaximumTag. Readings whose profile or resource is unknown
+// are passed on unannotated with a logged warning.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (metadata *ResourceMetadata) AddResourceMetadata(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function AddResourceMetadata in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function AddResourceMetadata in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	now := time.Now()
+
+	// Each profile is looked up once per Event so a failing lookup isn't repeated for every reading
+	entries := make(map[string]resourceMetadataEntry)
+	failed := make(map[string]bool)
+
+	// The readings are copied so the received Event isn't modified
+	readings := make([]dtos.BaseReading, len(event.Readings))
+	for index, reading := range event.Readings {
+		readings[index] = reading
+
+		profileName := reading.ProfileName
+		if len(profileName) == 0 {
+			profileName = event.ProfileName
+		}
+
+		if failed[profileName] {
+			continue
+		}
+
+		entry, found := entries[profileName]
+		if !found {
+			var err error
+			entry, err = metadata.lookup(ctx, profileName, now)
+			if err != nil {
+				ctx.LoggingClient().Warnf("Unable to add resource metadata for profile '%s' in pipeline '%s': %s", profileName, ctx.PipelineId(), err.Error())
+				failed[profileName] = true
+				continue
+			}
+			entries[profileName] = entry
+		}
+
+		if !entry.found {
+			continue
+		}
+
+		properties, found := entry.resources[reading.ResourceName]
+		if !found {
+			ctx.LoggingClient().Warnf("Resource '%s' not found in device profile '%s' in pipeline '%s'. Reading passed on without metadata",
+				reading.ResourceName, profileName, ctx.PipelineId())
+			continue
+		}
+
+		if len(reading.Units) == 0 {
+			reading.Units = properties.Units
+		}
+
+		if properties.Minimum != nil || properties.Maximum != nil {
+			reading.Tags = copyTags(reading.Tags)
+			if properties.Minimum != nil {
+				reading.Tags[ResourceMetadataMinimumTag] = *properties.Minimum
+			}
+			if properties.Maximum != nil {
+				reading.Tags[ResourceMetadataMaximumTag] = *properties.Maximum
+			}
+		}
+
+		readings[index] = reading
+	}
+
+	event.Readings = readings
+	return true, event
+}
+
+// lookup returns the cached resources for the device profile, retrieving the profile from Core Metadata if not
+// cached or expired. Unknown profiles are also cached so that Core Metadata isn't repeatedly requested for them.
+// Other lookup failures are not cached so the lookup is retried for the next Event.
+func (metadata *ResourceMetadata) lookup(ctx interfaces.AppFunctionContext, profileName string, now time.Time) (resourceMetadataEntry, error) {
+	metadata.mutex.Lock()
+	defer metadata.mutex.Unlock()
+
+	if entry, found := metadata.cache[profileName]; found && now.Before(entry.expires) {
+		return entry, nil
+	}
+
+	client := ctx.DeviceProfileClient()
+	if client == nil {
+		return resourceMetadataEntry{}, errors.New("DeviceProfileClient not initialized. Core Metadata is missing from clients configuration")
+	}
+
+	entry := resourceMetadataEntry{expires: now.Add(metadata.cacheTTL)}
+
+	response, err := client.DeviceProfileByName(context.Background(), profileName)
+	if err != nil {
+		if edgexErrors.Kind(err) != edgexErrors.KindEntityDoesNotExist {
+			return resourceMetadataEntry{}, fmt.Errorf("failed to retrieve device profile from Core Metadata: %s", err.Error())
+		}
+
+		ctx.LoggingClient().Warnf("Device profile '%s' not found in Core Metadata in pipeline '%s'. Readings passed on without metadata", profileName, ctx.PipelineId())
+		metadata.cache[profileName] = entry
+		return entry, nil
+	}
+
+	entry.found = true
+	entry.resources = make(map[string]dtos.ResourceProperties, len(response.Profile.DeviceResources))
+	for _, resource := range response.Profile.DeviceResources {
+		entry.resources[resource.Name] = resource.Properties
+	}
+
+	metadata.cache[profileName] = entry
+	return entry, nil
+}
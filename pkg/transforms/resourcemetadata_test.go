@@ -0,0 +1,164 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"testing"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/clients/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos/responses"
+	edgexErrors "github.com/edgexfoundry/go-mod-core-contracts/v3/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+)
+
+func resourceMetadataContext(client *mocks.DeviceProfileClient) *appfunction.Context {
+	return appfunction.NewContext("123", di.NewContainer(di.ServiceConstructorMap{
+		bootstrapContainer.LoggingClientInterfaceName: func(get di.Get) interface{} {
+			return lc
+		},
+		bootstrapContainer.DeviceProfileClientName: func(get di.Get) interface{} {
+			return client
+		},
+	}), "")
+}
+
+func resourceMetadataProfile() responses.DeviceProfileResponse {
+	minimum := -40.0
+	maximum := 125.0
+	return responses.DeviceProfileResponse{Profile: dtos.DeviceProfile{
+		DeviceProfileBasicInfo: dtos.DeviceProfileBasicInfo{Name: profileName1},
+		DeviceResources: []dtos.DeviceResource{
+			{Name: "temperature", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeFloat64, Units: "degC", Minimum: &minimum, Maximum: &maximum}},
+			{Name: "humidity", Properties: dtos.ResourceProperties{ValueType: common.ValueTypeFloat64, Units: "%"}},
+		},
+	}}
+}
+
+func resourceMetadataEvent() dtos.Event {
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	_ = event.AddSimpleReading("temperature", common.ValueTypeFloat64, 21.5)
+	_ = event.AddSimpleReading("humidity", common.ValueTypeFloat64, 40.0)
+	_ = event.AddSimpleReading("pressure", common.ValueTypeFloat64, 1013.0)
+	return event
+}
+
+func TestNewResourceMetadata(t *testing.T) {
+	_, err := NewResourceMetadata(-1)
+	assert.Error(t, err)
+
+	metadata, err := NewResourceMetadata(0)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultResourceMetadataCacheTTL, metadata.cacheTTL)
+}
+
+func TestAddResourceMetadata(t *testing.T) {
+	client := &mocks.DeviceProfileClient{}
+	client.On("DeviceProfileByName", mock.Anything, profileName1).Return(resourceMetadataProfile(), nil).Once()
+	metadataCtx := resourceMetadataContext(client)
+
+	metadata, err := NewResourceMetadata(0)
+	require.NoError(t, err)
+
+	input := resourceMetadataEvent()
+	input.Readings[1].Units = "percent"
+
+	for i := 0; i < 2; i++ {
+		continuePipeline, result := metadata.AddResourceMetadata(metadataCtx, input)
+		require.True(t, continuePipeline)
+
+		event := result.(dtos.Event)
+		require.Len(t, event.Readings, 3)
+
+		temperature := event.Readings[0]
+		assert.Equal(t, "degC", temperature.Units)
+		assert.Equal(t, dtos.Tags{ResourceMetadataMinimumTag: -40.0, ResourceMetadataMaximumTag: 125.0}, temperature.Tags)
+
+		// Units already set on the reading are kept and no tags are added without a minimum or maximum
+		humidity := event.Readings[1]
+		assert.Equal(t, "percent", humidity.Units)
+		assert.Nil(t, humidity.Tags)
+
+		// The resource isn't in the profile so the reading is left unannotated
+		pressure := event.Readings[2]
+		assert.Empty(t, pressure.Units)
+		assert.Nil(t, pressure.Tags)
+	}
+
+	// The profile is cached and the received Event isn't modified
+	client.AssertExpectations(t)
+	assert.Empty(t, input.Readings[0].Units)
+	assert.Nil(t, input.Readings[0].Tags)
+}
+
+func TestAddResourceMetadataLookupFailures(t *testing.T) {
+	client := &mocks.DeviceProfileClient{}
+	client.On("DeviceProfileByName", mock.Anything, "unknown").
+		Return(responses.DeviceProfileResponse{}, edgexErrors.NewCommonEdgeX(edgexErrors.KindEntityDoesNotExist, "not found", nil)).Once()
+	client.On("DeviceProfileByName", mock.Anything, "unavailable").
+		Return(responses.DeviceProfileResponse{}, edgexErrors.NewCommonEdgeX(edgexErrors.KindServiceUnavailable, "unavailable", errors.New("connection refused"))).Twice()
+	metadataCtx := resourceMetadataContext(client)
+
+	metadata, err := NewResourceMetadata(0)
+	require.NoError(t, err)
+
+	for _, profileName := range []string{"unknown", "unavailable"} {
+		for i := 0; i < 2; i++ {
+			event := resourceMetadataEvent()
+			event.ProfileName = profileName
+			for index := range event.Readings {
+				event.Readings[index].ProfileName = profileName
+			}
+
+			continuePipeline, result := metadata.AddResourceMetadata(metadataCtx, event)
+			require.True(t, continuePipeline)
+			for _, reading := range result.(dtos.Event).Readings {
+				assert.Empty(t, reading.Units)
+				assert.Nil(t, reading.Tags)
+			}
+		}
+	}
+
+	// Unknown profiles are cached, while other failures are retried for the next Event
+	client.AssertExpectations(t)
+}
+
+func TestAddResourceMetadataErrors(t *testing.T) {
+	metadata, err := NewResourceMetadata(0)
+	require.NoError(t, err)
+
+	continuePipeline, result := metadata.AddResourceMetadata(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = metadata.AddResourceMetadata(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+
+	// Without a DeviceProfileClient the Event is passed on unannotated
+	continuePipeline, result = metadata.AddResourceMetadata(ctx, resourceMetadataEvent())
+	require.True(t, continuePipeline)
+	assert.Empty(t, result.(dtos.Event).Readings[0].Units)
+}
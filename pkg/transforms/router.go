@@ -0,0 +1,166 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// RouteKeyExtractor returns the key used to select the route for the data
+type RouteKeyExtractor func(ctx interfaces.AppFunctionContext, data interface{}) (string, error)
+
+// Router directs the data to one of several named routes, each being a list of functions, based on the key
+// extracted from the data.
+type Router struct {
+	keyExtractor RouteKeyExtractor
+	routes       map[string][]interfaces.AppFunction
+	defaultRoute []interfaces.AppFunction
+}
+
+// NewRouter creates, initializes and returns a new instance of Router which uses the keyExtractor to determine
+// the route for the data. See RouteByDeviceName, RouteByResourceName and RouteByJSONPath for the provided extractors.
+func NewRouter(keyExtractor RouteKeyExtractor) (*Router, error) {
+	if keyExtractor == nil {
+		return nil, errors.New("route key extractor must be specified")
+	}
+
+	return &Router{
+		keyExtractor: keyExtractor,
+		routes:       make(map[string][]interfaces.AppFunction),
+	}, nil
+}
+
+// AddRoute adds the functions executed for data whose key matches the specified key, replacing any existing
+// route for the key.
+func (router *Router) AddRoute(key string, functions ...interfaces.AppFunction) error {
+	if len(functions) == 0 {
+		return fmt.Errorf("at least one function must be specified for route '%s'", key)
+	}
+
+	router.routes[key] = functions
+	return nil
+}
+
+// SetDefaultRoute sets the functions executed for data whose key doesn't match any route.
+// Data not matching any route is dropped if there is no default route.
+func (router *Router) SetDefaultRoute(functions ...interfaces.AppFunction) {
+	router.defaultRoute = functions
+}
+
+// Route executes the functions for the route matching the key extracted from the data. The continue flag and result
+// of the route's functions are passed on, so the pipeline continues with the route's result, stops if the route
+// stops or fails with the route's error. The pipeline is stopped if no route matches and there is no default route.
+// It will return an error and stop the pipeline if no data is received or if the key can't be extracted.
+// Note that the route's functions are executed using the pipeline's context, so retry data set by a sender in a
+// route is stored by Store and Forward for the Router's position in the pipeline. When retried, the Router receives
+// the stored []byte rather than the original data, which RouteByDeviceName and RouteByResourceName can't extract a
+// key from, so those retries fail until the stored data is removed. When Store and Forward is enabled, use
+// RouteByContextValue, since the context values are stored with the retry data, or RouteByJSONPath if the retry
+// data is JSON.
+func (router *Router) Route(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Route in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	key, err := router.keyExtractor(ctx, data)
+	if err != nil {
+		return false, fmt.Errorf("function Route in pipeline '%s': unable to extract route key: %s", ctx.PipelineId(), err.Error())
+	}
+
+	functions, found := router.routes[key]
+	if !found {
+		if len(router.defaultRoute) == 0 {
+			ctx.LoggingClient().Debugf("No route for key '%s' in pipeline '%s', dropping data", key, ctx.PipelineId())
+			return false, nil
+		}
+
+		ctx.LoggingClient().Debugf("No route for key '%s' in pipeline '%s', using default route", key, ctx.PipelineId())
+		functions = router.defaultRoute
+	} else {
+		ctx.LoggingClient().Debugf("Routing data for key '%s' in pipeline '%s'", key, ctx.PipelineId())
+	}
+
+	return executeFunctions(ctx, functions, data)
+}
+
+// RouteByDeviceName is a RouteKeyExtractor which uses the Event's device name as the route key. It can't route the
+// []byte retry data from Store and Forward, see Route.
+func RouteByDeviceName(_ interfaces.AppFunctionContext, data interface{}) (string, error) {
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return "", errors.New("type received is not an Event")
+	}
+
+	return event.DeviceName, nil
+}
+
+// RouteByResourceName is a RouteKeyExtractor which uses the resource name of the Event's first reading as the route
+// key. It can't route the []byte retry data from Store and Forward, see Route.
+func RouteByResourceName(_ interfaces.AppFunctionContext, data interface{}) (string, error) {
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return "", errors.New("type received is not an Event")
+	}
+
+	if len(event.Readings) == 0 {
+		return "", errors.New("event has no readings")
+	}
+
+	return event.Readings[0].ResourceName, nil
+}
+
+// RouteByContextValue returns a RouteKeyExtractor which uses the value stored in the context under the contextKey
+// as the route key, i.e. one set by an earlier function in the pipeline.
+func RouteByContextValue(contextKey string) RouteKeyExtractor {
+	return func(ctx interfaces.AppFunctionContext, _ interface{}) (string, error) {
+		value, found := ctx.GetValue(contextKey)
+		if !found {
+			return "", fmt.Errorf("context value '%s' not found", contextKey)
+		}
+
+		return value, nil
+	}
+}
+
+// RouteByJSONPath returns a RouteKeyExtractor which uses the value selected by the JSONPath expression from the
+// JSON representation of the data as the route key, i.e. "$.readings[0].resourceName". See NewJSONPathExtractor
+// for the supported JSONPath. Non-string values are used in their JSON form.
+func RouteByJSONPath(expression string) RouteKeyExtractor {
+	path, parseErr := parseJSONPath(expression)
+
+	return func(_ interfaces.AppFunctionContext, data interface{}) (string, error) {
+		if parseErr != nil {
+			return "", parseErr
+		}
+
+		value, found, err := path.lookupData(data)
+		if err != nil {
+			return "", err
+		}
+
+		if !found {
+			return "", fmt.Errorf("path '%s' not found in data", expression)
+		}
+
+		return jsonValueString(value)
+	}
+}
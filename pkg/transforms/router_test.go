@@ -0,0 +1,205 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+func routeTo(name string) interfaces.AppFunction {
+	return func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, name
+	}
+}
+
+func TestNewRouter(t *testing.T) {
+	_, err := NewRouter(nil)
+	require.Error(t, err)
+
+	router, err := NewRouter(RouteByDeviceName)
+	require.NoError(t, err)
+	require.Error(t, router.AddRoute("device1"))
+}
+
+func TestRouterRoute(t *testing.T) {
+	failRoute := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return false, errors.New("route failed")
+	}
+	stopRoute := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return false, nil
+	}
+
+	tests := []struct {
+		Name             string
+		DeviceName       string
+		UseDefault       bool
+		ExpectedContinue bool
+		ExpectedResult   interface{}
+		ExpectedError    string
+	}{
+		{"matched route", deviceName1, false, true, "route1", ""},
+		{"matched route with default", deviceName2, true, true, "route2", ""},
+		{"default route", "unknown", true, true, "default", ""},
+		{"no default drops", "unknown", false, false, nil, ""},
+		{"route error propagated", "failing", false, false, nil, "route failed"},
+		{"route stop propagated", "stopping", true, false, nil, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			router, err := NewRouter(RouteByDeviceName)
+			require.NoError(t, err)
+			require.NoError(t, router.AddRoute(deviceName1, routeTo("route1")))
+			require.NoError(t, router.AddRoute(deviceName2, routeTo("route2")))
+			require.NoError(t, router.AddRoute("failing", failRoute))
+			require.NoError(t, router.AddRoute("stopping", stopRoute, routeTo("never")))
+			if test.UseDefault {
+				router.SetDefaultRoute(routeTo("default"))
+			}
+
+			continuePipeline, result := router.Route(ctx, dtos.Event{DeviceName: test.DeviceName})
+
+			assert.Equal(t, test.ExpectedContinue, continuePipeline)
+			if len(test.ExpectedError) > 0 {
+				require.Error(t, result.(error))
+				assert.Contains(t, result.(error).Error(), test.ExpectedError)
+				return
+			}
+			assert.Equal(t, test.ExpectedResult, result)
+		})
+	}
+}
+
+func TestRouterRouteBadInput(t *testing.T) {
+	router, err := NewRouter(RouteByDeviceName)
+	require.NoError(t, err)
+
+	continuePipeline, result := router.Route(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = router.Route(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "unable to extract route key: type received is not an Event")
+}
+
+func TestRouterRouteRetryData(t *testing.T) {
+	tests := []struct {
+		Name          string
+		KeyExtractor  RouteKeyExtractor
+		ExpectedError string
+	}{
+		{"device name can't route retry data", RouteByDeviceName, "type received is not an Event"},
+		{"resource name can't route retry data", RouteByResourceName, "type received is not an Event"},
+		{"context value routes retry data", RouteByContextValue("route"), ""},
+		{"JSON path routes retry data", RouteByJSONPath("$.deviceName"), ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.SetRetryData(nil)
+			defer ctx.SetRetryData(nil)
+			ctx.AddValue("route", deviceName1)
+			defer ctx.RemoveValue("route")
+
+			var attempts []interface{}
+			persistingSend := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+				attempts = append(attempts, data)
+				ctx.SetRetryData([]byte(`{"deviceName":"` + deviceName1 + `"}`))
+				return false, errors.New("send failed")
+			}
+
+			router, err := NewRouter(test.KeyExtractor)
+			require.NoError(t, err)
+			require.NoError(t, router.AddRoute(deviceName1, persistingSend))
+			require.NoError(t, router.AddRoute("resource1", persistingSend))
+
+			event := dtos.Event{DeviceName: deviceName1, Readings: []dtos.BaseReading{{ResourceName: "resource1"}}}
+			continuePipeline, _ := router.Route(ctx, event)
+			require.False(t, continuePipeline)
+			require.NotNil(t, ctx.RetryData())
+
+			// Store and Forward retries at the Router's position with the retry data set by the route's sender
+			continuePipeline, result := router.Route(ctx, ctx.RetryData())
+			require.False(t, continuePipeline)
+			if len(test.ExpectedError) > 0 {
+				assert.Contains(t, result.(error).Error(), test.ExpectedError)
+				assert.Len(t, attempts, 1)
+				return
+			}
+
+			assert.Contains(t, result.(error).Error(), "send failed")
+			require.Len(t, attempts, 2)
+			assert.IsType(t, []byte{}, attempts[1])
+		})
+	}
+}
+
+func TestRouteKeyExtractors(t *testing.T) {
+	event := dtos.Event{
+		DeviceName: deviceName1,
+		Readings: []dtos.BaseReading{
+			{ResourceName: resource1, SimpleReading: dtos.SimpleReading{Value: "10"}},
+			{ResourceName: resource2},
+		},
+	}
+
+	ctx.AddValue("routetenant", "tenant-a")
+	defer ctx.RemoveValue("routetenant")
+
+	tests := []struct {
+		Name          string
+		Extractor     RouteKeyExtractor
+		Data          interface{}
+		ExpectedKey   string
+		ExpectedError string
+	}{
+		{"device name", RouteByDeviceName, event, deviceName1, ""},
+		{"resource name", RouteByResourceName, event, resource1, ""},
+		{"resource name no readings", RouteByResourceName, dtos.Event{}, "", "event has no readings"},
+		{"context value", RouteByContextValue("routetenant"), event, "tenant-a", ""},
+		{"context value missing", RouteByContextValue("bogus"), event, "", "context value 'bogus' not found"},
+		{"json path event", RouteByJSONPath("$.readings[1].resourceName"), event, resource2, ""},
+		{"json path string data", RouteByJSONPath("sensor.type"), `{"sensor": {"type": "temperature"}}`, "temperature", ""},
+		{"json path number", RouteByJSONPath("$.level"), []byte(`{"level": 3}`), "3", ""},
+		{"json path missing key", RouteByJSONPath("$.sensor.bogus"), `{"sensor": {"type": "temperature"}}`, "", "path '$.sensor.bogus' not found"},
+		{"json path bad index", RouteByJSONPath("$.readings[5].resourceName"), event, "", "not found"},
+		{"json path not json", RouteByJSONPath("$.type"), "not json", "", "unable to unmarshal data as JSON"},
+		{"json path invalid", RouteByJSONPath("$.readings[*]"), event, "", "unsupported JSONPath expression"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			key, err := test.Extractor(ctx, test.Data)
+			if len(test.ExpectedError) > 0 {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.ExpectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ExpectedKey, key)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// DefaultSamplerStateExpiry is how long the sampling state for a device and resource is kept when no state expiry
+// is specified.
+const DefaultSamplerStateExpiry = time.Hour
+
+// Sampler forwards a sample of the Events for each device and resource, either one of every N Events or
+// Events at least a minimum time interval apart.
+type Sampler struct {
+	everyN      int
+	minInterval time.Duration
+	stateExpiry time.Duration
+	mutex       sync.Mutex
+	states      map[string]*samplerState
+	lastEvicted time.Time
+}
+
+type samplerState struct {
+	received      int
+	lastForwarded time.Time
+	lastSeen      time.Time
+}
+
+// NewSampler creates, initializes and returns a new instance of Sampler. Exactly one of everyN or minInterval
+// must be specified. When everyN is specified, one of every N Events is forwarded for each device and resource.
+// When minInterval is specified, an Event is only forwarded for a device and resource if at least minInterval
+// has elapsed since the previous Event forwarded for it. The first Event for each device and resource is always
+// forwarded. The state kept for a device and resource is evicted when no readings have been received for it within
+// stateExpiry, after which its next Event is forwarded. DefaultSamplerStateExpiry is used if stateExpiry is zero.
+func NewSampler(everyN int, minInterval time.Duration, stateExpiry time.Duration) (*Sampler, error) {
+	if everyN < 0 || minInterval < 0 || stateExpiry < 0 {
+		return nil, errors.New("sample count, interval and state expiry must not be negative")
+	}
+
+	if (everyN > 0) == (minInterval > 0) {
+		return nil, errors.New("exactly one of sample count or interval must be specified")
+	}
+
+	if stateExpiry == 0 {
+		stateExpiry = DefaultSamplerStateExpiry
+	}
+
+	if minInterval > stateExpiry {
+		return nil, errors.New("state expiry must not be less than interval")
+	}
+
+	return &Sampler{
+		everyN:      everyN,
+		minInterval: minInterval,
+		stateExpiry: stateExpiry,
+		states:      make(map[string]*samplerState),
+	}, nil
+}
+
+// Sample forwards the Event if it is selected by the sampling for any of its readings' device and resource,
+// otherwise the pipeline is stopped. An Event without readings is sampled by its device and source.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (sampler *Sampler) Sample(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Sample in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function Sample in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	keys := samplerKeys(event)
+	if !sampler.isSelected(keys, time.Now()) {
+		ctx.LoggingClient().Debugf("Event not sampled for %v in pipeline '%s'", keys, ctx.PipelineId())
+		return false, nil
+	}
+
+	ctx.LoggingClient().Debugf("Event sampled for %v in pipeline '%s'", keys, ctx.PipelineId())
+	return true, event
+}
+
+// samplerKeys returns the device and resource keys of the Event's readings, or its device and source if it has none
+func samplerKeys(event dtos.Event) []string {
+	if len(event.Readings) == 0 {
+		return []string{event.DeviceName + "/" + event.SourceName}
+	}
+
+	keys := make([]string, 0, len(event.Readings))
+	seen := make(map[string]bool, len(event.Readings))
+	for _, reading := range event.Readings {
+		key := reading.DeviceName + "/" + reading.ResourceName
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (sampler *Sampler) isSelected(keys []string, now time.Time) bool {
+	sampler.mutex.Lock()
+	defer sampler.mutex.Unlock()
+
+	sampler.evictStale(now)
+
+	selected := false
+	states := make([]*samplerState, len(keys))
+	for index, key := range keys {
+		state, found := sampler.states[key]
+		if !found {
+			state = &samplerState{}
+			sampler.states[key] = state
+		}
+		states[index] = state
+
+		if sampler.everyN > 0 {
+			if state.received%sampler.everyN == 0 {
+				selected = true
+			}
+		} else if !found || now.Sub(state.lastForwarded) >= sampler.minInterval {
+			selected = true
+		}
+	}
+
+	for _, state := range states {
+		state.received++
+		state.lastSeen = now
+		if selected {
+			state.lastForwarded = now
+		}
+	}
+
+	return selected
+}
+
+// evictStale removes the state for devices and resources which haven't been seen within the state expiry.
+// The sweep is done at most once per state expiry so that the cost is amortized across Events.
+func (sampler *Sampler) evictStale(now time.Time) {
+	if now.Sub(sampler.lastEvicted) < sampler.stateExpiry {
+		return
+	}
+
+	for key, state := range sampler.states {
+		if now.Sub(state.lastSeen) >= sampler.stateExpiry {
+			delete(sampler.states, key)
+		}
+	}
+
+	sampler.lastEvicted = now
+}
@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSampler(t *testing.T) {
+	tests := []struct {
+		Name        string
+		EveryN      int
+		MinInterval time.Duration
+		StateExpiry time.Duration
+		ExpectError bool
+	}{
+		{"Valid - count", 3, 0, 0, false},
+		{"Valid - interval", 0, time.Second, 0, false},
+		{"Valid - interval and expiry", 0, time.Second, time.Second, false},
+		{"Invalid - neither", 0, 0, 0, true},
+		{"Invalid - both", 3, time.Second, 0, true},
+		{"Invalid - negative count", -1, 0, 0, true},
+		{"Invalid - negative interval", 0, -time.Second, 0, true},
+		{"Invalid - negative expiry", 3, 0, -time.Second, true},
+		{"Invalid - expiry less than interval", 0, time.Minute, time.Second, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			sampler, err := NewSampler(test.EveryN, test.MinInterval, test.StateExpiry)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, sampler)
+		})
+	}
+}
+
+func TestSamplerByCount(t *testing.T) {
+	sampler, err := NewSampler(3, 0, 0)
+	require.NoError(t, err)
+
+	device1Event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	device2Event := dtos.NewEvent(profileName1, deviceName2, sourceName1)
+
+	expected := []bool{true, false, false, true, false, false, true}
+	for i, expectedContinue := range expected {
+		continuePipeline, result := sampler.Sample(ctx, device1Event)
+		assert.Equal(t, expectedContinue, continuePipeline, "event %d", i)
+		if expectedContinue {
+			assert.Equal(t, device1Event, result)
+		} else {
+			assert.Nil(t, result)
+		}
+	}
+
+	// Each device is sampled independently, so first event for another device always passes
+	continuePipeline, _ := sampler.Sample(ctx, device2Event)
+	assert.True(t, continuePipeline)
+}
+
+func TestSamplerByTime(t *testing.T) {
+	sampler, err := NewSampler(0, time.Minute, 0)
+	require.NoError(t, err)
+
+	keys := []string{deviceName1 + "/" + sourceName1}
+	start := time.Now()
+
+	assert.True(t, sampler.isSelected(keys, start), "first event always passes")
+	assert.False(t, sampler.isSelected(keys, start.Add(30*time.Second)))
+	assert.False(t, sampler.isSelected(keys, start.Add(59*time.Second)))
+	assert.True(t, sampler.isSelected(keys, start.Add(time.Minute)))
+	assert.False(t, sampler.isSelected(keys, start.Add(time.Minute+time.Second)))
+	assert.True(t, sampler.isSelected([]string{deviceName2 + "/" + sourceName1}, start.Add(time.Minute+time.Second)),
+		"first event for another device always passes")
+
+	event := dtos.NewEvent(profileName1, deviceName2, sourceName2)
+	continuePipeline, result := sampler.Sample(ctx, event)
+	assert.True(t, continuePipeline)
+	assert.Equal(t, event, result)
+	continuePipeline, result = sampler.Sample(ctx, event)
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+}
+
+func samplerEvent(device string, resources ...string) dtos.Event {
+	event := dtos.Event{DeviceName: device, SourceName: "source"}
+	for _, resource := range resources {
+		event.Readings = append(event.Readings, dtos.BaseReading{DeviceName: device, ResourceName: resource})
+	}
+	return event
+}
+
+func TestSamplerByResource(t *testing.T) {
+	sampler, err := NewSampler(2, 0, 0)
+	require.NoError(t, err)
+
+	// Each resource of the same device and source is sampled independently
+	continuePipeline, _ := sampler.Sample(ctx, samplerEvent(deviceName1, "temperature"))
+	assert.True(t, continuePipeline)
+	continuePipeline, _ = sampler.Sample(ctx, samplerEvent(deviceName1, "humidity"))
+	assert.True(t, continuePipeline, "first event for another resource always passes")
+	continuePipeline, _ = sampler.Sample(ctx, samplerEvent(deviceName1, "temperature"))
+	assert.False(t, continuePipeline)
+
+	// The Event is forwarded if selected for any of its resources
+	continuePipeline, _ = sampler.Sample(ctx, samplerEvent(deviceName1, "temperature", "pressure"))
+	assert.True(t, continuePipeline)
+	assert.Equal(t, []string{deviceName1 + "/temperature", deviceName1 + "/pressure"},
+		samplerKeys(samplerEvent(deviceName1, "temperature", "pressure", "temperature")))
+}
+
+func TestSamplerStateEviction(t *testing.T) {
+	sampler, err := NewSampler(3, 0, time.Minute)
+	require.NoError(t, err)
+
+	device1 := []string{deviceName1 + "/temperature"}
+	device2 := []string{deviceName2 + "/temperature"}
+	start := time.Now()
+	assert.True(t, sampler.isSelected(device1, start))
+	assert.True(t, sampler.isSelected(device2, start))
+	assert.Len(t, sampler.states, 2)
+
+	// Device 2 keeps being seen, so only device 1 is evicted
+	assert.False(t, sampler.isSelected(device2, start.Add(30*time.Second)))
+	assert.False(t, sampler.isSelected(device2, start.Add(70*time.Second)))
+	assert.Len(t, sampler.states, 1)
+
+	// Evicted, so the next event is treated as the first
+	assert.True(t, sampler.isSelected(device1, start.Add(80*time.Second)))
+}
+
+func TestSamplerConcurrent(t *testing.T) {
+	const eventCount = 1000
+	const everyN = 10
+
+	sampler, err := NewSampler(everyN, 0, 0)
+	require.NoError(t, err)
+
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+
+	var forwarded atomic.Int32
+	wg := sync.WaitGroup{}
+	for i := 0; i < eventCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if continuePipeline, _ := sampler.Sample(ctx, event); continuePipeline {
+				forwarded.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(eventCount/everyN), forwarded.Load())
+}
+
+func TestSamplerBadData(t *testing.T) {
+	sampler, err := NewSampler(2, 0, 0)
+	require.NoError(t, err)
+
+	continuePipeline, result := sampler.Sample(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = sampler.Sample(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
@@ -0,0 +1,184 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+)
+
+// sequencerStoreVersion is the Version of the StoredObject used to persist the sequence number, which the
+// store requires to be set.
+const sequencerStoreVersion = "sequence"
+
+// Sequencer attaches a monotonically increasing sequence number to the data passing through the pipeline so
+// downstream consumers can detect gaps. Numbering starts at 1 and is local to the Sequencer instance, so each
+// pipeline should use its own instance.
+type Sequencer struct {
+	contextKey string
+	tagName    string
+	counter    atomic.Uint64
+
+	store     interfaces.StoreClient
+	storeKey  string
+	loaded    atomic.Bool
+	storeLock sync.Mutex
+	stored    interfaces.StoredObject
+	lastSaved uint64
+}
+
+// NewSequencer creates, initializes and returns a new instance of Sequencer which stores the sequence number in
+// the context using contextKey. contextKey may be empty if the number is only added as an Event tag, see SetTagName.
+func NewSequencer(contextKey string) *Sequencer {
+	return &Sequencer{
+		contextKey: contextKey,
+	}
+}
+
+// SetTagName sets the name of the Event tag the sequence number is added to. The tag is only added when the data
+// is an Event.
+func (s *Sequencer) SetTagName(tagName string) {
+	s.tagName = tagName
+}
+
+// SetStore enables persisting the last sequence number so numbering continues across restarts. The number is saved
+// to the store under storeKey, which is used as the StoredObject's AppServiceKey, and is loaded on first use.
+func (s *Sequencer) SetStore(store interfaces.StoreClient, storeKey string) {
+	s.store = store
+	s.storeKey = storeKey
+}
+
+// Sequence increments the sequence number and adds it to the context and/or the Event's tags, passing the data on.
+// The pipeline is stopped if the last persisted number can't be loaded, while a failure to persist the new number
+// is only logged.
+func (s *Sequencer) Sequence(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	ctx.LoggingClient().Debugf("Adding sequence number in pipeline '%s'", ctx.PipelineId())
+
+	if data == nil {
+		return false, fmt.Errorf("function Sequence in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	if len(s.contextKey) == 0 && len(s.tagName) == 0 {
+		return false, fmt.Errorf("function Sequence in pipeline '%s': context key or tag name must be set", ctx.PipelineId())
+	}
+
+	event, isEvent := data.(dtos.Event)
+	if len(s.contextKey) == 0 && !isEvent {
+		return false, fmt.Errorf("function Sequence in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	if err := s.load(); err != nil {
+		return false, fmt.Errorf("function Sequence in pipeline '%s': unable to load last sequence number: %s", ctx.PipelineId(), err.Error())
+	}
+
+	sequence := s.counter.Add(1)
+
+	if err := s.save(sequence); err != nil {
+		ctx.LoggingClient().Errorf("Unable to persist sequence number %d in pipeline '%s': %s", sequence, ctx.PipelineId(), err.Error())
+	}
+
+	value := strconv.FormatUint(sequence, 10)
+	if len(s.contextKey) > 0 {
+		ctx.AddValue(s.contextKey, value)
+	}
+
+	if isEvent && len(s.tagName) > 0 {
+		if event.Tags == nil {
+			event.Tags = make(map[string]interface{})
+		}
+		event.Tags[s.tagName] = value
+		return true, event
+	}
+
+	return true, data
+}
+
+// load reads the last persisted sequence number from the store the first time it is called.
+func (s *Sequencer) load() error {
+	if s.store == nil || s.loaded.Load() {
+		return nil
+	}
+
+	s.storeLock.Lock()
+	defer s.storeLock.Unlock()
+
+	if s.loaded.Load() {
+		return nil
+	}
+
+	objects, err := s.store.RetrieveFromStore(s.storeKey)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		last, err := strconv.ParseUint(string(object.Payload), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid sequence number '%s' in store: %s", string(object.Payload), err.Error())
+		}
+
+		if last >= s.lastSaved {
+			s.lastSaved = last
+			s.stored = object
+		}
+	}
+
+	s.counter.Store(s.lastSaved)
+	s.loaded.Store(true)
+	return nil
+}
+
+// save persists the sequence number unless a higher number has already been saved by a concurrent call.
+func (s *Sequencer) save(sequence uint64) error {
+	if s.store == nil {
+		return nil
+	}
+
+	s.storeLock.Lock()
+	defer s.storeLock.Unlock()
+
+	if sequence <= s.lastSaved {
+		return nil
+	}
+
+	s.stored.AppServiceKey = s.storeKey
+	s.stored.Version = sequencerStoreVersion
+	s.stored.Payload = []byte(strconv.FormatUint(sequence, 10))
+
+	if len(s.stored.ID) == 0 {
+		id, err := s.store.Store(s.stored)
+		if err != nil {
+			return err
+		}
+		if len(id) == 0 {
+			return errors.New("store didn't return an ID")
+		}
+		s.stored.ID = id
+	} else if err := s.store.Update(s.stored); err != nil {
+		return err
+	}
+
+	s.lastSaved = sequence
+	return nil
+}
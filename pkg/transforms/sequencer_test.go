@@ -0,0 +1,233 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	storeMocks "github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces/mocks"
+)
+
+const sequenceContextKey = "sequence"
+
+func TestSequence(t *testing.T) {
+	sequencer := NewSequencer(sequenceContextKey)
+	sequencer.SetTagName("seq")
+
+	for expected := 1; expected <= 3; expected++ {
+		continuePipeline, result := sequencer.Sequence(ctx, dtos.Event{DeviceName: deviceName1})
+		require.True(t, continuePipeline)
+		event, ok := result.(dtos.Event)
+		require.True(t, ok)
+		assert.Equal(t, strconv.Itoa(expected), event.Tags["seq"])
+		value, found := ctx.GetValue(sequenceContextKey)
+		require.True(t, found)
+		assert.Equal(t, strconv.Itoa(expected), value)
+	}
+
+	continuePipeline, result := sequencer.Sequence(ctx, []byte("not an event"))
+	require.True(t, continuePipeline)
+	assert.Equal(t, []byte("not an event"), result)
+	value, _ := ctx.GetValue(sequenceContextKey)
+	assert.Equal(t, "4", value)
+}
+
+func TestSequenceErrors(t *testing.T) {
+	continuePipeline, result := NewSequencer(sequenceContextKey).Sequence(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = NewSequencer("").Sequence(ctx, dtos.Event{})
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "context key or tag name must be set")
+
+	tagOnly := NewSequencer("")
+	tagOnly.SetTagName("seq")
+	continuePipeline, result = tagOnly.Sequence(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
+
+func TestSequenceConcurrent(t *testing.T) {
+	const callers = 20
+	const callsPerCaller = 50
+
+	sequencer := NewSequencer(sequenceContextKey)
+
+	var wait sync.WaitGroup
+	results := make(chan uint64, callers*callsPerCaller)
+	for i := 0; i < callers; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for j := 0; j < callsPerCaller; j++ {
+				callerCtx := appfunction.NewContext("123", dic, "")
+				continuePipeline, _ := sequencer.Sequence(callerCtx, []byte("data"))
+				if !assert.True(t, continuePipeline) {
+					return
+				}
+				value, _ := callerCtx.GetValue(sequenceContextKey)
+				sequence, err := strconv.ParseUint(value, 10, 64)
+				if !assert.NoError(t, err) {
+					return
+				}
+				results <- sequence
+			}
+		}()
+	}
+	wait.Wait()
+	close(results)
+
+	var sequences []uint64
+	for sequence := range results {
+		sequences = append(sequences, sequence)
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+
+	require.Len(t, sequences, callers*callsPerCaller)
+	for index, sequence := range sequences {
+		require.Equal(t, uint64(index+1), sequence, "sequence numbers must be unique and gap-free")
+	}
+}
+
+// memorySequenceStore is a minimal concurrency safe StoreClient which records every saved sequence number.
+type memorySequenceStore struct {
+	storeMocks.StoreClient
+	mutex   sync.Mutex
+	objects map[string]interfaces.StoredObject
+	saved   []uint64
+}
+
+func (s *memorySequenceStore) Store(o interfaces.StoredObject) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	o.ID = strconv.Itoa(len(s.objects) + 1)
+	s.objects[o.ID] = o
+	s.record(o)
+	return o.ID, nil
+}
+
+func (s *memorySequenceStore) Update(o interfaces.StoredObject) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.objects[o.ID] = o
+	s.record(o)
+	return nil
+}
+
+func (s *memorySequenceStore) RetrieveFromStore(appServiceKey string) ([]interfaces.StoredObject, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var objects []interfaces.StoredObject
+	for _, object := range s.objects {
+		if object.AppServiceKey == appServiceKey {
+			objects = append(objects, object)
+		}
+	}
+	return objects, nil
+}
+
+func (s *memorySequenceStore) record(o interfaces.StoredObject) {
+	sequence, _ := strconv.ParseUint(string(o.Payload), 10, 64)
+	s.saved = append(s.saved, sequence)
+}
+
+func TestSequencePersisted(t *testing.T) {
+	const calls = 200
+	store := &memorySequenceStore{objects: make(map[string]interfaces.StoredObject)}
+
+	sequencer := NewSequencer(sequenceContextKey)
+	sequencer.SetStore(store, "sequencer-test")
+
+	var wait sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			continuePipeline, _ := sequencer.Sequence(appfunction.NewContext("123", dic, ""), []byte("data"))
+			assert.True(t, continuePipeline)
+		}()
+	}
+	wait.Wait()
+
+	require.Len(t, store.objects, 1)
+	assert.True(t, sort.SliceIsSorted(store.saved, func(i, j int) bool { return store.saved[i] < store.saved[j] }),
+		"persisted sequence numbers must never go backwards")
+	assert.Equal(t, uint64(calls), store.saved[len(store.saved)-1])
+	for _, object := range store.objects {
+		assert.Equal(t, sequencerStoreVersion, object.Version)
+	}
+
+	// A new Sequencer, i.e. after a restart, continues from the persisted number
+	restarted := NewSequencer(sequenceContextKey)
+	restarted.SetStore(store, "sequencer-test")
+	continuePipeline, _ := restarted.Sequence(ctx, []byte("data"))
+	require.True(t, continuePipeline)
+	value, _ := ctx.GetValue(sequenceContextKey)
+	assert.Equal(t, strconv.Itoa(calls+1), value)
+	assert.Len(t, store.objects, 1)
+}
+
+func TestSequenceStoreErrors(t *testing.T) {
+	retrieveFailed := &storeMocks.StoreClient{}
+	retrieveFailed.On("RetrieveFromStore", "key").Return(nil, errors.New("store unavailable")).Once()
+	retrieveFailed.On("RetrieveFromStore", "key").Return([]interfaces.StoredObject{{ID: "1", AppServiceKey: "key", Payload: []byte("41")}}, nil)
+	retrieveFailed.On("Update", mock.Anything).Return(nil)
+
+	sequencer := NewSequencer(sequenceContextKey)
+	sequencer.SetStore(retrieveFailed, "key")
+
+	continuePipeline, result := sequencer.Sequence(ctx, []byte("data"))
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "unable to load last sequence number")
+
+	// Loading is retried on the next call
+	continuePipeline, _ = sequencer.Sequence(ctx, []byte("data"))
+	require.True(t, continuePipeline)
+	value, _ := ctx.GetValue(sequenceContextKey)
+	assert.Equal(t, "42", value)
+
+	invalid := &storeMocks.StoreClient{}
+	invalid.On("RetrieveFromStore", "key").Return([]interfaces.StoredObject{{ID: "1", AppServiceKey: "key", Payload: []byte("bad")}}, nil)
+	sequencer = NewSequencer(sequenceContextKey)
+	sequencer.SetStore(invalid, "key")
+	continuePipeline, result = sequencer.Sequence(ctx, []byte("data"))
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "invalid sequence number 'bad'")
+
+	// Failing to persist the number doesn't stop the pipeline
+	saveFailed := &storeMocks.StoreClient{}
+	saveFailed.On("RetrieveFromStore", "key").Return(nil, nil)
+	saveFailed.On("Store", mock.Anything).Return("", errors.New("store unavailable"))
+	sequencer = NewSequencer(sequenceContextKey)
+	sequencer.SetStore(saveFailed, "key")
+	continuePipeline, _ = sequencer.Sequence(ctx, []byte("data"))
+	require.True(t, continuePipeline)
+	value, _ = ctx.GetValue(sequenceContextKey)
+	assert.Equal(t, "1", value)
+}
@@ -0,0 +1,153 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+// SizeGuard enforces a maximum size for the serialized pipeline data, i.e. to avoid the destination rejecting
+// oversized batches with a 413 response.
+type SizeGuard struct {
+	maxBytes int
+	chunking bool
+}
+
+// NewSizeGuard creates, initializes and returns a new instance of SizeGuard which limits the serialized pipeline
+// data to maxBytes. Data larger than maxBytes results in an error unless chunking is enabled with SetChunking.
+func NewSizeGuard(maxBytes int) (*SizeGuard, error) {
+	if maxBytes <= 0 {
+		return nil, errors.New("maximum size must be greater than zero")
+	}
+
+	return &SizeGuard{maxBytes: maxBytes}, nil
+}
+
+// SetChunking specifies whether data larger than the maximum size is split in to chunks rather than resulting
+// in an error
+func (guard *SizeGuard) SetChunking(chunking bool) {
+	guard.chunking = chunking
+}
+
+// EnforceMaxSize measures the data as it would be serialized for export, i.e. as is for string and []byte and as
+// JSON for all other types, and passes it on unchanged if it is within the maximum size.
+// When chunking is enabled, the data is instead always passed on as a []interface{} of chunks, each within the
+// maximum size, which can be sent separately, i.e. using Unbatch. A slice, such as the batched data from Batch, is
+// split between elements in to slices of the same type, so each element is kept whole, while string and []byte data
+// is split in to []byte chunks. Data within the maximum size results in a single chunk.
+// It will return an error and stop the pipeline if the data is larger than the maximum size and can't be chunked,
+// i.e. chunking isn't enabled, a single slice element is larger than the maximum size or the data is neither a
+// slice nor a string, or if no data is received.
+func (guard *SizeGuard) EnforceMaxSize(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function EnforceMaxSize in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	serialized, err := util.CoerceType(data)
+	if err != nil {
+		return false, fmt.Errorf("function EnforceMaxSize in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	if !guard.chunking {
+		if len(serialized) > guard.maxBytes {
+			return false, fmt.Errorf("function EnforceMaxSize in pipeline '%s': data size of %d bytes exceeds the maximum of %d bytes",
+				ctx.PipelineId(), len(serialized), guard.maxBytes)
+		}
+
+		return true, data
+	}
+
+	if len(serialized) <= guard.maxBytes {
+		return true, []interface{}{data}
+	}
+
+	var chunks []interface{}
+	switch input := data.(type) {
+	case string, []byte:
+		chunks = guard.chunkBytes(serialized)
+	default:
+		if reflect.ValueOf(input).Kind() != reflect.Slice {
+			return false, fmt.Errorf("function EnforceMaxSize in pipeline '%s': data size of %d bytes exceeds the maximum of %d bytes and data of type %T can't be chunked",
+				ctx.PipelineId(), len(serialized), guard.maxBytes, data)
+		}
+
+		chunks, err = guard.chunkSlice(reflect.ValueOf(input))
+		if err != nil {
+			return false, fmt.Errorf("function EnforceMaxSize in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+		}
+	}
+
+	ctx.LoggingClient().Debugf("Split %d bytes of data in to %d chunks of at most %d bytes in pipeline '%s'",
+		len(serialized), len(chunks), guard.maxBytes, ctx.PipelineId())
+
+	return true, chunks
+}
+
+// chunkBytes splits the data in to chunks of the maximum size, the last of which may be smaller
+func (guard *SizeGuard) chunkBytes(data []byte) []interface{} {
+	var chunks []interface{}
+	for len(data) > guard.maxBytes {
+		chunks = append(chunks, data[:guard.maxBytes])
+		data = data[guard.maxBytes:]
+	}
+
+	return append(chunks, data)
+}
+
+// chunkSlice splits the slice in to consecutive slices of the same type whose JSON is within the maximum size
+func (guard *SizeGuard) chunkSlice(items reflect.Value) ([]interface{}, error) {
+	var chunks []interface{}
+	start := 0
+	// The JSON of a slice is its elements' JSON separated by commas and enclosed in brackets
+	chunkSize := 2
+	for index := 0; index < items.Len(); index++ {
+		element, err := json.Marshal(items.Index(index).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal element %d to JSON: %s", index, err.Error())
+		}
+
+		if 2+len(element) > guard.maxBytes {
+			return nil, fmt.Errorf("element %d of %d bytes can't be chunked within the maximum of %d bytes", index, len(element), guard.maxBytes)
+		}
+
+		elementSize := len(element)
+		if index > start {
+			elementSize++
+		}
+
+		if chunkSize+elementSize > guard.maxBytes {
+			chunks = append(chunks, items.Slice(start, index).Interface())
+			start = index
+			chunkSize = 2
+			elementSize = len(element)
+		}
+
+		chunkSize += elementSize
+	}
+
+	if start < items.Len() {
+		chunks = append(chunks, items.Slice(start, items.Len()).Interface())
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,189 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSizeGuard(t *testing.T) {
+	_, err := NewSizeGuard(1)
+	require.NoError(t, err)
+
+	_, err = NewSizeGuard(0)
+	require.Error(t, err)
+
+	_, err = NewSizeGuard(-1)
+	require.Error(t, err)
+}
+
+func TestSizeGuardEnforceMaxSize(t *testing.T) {
+	events := []dtos.Event{
+		dtos.NewEvent(profileName1, "device-1", sourceName1),
+		dtos.NewEvent(profileName1, "device-2", sourceName1),
+		dtos.NewEvent(profileName1, "device-3", sourceName1),
+	}
+	eventsJSON, err := json.Marshal(events)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name          string
+		Data          interface{}
+		MaxBytes      int
+		ExpectedError string
+	}{
+		{"string at maximum", "0123456789", 10, ""},
+		{"string over maximum", "0123456789", 9, "data size of 10 bytes exceeds the maximum of 9 bytes"},
+		{"bytes at maximum", []byte("0123456789"), 10, ""},
+		{"bytes over maximum", []byte("0123456789"), 9, "data size of 10 bytes exceeds the maximum of 9 bytes"},
+		{"events at maximum", events, len(eventsJSON), ""},
+		{"events over maximum", events, len(eventsJSON) - 1, "exceeds the maximum"},
+		{"no data", nil, 10, "No Data Received"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			guard, err := NewSizeGuard(test.MaxBytes)
+			require.NoError(t, err)
+
+			continuePipeline, result := guard.EnforceMaxSize(ctx, test.Data)
+			if len(test.ExpectedError) > 0 {
+				require.False(t, continuePipeline)
+				require.Error(t, result.(error))
+				assert.Contains(t, result.(error).Error(), test.ExpectedError)
+				return
+			}
+
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, test.Data, result)
+		})
+	}
+}
+
+func TestSizeGuardEnforceMaxSizeChunkingBytes(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Data     interface{}
+		MaxBytes int
+		Expected []interface{}
+	}{
+		{"at maximum", "0123456789", 10, []interface{}{"0123456789"}},
+		{"one over maximum", "0123456789", 9, []interface{}{[]byte("012345678"), []byte("9")}},
+		{"multiple of maximum", []byte("0123456789"), 5, []interface{}{[]byte("01234"), []byte("56789")}},
+		{"not multiple of maximum", []byte("0123456789"), 4, []interface{}{[]byte("0123"), []byte("4567"), []byte("89")}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			guard, err := NewSizeGuard(test.MaxBytes)
+			require.NoError(t, err)
+			guard.SetChunking(true)
+
+			continuePipeline, result := guard.EnforceMaxSize(ctx, test.Data)
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, test.Expected, result)
+		})
+	}
+}
+
+func TestSizeGuardEnforceMaxSizeChunkingSlices(t *testing.T) {
+	events := []dtos.Event{
+		dtos.NewEvent(profileName1, "device-1", sourceName1),
+		dtos.NewEvent(profileName1, "device-2", sourceName1),
+		dtos.NewEvent(profileName1, "device-3", sourceName1),
+	}
+	eventsJSON, err := json.Marshal(events)
+	require.NoError(t, err)
+	twoEventsJSON, err := json.Marshal(events[:2])
+	require.NoError(t, err)
+	oneEventJSON, err := json.Marshal(events[:1])
+	require.NoError(t, err)
+
+	batched := [][]byte{[]byte(`{"a":1}`), []byte(`{"b":2}`), []byte(`{"c":3}`)}
+	batchedJSON, err := json.Marshal(batched)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name     string
+		Data     interface{}
+		MaxBytes int
+		Expected []interface{}
+	}{
+		{"events at maximum", events, len(eventsJSON), []interface{}{events}},
+		{"events one over maximum", events, len(eventsJSON) - 1, []interface{}{events[:2], events[2:]}},
+		{"two events at maximum", events, len(twoEventsJSON), []interface{}{events[:2], events[2:]}},
+		{"two events one over maximum", events, len(twoEventsJSON) - 1, []interface{}{events[:1], events[1:2], events[2:]}},
+		{"one event at maximum", events, len(oneEventJSON), []interface{}{events[:1], events[1:2], events[2:]}},
+		{"batched bytes", batched, len(batchedJSON) - 1, []interface{}{batched[:2], batched[2:]}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			guard, err := NewSizeGuard(test.MaxBytes)
+			require.NoError(t, err)
+			guard.SetChunking(true)
+
+			continuePipeline, result := guard.EnforceMaxSize(ctx, test.Data)
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, test.Expected, result)
+
+			for _, chunk := range result.([]interface{}) {
+				chunkJSON, err := json.Marshal(chunk)
+				require.NoError(t, err)
+				assert.LessOrEqual(t, len(chunkJSON), test.MaxBytes)
+			}
+		})
+	}
+}
+
+func TestSizeGuardEnforceMaxSizeChunkingErrors(t *testing.T) {
+	events := []dtos.Event{
+		dtos.NewEvent(profileName1, "device-1", sourceName1),
+		dtos.NewEvent(profileName1, strings.Repeat("d", 100), sourceName1),
+	}
+	oneEventJSON, err := json.Marshal(events[:1])
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name          string
+		Data          interface{}
+		ExpectedError string
+	}{
+		{"element over maximum", events, "element 1 of"},
+		{"not a slice", map[string]string{"device": strings.Repeat("d", 200)}, "can't be chunked"},
+		{"no data", nil, "No Data Received"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			guard, err := NewSizeGuard(len(oneEventJSON))
+			require.NoError(t, err)
+			guard.SetChunking(true)
+
+			continuePipeline, result := guard.EnforceMaxSize(ctx, test.Data)
+			require.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			assert.Contains(t, result.(error).Error(), test.ExpectedError)
+		})
+	}
+}
@@ -17,9 +17,18 @@
 package transforms
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
 )
 
+// placeholderSpec matches the {key} placeholders in formats, as used by ctx.ApplyValues
+var placeholderSpec = regexp.MustCompile("{[^}]*}")
+
 // StringValuesFormatter defines a function signature to perform string formatting operations using an AppFunction payload.
 type StringValuesFormatter func(string, interfaces.AppFunctionContext, interface{}) (string, error)
 
@@ -31,3 +40,44 @@ func (f StringValuesFormatter) invoke(format string, ctx interfaces.AppFunctionC
 		return f(format, ctx, data)
 	}
 }
+
+// EventValuesFormatter is a StringValuesFormatter which resolves the {deviceName}, {profileName} and {sourceName}
+// placeholders from the Event passed as the AppFunction payload, and all other placeholders from the context storage.
+// Placeholders are matched case-insensitively. An error is returned if any placeholder can't be resolved.
+func EventValuesFormatter(format string, ctx interfaces.AppFunctionContext, data interface{}) (string, error) {
+	event, isEvent := data.(dtos.Event)
+
+	var unresolved []string
+	result := placeholderSpec.ReplaceAllStringFunc(format, func(placeholder string) string {
+		key := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(placeholder, "{"), "}"))
+
+		value := ""
+		if isEvent {
+			switch key {
+			case interfaces.DEVICENAME:
+				value = event.DeviceName
+			case interfaces.PROFILENAME:
+				value = event.ProfileName
+			case interfaces.SOURCENAME:
+				value = event.SourceName
+			}
+		}
+
+		if len(value) == 0 {
+			value, _ = ctx.GetValue(key)
+		}
+
+		if len(value) == 0 {
+			unresolved = append(unresolved, placeholder)
+			return placeholder
+		}
+
+		return value
+	})
+
+	if len(unresolved) > 0 {
+		return "", fmt.Errorf("unable to resolve placeholders %s in '%s'", strings.Join(unresolved, ", "), format)
+	}
+
+	return result, nil
+}
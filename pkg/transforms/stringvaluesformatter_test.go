@@ -19,6 +19,7 @@ package transforms
 import (
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal/appfunction"
 	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -53,3 +54,41 @@ func TestStringValuesFormatter_invoke(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "custom-formatted", result)
 }
+
+func TestEventValuesFormatter(t *testing.T) {
+	ctx := appfunction.NewContext(uuid.NewString(), nil, "")
+	ctx.AddValue("site", "plant1")
+	ctx.AddValue(interfaces.DEVICENAME, "context-device")
+
+	event := dtos.Event{DeviceName: "event-device", ProfileName: "profile", SourceName: "source"}
+
+	tests := []struct {
+		Name          string
+		Format        string
+		Data          interface{}
+		Expected      string
+		ErrorContains string
+	}{
+		{"no placeholders", "sensors/data", event, "sensors/data", ""},
+		{"device name from event", "sensors/{deviceName}/data", event, "sensors/event-device/data", ""},
+		{"event and context values", "{site}/{profilename}/{SourceName}/{deviceName}", event, "plant1/profile/source/event-device", ""},
+		{"device name from context when not an event", "sensors/{deviceName}/data", []byte("data"), "sensors/context-device/data", ""},
+		{"missing key", "sensors/{deviceName}/{line}/{area}", event, "", "unable to resolve placeholders {line}, {area}"},
+		{"empty event value not in context", "sensors/{profileName}", dtos.Event{}, "", "unable to resolve placeholders {profileName}"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			result, err := EventValuesFormatter(test.Format, ctx, test.Data)
+
+			if len(test.ErrorContains) > 0 {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.ErrorContains)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.Expected, result)
+		})
+	}
+}
@@ -26,7 +26,10 @@ import (
 
 // Tags contains the list of Tag key/values
 type Tags struct {
-	tags map[string]interface{}
+	tags                map[string]interface{}
+	resolvePlaceholders bool
+	formatter           StringValuesFormatter
+	strict              bool
 }
 
 // NewTags creates, initializes and returns a new instance of Tags using generic interface values
@@ -36,6 +39,19 @@ func NewTags(tags map[string]interface{}) *Tags {
 	}
 }
 
+// NewTagsWithFormatter creates, initializes and returns a new instance of Tags whose string values may contain
+// {placeholder} tokens, which are resolved for each Event using the formatter. ctx.ApplyValues is used to resolve
+// the placeholders from the context storage if the formatter is nil. When strict is true, a placeholder that
+// can't be resolved results in an error, otherwise it is left as-is in the tag value.
+func NewTagsWithFormatter(tags map[string]interface{}, formatter StringValuesFormatter, strict bool) *Tags {
+	return &Tags{
+		tags:                tags,
+		resolvePlaceholders: true,
+		formatter:           formatter,
+		strict:              strict,
+	}
+}
+
 // AddTags adds the pre-configured list of tags to the Event's tags collection.
 func (t *Tags) AddTags(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
 	ctx.LoggingClient().Debugf("Adding tags to Event in pipeline '%s'", ctx.PipelineId())
@@ -55,6 +71,14 @@ func (t *Tags) AddTags(ctx interfaces.AppFunctionContext, data interface{}) (boo
 		}
 
 		for tag, value := range t.tags {
+			if t.resolvePlaceholders {
+				var err error
+				value, err = t.resolveValue(ctx, value, event)
+				if err != nil {
+					return false, fmt.Errorf("function AddTags in pipeline '%s': unable to resolve value for tag '%s': %s", ctx.PipelineId(), tag, err.Error())
+				}
+			}
+
 			event.Tags[tag] = value
 		}
 		ctx.LoggingClient().Debugf("Tags added to Event in pipeline '%s'. Event tags=%v", ctx.PipelineId(), event.Tags)
@@ -64,3 +88,25 @@ func (t *Tags) AddTags(ctx interfaces.AppFunctionContext, data interface{}) (boo
 
 	return true, event
 }
+
+// resolveValue replaces the placeholders in string tag values. In non-strict mode each placeholder is resolved
+// individually so that those which can't be resolved are left as-is.
+func (t *Tags) resolveValue(ctx interfaces.AppFunctionContext, value interface{}, event dtos.Event) (interface{}, error) {
+	format, ok := value.(string)
+	if !ok || !placeholderSpec.MatchString(format) {
+		return value, nil
+	}
+
+	if t.strict {
+		return t.formatter.invoke(format, ctx, event)
+	}
+
+	return placeholderSpec.ReplaceAllStringFunc(format, func(placeholder string) string {
+		resolved, err := t.formatter.invoke(placeholder, ctx, event)
+		if err != nil {
+			ctx.LoggingClient().Debugf("Leaving unresolved tag placeholder %s as-is in pipeline '%s': %s", placeholder, ctx.PipelineId(), err.Error())
+			return placeholder
+		}
+		return resolved
+	}), nil
+}
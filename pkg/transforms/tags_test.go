@@ -17,8 +17,11 @@
 package transforms
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+
 	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
 
 	"github.com/stretchr/testify/assert"
@@ -93,3 +96,76 @@ func TestTags_AddTags(t *testing.T) {
 		})
 	}
 }
+
+func TestTags_AddTagsWithFormatter(t *testing.T) {
+	ctx.AddValue("correlationkey", "abc-123")
+	defer ctx.RemoveValue("correlationkey")
+
+	eventFormatter := func(format string, ctx interfaces.AppFunctionContext, data interface{}) (string, error) {
+		event := data.(dtos.Event)
+		ctx.AddValue("eventdevicename", event.DeviceName)
+		defer ctx.RemoveValue("eventdevicename")
+		return ctx.ApplyValues(format)
+	}
+
+	tagsToAdd := map[string]interface{}{
+		"GatewayId":   "HoustonStore000123",
+		"Coordinates": coordinates,
+		"Correlation": "{correlationkey}",
+		"Source":      "{eventdevicename}/{correlationkey}",
+	}
+
+	tests := []struct {
+		Name          string
+		Formatter     StringValuesFormatter
+		Strict        bool
+		Expected      dtos.Tags
+		ErrorExpected bool
+	}{
+		{
+			Name: "Unresolved left as-is",
+			Expected: dtos.Tags{
+				"GatewayId":   "HoustonStore000123",
+				"Coordinates": coordinates,
+				"Correlation": "abc-123",
+				"Source":      "{eventdevicename}/abc-123",
+			},
+		},
+		{
+			Name:          "Unresolved is error when strict",
+			Strict:        true,
+			ErrorExpected: true,
+		},
+		{
+			Name:      "Resolved from Event using formatter",
+			Formatter: eventFormatter,
+			Strict:    true,
+			Expected: dtos.Tags{
+				"GatewayId":   "HoustonStore000123",
+				"Coordinates": coordinates,
+				"Correlation": "abc-123",
+				"Source":      "device1/abc-123",
+			},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.Name, func(t *testing.T) {
+			target := NewTagsWithFormatter(tagsToAdd, testCase.Formatter, testCase.Strict)
+
+			continuePipeline, result := target.AddTags(ctx, dtos.Event{DeviceName: deviceName1})
+
+			if testCase.ErrorExpected {
+				require.False(t, continuePipeline)
+				require.IsType(t, fmt.Errorf(""), result)
+				assert.Contains(t, result.(error).Error(), "unable to resolve value for tag 'Source'")
+				return
+			}
+
+			require.True(t, continuePipeline)
+			actual, ok := result.(dtos.Event)
+			require.True(t, ok, "Result not an Event")
+			assert.Equal(t, testCase.Expected, actual.Tags)
+		})
+	}
+}
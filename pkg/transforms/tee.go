@@ -0,0 +1,119 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// Tee sends a copy of the data through a side list of functions, such as a shadow export for auditing or
+// debugging, while the main pipeline continues with the original data regardless of the side functions' result.
+type Tee struct {
+	functions []interfaces.AppFunction
+	async     bool
+}
+
+// NewTee creates, initializes and returns a new instance of Tee which executes the side functions on a copy of the data.
+func NewTee(functions ...interfaces.AppFunction) (*Tee, error) {
+	if len(functions) == 0 {
+		return nil, errors.New("at least one function must be specified for the side pipeline")
+	}
+
+	return &Tee{
+		functions: functions,
+	}, nil
+}
+
+// SetAsync sets whether the side functions are executed in the background, so the main pipeline doesn't wait for
+// them to complete. By default the side functions complete before the main pipeline continues.
+func (tee *Tee) SetAsync(async bool) {
+	tee.async = async
+}
+
+// Tee executes the side functions on a copy of the data using a clone of the context, so values set in the
+// context by the side functions don't affect the main pipeline. Errors from the side functions are logged and
+// the original data is always passed on to the next function.
+// It will return an error and stop the pipeline if no data is received.
+func (tee *Tee) Tee(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Tee in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	sideCtx := ctx.Clone()
+	sideData := copyTeeData(data)
+
+	if tee.async {
+		go tee.execute(sideCtx, sideData)
+	} else {
+		tee.execute(sideCtx, sideData)
+	}
+
+	return true, data
+}
+
+func (tee *Tee) execute(ctx interfaces.AppFunctionContext, data interface{}) {
+	completed, result := executeFunctions(ctx, tee.functions, data)
+	if err, ok := result.(error); ok && !completed {
+		ctx.LoggingClient().Errorf("Side pipeline of function Tee in pipeline '%s' failed: %s. Main pipeline continues", ctx.PipelineId(), err.Error())
+		return
+	}
+
+	ctx.LoggingClient().Debugf("Side pipeline of function Tee in pipeline '%s' completed", ctx.PipelineId())
+}
+
+// copyTeeData copies the data so the side functions can't modify what the main pipeline receives. Events and
+// byte slices are copied, other types are passed as is since they are either immutable or can't be generically copied.
+func copyTeeData(data interface{}) interface{} {
+	switch value := data.(type) {
+	case []byte:
+		return append([]byte(nil), value...)
+	case dtos.Event:
+		return copyEvent(value)
+	case *dtos.Event:
+		event := copyEvent(*value)
+		return &event
+	default:
+		return data
+	}
+}
+
+func copyEvent(event dtos.Event) dtos.Event {
+	if event.Tags != nil {
+		event.Tags = copyTags(event.Tags)
+	}
+
+	if event.Readings != nil {
+		readings := make([]dtos.BaseReading, len(event.Readings))
+		for index, reading := range event.Readings {
+			if reading.Tags != nil {
+				reading.Tags = copyTags(reading.Tags)
+			}
+			if reading.BinaryValue != nil {
+				reading.BinaryValue = append([]byte(nil), reading.BinaryValue...)
+			}
+			readings[index] = reading
+		}
+		event.Readings = readings
+	}
+
+	return event
+}
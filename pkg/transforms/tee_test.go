@@ -0,0 +1,119 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+func TestNewTee(t *testing.T) {
+	_, err := NewTee()
+	assert.Error(t, err)
+
+	tee, err := NewTee(func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, data
+	})
+	require.NoError(t, err)
+	assert.Len(t, tee.functions, 1)
+	assert.False(t, tee.async)
+}
+
+func TestTee(t *testing.T) {
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	event.Tags = map[string]interface{}{"site": "plant1"}
+	_ = event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(21))
+
+	var sideReceived []interface{}
+	side := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		sideReceived = append(sideReceived, data)
+		sideEvent := data.(dtos.Event)
+		// Modifications made by the side functions must not reach the main pipeline
+		sideEvent.Tags["site"] = "modified"
+		sideEvent.Readings[0].Value = "99"
+		ctx.AddValue("side", "true")
+		return true, sideEvent
+	}
+
+	tee, err := NewTee(side, func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return false, errors.New("side export failed")
+	})
+	require.NoError(t, err)
+
+	teeCtx := ctx.Clone()
+	continuePipeline, result := tee.Tee(teeCtx, event)
+	require.True(t, continuePipeline)
+
+	require.Len(t, sideReceived, 1)
+	assert.Equal(t, deviceName1, sideReceived[0].(dtos.Event).DeviceName)
+
+	actual := result.(dtos.Event)
+	assert.Equal(t, "plant1", actual.Tags["site"])
+	assert.Equal(t, "21", actual.Readings[0].Value)
+	_, found := teeCtx.GetValue("side")
+	assert.False(t, found)
+}
+
+func TestTeeBytes(t *testing.T) {
+	tee, err := NewTee(func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		data.([]byte)[0] = 'X'
+		return true, data
+	})
+	require.NoError(t, err)
+
+	continuePipeline, result := tee.Tee(ctx, []byte("hello"))
+	require.True(t, continuePipeline)
+	assert.Equal(t, []byte("hello"), result)
+}
+
+func TestTeeAsync(t *testing.T) {
+	received := make(chan interface{}, 1)
+	release := make(chan struct{})
+	tee, err := NewTee(func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		<-release
+		received <- data
+		return false, errors.New("side export failed")
+	})
+	require.NoError(t, err)
+	tee.SetAsync(true)
+
+	// The main pipeline continues while the side function is still blocked
+	continuePipeline, result := tee.Tee(ctx, msgStr)
+	require.True(t, continuePipeline)
+	assert.Equal(t, msgStr, result)
+
+	close(release)
+	assert.Equal(t, msgStr, <-received)
+}
+
+func TestTeeNoData(t *testing.T) {
+	tee, err := NewTee(func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, data
+	})
+	require.NoError(t, err)
+
+	continuePipeline, result := tee.Tee(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// Threshold filter modes
+const (
+	// ThresholdPassInside passes values inside the band, including the low and high values themselves
+	ThresholdPassInside = "inside"
+	// ThresholdPassOutside passes values outside the band, i.e. less than the low value or greater than the high value
+	ThresholdPassOutside = "outside"
+)
+
+// ThresholdFilter drops Events whose readings for a resource are not inside, or outside, a numeric band, such as
+// for simple threshold alerting on a temperature below 0 or above 40.
+type ThresholdFilter struct {
+	resourceName   string
+	low            float64
+	high           float64
+	mode           string
+	passNonNumeric bool
+}
+
+// NewThresholdFilter creates, initializes and returns a new instance of ThresholdFilter which passes the readings for
+// the resource whose value is inside the band from low to high when mode is ThresholdPassInside, or outside it when
+// mode is ThresholdPassOutside.
+func NewThresholdFilter(resourceName string, low float64, high float64, mode string) (*ThresholdFilter, error) {
+	if len(resourceName) == 0 {
+		return nil, errors.New("resource name must be specified")
+	}
+
+	if math.IsNaN(low) || math.IsNaN(high) || low > high {
+		return nil, fmt.Errorf("low value %v must not be greater than high value %v", low, high)
+	}
+
+	if mode != ThresholdPassInside && mode != ThresholdPassOutside {
+		return nil, fmt.Errorf("invalid threshold mode '%s', must be '%s' or '%s'", mode, ThresholdPassInside, ThresholdPassOutside)
+	}
+
+	return &ThresholdFilter{
+		resourceName: resourceName,
+		low:          low,
+		high:         high,
+		mode:         mode,
+	}, nil
+}
+
+// SetPassNonNumeric sets whether readings for the resource with a non-numeric value, or a value that can't be parsed,
+// are passed rather than dropped.
+func (filter *ThresholdFilter) SetPassNonNumeric(pass bool) {
+	filter.passNonNumeric = pass
+}
+
+// FilterByThreshold removes the readings for the resource which don't pass the threshold from the Event, keeping the
+// readings for other resources. The pipeline is stopped if none of the Event's readings for the resource pass,
+// including when the Event has no readings for the resource.
+// This function will return an error and stop the pipeline if a non-edgex event is received or if no data is received.
+func (filter *ThresholdFilter) FilterByThreshold(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function FilterByThreshold in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function FilterByThreshold in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	passed := 0
+	readings := make([]dtos.BaseReading, 0, len(event.Readings))
+	for _, reading := range event.Readings {
+		if reading.ResourceName != filter.resourceName {
+			readings = append(readings, reading)
+			continue
+		}
+
+		if !filter.passes(reading) {
+			ctx.LoggingClient().Debugf("Reading for %s with value '%s' not accepted by threshold in pipeline '%s'",
+				reading.ResourceName, reading.Value, ctx.PipelineId())
+			continue
+		}
+
+		passed++
+		readings = append(readings, reading)
+	}
+
+	if passed == 0 {
+		ctx.LoggingClient().Debugf("Event from %s not accepted: no '%s' readings pass the threshold in pipeline '%s'",
+			event.DeviceName, filter.resourceName, ctx.PipelineId())
+		return false, nil
+	}
+
+	event.Readings = readings
+	return true, event
+}
+
+func (filter *ThresholdFilter) passes(reading dtos.BaseReading) bool {
+	value, ok := numericReadingValue(reading)
+	if !ok || math.IsNaN(value) {
+		return filter.passNonNumeric
+	}
+
+	inside := value >= filter.low && value <= filter.high
+	if filter.mode == ThresholdPassInside {
+		return inside
+	}
+
+	return !inside
+}
@@ -0,0 +1,151 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewThresholdFilter(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Resource    string
+		Low         float64
+		High        float64
+		Mode        string
+		ExpectError bool
+	}{
+		{"Valid inside", "temperature", 0, 40, ThresholdPassInside, false},
+		{"Valid outside with equal bounds", "temperature", 10, 10, ThresholdPassOutside, false},
+		{"Missing resource", "", 0, 40, ThresholdPassInside, true},
+		{"Low greater than high", "temperature", 40, 0, ThresholdPassInside, true},
+		{"Bad mode", "temperature", 0, 40, "between", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := NewThresholdFilter(test.Resource, test.Low, test.High, test.Mode)
+			assert.Equal(t, test.ExpectError, err != nil)
+		})
+	}
+}
+
+func TestFilterByThreshold(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Mode       string
+		Value      float64
+		ExpectPass bool
+	}{
+		{"Inside - below band", ThresholdPassInside, -0.5, false},
+		{"Inside - at low", ThresholdPassInside, 0, true},
+		{"Inside - in band", ThresholdPassInside, 21.5, true},
+		{"Inside - at high", ThresholdPassInside, 40, true},
+		{"Inside - above band", ThresholdPassInside, 40.1, false},
+		{"Outside - below band", ThresholdPassOutside, -0.5, true},
+		{"Outside - at low", ThresholdPassOutside, 0, false},
+		{"Outside - in band", ThresholdPassOutside, 21.5, false},
+		{"Outside - at high", ThresholdPassOutside, 40, false},
+		{"Outside - above band", ThresholdPassOutside, 40.1, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			filter, err := NewThresholdFilter("temperature", 0, 40, test.Mode)
+			require.NoError(t, err)
+
+			event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+			_ = event.AddSimpleReading("temperature", common.ValueTypeFloat64, test.Value)
+
+			continuePipeline, result := filter.FilterByThreshold(ctx, event)
+			assert.Equal(t, test.ExpectPass, continuePipeline)
+			if test.ExpectPass {
+				assert.Equal(t, event, result)
+			} else {
+				assert.Nil(t, result)
+			}
+		})
+	}
+}
+
+func TestFilterByThresholdMultipleReadings(t *testing.T) {
+	filter, err := NewThresholdFilter("temperature", 0, 40, ThresholdPassOutside)
+	require.NoError(t, err)
+
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	_ = event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(-5))
+	_ = event.AddSimpleReading("humidity", common.ValueTypeInt32, int32(50))
+	_ = event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(20))
+	_ = event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(45))
+
+	// The in band temperature reading is removed while the other resource's reading is kept
+	continuePipeline, result := filter.FilterByThreshold(ctx, event)
+	require.True(t, continuePipeline)
+	readings := result.(dtos.Event).Readings
+	require.Len(t, readings, 3)
+	assert.Equal(t, event.Readings[0], readings[0])
+	assert.Equal(t, event.Readings[1], readings[1])
+	assert.Equal(t, event.Readings[3], readings[2])
+
+	// Events without any passing readings for the resource are dropped
+	event.Readings = []dtos.BaseReading{event.Readings[1], event.Readings[2]}
+	continuePipeline, result = filter.FilterByThreshold(ctx, event)
+	assert.False(t, continuePipeline)
+	assert.Nil(t, result)
+}
+
+func TestFilterByThresholdNonNumeric(t *testing.T) {
+	filter, err := NewThresholdFilter("temperature", 0, 40, ThresholdPassInside)
+	require.NoError(t, err)
+
+	nonNumeric := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	_ = nonNumeric.AddSimpleReading("temperature", common.ValueTypeString, "unavailable")
+
+	notANumber := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	_ = notANumber.AddSimpleReading("temperature", common.ValueTypeFloat64, 0.0)
+	notANumber.Readings[0].Value = "NaN"
+
+	for _, event := range []dtos.Event{nonNumeric, notANumber} {
+		continuePipeline, _ := filter.FilterByThreshold(ctx, event)
+		assert.False(t, continuePipeline)
+	}
+
+	filter.SetPassNonNumeric(true)
+	for _, event := range []dtos.Event{nonNumeric, notANumber} {
+		continuePipeline, result := filter.FilterByThreshold(ctx, event)
+		assert.True(t, continuePipeline)
+		assert.Equal(t, event, result)
+	}
+}
+
+func TestFilterByThresholdErrors(t *testing.T) {
+	filter, err := NewThresholdFilter("temperature", 0, 40, ThresholdPassInside)
+	require.NoError(t, err)
+
+	continuePipeline, result := filter.FilterByThreshold(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = filter.FilterByThreshold(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
@@ -0,0 +1,184 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+)
+
+// Layouts for the TimestampFormatter which convert the nanosecond origins to epoch numbers rather than
+// formatting them using a Go time layout.
+const (
+	// TimestampLayoutUnix converts origins to epoch seconds
+	TimestampLayoutUnix = "unix"
+	// TimestampLayoutUnixMilli converts origins to epoch milliseconds
+	TimestampLayoutUnixMilli = "unixmilli"
+	// TimestampLayoutUnixMicro converts origins to epoch microseconds
+	TimestampLayoutUnixMicro = "unixmicro"
+)
+
+// TimestampFormatter formats the Event's nanosecond Origin, and optionally the Readings' origins, using a
+// configured layout and timezone.
+type TimestampFormatter struct {
+	layout          string
+	location        *time.Location
+	tagName         string
+	includeReadings bool
+}
+
+// NewTimestampFormatter creates, initializes and returns a new instance of TimestampFormatter. layout is either a
+// Go time layout, i.e. time.RFC3339 or "2006-01-02T15:04:05.000Z07:00", or one of the TimestampLayoutUnix layouts.
+// timezone is an IANA time zone name, i.e. "America/New_York", or "Local", and defaults to UTC if empty.
+func NewTimestampFormatter(layout string, timezone string) (*TimestampFormatter, error) {
+	if len(layout) == 0 {
+		return nil, errors.New("layout must be specified")
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s': %s", timezone, err.Error())
+	}
+
+	return &TimestampFormatter{
+		layout:   layout,
+		location: location,
+	}, nil
+}
+
+// SetTagName sets the name of the tag the formatted origins are added to, keeping the original origins, rather
+// than replacing the origins with the formatted values.
+func (f *TimestampFormatter) SetTagName(tagName string) {
+	f.tagName = tagName
+}
+
+// SetIncludeReadings sets whether the Readings' origins are formatted as well as the Event's Origin
+func (f *TimestampFormatter) SetIncludeReadings(includeReadings bool) {
+	f.includeReadings = includeReadings
+}
+
+// FormatTimestamps formats the origins of the Event received. If a tag name is set, the formatted origins are added
+// to the Event's and Readings' tags and the Event is passed on. Otherwise, since an Event's origins are numbers,
+// the Event is converted to JSON with the origins replaced by the formatted values, which are numbers for the
+// TimestampLayoutUnix layouts and strings otherwise.
+// It will return an error and stop the pipeline if a non-Event is received or if no data is received.
+func (f *TimestampFormatter) FormatTimestamps(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function FormatTimestamps in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	ctx.LoggingClient().Debugf("Formatting timestamps in pipeline '%s'", ctx.PipelineId())
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function FormatTimestamps in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	if len(f.tagName) > 0 {
+		return true, f.addTags(event)
+	}
+
+	result, err := f.replaceOrigins(event)
+	if err != nil {
+		return false, fmt.Errorf("unable to format timestamps in pipeline '%s': %s", ctx.PipelineId(), err.Error())
+	}
+
+	ctx.SetResponseContentType(common.ContentTypeJSON)
+	return true, result
+}
+
+// addTags adds the formatted origins to the tags. The Readings are copied so the received Event isn't modified.
+func (f *TimestampFormatter) addTags(event dtos.Event) dtos.Event {
+	event.Tags = copyTags(event.Tags)
+	event.Tags[f.tagName] = fmt.Sprint(f.format(event.Origin))
+
+	if f.includeReadings {
+		readings := make([]dtos.BaseReading, len(event.Readings))
+		for index, reading := range event.Readings {
+			reading.Tags = copyTags(reading.Tags)
+			reading.Tags[f.tagName] = fmt.Sprint(f.format(reading.Origin))
+			readings[index] = reading
+		}
+		event.Readings = readings
+	}
+
+	return event
+}
+
+// replaceOrigins returns the JSON of the Event with the origins replaced by the formatted values
+func (f *TimestampFormatter) replaceOrigins(event dtos.Event) (string, error) {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+
+	var eventMap map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(eventJSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&eventMap); err != nil {
+		return "", err
+	}
+
+	eventMap["origin"] = f.format(event.Origin)
+
+	if f.includeReadings {
+		readings, _ := eventMap["readings"].([]interface{})
+		for index, reading := range readings {
+			if readingMap, ok := reading.(map[string]interface{}); ok && index < len(event.Readings) {
+				readingMap["origin"] = f.format(event.Readings[index].Origin)
+			}
+		}
+	}
+
+	result, err := json.Marshal(eventMap)
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// format returns the nanosecond origin as an epoch number for the TimestampLayoutUnix layouts and as a string
+// formatted using the layout in the configured timezone otherwise.
+func (f *TimestampFormatter) format(origin int64) interface{} {
+	switch f.layout {
+	case TimestampLayoutUnix:
+		return origin / int64(time.Second)
+	case TimestampLayoutUnixMilli:
+		return origin / int64(time.Millisecond)
+	case TimestampLayoutUnixMicro:
+		return origin / int64(time.Microsecond)
+	default:
+		return time.Unix(0, origin).In(f.location).Format(f.layout)
+	}
+}
+
+func copyTags(tags map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(tags)+1)
+	for key, value := range tags {
+		result[key] = value
+	}
+	return result
+}
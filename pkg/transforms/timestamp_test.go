@@ -0,0 +1,190 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 2024-03-10T06:59:59.123456789Z, one second before the US Eastern DST transition
+const timestampTestOrigin = int64(1710053999123456789)
+
+func timestampTestEvent() dtos.Event {
+	event := dtos.NewEvent(profileName1, deviceName1, sourceName1)
+	event.Origin = timestampTestOrigin
+	_ = event.AddSimpleReading("temperature", common.ValueTypeInt32, int32(21))
+	event.Readings[0].Origin = timestampTestOrigin + int64(2*time.Second)
+	return event
+}
+
+func TestNewTimestampFormatter(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Layout      string
+		Timezone    string
+		ExpectError bool
+	}{
+		{"Valid - UTC default", time.RFC3339, "", false},
+		{"Valid - named timezone", time.RFC3339, "America/New_York", false},
+		{"Valid - unix layout", TimestampLayoutUnixMilli, "", false},
+		{"Invalid - missing layout", "", "", true},
+		{"Invalid - unknown timezone", time.RFC3339, "Mars/Olympus_Mons", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			formatter, err := NewTimestampFormatter(test.Layout, test.Timezone)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, formatter)
+		})
+	}
+}
+
+func TestFormatTimestampsTag(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Layout          string
+		Timezone        string
+		ExpectedEvent   string
+		ExpectedReading string
+	}{
+		{"RFC3339 UTC", time.RFC3339, "", "2024-03-10T06:59:59Z", "2024-03-10T07:00:01Z"},
+		{"RFC3339Nano UTC", time.RFC3339Nano, "UTC", "2024-03-10T06:59:59.123456789Z", "2024-03-10T07:00:01.123456789Z"},
+		{"Milliseconds layout", "2006-01-02T15:04:05.000Z07:00", "UTC", "2024-03-10T06:59:59.123Z", "2024-03-10T07:00:01.123Z"},
+		// The Event is before and the Reading after the spring forward from 02:00 EST to 03:00 EDT
+		{"New York spring forward", time.RFC3339, "America/New_York", "2024-03-10T01:59:59-05:00", "2024-03-10T03:00:01-04:00"},
+		{"Zone abbreviation", "2006-01-02 15:04:05 MST", "America/New_York", "2024-03-10 01:59:59 EST", "2024-03-10 03:00:01 EDT"},
+		{"Unix seconds", TimestampLayoutUnix, "", "1710053999", "1710054001"},
+		{"Unix milliseconds", TimestampLayoutUnixMilli, "", "1710053999123", "1710054001123"},
+		{"Unix microseconds", TimestampLayoutUnixMicro, "America/New_York", "1710053999123456", "1710054001123456"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			formatter, err := NewTimestampFormatter(test.Layout, test.Timezone)
+			require.NoError(t, err)
+			formatter.SetTagName("originFormatted")
+			formatter.SetIncludeReadings(true)
+
+			input := timestampTestEvent()
+			continuePipeline, result := formatter.FormatTimestamps(ctx, input)
+			require.True(t, continuePipeline, result)
+
+			event, ok := result.(dtos.Event)
+			require.True(t, ok)
+			assert.Equal(t, timestampTestOrigin, event.Origin)
+			assert.Equal(t, test.ExpectedEvent, event.Tags["originFormatted"])
+			assert.Equal(t, test.ExpectedReading, event.Readings[0].Tags["originFormatted"])
+
+			// The received Event isn't modified
+			assert.Nil(t, input.Tags)
+			assert.Nil(t, input.Readings[0].Tags)
+		})
+	}
+}
+
+func TestFormatTimestampsReplace(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Layout          string
+		Timezone        string
+		IncludeReadings bool
+		ExpectedEvent   interface{}
+		ExpectedReading interface{}
+	}{
+		{"RFC3339 New York", time.RFC3339, "America/New_York", true, "2024-03-10T01:59:59-05:00", "2024-03-10T03:00:01-04:00"},
+		{"Event only", time.RFC3339, "America/New_York", false, "2024-03-10T01:59:59-05:00", json.Number("1710054001123456789")},
+		{"Unix milliseconds", TimestampLayoutUnixMilli, "", true, json.Number("1710053999123"), json.Number("1710054001123")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			formatter, err := NewTimestampFormatter(test.Layout, test.Timezone)
+			require.NoError(t, err)
+			formatter.SetIncludeReadings(test.IncludeReadings)
+
+			continuePipeline, result := formatter.FormatTimestamps(ctx, timestampTestEvent())
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, common.ContentTypeJSON, ctx.ResponseContentType())
+
+			resultJSON, ok := result.(string)
+			require.True(t, ok)
+
+			var actual struct {
+				DeviceName string      `json:"deviceName"`
+				Origin     interface{} `json:"origin"`
+				Readings   []struct {
+					ResourceName string      `json:"resourceName"`
+					Value        string      `json:"value"`
+					Origin       interface{} `json:"origin"`
+				} `json:"readings"`
+			}
+			decoder := json.NewDecoder(strings.NewReader(resultJSON))
+			decoder.UseNumber()
+			require.NoError(t, decoder.Decode(&actual))
+
+			assert.Equal(t, deviceName1, actual.DeviceName)
+			assert.Equal(t, test.ExpectedEvent, actual.Origin)
+			require.Len(t, actual.Readings, 1)
+			assert.Equal(t, "temperature", actual.Readings[0].ResourceName)
+			assert.Equal(t, "21", actual.Readings[0].Value)
+			assert.Equal(t, test.ExpectedReading, actual.Readings[0].Origin)
+		})
+	}
+}
+
+func TestFormatTimestampsFallBack(t *testing.T) {
+	formatter, err := NewTimestampFormatter(time.RFC3339, "America/New_York")
+	require.NoError(t, err)
+	formatter.SetTagName("local")
+
+	// 05:30Z and 06:30Z on 2024-11-03 are both 01:30 local, before and after falling back from EDT to EST
+	for origin, expected := range map[int64]string{
+		time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC).UnixNano(): "2024-11-03T01:30:00-04:00",
+		time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC).UnixNano(): "2024-11-03T01:30:00-05:00",
+	} {
+		continuePipeline, result := formatter.FormatTimestamps(ctx, dtos.Event{Origin: origin})
+		require.True(t, continuePipeline, result)
+		assert.Equal(t, expected, result.(dtos.Event).Tags["local"])
+	}
+}
+
+func TestFormatTimestampsErrors(t *testing.T) {
+	formatter, err := NewTimestampFormatter(time.RFC3339, "")
+	require.NoError(t, err)
+
+	continuePipeline, result := formatter.FormatTimestamps(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = formatter.FormatTimestamps(ctx, "not an event")
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not an Event")
+}
@@ -0,0 +1,102 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// tracerName is the instrumentation name used for the client spans created around HTTP requests
+const tracerName = "github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/transforms"
+
+// httpTracer propagates the trace context to the destination of HTTP requests and optionally creates
+// a client span around each request.
+type httpTracer struct {
+	propagator propagation.TextMapPropagator
+	// tracer is nil when client spans aren't created
+	tracer trace.Tracer
+}
+
+// start returns the request with the trace context injected into its headers along with the function to call once
+// the request has completed. The parent span is taken from the trace context in the pipeline's context storage,
+// i.e. the 'traceparent' and 'tracestate' values, if present, otherwise from the request's context.
+func (t *httpTracer) start(ctx interfaces.AppFunctionContext, req *http.Request) (*http.Request, func(*http.Response, error)) {
+	spanCtx := t.propagator.Extract(req.Context(), contextStorageCarrier{ctx: ctx})
+	end := func(*http.Response, error) {}
+
+	if t.tracer != nil {
+		var span trace.Span
+		spanCtx, span = t.tracer.Start(spanCtx, "HTTP "+req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.full", req.URL.Redacted()),
+				attribute.String("pipeline.id", ctx.PipelineId()),
+			))
+
+		end = func(response *http.Response, err error) {
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case response != nil:
+				span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+				if response.StatusCode >= http.StatusBadRequest {
+					span.SetStatus(codes.Error, response.Status)
+				}
+			}
+
+			span.End()
+		}
+	}
+
+	req = req.WithContext(spanCtx)
+	t.propagator.Inject(spanCtx, propagation.HeaderCarrier(req.Header))
+
+	return req, end
+}
+
+// contextStorageCarrier adapts the pipeline's context storage to satisfy the TextMapCarrier interface
+type contextStorageCarrier struct {
+	ctx interfaces.AppFunctionContext
+}
+
+func (c contextStorageCarrier) Get(key string) string {
+	value, _ := c.ctx.GetValue(key)
+	return value
+}
+
+func (c contextStorageCarrier) Set(key string, value string) {
+	c.ctx.AddValue(key, value)
+}
+
+func (c contextStorageCarrier) Keys() []string {
+	values := c.ctx.GetAllValues()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// Unbatch splits a slice, such as the batched data from Batch with IsEventData set, back in to the individual
+// items and executes the configured functions on each item in turn, as if each item was received individually.
+type Unbatch struct {
+	functions       []interfaces.AppFunction
+	continueOnError bool
+}
+
+// NewUnbatch creates, initializes and returns a new instance of Unbatch which executes the functions on each
+// item. When continueOnError is false, an error from the functions for one item aborts the remaining items.
+// Otherwise, the remaining items are still processed and the errors for all the items are returned together.
+func NewUnbatch(continueOnError bool, functions ...interfaces.AppFunction) (*Unbatch, error) {
+	if len(functions) == 0 {
+		return nil, errors.New("at least one function must be specified to execute on the unbatched items")
+	}
+
+	return &Unbatch{
+		functions:       functions,
+		continueOnError: continueOnError,
+	}, nil
+}
+
+// Split executes the configured functions on each item in the slice received. Each item is processed using a clone
+// of the context so values set in the context while processing one item don't affect the others. The results of
+// the items which made it through all the functions are returned as a slice to the next function.
+// A string or []byte is split into the elements of the JSON array it contains, each passed on as []byte, and is
+// otherwise processed as a single item. This is how the retry data is received when retried by Store and Forward.
+// The retry data set by the functions for the items which failed is set on the pipeline's context, as is for a
+// single item and as a JSON array otherwise, so only those items are processed again when retried.
+// It will return an error and stop the pipeline if a non-slice is received, if no data is received or if the
+// functions return an error for any of the items.
+func (unbatch *Unbatch) Split(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Split in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	switch data.(type) {
+	case string, []byte:
+		// Split as a JSON array by splitBatchItems
+	default:
+		if reflect.ValueOf(data).Kind() != reflect.Slice {
+			return false, fmt.Errorf("function Split in pipeline '%s', type received is not a slice", ctx.PipelineId())
+		}
+	}
+
+	items := splitBatchItems(data)
+
+	ctx.LoggingClient().Debugf("Splitting %d items in pipeline '%s'", len(items), ctx.PipelineId())
+
+	var results []interface{}
+	var retryData [][]byte
+	var errs error
+	for index, item := range items {
+		itemCtx := &retryDataContext{AppFunctionContext: ctx.Clone()}
+		completed, result := executeFunctions(itemCtx, unbatch.functions, item)
+		if err, ok := result.(error); ok && !completed {
+			if itemCtx.retryData != nil {
+				retryData = append(retryData, itemCtx.retryData)
+			}
+
+			err = fmt.Errorf("item #%d: %w", index, err)
+			if !unbatch.continueOnError {
+				unbatch.setRetryData(ctx, retryData)
+				return false, fmt.Errorf("function Split in pipeline '%s' aborted: %w", ctx.PipelineId(), err)
+			}
+
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if completed {
+			results = append(results, result)
+		}
+	}
+
+	if errs != nil {
+		unbatch.setRetryData(ctx, retryData)
+		return false, fmt.Errorf("function Split in pipeline '%s' failed for some items: %w", ctx.PipelineId(), errs)
+	}
+
+	return true, results
+}
+
+// setRetryData sets the retry data of the failed items on the pipeline's context, as is for a single item, unless
+// it is itself a JSON array which would be split when retried, and as a JSON array otherwise
+func (unbatch *Unbatch) setRetryData(ctx interfaces.AppFunctionContext, retryData [][]byte) {
+	if len(retryData) == 0 {
+		return
+	}
+
+	if len(retryData) == 1 {
+		var elements []json.RawMessage
+		if json.Unmarshal(retryData[0], &elements) != nil {
+			ctx.SetRetryData(retryData[0])
+			return
+		}
+	}
+
+	elements := make([]json.RawMessage, 0, len(retryData))
+	for _, data := range retryData {
+		elements = append(elements, data)
+	}
+
+	joined, err := json.Marshal(elements)
+	if err != nil {
+		ctx.LoggingClient().Errorf("Unable to persist failed items for retry in pipeline '%s', retry data must be JSON: %s", ctx.PipelineId(), err.Error())
+		return
+	}
+
+	ctx.SetRetryData(joined)
+}
+
+// retryDataContext captures the retry data set by the functions executed on a clone of the context, which would
+// otherwise be lost with the clone since only the retry data set on the pipeline's context is stored
+type retryDataContext struct {
+	interfaces.AppFunctionContext
+	retryData []byte
+}
+
+// SetRetryData captures the retry data as well as setting it on the wrapped context
+func (ctx *retryDataContext) SetRetryData(data []byte) {
+	ctx.retryData = data
+	ctx.AppFunctionContext.SetRetryData(data)
+}
@@ -0,0 +1,241 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+func TestNewUnbatchNoFunctions(t *testing.T) {
+	_, err := NewUnbatch(false)
+	require.Error(t, err)
+}
+
+func TestUnbatchSplit(t *testing.T) {
+	var received []string
+	collect := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		event := data.(dtos.Event)
+		received = append(received, event.DeviceName)
+		return true, event.DeviceName
+	}
+
+	unbatch, err := NewUnbatch(false, collect)
+	require.NoError(t, err)
+
+	batch := []dtos.Event{{DeviceName: "device1"}, {DeviceName: "device2"}, {DeviceName: "device3"}}
+	continuePipeline, result := unbatch.Split(ctx, batch)
+
+	require.True(t, continuePipeline)
+	assert.Equal(t, []string{"device1", "device2", "device3"}, received)
+	assert.Equal(t, []interface{}{"device1", "device2", "device3"}, result)
+}
+
+func TestUnbatchSplitFilteredItems(t *testing.T) {
+	filter := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return data.(int)%2 == 0, nil
+	}
+	double := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, data.(int) * 2
+	}
+
+	unbatch, err := NewUnbatch(false, filter, double)
+	require.NoError(t, err)
+
+	continuePipeline, result := unbatch.Split(ctx, []int{1, 2, 3, 4})
+
+	require.True(t, continuePipeline)
+	assert.Equal(t, []interface{}{4, 8}, result)
+}
+
+func TestUnbatchSplitErrors(t *testing.T) {
+	tests := []struct {
+		Name              string
+		ContinueOnError   bool
+		ExpectedProcessed []int
+		ExpectedErrors    []string
+	}{
+		{"abort on error", false, []int{1, 2}, []string{"aborted", "item #1: bad item 2"}},
+		{"continue on error", true, []int{1, 2, 3, 4}, []string{"failed for some items", "item #1: bad item 2", "item #3: bad item 4"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var processed []int
+			process := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+				item := data.(int)
+				processed = append(processed, item)
+				if item%2 == 0 {
+					return false, fmt.Errorf("bad item %d", item)
+				}
+				return true, item
+			}
+
+			unbatch, err := NewUnbatch(test.ContinueOnError, process)
+			require.NoError(t, err)
+
+			continuePipeline, result := unbatch.Split(ctx, []int{1, 2, 3, 4})
+
+			require.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			for _, expected := range test.ExpectedErrors {
+				assert.Contains(t, result.(error).Error(), expected)
+			}
+			assert.Equal(t, test.ExpectedProcessed, processed)
+		})
+	}
+}
+
+func TestUnbatchSplitContextIsolated(t *testing.T) {
+	setValue := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		_, found := ctx.GetValue("item")
+		if found {
+			return false, errors.New("value from previous item leaked")
+		}
+		ctx.AddValue("item", data.(string))
+		return true, data
+	}
+
+	unbatch, err := NewUnbatch(false, setValue)
+	require.NoError(t, err)
+
+	continuePipeline, result := unbatch.Split(ctx, []string{"a", "b"})
+	require.True(t, continuePipeline, result)
+
+	_, found := ctx.GetValue("item")
+	assert.False(t, found)
+}
+
+func TestUnbatchSplitBadInput(t *testing.T) {
+	unbatch, err := NewUnbatch(false, func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		return true, data
+	})
+	require.NoError(t, err)
+
+	continuePipeline, result := unbatch.Split(ctx, nil)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+
+	continuePipeline, result = unbatch.Split(ctx, 10)
+	assert.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "type received is not a slice")
+}
+
+func TestUnbatchSplitBytes(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Data     interface{}
+		Expected []string
+	}{
+		{"JSON array bytes", []byte(`[{"id":1},{"id":2}]`), []string{`{"id":1}`, `{"id":2}`}},
+		{"JSON array string", `[{"id":1},{"id":2}]`, []string{`{"id":1}`, `{"id":2}`}},
+		{"JSON object bytes", []byte(`{"id":1}`), []string{`{"id":1}`}},
+		{"non-JSON bytes", []byte("abc"), []string{"abc"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var received []string
+			collect := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+				switch item := data.(type) {
+				case []byte:
+					received = append(received, string(item))
+				case string:
+					received = append(received, item)
+				default:
+					return false, fmt.Errorf("unexpected type %T", data)
+				}
+				return true, data
+			}
+
+			unbatch, err := NewUnbatch(false, collect)
+			require.NoError(t, err)
+
+			continuePipeline, result := unbatch.Split(ctx, test.Data)
+			require.True(t, continuePipeline, result)
+			assert.Equal(t, test.Expected, received)
+		})
+	}
+}
+
+func TestUnbatchSplitRetryData(t *testing.T) {
+	tests := []struct {
+		Name              string
+		ContinueOnError   bool
+		ExpectedRetryData string
+		ExpectedRetried   []string
+	}{
+		{"abort on error", false, `{"id":2}`, []string{`{"id":2}`}},
+		{"continue on error", true, `[{"id":2},{"id":4}]`, []string{`{"id":2}`, `{"id":4}`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx.SetRetryData(nil)
+			defer ctx.SetRetryData(nil)
+
+			var attempted []string
+			persistingSend := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+				item := data.([]byte)
+				attempted = append(attempted, string(item))
+				if string(item) == `{"id":2}` || string(item) == `{"id":4}` {
+					ctx.SetRetryData(item)
+					return false, fmt.Errorf("failed to send %s", item)
+				}
+				return true, nil
+			}
+
+			unbatch, err := NewUnbatch(test.ContinueOnError, persistingSend)
+			require.NoError(t, err)
+
+			continuePipeline, result := unbatch.Split(ctx, []byte(`[{"id":1},{"id":2},{"id":3},{"id":4}]`))
+			require.False(t, continuePipeline)
+			require.Error(t, result.(error))
+			assert.Equal(t, test.ExpectedRetryData, string(ctx.RetryData()))
+
+			// Retrying the stored data only processes the failed items
+			attempted = nil
+			_, _ = unbatch.Split(ctx, ctx.RetryData())
+			assert.Equal(t, test.ExpectedRetried, attempted)
+		})
+	}
+}
+
+func TestUnbatchSplitRetryDataJSONArrayItem(t *testing.T) {
+	ctx.SetRetryData(nil)
+	defer ctx.SetRetryData(nil)
+
+	persistingSend := func(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+		ctx.SetRetryData([]byte(`[1,2]`))
+		return false, errors.New("failed to send")
+	}
+
+	unbatch, err := NewUnbatch(false, persistingSend)
+	require.NoError(t, err)
+
+	continuePipeline, _ := unbatch.Split(ctx, []int{1})
+	require.False(t, continuePipeline)
+	// A single item which is itself a JSON array is wrapped so it isn't split when retried
+	assert.Equal(t, `[[1,2]]`, string(ctx.RetryData()))
+}
@@ -0,0 +1,221 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+)
+
+// Names of the predefined unit conversions
+const (
+	UnitConversionFahrenheitToCelsius = "FahrenheitToCelsius"
+	UnitConversionCelsiusToFahrenheit = "CelsiusToFahrenheit"
+	UnitConversionCelsiusToKelvin     = "CelsiusToKelvin"
+	UnitConversionKelvinToCelsius     = "KelvinToCelsius"
+	UnitConversionPsiToKilopascal     = "PsiToKilopascal"
+	UnitConversionKilopascalToPsi     = "KilopascalToPsi"
+	UnitConversionPascalToKilopascal  = "PascalToKilopascal"
+	UnitConversionBarToKilopascal     = "BarToKilopascal"
+)
+
+const kilopascalsPerPsi = 6.894757293168361
+
+var namedUnitConversions = map[string]UnitConversion{
+	UnitConversionFahrenheitToCelsius: {Scale: 5.0 / 9.0, Offset: -32.0 * 5.0 / 9.0, Units: "C"},
+	UnitConversionCelsiusToFahrenheit: {Scale: 9.0 / 5.0, Offset: 32, Units: "F"},
+	UnitConversionCelsiusToKelvin:     {Scale: 1, Offset: 273.15, Units: "K"},
+	UnitConversionKelvinToCelsius:     {Scale: 1, Offset: -273.15, Units: "C"},
+	UnitConversionPsiToKilopascal:     {Scale: kilopascalsPerPsi, Units: "kPa"},
+	UnitConversionKilopascalToPsi:     {Scale: 1 / kilopascalsPerPsi, Units: "psi"},
+	UnitConversionPascalToKilopascal:  {Scale: 0.001, Units: "kPa"},
+	UnitConversionBarToKilopascal:     {Scale: 100, Units: "kPa"},
+}
+
+// integerBitSizes maps the integer reading value types to their bit size and whether they are signed
+var integerBitSizes = map[string]struct {
+	bitSize int
+	signed  bool
+}{
+	common.ValueTypeInt8:   {8, true},
+	common.ValueTypeInt16:  {16, true},
+	common.ValueTypeInt32:  {32, true},
+	common.ValueTypeInt64:  {64, true},
+	common.ValueTypeUint8:  {8, false},
+	common.ValueTypeUint16: {16, false},
+	common.ValueTypeUint32: {32, false},
+	common.ValueTypeUint64: {64, false},
+}
+
+// UnitConversion is a linear conversion of a reading value, i.e. value * Scale + Offset. When Units is not empty,
+// the reading's units are set to Units.
+type UnitConversion struct {
+	Scale  float64
+	Offset float64
+	Units  string
+}
+
+// NamedUnitConversion returns the predefined unit conversion with the specified name, such as UnitConversionFahrenheitToCelsius.
+func NamedUnitConversion(name string) (UnitConversion, error) {
+	conversion, found := namedUnitConversions[name]
+	if !found {
+		return UnitConversion{}, fmt.Errorf("unknown unit conversion '%s'", name)
+	}
+
+	return conversion, nil
+}
+
+// UnitConverter converts the numeric reading values of Events from one unit to another for the configured resources.
+type UnitConverter struct {
+	conversions   map[string]UnitConversion
+	decimalPlaces int
+}
+
+// NewUnitConverter creates, initializes and returns a new instance of UnitConverter which converts the reading
+// values for the resources specified as the keys of conversions. Readings for other resources pass through unchanged.
+func NewUnitConverter(conversions map[string]UnitConversion) (*UnitConverter, error) {
+	if len(conversions) == 0 {
+		return nil, errors.New("at least one unit conversion must be specified")
+	}
+
+	for resourceName, conversion := range conversions {
+		if conversion.Scale == 0 {
+			return nil, fmt.Errorf("unit conversion for resource '%s' must have a non-zero scale", resourceName)
+		}
+	}
+
+	return &UnitConverter{
+		conversions:   conversions,
+		decimalPlaces: -1,
+	}, nil
+}
+
+// SetDecimalPlaces sets the number of decimal places converted float values are rounded to. Negative values,
+// the default, disable rounding. Converted integer values are always rounded to the nearest whole number.
+func (converter *UnitConverter) SetDecimalPlaces(decimalPlaces int) {
+	converter.decimalPlaces = decimalPlaces
+}
+
+// Convert converts the values of the Event's numeric readings for the configured resources and updates their
+// units. Non-numeric readings pass through unchanged.
+// This function will return an error and stop the pipeline if a non-edgex event is received, if no data is received
+// or if a reading value can not be converted.
+func (converter *UnitConverter) Convert(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function Convert in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	event, ok := data.(dtos.Event)
+	if !ok {
+		return false, fmt.Errorf("function Convert in pipeline '%s', type received is not an Event", ctx.PipelineId())
+	}
+
+	for index, reading := range event.Readings {
+		conversion, found := converter.conversions[reading.ResourceName]
+		if !found {
+			continue
+		}
+
+		value, converted, err := converter.convertValue(reading.ValueType, reading.Value, conversion)
+		if err != nil {
+			return false, fmt.Errorf("function Convert in pipeline '%s': unable to convert reading for resource '%s': %s",
+				ctx.PipelineId(), reading.ResourceName, err.Error())
+		}
+
+		if !converted {
+			continue
+		}
+
+		event.Readings[index].Value = value
+		if len(conversion.Units) > 0 {
+			event.Readings[index].Units = conversion.Units
+		}
+
+		ctx.LoggingClient().Debugf("Converted '%s' reading value from '%s' to '%s' in pipeline '%s'",
+			reading.ResourceName, reading.Value, value, ctx.PipelineId())
+	}
+
+	return true, event
+}
+
+// convertValue returns the converted value encoded the same way as the original value for its value type.
+// The returned bool is false when the value type is not numeric.
+func (converter *UnitConverter) convertValue(valueType string, value string, conversion UnitConversion) (string, bool, error) {
+	switch valueType {
+	case common.ValueTypeFloat32, common.ValueTypeFloat64:
+		bitSize := 64
+		if valueType == common.ValueTypeFloat32 {
+			bitSize = 32
+		}
+
+		original, err := strconv.ParseFloat(value, bitSize)
+		if err != nil {
+			return "", false, err
+		}
+
+		result := original*conversion.Scale + conversion.Offset
+		if converter.decimalPlaces >= 0 {
+			pow := math.Pow(10, float64(converter.decimalPlaces))
+			result = math.Round(result*pow) / pow
+		}
+
+		// Float values are encoded the same way as the EdgeX reading DTOs encode them
+		if bitSize == 32 {
+			return fmt.Sprintf("%e", float32(result)), true, nil
+		}
+		return fmt.Sprintf("%e", result), true, nil
+	}
+
+	integerType, found := integerBitSizes[valueType]
+	if !found {
+		return "", false, nil
+	}
+
+	if integerType.signed {
+		original, err := strconv.ParseInt(value, 10, integerType.bitSize)
+		if err != nil {
+			return "", false, err
+		}
+
+		result := math.Round(float64(original)*conversion.Scale + conversion.Offset)
+		limit := math.Ldexp(1, integerType.bitSize-1)
+		if result < -limit || result >= limit {
+			return "", false, fmt.Errorf("converted value %v is out of range for %s", result, valueType)
+		}
+
+		return strconv.FormatInt(int64(result), 10), true, nil
+	}
+
+	original, err := strconv.ParseUint(value, 10, integerType.bitSize)
+	if err != nil {
+		return "", false, err
+	}
+
+	result := math.Round(float64(original)*conversion.Scale + conversion.Offset)
+	if result < 0 || result >= math.Ldexp(1, integerType.bitSize) {
+		return "", false, fmt.Errorf("converted value %v is out of range for %s", result, valueType)
+	}
+
+	return strconv.FormatUint(uint64(result), 10), true, nil
+}
@@ -0,0 +1,195 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/edgexfoundry/go-mod-core-contracts/v3/dtos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func unitReading(resourceName string, valueType string, value string, units string) dtos.BaseReading {
+	return dtos.BaseReading{
+		DeviceName:    deviceName1,
+		ResourceName:  resourceName,
+		ValueType:     valueType,
+		Units:         units,
+		SimpleReading: dtos.SimpleReading{Value: value},
+	}
+}
+
+func TestNewUnitConverter(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Conversions map[string]UnitConversion
+		ExpectError bool
+	}{
+		{"Valid", map[string]UnitConversion{"Temperature": {Scale: 1, Offset: 10}}, false},
+		{"Invalid - no conversions", nil, true},
+		{"Invalid - zero scale", map[string]UnitConversion{"Temperature": {Offset: 10}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			converter, err := NewUnitConverter(test.Conversions)
+			if test.ExpectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, converter)
+		})
+	}
+}
+
+func TestNamedUnitConversion(t *testing.T) {
+	conversion, err := NamedUnitConversion(UnitConversionFahrenheitToCelsius)
+	require.NoError(t, err)
+	assert.Equal(t, "C", conversion.Units)
+
+	_, err = NamedUnitConversion("FurlongsToFathoms")
+	require.Error(t, err)
+}
+
+func TestUnitConverterTemperature(t *testing.T) {
+	fahrenheitToCelsius, err := NamedUnitConversion(UnitConversionFahrenheitToCelsius)
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name          string
+		DecimalPlaces int
+		Reading       dtos.BaseReading
+		ExpectedValue string
+	}{
+		{"Float64", -1, unitReading("Temperature", common.ValueTypeFloat64, "9.860000e+01", "F"), "3.700000e+01"},
+		{"Float32", -1, unitReading("Temperature", common.ValueTypeFloat32, "2.120000e+02", "F"), "1.000000e+02"},
+		{"Float64 not rounded", -1, unitReading("Temperature", common.ValueTypeFloat64, "1.000000e+02", "F"), "3.777778e+01"},
+		{"Float64 rounded to 1 decimal place", 1, unitReading("Temperature", common.ValueTypeFloat64, "1.000000e+02", "F"), "3.780000e+01"},
+		{"Float64 rounded to whole number", 0, unitReading("Temperature", common.ValueTypeFloat64, "1.000000e+02", "F"), "3.800000e+01"},
+		{"Float64 not in exponent format", -1, unitReading("Temperature", common.ValueTypeFloat64, "212", "F"), "1.000000e+02"},
+		{"Int16", -1, unitReading("Temperature", common.ValueTypeInt16, "212", "F"), "100"},
+		{"Int16 rounded", -1, unitReading("Temperature", common.ValueTypeInt16, "100", "F"), "38"},
+		{"Int32 negative", -1, unitReading("Temperature", common.ValueTypeInt32, "-40", "F"), "-40"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			converter, err := NewUnitConverter(map[string]UnitConversion{"Temperature": fahrenheitToCelsius})
+			require.NoError(t, err)
+			converter.SetDecimalPlaces(test.DecimalPlaces)
+
+			event := dtos.Event{DeviceName: deviceName1, Readings: []dtos.BaseReading{test.Reading}}
+
+			continuePipeline, result := converter.Convert(ctx, event)
+			require.True(t, continuePipeline)
+			actual, ok := result.(dtos.Event)
+			require.True(t, ok)
+			assert.Equal(t, test.ExpectedValue, actual.Readings[0].Value)
+			assert.Equal(t, "C", actual.Readings[0].Units)
+		})
+	}
+}
+
+func TestUnitConverterPressure(t *testing.T) {
+	psiToKilopascal, err := NamedUnitConversion(UnitConversionPsiToKilopascal)
+	require.NoError(t, err)
+	pascalToKilopascal, err := NamedUnitConversion(UnitConversionPascalToKilopascal)
+	require.NoError(t, err)
+
+	converter, err := NewUnitConverter(map[string]UnitConversion{
+		"Pressure":     psiToKilopascal,
+		"LinePressure": pascalToKilopascal,
+		"Depth":        {Scale: 0.01, Units: "m"},
+	})
+	require.NoError(t, err)
+
+	event := dtos.Event{
+		DeviceName: deviceName1,
+		Readings: []dtos.BaseReading{
+			unitReading("Pressure", common.ValueTypeFloat64, "1.470000e+01", "psi"),
+			unitReading("LinePressure", common.ValueTypeUint32, "101325", "Pa"),
+			unitReading("Depth", common.ValueTypeFloat32, "2.500000e+02", "cm"),
+		},
+	}
+
+	continuePipeline, result := converter.Convert(ctx, event)
+	require.True(t, continuePipeline)
+	actual := result.(dtos.Event)
+	assert.Equal(t, "1.013529e+02", actual.Readings[0].Value)
+	assert.Equal(t, "kPa", actual.Readings[0].Units)
+	assert.Equal(t, "101", actual.Readings[1].Value)
+	assert.Equal(t, "kPa", actual.Readings[1].Units)
+	assert.Equal(t, "2.500000e+00", actual.Readings[2].Value)
+	assert.Equal(t, "m", actual.Readings[2].Units)
+
+	converter.SetDecimalPlaces(2)
+	event.Readings[0] = unitReading("Pressure", common.ValueTypeFloat64, "1.470000e+01", "psi")
+
+	continuePipeline, result = converter.Convert(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, "1.013500e+02", result.(dtos.Event).Readings[0].Value)
+}
+
+func TestUnitConverterPassThrough(t *testing.T) {
+	converter, err := NewUnitConverter(map[string]UnitConversion{"Temperature": {Scale: 2, Units: "C"}})
+	require.NoError(t, err)
+
+	event := dtos.Event{
+		DeviceName: deviceName1,
+		Readings: []dtos.BaseReading{
+			unitReading("Temperature", common.ValueTypeString, "hot", "F"),
+			unitReading("Temperature", common.ValueTypeBool, "true", "F"),
+			unitReading("Humidity", common.ValueTypeFloat64, "5.000000e+01", "%"),
+		},
+	}
+	expected := dtos.Event{DeviceName: deviceName1, Readings: append([]dtos.BaseReading{}, event.Readings...)}
+
+	continuePipeline, result := converter.Convert(ctx, event)
+	require.True(t, continuePipeline)
+	assert.Equal(t, expected, result)
+}
+
+func TestUnitConverterErrors(t *testing.T) {
+	converter, err := NewUnitConverter(map[string]UnitConversion{
+		"Temperature": {Scale: 1, Offset: 273.15, Units: "K"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		Name          string
+		Data          interface{}
+		ExpectedError string
+	}{
+		{"No data", nil, "No Data Received"},
+		{"Not an Event", "not an event", "type received is not an Event"},
+		{"Invalid float", dtos.Event{Readings: []dtos.BaseReading{unitReading("Temperature", common.ValueTypeFloat64, "warm", "C")}}, "unable to convert reading for resource 'Temperature'"},
+		{"Out of range", dtos.Event{Readings: []dtos.BaseReading{unitReading("Temperature", common.ValueTypeInt8, "100", "C")}}, "out of range for Int8"},
+		{"Negative unsigned", dtos.Event{Readings: []dtos.BaseReading{unitReading("Temperature", common.ValueTypeUint8, "-300", "C")}}, "unable to convert reading"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			continuePipeline, result := converter.Convert(ctx, test.Data)
+			require.False(t, continuePipeline)
+			assert.Contains(t, result.(error).Error(), test.ExpectedError)
+		})
+	}
+}
@@ -0,0 +1,306 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	coreCommon "github.com/edgexfoundry/go-mod-core-contracts/v3/common"
+	"github.com/gorilla/websocket"
+	gometrics "github.com/rcrowley/go-metrics"
+
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/internal"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/interfaces"
+	"github.com/edgexfoundry/app-functions-sdk-go/v3/pkg/util"
+)
+
+const (
+	// DefaultWebSocketReconnectInterval is the initial wait before reconnecting when no interval is specified
+	DefaultWebSocketReconnectInterval = time.Second
+	// DefaultWebSocketMaxReconnectInterval is the maximum wait before reconnecting when no maximum is specified
+	DefaultWebSocketMaxReconnectInterval = time.Minute
+	// DefaultWebSocketHandshakeTimeout is the time limit for connecting and the handshake when no timeout is specified
+	DefaultWebSocketHandshakeTimeout = 10 * time.Second
+)
+
+// WebSocketSenderOptions contains all options available to the WebSocketSender
+type WebSocketSenderOptions struct {
+	// URL of the websocket endpoint, i.e. ws://host:port/path or wss://host:port/path
+	URL string
+	// PersistOnError enables use of store & forward loop if true
+	PersistOnError bool
+	// HTTPHeaderName is the header the secret value is sent in during the handshake. Not used if empty.
+	HTTPHeaderName string
+	// SecretName is the name of the secret in the SecretStore containing the handshake header value
+	SecretName string
+	// SecretValueKey is the key for the handshake header value in the SecretName secret data
+	SecretValueKey string
+	// TextMessages sends the data as text messages when true, otherwise as binary messages
+	TextMessages bool
+	// HandshakeTimeout is the time limit for connecting and the handshake.
+	// Defaults to DefaultWebSocketHandshakeTimeout if zero.
+	HandshakeTimeout time.Duration
+	// WriteTimeout is the time limit for writing each message. Zero means no timeout.
+	WriteTimeout time.Duration
+	// ReconnectInterval is the initial wait after a failed connection attempt before trying again, which is doubled
+	// on each subsequent failure. Defaults to DefaultWebSocketReconnectInterval if zero.
+	ReconnectInterval time.Duration
+	// MaxReconnectInterval is the maximum wait between connection attempts.
+	// Defaults to DefaultWebSocketMaxReconnectInterval if zero.
+	MaxReconnectInterval time.Duration
+}
+
+// WebSocketSender sends data over a persistent websocket connection, which is reconnected with backoff when lost.
+type WebSocketSender struct {
+	options          WebSocketSenderOptions
+	lock             sync.Mutex
+	conn             *websocket.Conn
+	reconnectWait    time.Duration
+	nextConnect      time.Time
+	wsSizeMetrics    gometrics.Histogram
+	wsErrorMetric    gometrics.Counter
+	redactedURL      string
+	secretsRetrieved time.Time
+	closed           bool
+	closeContext     context.Context
+	cancelClose      context.CancelFunc
+	readers          sync.WaitGroup
+}
+
+// NewWebSocketSender creates, initializes and returns a new instance of WebSocketSender. The connection is made
+// when the first data is sent.
+func NewWebSocketSender(options WebSocketSenderOptions) *WebSocketSender {
+	if options.ReconnectInterval <= 0 {
+		options.ReconnectInterval = DefaultWebSocketReconnectInterval
+	}
+
+	if options.MaxReconnectInterval <= 0 {
+		options.MaxReconnectInterval = DefaultWebSocketMaxReconnectInterval
+	}
+
+	if options.HandshakeTimeout <= 0 {
+		options.HandshakeTimeout = DefaultWebSocketHandshakeTimeout
+	}
+
+	redactedURL := options.URL
+	if parsedUrl, err := url.Parse(options.URL); err == nil {
+		redactedURL = parsedUrl.Redacted()
+	}
+
+	closeContext, cancelClose := context.WithCancel(context.Background())
+
+	return &WebSocketSender{
+		options:       options,
+		reconnectWait: options.ReconnectInterval,
+		redactedURL:   redactedURL,
+		wsErrorMetric: gometrics.NewCounter(),
+		wsSizeMetrics: gometrics.NewHistogram(gometrics.NewUniformSample(internal.MetricsReservoirSize)),
+		closeContext:  closeContext,
+		cancelClose:   cancelClose,
+	}
+}
+
+// WebSocketSend writes the data from the previous function as a single message on the websocket connection,
+// connecting first if not connected. When connecting fails, further attempts are not made until the reconnect
+// interval, which backs off on each failure, has elapsed and sends fail in the meantime.
+// If no previous function exists, then the event that triggered the pipeline will be used.
+func (sender *WebSocketSender) WebSocketSend(ctx interfaces.AppFunctionContext, data interface{}) (bool, interface{}) {
+	if data == nil {
+		return false, fmt.Errorf("function WebSocketSend in pipeline '%s': No Data Received", ctx.PipelineId())
+	}
+
+	exportData, err := util.CoerceType(data)
+	if err != nil {
+		return false, err
+	}
+
+	tag := map[string]string{"url": sender.redactedURL}
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.WebSocketExportErrorsName, sender.redactedURL) },
+		func() any { return sender.wsErrorMetric },
+		tag)
+
+	registerMetric(ctx,
+		func() string { return fmt.Sprintf("%s-%s", internal.WebSocketExportSizeName, sender.redactedURL) },
+		func() any { return sender.wsSizeMetrics },
+		tag)
+
+	// The lock is held while writing since a websocket connection only supports one concurrent writer
+	sender.lock.Lock()
+	defer sender.lock.Unlock()
+
+	conn, err := sender.connect(ctx)
+	if err != nil {
+		sender.wsErrorMetric.Inc(1)
+		sender.setRetryData(ctx, exportData)
+		return false, fmt.Errorf("in pipeline '%s', unable to connect to websocket %s: %s", ctx.PipelineId(), sender.redactedURL, err.Error())
+	}
+
+	if sender.options.WriteTimeout > 0 {
+		_ = conn.SetWriteDeadline(time.Now().Add(sender.options.WriteTimeout))
+	}
+
+	messageType := websocket.BinaryMessage
+	if sender.options.TextMessages {
+		messageType = websocket.TextMessage
+	}
+
+	if err := conn.WriteMessage(messageType, exportData); err != nil {
+		// The connection can't be used after a failed write, so it is re-established on the next send
+		sender.closeConnection(conn)
+		sender.wsErrorMetric.Inc(1)
+		sender.setRetryData(ctx, exportData)
+		return false, fmt.Errorf("in pipeline '%s', websocket write to %s failed: %s", ctx.PipelineId(), sender.redactedURL, err.Error())
+	}
+
+	// Data successfully sent, so retry any failed data, if Store and Forward enabled and data has been saved
+	if sender.options.PersistOnError {
+		ctx.TriggerRetryFailedData()
+	}
+
+	sender.wsSizeMetrics.Update(int64(len(exportData)))
+
+	ctx.LoggingClient().Debugf("Sent %d bytes of data to websocket %s in pipeline '%s'", len(exportData), sender.redactedURL, ctx.PipelineId())
+	ctx.LoggingClient().Tracef("Data exported to websocket in pipeline '%s': %s=%s", ctx.PipelineId(), coreCommon.CorrelationHeader, ctx.CorrelationID())
+
+	return true, nil
+}
+
+// Close closes the connection and waits for the goroutine reading from the connection to exit. A connection attempt
+// in progress is aborted if still dialing and otherwise completes, within the HandshakeTimeout, before the connection
+// is closed. Sends after Close fail.
+func (sender *WebSocketSender) Close() error {
+	sender.cancelClose()
+
+	sender.lock.Lock()
+	sender.closed = true
+	var err error
+	if sender.conn != nil {
+		err = sender.conn.Close()
+		sender.conn = nil
+	}
+	sender.lock.Unlock()
+
+	// The lock must not be held while waiting since the reader takes it when the connection is closed
+	sender.readers.Wait()
+	return err
+}
+
+// connect returns the current connection, establishing a new one if not connected or the secrets have been updated
+// since connecting. Must be called with the lock held.
+func (sender *WebSocketSender) connect(ctx interfaces.AppFunctionContext) (*websocket.Conn, error) {
+	if sender.closed {
+		return nil, errors.New("sender is closed")
+	}
+
+	usingSecret := len(sender.options.SecretName) > 0
+	if sender.conn != nil {
+		if !usingSecret || !sender.secretsRetrieved.Before(ctx.SecretProvider().SecretsLastUpdated()) {
+			return sender.conn, nil
+		}
+
+		ctx.LoggingClient().Infof("Secrets updated, reconnecting to websocket %s", sender.redactedURL)
+		sender.closeConnection(sender.conn)
+	}
+
+	if wait := time.Until(sender.nextConnect); wait > 0 {
+		return nil, fmt.Errorf("not connected, next connection attempt in %s", wait.Round(time.Millisecond).String())
+	}
+
+	header := http.Header{}
+	if usingSecret {
+		if len(sender.options.HTTPHeaderName) == 0 || len(sender.options.SecretValueKey) == 0 {
+			return nil, errors.New("HTTPHeaderName & SecretValueKey must be specified when SecretName is specified")
+		}
+
+		secrets, err := ctx.SecretProvider().GetSecret(sender.options.SecretName, sender.options.SecretValueKey)
+		if err != nil {
+			return nil, err
+		}
+
+		header.Set(sender.options.HTTPHeaderName, secrets[sender.options.SecretValueKey])
+		sender.secretsRetrieved = time.Now()
+	}
+
+	dialer := *websocket.DefaultDialer
+	dialer.HandshakeTimeout = sender.options.HandshakeTimeout
+
+	ctx.LoggingClient().Infof("Connecting to websocket %s in pipeline '%s'", sender.redactedURL, ctx.PipelineId())
+
+	// The dialing is aborted if the sender is closed. The whole attempt is limited by the handshake timeout.
+	dialContext, cancel := context.WithTimeout(sender.closeContext, sender.options.HandshakeTimeout)
+	defer cancel()
+
+	conn, response, err := dialer.DialContext(dialContext, sender.options.URL, header)
+	if response != nil && response.Body != nil {
+		_ = response.Body.Close()
+	}
+	if err != nil {
+		sender.nextConnect = time.Now().Add(sender.reconnectWait)
+		sender.reconnectWait *= 2
+		if sender.reconnectWait > sender.options.MaxReconnectInterval {
+			sender.reconnectWait = sender.options.MaxReconnectInterval
+		}
+		return nil, err
+	}
+
+	sender.conn = conn
+	sender.reconnectWait = sender.options.ReconnectInterval
+	sender.nextConnect = time.Time{}
+
+	sender.readers.Add(1)
+	go sender.readMessages(conn)
+
+	ctx.LoggingClient().Infof("Connected to websocket %s in pipeline '%s'", sender.redactedURL, ctx.PipelineId())
+	return conn, nil
+}
+
+// readMessages discards messages received on the connection, which is required for control messages such as pings
+// and close to be processed, until the connection is closed, at which point it is cleared so the next send reconnects.
+func (sender *WebSocketSender) readMessages(conn *websocket.Conn) {
+	defer sender.readers.Done()
+
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			sender.lock.Lock()
+			sender.closeConnection(conn)
+			sender.lock.Unlock()
+			return
+		}
+	}
+}
+
+// closeConnection closes the connection and clears it if it is the current connection. Must be called with the lock held.
+func (sender *WebSocketSender) closeConnection(conn *websocket.Conn) {
+	_ = conn.Close()
+	if sender.conn == conn {
+		sender.conn = nil
+	}
+}
+
+func (sender *WebSocketSender) setRetryData(ctx interfaces.AppFunctionContext, exportData []byte) {
+	if sender.options.PersistOnError {
+		ctx.SetRetryData(exportData)
+	}
+}
@@ -0,0 +1,296 @@
+//
+// Copyright (c) 2024 Intel Corporation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package transforms
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	bootstrapContainer "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/container"
+	mocks2 "github.com/edgexfoundry/go-mod-bootstrap/v3/bootstrap/interfaces/mocks"
+	"github.com/edgexfoundry/go-mod-bootstrap/v3/di"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testWebSocketServer echoes and counts the messages received
+type testWebSocketServer struct {
+	server     *httptest.Server
+	received   atomic.Int64
+	lastHeader atomic.Value
+	connLock   sync.Mutex
+	conns      []*websocket.Conn
+}
+
+func newTestWebSocketServer(t *testing.T) *testWebSocketServer {
+	wsServer := &testWebSocketServer{}
+	upgrader := websocket.Upgrader{}
+
+	wsServer.server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		wsServer.lastHeader.Store(request.Header.Get("X-Api-Key"))
+		conn, err := upgrader.Upgrade(writer, request, nil)
+		require.NoError(t, err)
+
+		wsServer.connLock.Lock()
+		wsServer.conns = append(wsServer.conns, conn)
+		wsServer.connLock.Unlock()
+
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			wsServer.received.Add(1)
+			_ = conn.WriteMessage(messageType, message)
+		}
+	}))
+
+	return wsServer
+}
+
+func (wsServer *testWebSocketServer) url() string {
+	return "ws" + strings.TrimPrefix(wsServer.server.URL, "http")
+}
+
+// dropConnections closes the server side of all connections
+func (wsServer *testWebSocketServer) dropConnections() {
+	wsServer.connLock.Lock()
+	defer wsServer.connLock.Unlock()
+
+	for _, conn := range wsServer.conns {
+		_ = conn.Close()
+	}
+	wsServer.conns = nil
+}
+
+func TestWebSocketSend(t *testing.T) {
+	wsServer := newTestWebSocketServer(t)
+	defer wsServer.server.Close()
+
+	sender := NewWebSocketSender(WebSocketSenderOptions{URL: wsServer.url()})
+
+	for i := 0; i < 3; i++ {
+		continuePipeline, result := sender.WebSocketSend(ctx, msgStr)
+		require.True(t, continuePipeline, result)
+	}
+
+	assert.Eventually(t, func() bool { return wsServer.received.Load() == 3 }, time.Second, 10*time.Millisecond)
+	// The connection is reused for all sends
+	wsServer.connLock.Lock()
+	assert.Len(t, wsServer.conns, 1)
+	wsServer.connLock.Unlock()
+	assert.Equal(t, int64(3), sender.wsSizeMetrics.Count())
+}
+
+func TestWebSocketSendConcurrent(t *testing.T) {
+	wsServer := newTestWebSocketServer(t)
+	defer wsServer.server.Close()
+
+	sender := NewWebSocketSender(WebSocketSenderOptions{URL: wsServer.url(), TextMessages: true})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			continuePipeline, result := sender.WebSocketSend(ctx, msgStr)
+			assert.True(t, continuePipeline, result)
+		}()
+	}
+	wg.Wait()
+
+	assert.Eventually(t, func() bool { return wsServer.received.Load() == 20 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(0), sender.wsErrorMetric.Count())
+}
+
+func TestWebSocketSendWithSecretHeader(t *testing.T) {
+	mockSP := &mocks2.SecretProvider{}
+	mockSP.On("GetSecret", "ws-secret", "api-key").Return(map[string]string{"api-key": "my-API-key"}, nil)
+	mockSP.On("SecretsLastUpdated").Return(time.Now().Add(-time.Hour))
+
+	dic.Update(di.ServiceConstructorMap{
+		bootstrapContainer.SecretProviderName: func(get di.Get) interface{} {
+			return mockSP
+		},
+	})
+
+	wsServer := newTestWebSocketServer(t)
+	defer wsServer.server.Close()
+
+	sender := NewWebSocketSender(WebSocketSenderOptions{
+		URL:            wsServer.url(),
+		HTTPHeaderName: "X-Api-Key",
+		SecretName:     "ws-secret",
+		SecretValueKey: "api-key",
+	})
+
+	continuePipeline, result := sender.WebSocketSend(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	assert.Equal(t, "my-API-key", wsServer.lastHeader.Load())
+
+	sender = NewWebSocketSender(WebSocketSenderOptions{URL: wsServer.url(), SecretName: "ws-secret"})
+	continuePipeline, result = sender.WebSocketSend(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "HTTPHeaderName & SecretValueKey must be specified")
+}
+
+func TestWebSocketSendReconnect(t *testing.T) {
+	wsServer := newTestWebSocketServer(t)
+	defer wsServer.server.Close()
+
+	sender := NewWebSocketSender(WebSocketSenderOptions{URL: wsServer.url(), PersistOnError: true})
+
+	continuePipeline, result := sender.WebSocketSend(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	require.Eventually(t, func() bool { return wsServer.received.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	wsServer.dropConnections()
+
+	// The lost connection is detected and cleared, so the next send reconnects
+	assert.Eventually(t, func() bool {
+		sender.lock.Lock()
+		defer sender.lock.Unlock()
+		return sender.conn == nil
+	}, time.Second, 10*time.Millisecond)
+
+	ctx.SetRetryData(nil)
+	continuePipeline, result = sender.WebSocketSend(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+	assert.Nil(t, ctx.RetryData())
+	assert.Eventually(t, func() bool { return wsServer.received.Load() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestWebSocketSendConnectFailureBackoff(t *testing.T) {
+	wsServer := newTestWebSocketServer(t)
+	targetUrl := wsServer.url()
+	wsServer.server.Close()
+
+	sender := NewWebSocketSender(WebSocketSenderOptions{
+		URL:                  targetUrl,
+		PersistOnError:       true,
+		ReconnectInterval:    50 * time.Millisecond,
+		MaxReconnectInterval: 80 * time.Millisecond,
+	})
+
+	ctx.SetRetryData(nil)
+	continuePipeline, result := sender.WebSocketSend(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "unable to connect to websocket")
+	// Failed data is persisted for Store and Forward
+	assert.Equal(t, []byte(msgStr), ctx.RetryData())
+	ctx.SetRetryData(nil)
+
+	// Within the reconnect interval, no connection attempt is made
+	continuePipeline, result = sender.WebSocketSend(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "next connection attempt in")
+
+	// Wait is doubled and capped at the maximum
+	sender.lock.Lock()
+	assert.Equal(t, 80*time.Millisecond, sender.reconnectWait)
+	sender.lock.Unlock()
+
+	assert.Equal(t, int64(2), sender.wsErrorMetric.Count())
+	ctx.SetRetryData(nil)
+}
+
+// newHangingListener returns a listener which accepts connections but never responds to the handshake
+func newHangingListener(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	return listener
+}
+
+func TestWebSocketSendHandshakeTimeout(t *testing.T) {
+	sender := NewWebSocketSender(WebSocketSenderOptions{URL: "ws://localhost:0"})
+	assert.Equal(t, DefaultWebSocketHandshakeTimeout, sender.options.HandshakeTimeout)
+
+	listener := newHangingListener(t)
+	defer listener.Close()
+
+	sender = NewWebSocketSender(WebSocketSenderOptions{URL: "ws://" + listener.Addr().String(), HandshakeTimeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	continuePipeline, result := sender.WebSocketSend(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "unable to connect to websocket")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWebSocketSenderClose(t *testing.T) {
+	wsServer := newTestWebSocketServer(t)
+	defer wsServer.server.Close()
+
+	sender := NewWebSocketSender(WebSocketSenderOptions{URL: wsServer.url()})
+
+	continuePipeline, result := sender.WebSocketSend(ctx, msgStr)
+	require.True(t, continuePipeline, result)
+
+	// Close waits for the reader to exit once the connection is closed
+	require.NoError(t, sender.Close())
+	assert.Nil(t, sender.conn)
+
+	continuePipeline, result = sender.WebSocketSend(ctx, msgStr)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "sender is closed")
+
+	// Close waits for a connection attempt in progress, which is limited by the handshake timeout
+	listener := newHangingListener(t)
+	defer listener.Close()
+
+	sender = NewWebSocketSender(WebSocketSenderOptions{URL: "ws://" + listener.Addr().String(), HandshakeTimeout: 200 * time.Millisecond})
+
+	sendDone := make(chan bool, 1)
+	go func() {
+		continuePipeline, _ := sender.WebSocketSend(ctx, msgStr)
+		sendDone <- continuePipeline
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	require.NoError(t, sender.Close())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.False(t, <-sendDone)
+	assert.Nil(t, sender.conn)
+}
+
+func TestWebSocketSendNoData(t *testing.T) {
+	sender := NewWebSocketSender(WebSocketSenderOptions{URL: "ws://localhost:0"})
+
+	continuePipeline, result := sender.WebSocketSend(ctx, nil)
+	require.False(t, continuePipeline)
+	assert.Contains(t, result.(error).Error(), "No Data Received")
+}